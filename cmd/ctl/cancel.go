@@ -0,0 +1,44 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+)
+
+func CancelCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "cancel",
+		Usage:       "cancels communications which haven't been sent yet",
+		Description: "cancels communications waiting for their send-at time or delivery window. a communication already being sent is left as it is, watch shows whether it was cancelled",
+		ArgsUsage:   "<id>...",
+		Flags:       connectionFlags(),
+		Action:      cancel,
+	}
+}
+
+func cancel(ctx context.Context, c *cli.Command) error {
+	if err := requireArgs(c, "cancel"); err != nil {
+		return err
+	}
+	conn, err := dial(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	p := newPrinter(c)
+	for _, id := range c.Args().Slice() {
+		resp, err := conn.API().CancelCommunication(ctx, &pb.CancelCommunicationRequest{Id: id})
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		if err := p.line(resp, "cancellation requested for "+resp.GetId()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
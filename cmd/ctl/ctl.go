@@ -0,0 +1,255 @@
+// Package ctl is the command line client of the unicom API: it sends
+// communications, looks up their status, lists, cancels and watches them.
+package ctl
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/anicoll/unicom/pkg/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// Commands returns the client commands.
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		SendCommand(),
+		RenderCommand(),
+		StatusCommand(),
+		ListCommand(),
+		CancelCommand(),
+		WatchCommand(),
+	}
+}
+
+// connectionFlags are the flags every client command connects to the API and
+// prints its output with.
+func connectionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "address",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_ADDRESS")),
+			Required: false,
+			Value:    "localhost:8090",
+			Usage:    "host:port of the unicom grpc api",
+		},
+		&cli.StringFlag{
+			Name:     "token",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_TOKEN")),
+			Required: false,
+			Value:    "",
+			Usage:    "bearer token sent in the authorization header, for gateways authenticating callers",
+		},
+		&cli.StringFlag{
+			Name:     "principal",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_PRINCIPAL")),
+			Required: false,
+			Value:    "",
+			Usage:    "who is calling, recorded in the audit log when no client certificate is used",
+		},
+		&cli.StringFlag{
+			Name:     "principal-header",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_PRINCIPAL_HEADER")),
+			Required: false,
+			Value:    client.DefaultPrincipalHeader,
+			Usage:    "header the principal is sent in, matching the server's audit-principal-header",
+		},
+		&cli.BoolFlag{
+			Name:     "tls",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_TLS")),
+			Required: false,
+			Usage:    "connect with tls, implied by the other tls flags",
+		},
+		&cli.StringFlag{
+			Name:     "tls-ca-file",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_TLS_CA_FILE")),
+			Required: false,
+			Value:    "",
+			Usage:    "pem file of the certificate authorities the server is verified with, the system's when empty",
+		},
+		&cli.StringFlag{
+			Name:     "tls-cert-file",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_TLS_CERT_FILE")),
+			Required: false,
+			Value:    "",
+			Usage:    "pem file of the client certificate, for mutual tls",
+		},
+		&cli.StringFlag{
+			Name:     "tls-key-file",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_TLS_KEY_FILE")),
+			Required: false,
+			Value:    "",
+			Usage:    "pem file of the client certificate's key",
+		},
+		&cli.StringFlag{
+			Name:     "tls-server-name",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_TLS_SERVER_NAME")),
+			Required: false,
+			Value:    "",
+			Usage:    "name the server's certificate is verified against, the address's host when empty",
+		},
+		&cli.StringFlag{
+			Name:     "output",
+			Aliases:  []string{"o"},
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("UNICOM_OUTPUT")),
+			Required: false,
+			Value:    outputTable,
+			Usage:    "output format, table or json",
+			Validator: func(output string) error {
+				if output != outputTable && output != outputJSON {
+					return fmt.Errorf("unknown output format %q, expected table or json", output)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// dial connects to the API with the token and principal. Calls the server
+// is unavailable for are retried.
+func dial(c *cli.Command) (*client.Client, error) {
+	opts := []client.Option{
+		client.WithToken(c.String("token")),
+		client.WithPrincipal(c.String("principal")),
+		client.WithPrincipalHeader(c.String("principal-header")),
+	}
+	config, err := tlsConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		opts = append(opts, client.WithTLS(config))
+	}
+	return client.Dial(c.String("address"), opts...)
+}
+
+// tlsConfig returns the config the API is connected to with, nil connecting
+// without tls.
+func tlsConfig(c *cli.Command) (*tls.Config, error) {
+	if !c.Bool("tls") && c.String("tls-ca-file") == "" && c.String("tls-cert-file") == "" && c.String("tls-key-file") == "" && c.String("tls-server-name") == "" {
+		return nil, nil
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.String("tls-server-name"),
+	}
+	if caFile := c.String("tls-ca-file"); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+	}
+	if certFile, keyFile := c.String("tls-cert-file"), c.String("tls-key-file"); certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, errors.New("tls-cert-file and tls-key-file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// printer writes a command's result as a table, or as the JSON of the
+// response it came from.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(c *cli.Command) printer {
+	return printer{
+		w:    c.Root().Writer,
+		json: c.String("output") == outputJSON,
+	}
+}
+
+// print writes msg as JSON, or headers and rows as a table.
+func (p printer) print(msg proto.Message, headers []string, rows ...[]string) error {
+	if p.json {
+		data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.w, string(data))
+		return err
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// line writes msg as a single line of JSON, or row as tab separated values,
+// for output which is streamed.
+func (p printer) line(msg proto.Message, row ...string) error {
+	if p.json {
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.w, string(data))
+		return err
+	}
+	_, err := fmt.Fprintln(p.w, strings.Join(row, "\t"))
+	return err
+}
+
+// parseTime parses an RFC 3339 time, or a duration relative to now: "10m" is
+// ten minutes from now, or ten minutes ago when past is set.
+func parseTime(value string, now time.Time, past bool) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if past {
+			d = -d
+		}
+		return timestamppb.New(now.Add(d)), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q, expected an RFC 3339 time or a duration", value)
+	}
+	return timestamppb.New(t), nil
+}
+
+// formatTime formats a timestamp for a table, empty when unset.
+func formatTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().Local().Format(time.DateTime)
+}
+
+// requireArgs checks a command was given at least one argument.
+func requireArgs(c *cli.Command, name string) error {
+	if c.Args().Len() == 0 {
+		return fmt.Errorf("%s requires a communication id", name)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package ctl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+)
+
+func ListCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "lists communications, newest first",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "domain",
+				Usage: "only communications of this domain",
+			},
+			&cli.StringFlag{
+				Name:  "status",
+				Usage: "only communications with this status: pending, success, failed, expired or cancelled",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "only communications requested at or after this time, an RFC 3339 time or a duration ago such as 24h",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "only communications requested before this time, an RFC 3339 time or a duration ago",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Value: 100,
+				Usage: "most communications to list, 0 lists them all",
+			},
+			&cli.StringFlag{
+				Name:  "page-token",
+				Usage: "next page token printed by a previous list",
+			},
+		}, connectionFlags()...),
+		Action: list,
+	}
+}
+
+func list(ctx context.Context, c *cli.Command) error {
+	now := time.Now()
+	since, err := parseTime(c.String("since"), now, true)
+	if err != nil {
+		return err
+	}
+	until, err := parseTime(c.String("until"), now, true)
+	if err != nil {
+		return err
+	}
+	conn, err := dial(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	limit := c.Int("limit")
+	req := &pb.ListCommunicationsRequest{
+		Domain:    c.String("domain"),
+		Status:    strings.ToUpper(c.String("status")),
+		StartTime: since,
+		EndTime:   until,
+		PageToken: c.String("page-token"),
+	}
+	// Pages are fetched until the limit is reached, the token of the last one
+	// is printed so the listing can be carried on.
+	listed := &pb.ListCommunicationsResponse{}
+	for {
+		if limit > 0 {
+			req.PageSize = int32(min(limit-len(listed.Communications), 1000))
+		}
+		resp, err := conn.List(ctx, req)
+		if err != nil {
+			return err
+		}
+		listed.Communications = append(listed.Communications, resp.GetCommunications()...)
+		listed.NextPageToken = resp.GetNextPageToken()
+		if listed.NextPageToken == "" || (limit > 0 && len(listed.Communications) >= limit) {
+			break
+		}
+		req.PageToken = listed.NextPageToken
+	}
+
+	p := newPrinter(c)
+	rows := make([][]string, len(listed.Communications))
+	for i, comm := range listed.Communications {
+		rows[i] = []string{comm.GetId(), comm.GetDomain(), comm.GetType(), comm.GetStatus(), formatTime(comm.GetCreatedAt()), formatTime(comm.GetSentAt()), comm.GetErrorKind()}
+	}
+	if err := p.print(listed, []string{"ID", "DOMAIN", "TYPE", "STATUS", "CREATED", "SENT", "ERROR"}, rows...); err != nil {
+		return err
+	}
+	if !p.json && listed.NextPageToken != "" {
+		_, err = c.Root().ErrWriter.Write([]byte("more communications with --page-token " + listed.NextPageToken + "\n"))
+	}
+	return err
+}
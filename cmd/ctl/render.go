@@ -0,0 +1,45 @@
+package ctl
+
+import (
+	"context"
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
+
+func RenderCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "render",
+		Usage:       "prints an email or push notification as it would be sent",
+		Description: "renders the communication send would send, without sending it: an email as its MIME message, a push notification as the json body of the domain's push provider. it takes the flags of send",
+		Flags:       append(sendFlags(), connectionFlags()...),
+		Action:      render,
+	}
+}
+
+func render(ctx context.Context, c *cli.Command) error {
+	req, err := sendRequest(c, time.Now())
+	if err != nil {
+		return err
+	}
+	conn, err := dial(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := conn.API().RenderCommunication(ctx, req)
+	if err != nil {
+		return err
+	}
+	p := newPrinter(c)
+	if p.json {
+		return p.print(resp, nil)
+	}
+	rendered := resp.GetEmailMime()
+	if len(rendered) == 0 {
+		rendered = resp.GetPushPayload()
+	}
+	_, err = p.w.Write(append(rendered, '\n'))
+	return err
+}
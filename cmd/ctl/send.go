@@ -0,0 +1,311 @@
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+)
+
+func SendCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "send",
+		Usage:       "sends an email or push notification",
+		Description: "sends the communication described by the flags, or by a json file which the flags override, and prints its id. it is sent asynchronously unless --sync is set",
+		Flags:       append(sendFlags(), connectionFlags()...),
+		Action:      send,
+	}
+}
+
+// sendFlags are the flags describing a communication, see sendRequest.
+func sendFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "file",
+			Aliases: []string{"f"},
+			Usage:   "json SendCommunicationRequest to send, - reads it from stdin. flags which are set override it",
+		},
+		&cli.StringFlag{
+			Name:  "domain",
+			Usage: "domain the communication is sent for",
+		},
+		&cli.StringFlag{
+			Name:  "customer",
+			Usage: "external customer id the recipients are looked up for when left out",
+		},
+		&cli.BoolFlag{
+			Name:  "sync",
+			Usage: "wait for the communication to be sent before returning",
+		},
+		&cli.StringFlag{
+			Name:  "send-at",
+			Usage: "when to send, an RFC 3339 time or a duration from now such as 30m",
+		},
+		&cli.StringFlag{
+			Name:  "priority",
+			Usage: "critical, transactional or bulk, the domain's priority when empty",
+		},
+		&cli.BoolFlag{
+			Name:  "urgent",
+			Usage: "send outside the delivery window and quiet hours",
+		},
+		&cli.BoolFlag{
+			Name:  "test",
+			Usage: "send to the domain's seed list instead of the recipients",
+		},
+		&cli.StringFlag{
+			Name:  "idempotency-key",
+			Usage: "key a retried send is deduplicated with, a random one when empty",
+		},
+		&cli.StringSliceFlag{
+			Name:  "webhook",
+			Usage: "url the outcome is posted to",
+		},
+		&cli.StringSliceFlag{
+			Name:  "sqs",
+			Usage: "sqs queue url the outcome is sent to",
+		},
+		&cli.StringFlag{
+			Name:  "email-from",
+			Usage: "address the email is sent from",
+		},
+		&cli.StringFlag{
+			Name:  "email-from-name",
+			Usage: "display name the email is sent from",
+		},
+		&cli.StringSliceFlag{
+			Name:  "email-to",
+			Usage: "address the email is sent to",
+		},
+		&cli.StringSliceFlag{
+			Name:  "email-cc",
+			Usage: "address the email is copied to",
+		},
+		&cli.StringSliceFlag{
+			Name:  "email-bcc",
+			Usage: "address the email is blind copied to",
+		},
+		&cli.StringFlag{
+			Name:  "email-subject",
+			Usage: "subject of the email",
+		},
+		&cli.StringFlag{
+			Name:  "email-html",
+			Usage: "html body of the email",
+		},
+		&cli.StringFlag{
+			Name:  "email-text",
+			Usage: "plain text body of the email",
+		},
+		&cli.StringFlag{
+			Name:  "push-customer",
+			Usage: "external customer id the push notification is sent to",
+		},
+		&cli.StringFlag{
+			Name:  "push-idempotency-key",
+			Usage: "key the push provider deduplicates the notification with",
+		},
+		&cli.StringFlag{
+			Name:  "push-heading",
+			Usage: "english heading of the push notification",
+		},
+		&cli.StringFlag{
+			Name:  "push-content",
+			Usage: "english content of the push notification",
+		},
+		&cli.StringFlag{
+			Name:  "push-heading-arabic",
+			Usage: "arabic heading of the push notification",
+		},
+		&cli.StringFlag{
+			Name:  "push-content-arabic",
+			Usage: "arabic content of the push notification",
+		},
+	}
+}
+
+func send(ctx context.Context, c *cli.Command) error {
+	req, err := sendRequest(c, time.Now())
+	if err != nil {
+		return err
+	}
+	conn, err := dial(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := conn.API().SendCommunication(ctx, req)
+	if err != nil {
+		return err
+	}
+	return newPrinter(c).print(resp, []string{"ID"}, []string{resp.GetId()})
+}
+
+// sendRequest builds the request of the send command: the file's, if any,
+// with the flags which are set applied over it.
+func sendRequest(c *cli.Command, now time.Time) (*pb.SendCommunicationRequest, error) {
+	req := &pb.SendCommunicationRequest{IsAsync: true}
+	if file := c.String("file"); file != "" {
+		data, err := readFile(c, file)
+		if err != nil {
+			return nil, err
+		}
+		req = &pb.SendCommunicationRequest{}
+		if err := protojson.Unmarshal(data, req); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", file, err)
+		}
+	}
+
+	if c.IsSet("domain") {
+		req.Domain = c.String("domain")
+	}
+	if c.IsSet("customer") {
+		req.ExternalCustomerId = c.String("customer")
+	}
+	if c.IsSet("sync") {
+		req.IsAsync = !c.Bool("sync")
+	}
+	if c.IsSet("send-at") {
+		sendAt, err := parseTime(c.String("send-at"), now, false)
+		if err != nil {
+			return nil, err
+		}
+		req.SendAt = sendAt
+	}
+	if c.IsSet("priority") {
+		priority, ok := pb.Priority_value["PRIORITY_"+strings.ToUpper(c.String("priority"))]
+		if !ok {
+			return nil, fmt.Errorf("unknown priority %q, expected critical, transactional or bulk", c.String("priority"))
+		}
+		req.Priority = pb.Priority(priority)
+	}
+	if c.IsSet("urgent") {
+		req.Urgent = c.Bool("urgent")
+	}
+	if c.IsSet("test") {
+		req.Test = c.Bool("test")
+	}
+	if c.IsSet("idempotency-key") {
+		req.IdempotencyKey = c.String("idempotency-key")
+	}
+	if req.GetIdempotencyKey() == "" {
+		req.IdempotencyKey = uuid.NewString()
+	}
+	for _, url := range c.StringSlice("webhook") {
+		req.ResponseChannels = append(req.ResponseChannels, &pb.ResponseChannel{Schema: pb.ResponseSchema_RESPONSE_SCHEMA_HTTP, Url: url})
+	}
+	for _, url := range c.StringSlice("sqs") {
+		req.ResponseChannels = append(req.ResponseChannels, &pb.ResponseChannel{Schema: pb.ResponseSchema_RESPONSE_SCHEMA_SQS, Url: url})
+	}
+
+	if setsAny(c, "email-") {
+		if req.Email == nil {
+			req.Email = &pb.EmailRequest{}
+		}
+		applyEmailFlags(c, req.Email)
+	}
+	if setsAny(c, "push-") {
+		if req.Push == nil {
+			req.Push = &pb.PushRequest{}
+		}
+		applyPushFlags(c, req.Push)
+	}
+	if req.GetEmail() == nil && req.GetPush() == nil {
+		return nil, errors.New("nothing to send, set the email-* or push-* flags or --file")
+	}
+	return req, nil
+}
+
+func applyEmailFlags(c *cli.Command, email *pb.EmailRequest) {
+	if c.IsSet("email-from") {
+		email.FromAddress = c.String("email-from")
+	}
+	if c.IsSet("email-from-name") {
+		email.FromName = c.String("email-from-name")
+	}
+	if c.IsSet("email-to") {
+		email.To = emailAddresses(c.StringSlice("email-to"))
+	}
+	if c.IsSet("email-cc") {
+		email.Cc = emailAddresses(c.StringSlice("email-cc"))
+	}
+	if c.IsSet("email-bcc") {
+		email.Bcc = emailAddresses(c.StringSlice("email-bcc"))
+	}
+	if c.IsSet("email-subject") {
+		email.Subject = c.String("email-subject")
+	}
+	if c.IsSet("email-html") {
+		email.Html = c.String("email-html")
+	}
+	if c.IsSet("email-text") {
+		email.Text = c.String("email-text")
+	}
+}
+
+func applyPushFlags(c *cli.Command, push *pb.PushRequest) {
+	if c.IsSet("push-customer") {
+		push.ExternalCustomerId = c.String("push-customer")
+	}
+	if c.IsSet("push-idempotency-key") {
+		push.IdempotencyKey = c.String("push-idempotency-key")
+	}
+	if c.IsSet("push-heading") || c.IsSet("push-heading-arabic") {
+		if push.Heading == nil {
+			push.Heading = &pb.LanguageContent{}
+		}
+		if c.IsSet("push-heading") {
+			push.Heading.English = c.String("push-heading")
+		}
+		if c.IsSet("push-heading-arabic") {
+			push.Heading.Arabic = c.String("push-heading-arabic")
+		}
+	}
+	if c.IsSet("push-content") || c.IsSet("push-content-arabic") {
+		if push.Content == nil {
+			push.Content = &pb.LanguageContent{}
+		}
+		if c.IsSet("push-content") {
+			push.Content.English = c.String("push-content")
+		}
+		if c.IsSet("push-content-arabic") {
+			push.Content.Arabic = c.String("push-content-arabic")
+		}
+	}
+}
+
+func emailAddresses(addresses []string) []*pb.EmailAddress {
+	resp := make([]*pb.EmailAddress, len(addresses))
+	for i, address := range addresses {
+		resp[i] = &pb.EmailAddress{Address: address}
+	}
+	return resp
+}
+
+// setsAny reports whether any of the command's flags starting with prefix is
+// set.
+func setsAny(c *cli.Command, prefix string) bool {
+	for _, flag := range c.Flags {
+		if name := flag.Names()[0]; strings.HasPrefix(name, prefix) && c.IsSet(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func readFile(c *cli.Command, name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(c.Root().Reader)
+	}
+	return os.ReadFile(name)
+}
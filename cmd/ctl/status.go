@@ -0,0 +1,109 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/pkg/client"
+)
+
+func StatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Usage:     "prints the status of communications",
+		ArgsUsage: "<id>...",
+		Flags:     connectionFlags(),
+		Action:    status,
+	}
+}
+
+func status(ctx context.Context, c *cli.Command) error {
+	if err := requireArgs(c, "status"); err != nil {
+		return err
+	}
+	conn, err := dial(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	p := newPrinter(c)
+	var rows [][]string
+	for _, id := range c.Args().Slice() {
+		resp, err := conn.API().GetStatus(ctx, &pb.GetStatusRequest{Id: id})
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		if p.json {
+			if err := p.line(resp); err != nil {
+				return err
+			}
+			continue
+		}
+		rows = append(rows, []string{resp.GetId(), resp.GetStatus()})
+	}
+	if p.json {
+		return nil
+	}
+	return p.print(nil, []string{"ID", "STATUS"}, rows...)
+}
+
+func WatchCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "watch",
+		Usage:       "follows the status of a communication until it finishes",
+		Description: "prints the status of a communication each time it changes, until it is COMPLETE, ERROR or CANCELLED. it fails unless the communication completed",
+		ArgsUsage:   "<id>",
+		Flags: append([]cli.Flag{
+			&cli.DurationFlag{
+				Name:  "interval",
+				Value: client.DefaultPollInterval,
+				Usage: "how often the status is polled",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "how long to watch for, unbounded when 0",
+			},
+		}, connectionFlags()...),
+		Action: watch,
+	}
+}
+
+func watch(ctx context.Context, c *cli.Command) error {
+	if err := requireArgs(c, "watch"); err != nil {
+		return err
+	}
+	id := c.Args().First()
+	if timeout := c.Duration("timeout"); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	conn, err := dial(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	p := newPrinter(c)
+	var printErr error
+	last, err := conn.Watch(ctx, id, c.Duration("interval"), func(status string) {
+		if printErr == nil {
+			printErr = p.line(&pb.GetStatusResponse{Id: id, Status: status}, time.Now().Format(time.DateTime), id, status)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if printErr != nil {
+		return printErr
+	}
+	if last != client.StatusComplete {
+		return fmt.Errorf("communication %s ended with status %s", id, last)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/urfave/cli/v3"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/testsuite"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	zapadapter "logur.dev/adapter/zap"
+	"logur.dev/logur"
+
+	"github.com/anicoll/unicom/cmd/server"
+	"github.com/anicoll/unicom/cmd/worker"
+	"github.com/anicoll/unicom/internal/lifecycle"
+	"github.com/anicoll/unicom/internal/outbox"
+)
+
+// defaults are the flags the dev command sets unless they're set otherwise,
+// so messages are captured in the outbox and the process stops at once.
+var defaults = map[string]string{
+	"email-provider": "outbox",
+	"push-provider":  "outbox",
+	"sqs-outbox":     "true",
+	"drain-delay":    "0s",
+}
+
+func DevCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "temporal-server",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("TEMPORAL_SERVER")),
+			Required: false,
+			Value:    "",
+			Usage:    "temporal frontend to use, a temporal dev server is started when empty",
+		},
+		&cli.StringFlag{
+			Name:     "temporal-cli",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("TEMPORAL_CLI")),
+			Required: false,
+			Value:    "",
+			Usage:    "path to the temporal cli the dev server is started with, downloaded and cached when empty",
+		},
+		&cli.StringFlag{
+			Name:     "temporal-db",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("TEMPORAL_DB")),
+			Required: false,
+			Value:    "",
+			Usage:    "sqlite file the temporal dev server keeps its state in, kept in memory when empty",
+		},
+		&cli.IntFlag{
+			Name:     "temporal-ui-port",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("TEMPORAL_UI_PORT")),
+			Required: false,
+			Value:    8233,
+			Usage:    "port of the temporal dev server's web ui",
+		},
+		&cli.IntFlag{
+			Name:     "outbox-port",
+			Sources:  cli.NewValueSourceChain(cli.EnvVar("OUTBOX_PORT")),
+			Required: false,
+			Value:    8026,
+			Usage:    "port the captured messages are listed on, and webhooks posted to /webhooks/<name> are captured on",
+		},
+	}
+	// The server and worker read their own flags, so the dev command has them
+	// all.
+	for _, cmd := range []*cli.Command{server.ServerCommand(), worker.CommunicationWorkerCommand()} {
+		for _, flag := range cmd.Flags {
+			if !slices.ContainsFunc(flags, func(f cli.Flag) bool { return f.Names()[0] == flag.Names()[0] }) {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	return &cli.Command{
+		Name:        "dev",
+		Description: "runs the server and worker in one process against a temporal dev server, capturing emails, pushes and response channel notifications in an outbox instead of sending them",
+		Flags:       flags,
+		Action:      run,
+	}
+}
+
+func run(ctx context.Context, c *cli.Command) error {
+	ctx, stop := lifecycle.SignalContext(ctx)
+	defer stop()
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logger.Sync() }()
+
+	for name, value := range defaults {
+		if c.IsSet(name) {
+			continue
+		}
+		if err := c.Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	if c.String("temporal-server") == "" {
+		devServer, err := testsuite.StartDevServer(ctx, testsuite.DevServerOptions{
+			ExistingPath: c.String("temporal-cli"),
+			ClientOptions: &client.Options{
+				Namespace: c.String("temporal-namespace"),
+				Logger:    logur.LoggerToKV(zapadapter.New(logger)),
+			},
+			DBFilename: c.String("temporal-db"),
+			EnableUI:   true,
+			UIPort:     strconv.Itoa(c.Int("temporal-ui-port")),
+		})
+		if err != nil {
+			return fmt.Errorf("starting temporal dev server: %w", err)
+		}
+		defer func() {
+			if err := devServer.Stop(); err != nil {
+				logger.Warn("unable to stop temporal dev server", zap.Error(err))
+			}
+		}()
+		if err := c.Set("temporal-server", devServer.FrontendHostPort()); err != nil {
+			return err
+		}
+		logger.Info("started temporal dev server", zap.String("ui", fmt.Sprintf("http://localhost:%d", c.Int("temporal-ui-port"))))
+	}
+
+	box, err := outbox.New(c.String("outbox-dir"))
+	if err != nil {
+		return err
+	}
+	logger.Info("unicom dev mode",
+		zap.String("grpc", fmt.Sprintf("localhost:%d", c.Int("grpc-port"))),
+		zap.String("http", fmt.Sprintf("http://localhost:%d", c.Int("http-port"))),
+		zap.String("outbox", fmt.Sprintf("http://localhost:%d", c.Int("outbox-port"))),
+		zap.String("outbox-dir", box.Dir()),
+		zap.String("temporal", c.String("temporal-server")),
+	)
+
+	// The worker serves its ops status next to the server's.
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return server.Run(egCtx, c)
+	})
+	eg.Go(func() error {
+		return worker.Run(egCtx, c, c.Int("ops-port")+1)
+	})
+	eg.Go(func() error {
+		return lifecycle.New(lifecycle.Config{Timeout: c.Duration("drain-timeout")}, logger).Run(egCtx,
+			lifecycle.HTTPServer(&http.Server{
+				Addr:    fmt.Sprintf(":%d", c.Int("outbox-port")),
+				Handler: box.Handler(),
+			}),
+		)
+	})
+	return eg.Wait()
+}
@@ -1,15 +1,24 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"log"
 	"os"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/anicoll/unicom/cmd/ctl"
 	"github.com/anicoll/unicom/cmd/dbinit"
+	"github.com/anicoll/unicom/cmd/dev"
 	"github.com/anicoll/unicom/cmd/server"
 	"github.com/anicoll/unicom/cmd/worker"
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/config"
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/lifecycle"
+	"github.com/anicoll/unicom/internal/payload"
+	"github.com/anicoll/unicom/internal/tracing"
 )
 
 var (
@@ -18,17 +27,36 @@ var (
 )
 
 func main() {
+	var configPath string
+	configFile := config.New(&configPath)
 	app := &cli.Command{
 		Name:    "unicom-public-api-service",
 		Usage:   "exposes a 'public' api to other domains",
 		Version: version,
 		Authors: []any{author},
-		Commands: []*cli.Command{
+		Commands: append([]*cli.Command{
 			server.ServerCommand(),
 			worker.CommunicationWorkerCommand(),
 			dbinit.DatabaseCreationCommand(),
+			dev.DevCommand(),
+		}, ctl.Commands()...),
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if err := configFile.Validate(c); err != nil {
+				return ctx, err
+			}
+			// Checked up front so a bad file fails before anything is connected to.
+			_, err := domain.Load(cmp.Or(c.String("domain-config"), configPath))
+			return ctx, err
 		},
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config",
+				Sources:     cli.NewValueSourceChain(cli.EnvVar("CONFIG")),
+				Required:    false,
+				Value:       "",
+				Destination: &configPath,
+				Usage:       "path to a yaml file setting any flag by name, and the per domain settings unless domain-config is set",
+			},
 			&cli.StringFlag{
 				Name:     "log-level",
 				Sources:  cli.NewValueSourceChain(cli.EnvVar("LOG_LEVEL")),
@@ -54,8 +82,138 @@ func main() {
 				Value:    "up",
 				Usage:    "to indicate either up/down for migrations",
 			},
+			&cli.StringFlag{
+				Name:     "domain-config",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("DOMAIN_CONFIG")),
+				Required: false,
+				Value:    "",
+				Usage:    "path to the yaml file of per domain delivery policies, senders and retention windows",
+			},
+			&cli.StringSliceFlag{
+				Name:     "attachment-schemes",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ATTACHMENT_SCHEMES")),
+				Required: false,
+				Value:    attachment.DefaultConfig.Schemes,
+				Usage:    "url schemes attachments may be fetched with, out of http, https and s3",
+			},
+			&cli.StringSliceFlag{
+				Name:     "attachment-hosts",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ATTACHMENT_HOSTS")),
+				Required: false,
+				Usage:    "hosts or s3 buckets attachments may be fetched from, a leading dot allows subdomains, empty allows any public host",
+			},
+			&cli.IntFlag{
+				Name:     "attachment-max-size",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ATTACHMENT_MAX_SIZE")),
+				Required: false,
+				Value:    int(attachment.DefaultConfig.MaxSize),
+				Usage:    "largest attachment in bytes",
+			},
+			&cli.StringSliceFlag{
+				Name:     "attachment-content-types",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ATTACHMENT_CONTENT_TYPES")),
+				Required: false,
+				Value:    attachment.DefaultConfig.ContentTypes,
+				Usage:    "media types attachments may have, e.g. image/* or application/pdf",
+			},
+			&cli.StringFlag{
+				Name:     "payload-store",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PAYLOAD_STORE")),
+				Required: false,
+				Value:    payload.DefaultConfig.Store,
+				Usage:    "where large email content is kept out of workflow history, one of none, postgres, file or s3",
+			},
+			&cli.IntFlag{
+				Name:     "payload-offload-threshold",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PAYLOAD_OFFLOAD_THRESHOLD")),
+				Required: false,
+				Value:    payload.DefaultConfig.Threshold,
+				Usage:    "size in bytes above which an email body or attachment is offloaded",
+			},
+			&cli.StringFlag{
+				Name:     "payload-dir",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PAYLOAD_DIR")),
+				Required: false,
+				Value:    "",
+				Usage:    "directory of the file payload store",
+			},
+			&cli.StringFlag{
+				Name:     "payload-s3-bucket",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PAYLOAD_S3_BUCKET")),
+				Required: false,
+				Value:    "",
+			},
+			&cli.StringFlag{
+				Name:     "payload-s3-prefix",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PAYLOAD_S3_PREFIX")),
+				Required: false,
+				Value:    payload.DefaultConfig.Prefix,
+			},
+			&cli.StringFlag{
+				Name:     "payload-s3-endpoint",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PAYLOAD_S3_ENDPOINT")),
+				Required: false,
+				Value:    "",
+				Usage:    "overrides the s3 endpoint of the payload store, e.g. for localstack",
+			},
+			&cli.StringFlag{
+				Name:     "encryption-key-file",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ENCRYPTION_KEY_FILE")),
+				Required: false,
+				Value:    "",
+				Usage:    "path to the yaml file of master keys personal data is encrypted with, empty leaves it in plaintext",
+			},
+			&cli.StringFlag{
+				Name:     "blind-index-key",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("BLIND_INDEX_KEY")),
+				Required: false,
+				Value:    "",
+				Usage:    "base64 encoded 32 byte key personal data is looked up by, required and must never change",
+			},
+			&cli.StringFlag{
+				Name:     "trace-exporter",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("TRACE_EXPORTER")),
+				Required: false,
+				Value:    tracing.DefaultConfig.Exporter,
+				Usage:    "where spans are exported, one of none, otlp or stdout",
+			},
+			&cli.StringFlag{
+				Name:     "otlp-endpoint",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("OTLP_ENDPOINT")),
+				Required: false,
+				Value:    tracing.DefaultConfig.Endpoint,
+				Usage:    "host:port of the otlp grpc collector",
+			},
+			&cli.BoolFlag{
+				Name:     "otlp-insecure",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("OTLP_INSECURE")),
+				Required: false,
+				Usage:    "connect to the otlp collector without tls",
+			},
+			&cli.FloatFlag{
+				Name:     "trace-sample-ratio",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("TRACE_SAMPLE_RATIO")),
+				Required: false,
+				Value:    tracing.DefaultConfig.SampleRatio,
+				Usage:    "share of new traces which are sampled, traces started by callers follow their decision",
+			},
+			&cli.DurationFlag{
+				Name:     "drain-delay",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("DRAIN_DELAY")),
+				Required: false,
+				Value:    lifecycle.DefaultConfig.Delay,
+				Usage:    "how long to report unready after SIGTERM before draining, so load balancers stop routing new requests",
+			},
+			&cli.DurationFlag{
+				Name:     "drain-timeout",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("DRAIN_TIMEOUT")),
+				Required: false,
+				Value:    lifecycle.DefaultConfig.Timeout,
+				Usage:    "how long requests and activities in flight have to finish while draining",
+			},
 		},
 	}
+	configFile.Attach(app)
 	ctx := context.Background()
 	err := app.Run(ctx, os.Args)
 	if err != nil {
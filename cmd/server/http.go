@@ -2,24 +2,104 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/tracing"
 )
 
-func runHTTPGateway(ctx context.Context, httpPort, grpcPort int) error {
-	mux := runtime.NewServeMux()
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	err := pb.RegisterUnicomServiceHandlerFromEndpoint(ctx, mux, fmt.Sprintf("localhost:%d", grpcPort), opts)
+// newHTTPGateway returns the server proxying HTTP calls to the gRPC server.
+// Its connections to the gRPC server are closed once ctx is done, which
+// should be after the gateway has drained.
+func newHTTPGateway(ctx context.Context, httpPort, grpcPort int, principalHeader string) (*http.Server, error) {
+	mux := runtime.NewServeMux(
+		// Forward the caller's identity for the audit log.
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			if principalHeader != "" && strings.EqualFold(key, principalHeader) {
+				return strings.ToLower(key), true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+	)
+	endpoint := fmt.Sprintf("localhost:%d", grpcPort)
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor()),
+	}
+	err := pb.RegisterUnicomServiceHandlerFromEndpoint(ctx, mux, endpoint, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(endpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+	err = mux.HandlePath(http.MethodGet, "/unicom/v1/audit-events:export", exportAuditEventsHandler(mux, pb.NewUnicomServiceClient(conn)))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Start HTTP server (and proxy calls to gRPC server endpoint)
-	return http.ListenAndServe(fmt.Sprintf(":%d", httpPort), mux)
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%d", httpPort),
+		Handler: otelhttp.NewHandler(mux, "grpc-gateway"),
+	}, nil
+}
+
+// exportAuditEventsHandler serves ExportAuditEvents as NDJSON, one event per
+// line, taking the same query parameters as ListAuditEvents.
+func exportAuditEventsHandler(mux *runtime.ServeMux, client pb.UnicomServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		req := &pb.ListAuditEventsRequest{}
+		if err := runtime.PopulateQueryParameters(req, r.URL.Query(), &utilities.DoubleArray{}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, pb.UnicomService_ExportAuditEvents_FullMethodName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		stream, err := client.ExportAuditEvents(ctx, req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, &runtime.JSONPb{}, w, r, err)
+			return
+		}
+
+		event, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			// Nothing has been written yet, so the error can still be reported.
+			runtime.HTTPError(ctx, mux, &runtime.JSONPb{}, w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for ; err == nil; event, err = stream.Recv() {
+			line, marshalErr := protojson.Marshal(event)
+			if marshalErr != nil {
+				return
+			}
+			if _, writeErr := w.Write(append(line, '\n')); writeErr != nil {
+				return
+			}
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
 }
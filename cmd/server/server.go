@@ -1,28 +1,44 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 
+	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	"github.com/jackc/pgx/v5/pgxpool"
+	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/urfave/cli/v3"
 	"github.com/utilitywarehouse/go-operational/op"
 	"go.temporal.io/sdk/client"
+	temporalotel "go.temporal.io/sdk/contrib/opentelemetry"
+	sdktally "go.temporal.io/sdk/contrib/tally"
+	"go.temporal.io/sdk/interceptor"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	zapadapter "logur.dev/adapter/zap"
 	"logur.dev/logur"
 
+	"github.com/anicoll/unicom/cmd/worker"
 	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/audit"
+	"github.com/anicoll/unicom/internal/contact"
 	"github.com/anicoll/unicom/internal/database"
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/encryption"
+	"github.com/anicoll/unicom/internal/erasure"
+	"github.com/anicoll/unicom/internal/lifecycle"
+	"github.com/anicoll/unicom/internal/metrics"
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/payload"
 	"github.com/anicoll/unicom/internal/server"
 	"github.com/anicoll/unicom/internal/temporalclient"
+	"github.com/anicoll/unicom/internal/tracing"
 )
 
 func ServerCommand() *cli.Command {
@@ -60,49 +76,150 @@ func ServerCommand() *cli.Command {
 				Required: false,
 				Value:    "default",
 			},
+			&cli.StringFlag{
+				Name:     "render-priority",
+				Usage:    "priority whose lane communications are rendered on, which a worker must poll",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("RENDER_PRIORITY")),
+				Required: false,
+				Value:    string(model.PriorityBulk),
+			},
+			&cli.StringFlag{
+				Name:     "audit-principal-header",
+				Usage:    "request header identifying the caller in the audit log, when they have no client certificate",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("AUDIT_PRINCIPAL_HEADER")),
+				Required: false,
+				Value:    "x-unicom-principal",
+			},
+			&cli.BoolFlag{
+				Name:     "audit-trust-principal-header",
+				Usage:    "take the principal and x-forwarded-for headers as the caller's identity and address, only for servers reachable solely through a gateway which authenticates callers and sets them",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("AUDIT_TRUST_PRINCIPAL_HEADER")),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "contact-provider",
+				Usage:    "where contacts are looked up, postgres or http",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("CONTACT_PROVIDER")),
+				Required: false,
+				Value:    contact.DefaultConfig.Provider,
+			},
+			&cli.StringFlag{
+				Name:     "contact-url",
+				Usage:    "url of the http contact provider, called with the external customer id appended",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("CONTACT_URL")),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "contact-token",
+				Usage:    "bearer token sent to the http contact provider",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("CONTACT_TOKEN")),
+				Required: false,
+			},
+			&cli.DurationFlag{
+				Name:     "contact-timeout",
+				Usage:    "timeout of calls to the http contact provider",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("CONTACT_TIMEOUT")),
+				Required: false,
+				Value:    contact.DefaultConfig.Timeout,
+			},
 		},
-		Action: func(ctx context.Context, c *cli.Command) error {
-			args := serverArgs{
-				grpcPort:          c.Int("grpc-port"),
-				httpPort:          c.Int("http-port"),
-				opsPort:           c.Int("ops-port"),
-				dbDsn:             c.String("db-dsn"),
-				migrationAction:   c.String("migrate-action"),
-				temporalNamespace: c.String("temporal-namespace"),
-				temporalAddress:   c.String("temporal-server"),
-				name:              c.Name,
-				description:       c.Description,
-				version:           c.Version,
-				owner:             c.Authors[0].(string),
-			}
-			return run(args)
+		Action: Run,
+	}
+}
+
+// Run runs the server with the flags of c until the process is signalled to
+// stop or ctx is done.
+func Run(ctx context.Context, c *cli.Command) error {
+	renderPriority, err := model.ParsePriority(c.String("render-priority"))
+	if err != nil {
+		return fmt.Errorf("render priority: %w", err)
+	}
+	args := serverArgs{
+		grpcPort:          c.Int("grpc-port"),
+		httpPort:          c.Int("http-port"),
+		opsPort:           c.Int("ops-port"),
+		dbDsn:             c.String("db-dsn"),
+		migrationAction:   c.String("migrate-action"),
+		temporalNamespace: c.String("temporal-namespace"),
+		temporalAddress:   c.String("temporal-server"),
+		domainConfig:      cmp.Or(c.String("domain-config"), c.String("config")),
+		attachments: attachment.Config{
+			Schemes:      c.StringSlice("attachment-schemes"),
+			Hosts:        c.StringSlice("attachment-hosts"),
+			MaxSize:      int64(c.Int("attachment-max-size")),
+			ContentTypes: c.StringSlice("attachment-content-types"),
 		},
+		payloads: payload.Config{
+			Store:      c.String("payload-store"),
+			Threshold:  c.Int("payload-offload-threshold"),
+			Dir:        c.String("payload-dir"),
+			Bucket:     c.String("payload-s3-bucket"),
+			Prefix:     c.String("payload-s3-prefix"),
+			S3Endpoint: c.String("payload-s3-endpoint"),
+		},
+		contacts: contact.Config{
+			Provider: c.String("contact-provider"),
+			URL:      c.String("contact-url"),
+			Token:    c.String("contact-token"),
+			Timeout:  c.Duration("contact-timeout"),
+		},
+		tracing: tracing.Config{
+			Exporter:    c.String("trace-exporter"),
+			Endpoint:    c.String("otlp-endpoint"),
+			Insecure:    c.Bool("otlp-insecure"),
+			SampleRatio: c.Float("trace-sample-ratio"),
+		},
+		lifecycle: lifecycle.Config{
+			Delay:   c.Duration("drain-delay"),
+			Timeout: c.Duration("drain-timeout"),
+		},
+		encryptionKeyFile:    c.String("encryption-key-file"),
+		blindIndexKey:        c.String("blind-index-key"),
+		auditPrincipalHeader: c.String("audit-principal-header"),
+		auditTrustPrincipal:  c.Bool("audit-trust-principal-header"),
+		renderTaskQueue:      worker.TaskQueue(renderPriority),
+		region:               c.String("aws-region"),
+		name:                 c.Name,
+		description:          c.Description,
+		version:              c.Root().Version,
+		owner:                c.Root().Authors[0].(string),
 	}
+	return run(ctx, args)
 }
 
 type serverArgs struct {
-	grpcPort          int
-	httpPort          int
-	opsPort           int
-	owner             string
-	temporalAddress   string
-	temporalNamespace string
-	name              string
-	dbDsn             string
-	migrationAction   string
-	description       string
-	version           string
+	grpcPort             int
+	httpPort             int
+	opsPort              int
+	owner                string
+	temporalAddress      string
+	temporalNamespace    string
+	domainConfig         string
+	attachments          attachment.Config
+	payloads             payload.Config
+	contacts             contact.Config
+	tracing              tracing.Config
+	lifecycle            lifecycle.Config
+	encryptionKeyFile    string
+	blindIndexKey        string
+	auditPrincipalHeader string
+	auditTrustPrincipal  bool
+	renderTaskQueue      string
+	region               string
+	name                 string
+	dbDsn                string
+	migrationAction      string
+	description          string
+	version              string
 }
 
-func run(args serverArgs) error {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func run(ctx context.Context, args serverArgs) error {
+	ctx, stop := lifecycle.SignalContext(ctx)
+	defer stop()
 
 	status := op.NewStatus(args.name, args.description).
 		SetRevision(args.version)
 
-	eg, ctx := errgroup.WithContext(ctx)
-
 	zapConfig := zap.NewProductionConfig()
 	zapConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
 	logger, err := zapConfig.Build()
@@ -113,6 +230,20 @@ func run(args serverArgs) error {
 	// nolint: errcheck
 	defer logger.Sync()
 
+	shutdownTracing, err := tracing.Setup(ctx, args.tracing, args.name, args.version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("unable to flush spans", zap.Error(err))
+		}
+	}()
+	tracingInterceptor, err := temporalotel.NewTracingInterceptor(temporalotel.TracerOptions{})
+	if err != nil {
+		return err
+	}
+
 	parsedCfg, err := pgxpool.ParseConfig(args.dbDsn)
 	if err != nil {
 		return err
@@ -121,6 +252,7 @@ func run(args serverArgs) error {
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
 
 	migrationAction := database.MigrateUp
 	if args.migrationAction == "down" {
@@ -132,73 +264,137 @@ func run(args serverArgs) error {
 		return err
 	}
 
-	db := database.New(conn, logger)
+	encryptor, err := encryption.Load(args.encryptionKeyFile, args.blindIndexKey)
+	if err != nil {
+		return err
+	}
+	if !encryptor.Enabled() {
+		logger.Warn("no encryption key file configured, personal data is stored in plaintext")
+	}
+
+	db := database.New(conn, logger, encryptor)
+	if err := db.CheckBlindIndex(ctx); err != nil {
+		return fmt.Errorf("checking blind index key: %w", err)
+	}
 
 	status.AddChecker("database", func(cr *op.CheckResponse) {
-		if err := db.Ping(ctx); err != nil {
+		if err := db.Ping(context.Background()); err != nil {
 			cr.Unhealthy("database unavailable", "check database connection/network", "service wont be able to record expressions-of-interest")
 		} else {
 			cr.Healthy("healthy")
 		}
 	})
 
+	registry := prom.NewRegistry()
+	grpcMetrics := prometheus.NewServerMetrics()
+	registry.MustRegister(grpcMetrics)
+	metricsScope, metricsHTTPHandler, err := metrics.NewPrometheusScope(registry, args.owner, func(err error) {
+		logger.Error("error in prometheus reporter", zap.Error(err))
+	})
+	if err != nil {
+		return err
+	}
+	metricsHandler := sdktally.NewMetricsHandler(metricsScope)
+
 	tClient, err := client.Dial(client.Options{
-		HostPort:  args.temporalAddress,
-		Namespace: args.temporalNamespace,
-		Logger:    logur.LoggerToKV(zapadapter.New(logger)),
-		// MetricsHandler: sdktally.NewMetricsHandler(newPrometheusScope(prometheus.Configuration{
-		// 	ListenAddress: fmt.Sprintf("0.0.0.0:%d", args.opsPort),
-		// 	TimerType:     "histogram",
-		// }, args.owner)),
+		HostPort:       args.temporalAddress,
+		Namespace:      args.temporalNamespace,
+		Logger:         logur.LoggerToKV(zapadapter.New(logger)),
+		DataConverter:  encryption.DataConverter(encryption.NewCodec(encryptor)),
+		Interceptors:   []interceptor.ClientInterceptor{tracingInterceptor},
+		MetricsHandler: metricsHandler,
 	})
 	if err != nil {
-		log.Fatalln("Unable to create client", err)
+		return fmt.Errorf("unable to create temporal client: %w", err)
 	}
 	defer tClient.Close()
 
 	status.AddChecker("temporal-client", func(cr *op.CheckResponse) {
-		if _, err := tClient.CheckHealth(ctx, &client.CheckHealthRequest{}); err != nil {
+		if _, err := tClient.CheckHealth(context.Background(), &client.CheckHealthRequest{}); err != nil {
 			cr.Unhealthy("temporal-client unresponsive", "check server connection/network", "service wont be able to initiate any new workflows")
 		} else {
 			cr.Healthy("healthy")
 		}
 	})
 
-	tc := temporalclient.New(tClient)
+	domains, err := domain.Load(args.domainConfig)
+	if err != nil {
+		return err
+	}
+	if args.domainConfig != "" {
+		go domain.Watch(ctx, args.domainConfig, domains, domain.WatchInterval, logger)
+	}
 
-	server := server.New(logger, tc, db)
+	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(args.region))
+	if err != nil {
+		return err
+	}
+	payloadStore, err := payload.NewStore(args.payloads, db, awsConfig, encryptor)
+	if err != nil {
+		return err
+	}
 
-	eg.Go(func() error {
-		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", args.grpcPort))
-		if err != nil {
-			log.Fatalf("failed to listen: %v", err)
-		}
+	tc := temporalclient.New(tClient, args.renderTaskQueue)
 
-		opts := []logging.Option{
-			logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
-			// Add any other option (check functions starting with logging.With).
-		}
+	offloader := payload.NewOffloader(payloadStore, args.payloads.Threshold)
+	contacts, err := contact.New(args.contacts, db)
+	if err != nil {
+		return err
+	}
+	server := server.New(logger, tc, db, domains, args.attachments, offloader, erasure.NewEraser(db, offloader), contacts, metricsHandler)
 
-		s := grpc.NewServer(
-			grpc.ChainUnaryInterceptor(
-				logging.UnaryServerInterceptor(InterceptorLogger(logger), opts...),
-				prometheus.NewServerMetrics().UnaryServerInterceptor(),
-			))
-		pb.RegisterUnicomServiceServer(s, server)
-		logger.Info("serving GRPC", zap.Int("port", args.grpcPort))
-		return s.Serve(lis)
-	})
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", args.grpcPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
 
-	eg.Go(func() error {
-		logger.Info("serving HTTP", zap.Int("port", args.httpPort))
-		return runHTTPGateway(context.Background(), args.httpPort, args.grpcPort)
-	})
+	opts := []logging.Option{
+		logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
+		// Add any other option (check functions starting with logging.With).
+	}
 
-	eg.Go(func() error {
-		logger.Info("serving ops status", zap.Int("port", args.opsPort))
-		http.Handle("/__/", op.NewHandler(status.ReadyUseHealthCheck()))
-		return http.ListenAndServe(fmt.Sprintf(":%d", args.opsPort), nil)
-	})
+	auditor := audit.NewInterceptor(db, logger, args.auditPrincipalHeader, args.auditTrustPrincipal)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			tracing.UnaryServerInterceptor(),
+			logging.UnaryServerInterceptor(InterceptorLogger(logger), opts...),
+			grpcMetrics.UnaryServerInterceptor(),
+			auditor.Unary(),
+		),
+		grpc.ChainStreamInterceptor(
+			tracing.StreamServerInterceptor(),
+			grpcMetrics.StreamServerInterceptor(),
+			auditor.Stream(),
+		))
+	pb.RegisterUnicomServiceServer(s, server)
+	grpcMetrics.InitializeMetrics(s)
+
+	// The gateway keeps its connections to the gRPC server until it has
+	// drained.
+	gatewayCtx, closeGateway := context.WithCancel(context.Background())
+	defer closeGateway()
+	gateway, err := newHTTPGateway(gatewayCtx, args.httpPort, args.grpcPort, args.auditPrincipalHeader)
+	if err != nil {
+		return err
+	}
+
+	lc := lifecycle.New(args.lifecycle, logger)
+	opsMux := http.NewServeMux()
+	opsMux.Handle("/__/", op.NewHandler(lc.ReadyUseHealthCheck(status)))
+	opsMux.Handle("/metrics", metricsHTTPHandler)
 
-	return eg.Wait()
+	logger.Info("serving GRPC", zap.Int("port", args.grpcPort))
+	logger.Info("serving HTTP", zap.Int("port", args.httpPort))
+	logger.Info("serving ops status", zap.Int("port", args.opsPort))
+	// The gateway drains before the gRPC server it calls, and the ops status
+	// is served until the end so the server is seen to be unready.
+	return lc.Run(ctx,
+		lifecycle.HTTPServer(gateway),
+		lifecycle.GRPCServer(s, lis),
+		lifecycle.HTTPServer(&http.Server{
+			Addr:    fmt.Sprintf(":%d", args.opsPort),
+			Handler: opsMux,
+		}),
+	)
 }
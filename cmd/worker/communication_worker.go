@@ -3,55 +3,103 @@ package worker
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
-	ses "github.com/aws/aws-sdk-go-v2/service/sesv2"
 	aws_sqs "github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/uber-go/tally/v4/prometheus"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/utilitywarehouse/go-operational/op"
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
+	temporalotel "go.temporal.io/sdk/contrib/opentelemetry"
 	sdktally "go.temporal.io/sdk/contrib/tally"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
 	"go.uber.org/zap"
 	zapadapter "logur.dev/adapter/zap"
 	"logur.dev/logur"
 
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/breaker"
 	"github.com/anicoll/unicom/internal/database"
-	"github.com/anicoll/unicom/internal/email"
-	"github.com/anicoll/unicom/internal/push"
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/encryption"
+	"github.com/anicoll/unicom/internal/erasure"
+	"github.com/anicoll/unicom/internal/lifecycle"
+	"github.com/anicoll/unicom/internal/metrics"
+	"github.com/anicoll/unicom/internal/outbox"
+	"github.com/anicoll/unicom/internal/payload"
 	"github.com/anicoll/unicom/internal/responsechannel"
+	"github.com/anicoll/unicom/internal/sandbox"
+	"github.com/anicoll/unicom/internal/tracing"
 	"github.com/anicoll/unicom/internal/workflows"
 )
 
-const CommunicationTaskQueue string = "unicom_task_queue"
+const (
+	// CommunicationTaskQueue is the task queue of transactional communications.
+	CommunicationTaskQueue string = "unicom_task_queue"
+	CriticalTaskQueue      string = "unicom_critical_task_queue"
+	BulkTaskQueue          string = "unicom_bulk_task_queue"
+)
+
+// CommunicationWorker runs a worker for each lane until stop is closed, then
+// stops them all, waiting for the activities they're running.
+func CommunicationWorker(temporalClient client.Client, lanes []lane, stop <-chan struct{}, emailClient *sandbox.EmailService, pushService *sandbox.PushService, sqsClient *responsechannel.SQSService, webhookClient *responsechannel.WebhookService, db *database.Postgres, attachments *attachment.Fetcher, payloads *payload.Offloader, retention *workflows.RetentionActivities) error {
+	activities := workflows.NewActivities(emailClient, pushService, sqsClient, webhookClient, db, attachments, payloads)
 
-func CommunicationWorker(temporalClient client.Client, emailClient *email.Service, pushService *push.Service, sqsClient *responsechannel.SQSService, webhookClient *responsechannel.WebhookService, db *database.Postgres) error {
-	w := worker.New(temporalClient, CommunicationTaskQueue, worker.Options{})
+	workers := make([]worker.Worker, 0, len(lanes))
+	defer func() {
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.Stop()
+			}()
+		}
+		wg.Wait()
+	}()
+	for _, l := range lanes {
+		w := worker.New(temporalClient, l.taskQueue, l.options)
 
-	registerOptions := workflow.RegisterOptions{}
+		registerOptions := workflow.RegisterOptions{}
 
-	activities := workflows.NewActivities(emailClient, pushService, sqsClient, webhookClient, db)
+		w.RegisterWorkflowWithOptions(workflows.CommunicationWorkflow, registerOptions)
+		w.RegisterWorkflowWithOptions(workflows.RetentionWorkflow, registerOptions)
+		w.RegisterWorkflowWithOptions(workflows.RenderWorkflow, registerOptions)
 
-	w.RegisterWorkflowWithOptions(workflows.CommunicationWorkflow, registerOptions)
+		w.RegisterActivityWithOptions(activities.SendEmail, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.DeleteEmailPayloads, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.SendPush, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.RenderEmail, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.RenderPush, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.NotifySqs, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.NotifyWebhook, activity.RegisterOptions{})
 
-	w.RegisterActivityWithOptions(activities.SendEmail, activity.RegisterOptions{})
-	w.RegisterActivityWithOptions(activities.SendPush, activity.RegisterOptions{})
-	w.RegisterActivityWithOptions(activities.NotifySqs, activity.RegisterOptions{})
-	w.RegisterActivityWithOptions(activities.NotifyWebhook, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.UpdateCommunicationStatus, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.RecordCommunicationError, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(activities.SaveResponseChannelOutcome, activity.RegisterOptions{})
 
-	w.RegisterActivityWithOptions(activities.UpdateCommunicationStatus, activity.RegisterOptions{})
-	w.RegisterActivityWithOptions(activities.SaveResponseChannelOutcome, activity.RegisterOptions{})
+		w.RegisterActivityWithOptions(retention.ApplyRetention, activity.RegisterOptions{})
+
+		if err := w.Start(); err != nil {
+			return fmt.Errorf("starting %s worker: %w", l.priority, err)
+		}
+		workers = append(workers, w)
+	}
 
-	return w.Run(worker.InterruptCh())
+	<-stop
+	return nil
 }
 
-func communicationWorkerAction(args workerArgs) error {
-	ctx := context.Background()
+func communicationWorkerAction(ctx context.Context, args workerArgs) error {
+	ctx, stop := lifecycle.SignalContext(ctx)
+	defer stop()
 
 	zapLogger, err := zap.NewProduction()
 	if err != nil {
@@ -59,6 +107,16 @@ func communicationWorkerAction(args workerArgs) error {
 	}
 	defer func() { _ = zapLogger.Sync() }()
 
+	shutdownTracing, err := tracing.Setup(ctx, args.tracing, args.name, args.version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			zapLogger.Error("unable to flush spans", zap.Error(err))
+		}
+	}()
+
 	parsedCfg, err := pgxpool.ParseConfig(args.dbDsn)
 	if err != nil {
 		return err
@@ -67,6 +125,7 @@ func communicationWorkerAction(args workerArgs) error {
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
 
 	migrationAction := database.MigrateUp
 	if args.migrationAction == "down" {
@@ -77,41 +136,152 @@ func communicationWorkerAction(args workerArgs) error {
 	if err != nil {
 		return err
 	}
-	db := database.New(conn, zapLogger)
+
+	encryptor, err := encryption.Load(args.encryptionKeyFile, args.blindIndexKey)
+	if err != nil {
+		return err
+	}
+	if !encryptor.Enabled() {
+		zapLogger.Warn("no encryption key file configured, personal data is stored in plaintext")
+	}
+	codec := encryption.NewCodec(encryptor)
+
+	db := database.New(conn, zapLogger, encryptor)
+	if err := db.CheckBlindIndex(ctx); err != nil {
+		return fmt.Errorf("checking blind index key: %w", err)
+	}
 
 	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(args.region))
 	if err != nil {
-		log.Fatalf("unable to load SDK config, %v", err)
+		return fmt.Errorf("unable to load SDK config: %w", err)
 	}
 
-	pushService := push.New(zapLogger, args.onesignalAppId, args.onesignalAuthKey)
+	status := op.NewStatus(args.name, args.description).
+		SetRevision(args.version)
+
+	status.AddChecker("database", func(cr *op.CheckResponse) {
+		if err := db.Ping(context.Background()); err != nil {
+			cr.Unhealthy("database unavailable", "check database connection/network", "communication statuses wont be recorded")
+		} else {
+			cr.Healthy("healthy")
+		}
+	})
 
-	// TODO: add status Checkers
-	sqsClient := aws_sqs.NewFromConfig(awsConfig)
-	sqsService := responsechannel.NewSQSService(sqsClient)
+	breakers := breaker.NewRegistry(args.breaker)
 
-	// TODO: add status Checkers
+	pushService, err := newPushService(ctx, zapLogger, args.push, db, breakers, status)
+	if err != nil {
+		return err
+	}
+
+	sqsService := responsechannel.NewSQSService(aws_sqs.NewFromConfig(awsConfig))
+	if args.sqsOutbox {
+		box, err := outbox.New(args.outboxDir)
+		if err != nil {
+			return err
+		}
+		sqsService = responsechannel.NewOutboxSQSService(box)
+	}
+	status.AddChecker("sqs", sqsChecker(sqsService))
+
+	// Webhooks are given by each communication, so there's nothing to check.
 	webhookClient := responsechannel.NewWebhookService(&http.Client{
 		Timeout: time.Second * 30,
+	}, args.webhookSigningSecret)
+
+	emailService, err := newEmailService(args.email, awsConfig, breakers, status)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range breakers.All() {
+		status.AddChecker("provider "+b.Name(), breakerChecker(b))
+	}
+
+	metricsScope, metricsHandler, err := metrics.NewPrometheusScope(prom.NewRegistry(), args.owner, func(err error) {
+		zapLogger.Error("error in prometheus reporter", zap.Error(err))
 	})
+	if err != nil {
+		return err
+	}
 
-	// TODO: add status Checkers
-	sesClient := ses.NewFromConfig(awsConfig)
-	emailService := email.NewService(sesClient)
+	lc := lifecycle.New(args.lifecycle, zapLogger)
+	gate := newReadinessGate(status, zapLogger)
+	mux := http.NewServeMux()
+	mux.Handle("/__/", op.NewHandler(lc.ReadyUseHealthCheck(status, gate.Opened)))
+	mux.Handle("/metrics", metricsHandler)
+	if len(args.codecTokens) > 0 {
+		if slices.Contains(args.codecOrigins, "*") {
+			return fmt.Errorf("codec-cors-origins can't be *, as browsers call the codec server with credentials: list the origins of the temporal uis")
+		}
+		mux.Handle("/codec/", encryption.NewCodecHandler(codec, args.codecTokens, args.codecOrigins))
+	}
 
+	// Continues the traces of the communications the server started.
+	tracingInterceptor, err := temporalotel.NewTracingInterceptor(temporalotel.TracerOptions{})
+	if err != nil {
+		return err
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort:  args.temporalAddress,
-		Namespace: args.temporalNamespace,
-		Logger:    logur.LoggerToKV(zapadapter.New(zapLogger)),
-		MetricsHandler: sdktally.NewMetricsHandler(newPrometheusScope(prometheus.Configuration{
-			ListenAddress: fmt.Sprintf("0.0.0.0:%d", args.opsPort),
-			TimerType:     "histogram",
-		}, args.owner)),
+		HostPort:       args.temporalAddress,
+		Namespace:      args.temporalNamespace,
+		Logger:         logur.LoggerToKV(zapadapter.New(zapLogger)),
+		MetricsHandler: sdktally.NewMetricsHandler(metricsScope),
+		DataConverter:  encryption.DataConverter(codec),
+		Interceptors:   []interceptor.ClientInterceptor{tracingInterceptor},
 	})
 	if err != nil {
-		log.Fatalln("Unable to create client", err)
+		return fmt.Errorf("unable to create temporal client: %w", err)
 	}
 	defer temporalClient.Close()
+	status.AddChecker("temporal-client", temporalChecker(temporalClient))
+
+	attachmentFetcher := attachment.NewFetcher(args.attachments, awsConfig)
 
-	return CommunicationWorker(temporalClient, emailService, pushService, sqsService, webhookClient, db)
+	payloadStore, err := payload.NewStore(args.payloads, db, awsConfig, encryptor)
+	if err != nil {
+		return err
+	}
+
+	domains, err := domain.Load(args.domainConfig)
+	if err != nil {
+		return err
+	}
+	if args.domainConfig != "" {
+		go domain.Watch(ctx, args.domainConfig, domains, domain.WatchInterval, zapLogger)
+	}
+	// Sends of domains in sandbox mode are simulated instead of reaching the
+	// providers.
+	sandboxedEmail := sandbox.NewEmailService(emailService, domains)
+	sandboxedPush := sandbox.NewPushService(pushService, domains)
+	offloader := payload.NewOffloader(payloadStore, args.payloads.Threshold)
+	retention := workflows.NewRetentionActivities(erasure.NewEraser(db, offloader), domains)
+	if err := scheduleRetention(ctx, temporalClient, args.retentionSchedule, args.retentionDryRun); err != nil {
+		return err
+	}
+
+	backlogCtx, stopBacklogs := context.WithCancel(ctx)
+	defer stopBacklogs()
+	go reportBacklogs(backlogCtx, temporalClient, args.temporalNamespace, args.lanes, metricsScope, zapLogger)
+	go reportPendingCommunications(backlogCtx, db, metricsScope, zapLogger)
+
+	// Activities in flight get the drain timeout to finish once stopping.
+	for i := range args.lanes {
+		args.lanes[i].options.WorkerStopTimeout = args.lifecycle.Timeout
+	}
+	zapLogger.Info("serving ops status", zap.Int("port", args.opsPort))
+	// The workers stop before the ops status, so the worker is seen to be
+	// unready while they drain.
+	return lc.Run(ctx,
+		lifecycle.Func(func(stop <-chan struct{}) error {
+			if !gate.Wait(ctx) {
+				return nil
+			}
+			return CommunicationWorker(temporalClient, args.lanes, stop, sandboxedEmail, sandboxedPush, sqsService, webhookClient, db, attachmentFetcher, offloader, retention)
+		}),
+		lifecycle.HTTPServer(&http.Server{
+			Addr:    fmt.Sprintf(":%d", args.opsPort),
+			Handler: mux,
+		}),
+	)
 }
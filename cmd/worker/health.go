@@ -0,0 +1,166 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/utilitywarehouse/go-operational/op"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/breaker"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/push"
+	"github.com/anicoll/unicom/internal/responsechannel"
+)
+
+const (
+	// checkTimeout bounds each call a checker makes to a dependency.
+	checkTimeout = time.Second * 5
+	// dependencyInterval is how often the readiness gate checks dependencies
+	// while any of them is unhealthy.
+	dependencyInterval = time.Second * 5
+	// unhealthy is the health op reports for failed checks.
+	unhealthy = "unhealthy"
+	// sesQuotaWarning is the share of the daily SES quota after which the
+	// worker reports degraded.
+	sesQuotaWarning = 0.9
+)
+
+// readinessGate holds off polling the task queues until no dependency of the
+// worker is unhealthy, so a worker which can't send leaves tasks to workers
+// which can.
+type readinessGate struct {
+	status *op.Status
+	logger *zap.Logger
+	open   atomic.Bool
+}
+
+func newReadinessGate(status *op.Status, logger *zap.Logger) *readinessGate {
+	return &readinessGate{
+		status: status,
+		logger: logger,
+	}
+}
+
+// Opened reports whether the gate has opened, so the worker is polling.
+func (g *readinessGate) Opened() bool {
+	return g.open.Load()
+}
+
+// Wait blocks until no check is unhealthy, logging those which are, and opens
+// the gate. It returns false if ctx is done first.
+func (g *readinessGate) Wait(ctx context.Context) bool {
+	for {
+		result := g.status.Check()
+		if result.Health != unhealthy {
+			g.open.Store(true)
+			return true
+		}
+		for _, check := range result.CheckResults {
+			if check.Health == unhealthy {
+				g.logger.Warn("waiting for dependency before polling task queues",
+					zap.String("check", check.Name), zap.String("output", check.Output))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(dependencyInterval):
+		}
+	}
+}
+
+// temporalChecker reports whether the Temporal frontend responds.
+func temporalChecker(temporalClient client.Client) func(cr *op.CheckResponse) {
+	return func(cr *op.CheckResponse) {
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		if _, err := temporalClient.CheckHealth(ctx, &client.CheckHealthRequest{}); err != nil {
+			cr.Unhealthy("temporal-client unresponsive", "check server connection/network", "no communications will be sent")
+		} else {
+			cr.Healthy("healthy")
+		}
+	}
+}
+
+// sesChecker reports whether the SES account in a region can send. The worker
+// is degraded once the account is on probation or most of its daily quota is
+// used.
+func sesChecker(provider *email.SESProvider) func(cr *op.CheckResponse) {
+	return func(cr *op.CheckResponse) {
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		account, err := provider.Account(ctx)
+		switch {
+		case err != nil:
+			cr.Unhealthy(fmt.Sprintf("ses unavailable: %v", err), credentialsAction(err), "emails sent through ses will fail over or fail")
+		case !account.SendingEnabled || account.EnforcementStatus == "SHUTDOWN":
+			cr.Unhealthy(account.String(), "check the account's status in the ses console", "emails sent through ses will fail over or fail")
+		case account.Max24HourSend > 0 && account.SentLast24Hours >= account.Max24HourSend:
+			cr.Unhealthy(account.String(), "request a higher sending quota", "emails sent through ses will fail over or fail until the quota frees up")
+		case account.EnforcementStatus == "PROBATION":
+			cr.Degraded(account.String(), "check bounces and complaints in the ses console")
+		case account.QuotaUsed() >= sesQuotaWarning:
+			cr.Degraded(account.String(), "request a higher sending quota")
+		default:
+			cr.Healthy(account.String())
+		}
+	}
+}
+
+// oneSignalChecker reports whether OneSignal accepts the app ID and REST API
+// key.
+func oneSignalChecker(provider *push.OneSignalProvider) func(cr *op.CheckResponse) {
+	return func(cr *op.CheckResponse) {
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		if err := provider.Check(ctx); err != nil {
+			cr.Unhealthy(fmt.Sprintf("onesignal unavailable: %v", err), credentialsAction(err), "push notifications sent through onesignal will fail over or fail")
+		} else {
+			cr.Healthy("credentials accepted")
+		}
+	}
+}
+
+// sqsChecker reports whether SQS can be reached. SQS response channels are
+// notified after sending, so the worker is only degraded without them.
+func sqsChecker(service *responsechannel.SQSService) func(cr *op.CheckResponse) {
+	return func(cr *op.CheckResponse) {
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		if err := service.Check(ctx); err != nil {
+			cr.Degraded(fmt.Sprintf("sqs unavailable: %v", err), credentialsAction(err))
+		} else {
+			cr.Healthy("healthy")
+		}
+	}
+}
+
+// credentialsAction returns the action for a failed check, depending on
+// whether the credentials were rejected.
+func credentialsAction(err error) string {
+	if failure.KindOf(err) == failure.Auth {
+		return "check the credentials the worker is configured with"
+	}
+	return "check connection/network and the provider's status page"
+}
+
+// breakerChecker reports a provider's circuit breaker on the ops status page.
+// An open breaker only degrades the worker as sends can still fail over.
+func breakerChecker(b *breaker.Breaker) func(cr *op.CheckResponse) {
+	return func(cr *op.CheckResponse) {
+		snapshot := b.Snapshot()
+		switch snapshot.State {
+		case breaker.Open:
+			cr.Degraded(snapshot.String(), "check the provider's status page, sends are failing over to the next provider")
+		case breaker.HalfOpen:
+			cr.Degraded(snapshot.String(), "provider is being probed for recovery")
+		default:
+			cr.Healthy(snapshot.String())
+		}
+	}
+}
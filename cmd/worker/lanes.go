@@ -0,0 +1,137 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/uber-go/tally/v4"
+	"go.temporal.io/api/enums/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/worker"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// backlogInterval is how often the backlog of each lane's task queues is
+// reported.
+const backlogInterval = 30 * time.Second
+
+// TaskQueue returns the task queue communications of a priority are processed
+// on. Transactional communications, and those without a priority, keep the
+// queue every communication used before priorities existed.
+func TaskQueue(priority model.Priority) string {
+	switch priority {
+	case model.PriorityCritical:
+		return CriticalTaskQueue
+	case model.PriorityBulk:
+		return BulkTaskQueue
+	}
+	return CommunicationTaskQueue
+}
+
+// lane is a priority's task queue and the options of the worker polling it.
+type lane struct {
+	priority  model.Priority
+	taskQueue string
+	options   worker.Options
+}
+
+// parseLanes returns the lanes a worker runs: one for each of the given
+// priorities, or every priority when none are given. maxActivities and
+// maxWorkflowTasks limit the concurrency of a lane's worker, keyed by
+// priority, with the SDK's defaults for lanes without an entry.
+func parseLanes(priorities []string, maxActivities, maxWorkflowTasks map[string]string) ([]lane, error) {
+	selected := model.Priorities
+	if len(priorities) > 0 {
+		selected = make([]model.Priority, 0, len(priorities))
+		for _, name := range priorities {
+			priority, err := model.ParsePriority(name)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, priority)
+		}
+	}
+	for _, limits := range []map[string]string{maxActivities, maxWorkflowTasks} {
+		for name := range limits {
+			if _, err := model.ParsePriority(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	lanes := make([]lane, 0, len(selected))
+	for _, priority := range selected {
+		activities, err := parseLimit(maxActivities, priority)
+		if err != nil {
+			return nil, fmt.Errorf("max concurrent activities: %w", err)
+		}
+		workflowTasks, err := parseLimit(maxWorkflowTasks, priority)
+		if err != nil {
+			return nil, fmt.Errorf("max concurrent workflow tasks: %w", err)
+		}
+		lanes = append(lanes, lane{
+			priority:  priority,
+			taskQueue: TaskQueue(priority),
+			options: worker.Options{
+				MaxConcurrentActivityExecutionSize:     activities,
+				MaxConcurrentWorkflowTaskExecutionSize: workflowTasks,
+			},
+		})
+	}
+	return lanes, nil
+}
+
+// parseLimit returns a priority's limit, zero when it has none.
+func parseLimit(limits map[string]string, priority model.Priority) (int, error) {
+	value, ok := limits[string(priority)]
+	if !ok {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("%s must be a positive number, got %q", priority, value)
+	}
+	return limit, nil
+}
+
+// reportBacklogs periodically reports the approximate backlog of each lane's
+// workflow and activity task queues until ctx is done.
+func reportBacklogs(ctx context.Context, temporalClient client.Client, namespace string, lanes []lane, scope tally.Scope, logger *zap.Logger) {
+	ticker := time.NewTicker(backlogInterval)
+	defer ticker.Stop()
+	for {
+		for _, l := range lanes {
+			for _, queueType := range []enums.TaskQueueType{enums.TASK_QUEUE_TYPE_WORKFLOW, enums.TASK_QUEUE_TYPE_ACTIVITY} {
+				resp, err := temporalClient.WorkflowService().DescribeTaskQueue(ctx, &workflowservice.DescribeTaskQueueRequest{
+					Namespace:     namespace,
+					TaskQueue:     &taskqueuepb.TaskQueue{Name: l.taskQueue, Kind: enums.TASK_QUEUE_KIND_NORMAL},
+					TaskQueueType: queueType,
+					ReportStats:   true,
+				})
+				if err != nil {
+					logger.Warn("unable to describe task queue", zap.Error(err), zap.String("task_queue", l.taskQueue))
+					continue
+				}
+				tagged := scope.Tagged(map[string]string{
+					"task_queue":      l.taskQueue,
+					"task_queue_type": queueType.String(),
+					"priority":        string(l.priority),
+				})
+				stats := resp.GetStats()
+				tagged.Gauge("task_queue_backlog").Update(float64(stats.GetApproximateBacklogCount()))
+				tagged.Gauge("task_queue_backlog_age_seconds").Update(stats.GetApproximateBacklogAge().AsDuration().Seconds())
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/uber-go/tally/v4"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/database"
+	"github.com/anicoll/unicom/internal/metrics"
+)
+
+// reportPendingCommunications periodically reports how many communications of
+// each domain are waiting to be sent until ctx is done. Domains which no
+// longer have any are reported as zero.
+func reportPendingCommunications(ctx context.Context, db *database.Postgres, scope tally.Scope, logger *zap.Logger) {
+	ticker := time.NewTicker(backlogInterval)
+	defer ticker.Stop()
+	seen := map[string]bool{}
+	for {
+		counts, err := db.CountPendingCommunications(ctx)
+		if err != nil {
+			logger.Warn("unable to count pending communications", zap.Error(err))
+		} else {
+			for domain := range counts {
+				seen[domain] = true
+			}
+			for domain := range seen {
+				scope.Tagged(map[string]string{metrics.DomainTag: domain}).
+					Gauge(metrics.PendingCommunications).Update(float64(counts[domain]))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ses "github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/utilitywarehouse/go-operational/op"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/anicoll/unicom/internal/breaker"
+	"github.com/anicoll/unicom/internal/database"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/outbox"
+	"github.com/anicoll/unicom/internal/push"
+)
+
+const (
+	emailProviderSES      = "ses"
+	emailProviderSMTP     = "smtp"
+	emailProviderSendgrid = "sendgrid"
+	emailProviderOutbox   = "outbox"
+)
+
+// newEmailService builds every email provider referenced by the worker
+// configuration and routes domains to them. Each provider is guarded by a
+// circuit breaker and fails over to the secondary SES region and then the
+// failover provider, when configured. The SES accounts used are checked on the
+// ops status page.
+func newEmailService(args emailArgs, awsConfig aws.Config, breakers *breaker.Registry, status *op.Status) (*email.Service, error) {
+	providers := map[string]email.Provider{}
+	build := func(name string) (email.Provider, error) {
+		if provider, ok := providers[name]; ok {
+			return provider, nil
+		}
+		var provider email.Provider
+		switch name {
+		case emailProviderSES:
+			sesProvider := email.NewSESProvider(ses.NewFromConfig(awsConfig))
+			status.AddChecker("email/ses/"+awsConfig.Region, sesChecker(sesProvider))
+			provider = sesProvider
+		case emailProviderSMTP:
+			provider = email.NewSMTPProvider(email.SMTPConfig{
+				Host:     args.smtpHost,
+				Port:     args.smtpPort,
+				Username: args.smtpUsername,
+				Password: args.smtpPassword,
+			})
+		case emailProviderSendgrid:
+			if args.sendgridAPIKey == "" {
+				return nil, fmt.Errorf("email provider %q requires a sendgrid-api-key", name)
+			}
+			provider = email.NewHTTPAPIProvider(&http.Client{
+				Timeout: time.Second * 30,
+			}, email.HTTPAPIConfig{
+				BaseURL: args.sendgridURL,
+				APIKey:  args.sendgridAPIKey,
+			})
+		case emailProviderOutbox:
+			box, err := outbox.New(args.outboxDir)
+			if err != nil {
+				return nil, fmt.Errorf("email provider %q: %w", name, err)
+			}
+			provider = email.NewOutboxProvider(box)
+		default:
+			return nil, fmt.Errorf("unknown email provider %q", name)
+		}
+		providers[name] = provider
+		return provider, nil
+	}
+
+	var sesFailover *email.SESProvider
+	chain := func(name string) (email.Provider, error) {
+		primary, err := build(name)
+		if err != nil {
+			return nil, err
+		}
+		breakerName := "email/" + name
+		if name == emailProviderSES {
+			breakerName += "/" + awsConfig.Region
+		}
+		targets := []breaker.Target[email.Provider]{{Provider: primary, Breaker: breakers.Get(breakerName)}}
+		if name == emailProviderSES && args.sesFailoverRegion != "" && args.sesFailoverRegion != awsConfig.Region {
+			region := args.sesFailoverRegion
+			if sesFailover == nil {
+				sesFailover = email.NewSESProvider(ses.NewFromConfig(awsConfig, func(o *ses.Options) {
+					o.Region = region
+				}))
+				status.AddChecker("email/ses/"+region, sesChecker(sesFailover))
+			}
+			targets = append(targets, breaker.Target[email.Provider]{
+				Provider: sesFailover,
+				Breaker:  breakers.Get("email/ses/" + region),
+			})
+		}
+		if args.failoverProvider != "" && args.failoverProvider != name {
+			secondary, err := build(args.failoverProvider)
+			if err != nil {
+				return nil, fmt.Errorf("failover: %w", err)
+			}
+			targets = append(targets, breaker.Target[email.Provider]{
+				Provider: secondary,
+				Breaker:  breakers.Get("email/" + args.failoverProvider),
+			})
+		}
+		return email.NewFailoverProvider(targets...), nil
+	}
+
+	defaultProvider, err := chain(args.provider)
+	if err != nil {
+		return nil, err
+	}
+	domainProviders := make(map[string]email.Provider, len(args.domainProviders))
+	for domain, name := range args.domainProviders {
+		provider, err := chain(name)
+		if err != nil {
+			return nil, fmt.Errorf("domain %s: %w", domain, err)
+		}
+		domainProviders[domain] = provider
+	}
+	return email.NewService(defaultProvider, domainProviders), nil
+}
+
+const (
+	pushProviderOneSignal = "onesignal"
+	pushProviderFCM       = "fcm"
+	pushProviderAPNs      = "apns"
+	pushProviderOutbox    = "outbox"
+)
+
+// newPushService builds every push provider referenced by the worker
+// configuration and routes domains to them. Each provider is guarded by a
+// circuit breaker and fails over to the failover provider, when configured.
+// OneSignal's credentials are checked on the ops status page.
+func newPushService(ctx context.Context, logger *zap.Logger, args pushArgs, db *database.Postgres, breakers *breaker.Registry, status *op.Status) (*push.Service, error) {
+	providers := map[string]push.Provider{}
+	build := func(name string) (push.Provider, error) {
+		if provider, ok := providers[name]; ok {
+			return provider, nil
+		}
+		var provider push.Provider
+		switch name {
+		case pushProviderOneSignal:
+			if args.onesignalAppId == "" || args.onesignalAuthKey == "" {
+				return nil, fmt.Errorf("push provider %q requires onesignal-app-id and onesignal-auth-key", name)
+			}
+			oneSignalProvider := push.NewOneSignalProvider(logger, args.onesignalAppId, args.onesignalAuthKey)
+			status.AddChecker("push/onesignal", oneSignalChecker(oneSignalProvider))
+			provider = oneSignalProvider
+		case pushProviderFCM:
+			credentials, err := os.ReadFile(args.fcmCredentialsFile)
+			if err != nil {
+				return nil, fmt.Errorf("push provider %q requires fcm-credentials-file: %w", name, err)
+			}
+			tokenSource, err := push.NewFCMTokenSource(ctx, credentials)
+			if err != nil {
+				return nil, err
+			}
+			client := oauth2.NewClient(ctx, tokenSource)
+			client.Timeout = time.Second * 30
+			provider = push.NewFCMProvider(logger, client, db, push.FCMConfig{
+				ProjectID: args.fcmProjectId,
+				BaseURL:   args.fcmURL,
+			})
+		case pushProviderAPNs:
+			keyData, err := os.ReadFile(args.apnsKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("push provider %q requires apns-key-file: %w", name, err)
+			}
+			key, err := push.ParseAPNsKey(keyData)
+			if err != nil {
+				return nil, err
+			}
+			provider = push.NewAPNsProvider(logger, &http.Client{
+				Timeout:   time.Second * 30,
+				Transport: &http.Transport{ForceAttemptHTTP2: true},
+			}, db, push.APNsConfig{
+				BaseURL:    args.apnsURL,
+				TeamID:     args.apnsTeamId,
+				KeyID:      args.apnsKeyId,
+				Topic:      args.apnsTopic,
+				PrivateKey: key,
+			})
+		case pushProviderOutbox:
+			box, err := outbox.New(args.outboxDir)
+			if err != nil {
+				return nil, fmt.Errorf("push provider %q: %w", name, err)
+			}
+			provider = push.NewOutboxProvider(box, args.onesignalAppId)
+		default:
+			return nil, fmt.Errorf("unknown push provider %q", name)
+		}
+		providers[name] = provider
+		return provider, nil
+	}
+
+	chain := func(name string) (push.Provider, error) {
+		primary, err := build(name)
+		if err != nil {
+			return nil, err
+		}
+		targets := []breaker.Target[push.Provider]{{Provider: primary, Breaker: breakers.Get("push/" + name)}}
+		if args.failoverProvider != "" && args.failoverProvider != name {
+			secondary, err := build(args.failoverProvider)
+			if err != nil {
+				return nil, fmt.Errorf("failover: %w", err)
+			}
+			targets = append(targets, breaker.Target[push.Provider]{
+				Provider: secondary,
+				Breaker:  breakers.Get("push/" + args.failoverProvider),
+			})
+		}
+		return push.NewFailoverProvider(targets...), nil
+	}
+
+	defaultProvider, err := chain(args.provider)
+	if err != nil {
+		return nil, err
+	}
+	domainProviders := make(map[string]push.Provider, len(args.domainProviders))
+	for domain, name := range args.domainProviders {
+		provider, err := chain(name)
+		if err != nil {
+			return nil, fmt.Errorf("domain %s: %w", domain, err)
+		}
+		domainProviders[domain] = provider
+	}
+	return push.NewService(defaultProvider, domainProviders), nil
+}
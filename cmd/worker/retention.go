@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"errors"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/anicoll/unicom/internal/workflows"
+)
+
+const RetentionScheduleID string = "unicom-retention"
+
+// scheduleRetention creates or updates the schedule starting the retention
+// workflow, so the schedule follows the worker's flags. An empty cron
+// expression removes it.
+func scheduleRetention(ctx context.Context, temporalClient client.Client, cron string, dryRun bool) error {
+	schedules := temporalClient.ScheduleClient()
+	if cron == "" {
+		err := schedules.GetHandle(ctx, RetentionScheduleID).Delete(ctx)
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+
+	spec := client.ScheduleSpec{CronExpressions: []string{cron}}
+	action := &client.ScheduleWorkflowAction{
+		ID:        RetentionScheduleID,
+		Workflow:  workflows.RetentionWorkflow,
+		Args:      []any{workflows.RetentionRequest{DryRun: dryRun}},
+		TaskQueue: BulkTaskQueue,
+	}
+	_, err := schedules.Create(ctx, client.ScheduleOptions{
+		ID:      RetentionScheduleID,
+		Spec:    spec,
+		Action:  action,
+		Overlap: enums.SCHEDULE_OVERLAP_POLICY_SKIP,
+	})
+	if !errors.Is(err, temporal.ErrScheduleAlreadyRunning) {
+		return err
+	}
+	return schedules.GetHandle(ctx, RetentionScheduleID).Update(ctx, client.ScheduleUpdateOptions{
+		DoUpdate: func(input client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+			schedule := input.Description.Schedule
+			schedule.Spec = &spec
+			schedule.Action = action
+			return &client.ScheduleUpdate{Schedule: &schedule}, nil
+		},
+	})
+}
@@ -1,29 +1,78 @@
 package worker
 
 import (
+	"cmp"
 	"context"
-	"log"
-	"time"
 
-	prom "github.com/prometheus/client_golang/prometheus"
-	"github.com/uber-go/tally/v4"
-	"github.com/uber-go/tally/v4/prometheus"
 	"github.com/urfave/cli/v3"
+
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/breaker"
+	"github.com/anicoll/unicom/internal/lifecycle"
+	"github.com/anicoll/unicom/internal/payload"
+	"github.com/anicoll/unicom/internal/tracing"
 )
 
 type workerArgs struct {
-	temporalAddress   string
-	temporalNamespace string
-	owner             string
-	opsPort           int
-	dbDsn             string
-	migrationAction   string
-	name              string
-	region            string
-	description       string
-	version           string
-	onesignalAppId    string
-	onesignalAuthKey  string
+	temporalAddress      string
+	temporalNamespace    string
+	owner                string
+	opsPort              int
+	dbDsn                string
+	migrationAction      string
+	name                 string
+	region               string
+	description          string
+	version              string
+	email                emailArgs
+	push                 pushArgs
+	breaker              breaker.Config
+	attachments          attachment.Config
+	payloads             payload.Config
+	domainConfig         string
+	sqsOutbox            bool
+	outboxDir            string
+	webhookSigningSecret string
+	retentionSchedule    string
+	retentionDryRun      bool
+	encryptionKeyFile    string
+	blindIndexKey        string
+	codecTokens          []string
+	codecOrigins         []string
+	tracing              tracing.Config
+	lifecycle            lifecycle.Config
+	lanes                []lane
+}
+
+type emailArgs struct {
+	provider          string
+	domainProviders   map[string]string
+	failoverProvider  string
+	sesFailoverRegion string
+	smtpHost          string
+	smtpPort          int
+	smtpUsername      string
+	smtpPassword      string
+	sendgridURL       string
+	sendgridAPIKey    string
+	outboxDir         string
+}
+
+type pushArgs struct {
+	provider           string
+	domainProviders    map[string]string
+	failoverProvider   string
+	onesignalAppId     string
+	onesignalAuthKey   string
+	fcmProjectId       string
+	fcmCredentialsFile string
+	fcmURL             string
+	apnsKeyFile        string
+	apnsKeyId          string
+	apnsTeamId         string
+	apnsTopic          string
+	apnsURL            string
+	outboxDir          string
 }
 
 func CommunicationWorkerCommand() *cli.Command {
@@ -48,83 +97,359 @@ func CommunicationWorkerCommand() *cli.Command {
 				Required: false,
 				Value:    "default",
 			},
+			&cli.StringFlag{
+				Name:     "push-provider",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PUSH_PROVIDER")),
+				Required: false,
+				Value:    pushProviderOneSignal,
+				Usage:    "default push provider, one of onesignal/fcm/apns/outbox",
+			},
+			&cli.StringMapFlag{
+				Name:     "push-domain-providers",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PUSH_DOMAIN_PROVIDERS")),
+				Required: false,
+				Usage:    "per domain push provider overrides, e.g. android-app=fcm,ios-app=apns",
+			},
+			&cli.StringFlag{
+				Name:     "push-failover-provider",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PUSH_FAILOVER_PROVIDER")),
+				Required: false,
+				Value:    "",
+				Usage:    "push provider used when the primary provider's circuit breaker is open or it fails",
+			},
 			&cli.StringFlag{
 				Name:     "onesignal-app-id",
 				Sources:  cli.NewValueSourceChain(cli.EnvVar("ONESIGNAL_APP_ID")),
-				Required: true,
+				Required: false,
 				Value:    "",
 			},
 			&cli.StringFlag{
 				Name:     "onesignal-auth-key",
 				Sources:  cli.NewValueSourceChain(cli.EnvVar("ONESIGNAL_AUTH_KEY")),
-				Required: true,
+				Required: false,
+				Value:    "",
+			},
+			&cli.StringFlag{
+				Name:     "fcm-project-id",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("FCM_PROJECT_ID")),
+				Required: false,
+				Value:    "",
+			},
+			&cli.StringFlag{
+				Name:     "fcm-credentials-file",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("FCM_CREDENTIALS_FILE")),
+				Required: false,
+				Value:    "",
+				Usage:    "path to the google service account json key used for fcm",
+			},
+			&cli.StringFlag{
+				Name:     "fcm-api-url",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("FCM_API_URL")),
+				Required: false,
+				Value:    "https://fcm.googleapis.com",
+			},
+			&cli.StringFlag{
+				Name:     "apns-key-file",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("APNS_KEY_FILE")),
+				Required: false,
+				Value:    "",
+				Usage:    "path to the .p8 apns auth key",
+			},
+			&cli.StringFlag{
+				Name:     "apns-key-id",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("APNS_KEY_ID")),
+				Required: false,
+				Value:    "",
+			},
+			&cli.StringFlag{
+				Name:     "apns-team-id",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("APNS_TEAM_ID")),
+				Required: false,
+				Value:    "",
+			},
+			&cli.StringFlag{
+				Name:     "apns-topic",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("APNS_TOPIC")),
+				Required: false,
+				Value:    "",
+				Usage:    "bundle id of the app receiving apns notifications",
+			},
+			&cli.StringFlag{
+				Name:     "apns-api-url",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("APNS_API_URL")),
+				Required: false,
+				Value:    "https://api.push.apple.com",
+			},
+			&cli.StringFlag{
+				Name:     "outbox-dir",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("OUTBOX_DIR")),
+				Required: false,
+				Value:    "outbox",
+				Usage:    "directory the outbox email and push providers, and the sqs outbox, write messages to instead of sending them",
+			},
+			&cli.BoolFlag{
+				Name:     "sqs-outbox",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SQS_OUTBOX")),
+				Required: false,
+				Usage:    "write sqs response channel notifications to outbox-dir instead of sending them",
+			},
+			&cli.StringFlag{
+				Name:     "webhook-signing-secret",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("WEBHOOK_SIGNING_SECRET")),
+				Required: false,
+				Value:    "",
+				Usage:    "secret webhook response channel notifications are signed with in the X-Unicom-Signature header, empty leaves them unsigned",
+			},
+			&cli.StringFlag{
+				Name:     "email-provider",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("EMAIL_PROVIDER")),
+				Required: false,
+				Value:    emailProviderSES,
+				Usage:    "default email provider, one of ses/smtp/sendgrid/outbox",
+			},
+			&cli.StringMapFlag{
+				Name:     "email-domain-providers",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("EMAIL_DOMAIN_PROVIDERS")),
+				Required: false,
+				Usage:    "per domain email provider overrides, e.g. marketing=sendgrid,local=smtp",
+			},
+			&cli.StringFlag{
+				Name:     "email-failover-provider",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("EMAIL_FAILOVER_PROVIDER")),
+				Required: false,
+				Value:    "",
+				Usage:    "email provider used when the primary provider's circuit breaker is open or it fails",
+			},
+			&cli.StringFlag{
+				Name:     "ses-failover-region",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SES_FAILOVER_REGION")),
+				Required: false,
+				Value:    "",
+				Usage:    "secondary aws region to send ses email through when the primary region fails",
+			},
+			&cli.StringFlag{
+				Name:     "smtp-host",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SMTP_HOST")),
+				Required: false,
+				Value:    "localhost",
+			},
+			&cli.IntFlag{
+				Name:     "smtp-port",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SMTP_PORT")),
+				Required: false,
+				Value:    1025,
+			},
+			&cli.StringFlag{
+				Name:     "smtp-username",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SMTP_USERNAME")),
+				Required: false,
+				Value:    "",
+			},
+			&cli.StringFlag{
+				Name:     "smtp-password",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SMTP_PASSWORD")),
+				Required: false,
+				Value:    "",
+			},
+			&cli.StringFlag{
+				Name:     "sendgrid-api-url",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SENDGRID_API_URL")),
+				Required: false,
+				Value:    "https://api.sendgrid.com",
+			},
+			&cli.StringFlag{
+				Name:     "sendgrid-api-key",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("SENDGRID_API_KEY")),
+				Required: false,
 				Value:    "",
 			},
+			&cli.DurationFlag{
+				Name:     "breaker-window",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("BREAKER_WINDOW")),
+				Required: false,
+				Value:    breaker.DefaultConfig.Window,
+				Usage:    "how far back provider errors are considered when scoring provider health",
+			},
+			&cli.IntFlag{
+				Name:     "breaker-min-requests",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("BREAKER_MIN_REQUESTS")),
+				Required: false,
+				Value:    breaker.DefaultConfig.MinRequests,
+			},
+			&cli.FloatFlag{
+				Name:     "breaker-failure-ratio",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("BREAKER_FAILURE_RATIO")),
+				Required: false,
+				Value:    breaker.DefaultConfig.FailureRatio,
+				Usage:    "proportion of failed sends within the window that opens a provider's circuit breaker",
+			},
+			&cli.DurationFlag{
+				Name:     "breaker-cooldown",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("BREAKER_COOLDOWN")),
+				Required: false,
+				Value:    breaker.DefaultConfig.Cooldown,
+			},
+			&cli.DurationFlag{
+				Name:     "attachment-fetch-timeout",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ATTACHMENT_FETCH_TIMEOUT")),
+				Required: false,
+				Value:    attachment.DefaultConfig.Timeout,
+			},
+			&cli.BoolFlag{
+				Name:     "attachment-allow-private-networks",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ATTACHMENT_ALLOW_PRIVATE_NETWORKS")),
+				Required: false,
+				Usage:    "allow fetching attachments from loopback and private addresses, for local development only",
+			},
+			&cli.StringFlag{
+				Name:     "attachment-s3-endpoint",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("ATTACHMENT_S3_ENDPOINT")),
+				Required: false,
+				Value:    "",
+				Usage:    "overrides the s3 endpoint attachments are fetched from, e.g. for localstack",
+			},
+			&cli.StringFlag{
+				Name:     "retention-schedule",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("RETENTION_SCHEDULE")),
+				Required: false,
+				Value:    "0 3 * * *",
+				Usage:    "cron expression the retention workflow is scheduled with, empty removes the schedule",
+			},
+			&cli.BoolFlag{
+				Name:     "retention-dry-run",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("RETENTION_DRY_RUN")),
+				Required: false,
+				Value:    true,
+				Usage:    "only report what the retention workflow would erase",
+			},
+			&cli.StringSliceFlag{
+				Name:     "codec-server-tokens",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("CODEC_SERVER_TOKENS")),
+				Required: false,
+				Usage:    "bearer tokens allowed to decrypt payloads through the codec server on the ops port, empty disables it",
+			},
+			&cli.StringSliceFlag{
+				Name:     "codec-cors-origins",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("CODEC_CORS_ORIGINS")),
+				Required: false,
+				Usage:    "origins of temporal uis allowed to call the codec server",
+			},
+			&cli.StringSliceFlag{
+				Name:     "priorities",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("PRIORITIES")),
+				Required: false,
+				Usage:    "priorities whose task queues this worker polls, any of critical/transactional/bulk, empty polls all of them",
+			},
+			&cli.StringMapFlag{
+				Name:     "max-concurrent-activities",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("MAX_CONCURRENT_ACTIVITIES")),
+				Required: false,
+				Usage:    "per priority limit of concurrently executing activities, e.g. critical=200,bulk=20",
+			},
+			&cli.StringMapFlag{
+				Name:     "max-concurrent-workflow-tasks",
+				Sources:  cli.NewValueSourceChain(cli.EnvVar("MAX_CONCURRENT_WORKFLOW_TASKS")),
+				Required: false,
+				Usage:    "per priority limit of concurrently executing workflow tasks, e.g. critical=100,bulk=10",
+			},
 		},
 		Action: func(ctx context.Context, c *cli.Command) error {
-			args := workerArgs{
-				temporalNamespace: c.String("temporal-namespace"),
-				temporalAddress:   c.String("temporal-server"),
-				opsPort:           c.Int("ops-port"),
-				region:            c.String("aws-region"),
-				dbDsn:             c.String("db-dsn"),
-				migrationAction:   c.String("migrate-action"),
-				onesignalAppId:    c.String("onesignal-app-id"),
-				onesignalAuthKey:  c.String("onesignal-auth-key"),
-				name:              c.Name,
-				description:       c.Description,
-				version:           c.Version,
-				owner:             c.Authors[0].(string),
-			}
-			return communicationWorkerAction(args)
+			return Run(ctx, c, c.Int("ops-port"))
 		},
 	}
 }
 
-func newPrometheusScope(c prometheus.Configuration, prefix string) tally.Scope {
-	reporter, err := c.NewReporter(
-		prometheus.ConfigurationOptions{
-			Registry: prom.NewRegistry(),
-			OnError: func(err error) {
-				log.Println("error in prometheus reporter", err)
-			},
-		},
-	)
+// Run runs the worker with the flags of c, serving its ops status on opsPort,
+// until the process is signalled to stop or ctx is done.
+func Run(ctx context.Context, c *cli.Command, opsPort int) error {
+	lanes, err := parseLanes(c.StringSlice("priorities"), c.StringMap("max-concurrent-activities"), c.StringMap("max-concurrent-workflow-tasks"))
 	if err != nil {
-		log.Fatalln("error creating prometheus reporter", err)
-	}
-	scopeOpts := tally.ScopeOptions{
-		CachedReporter:  reporter,
-		Separator:       prometheus.DefaultSeparator,
-		SanitizeOptions: &sanitizeOptions,
-		Prefix:          prefix,
+		return err
 	}
-	scope, _ := tally.NewRootScope(scopeOpts, time.Second)
-
-	log.Println("prometheus metrics scope created")
-	return scope
-}
-
-// tally sanitizer options that satisfy Prometheus restrictions.
-// This will rename metrics at the tally emission level, so metrics name we
-// use maybe different from what gets emitted. In the current implementation
-// it will replace - and . with _
-var (
-	safeCharacters = []rune{'_'}
-
-	sanitizeOptions = tally.SanitizeOptions{
-		NameCharacters: tally.ValidCharacters{
-			Ranges:     tally.AlphanumericRange,
-			Characters: safeCharacters,
+	args := workerArgs{
+		temporalNamespace: c.String("temporal-namespace"),
+		temporalAddress:   c.String("temporal-server"),
+		opsPort:           opsPort,
+		region:            c.String("aws-region"),
+		dbDsn:             c.String("db-dsn"),
+		migrationAction:   c.String("migrate-action"),
+		email: emailArgs{
+			provider:          c.String("email-provider"),
+			domainProviders:   c.StringMap("email-domain-providers"),
+			failoverProvider:  c.String("email-failover-provider"),
+			sesFailoverRegion: c.String("ses-failover-region"),
+			smtpHost:          c.String("smtp-host"),
+			smtpPort:          c.Int("smtp-port"),
+			smtpUsername:      c.String("smtp-username"),
+			smtpPassword:      c.String("smtp-password"),
+			sendgridURL:       c.String("sendgrid-api-url"),
+			sendgridAPIKey:    c.String("sendgrid-api-key"),
+			outboxDir:         c.String("outbox-dir"),
+		},
+		push: pushArgs{
+			provider:           c.String("push-provider"),
+			domainProviders:    c.StringMap("push-domain-providers"),
+			failoverProvider:   c.String("push-failover-provider"),
+			onesignalAppId:     c.String("onesignal-app-id"),
+			onesignalAuthKey:   c.String("onesignal-auth-key"),
+			fcmProjectId:       c.String("fcm-project-id"),
+			fcmCredentialsFile: c.String("fcm-credentials-file"),
+			fcmURL:             c.String("fcm-api-url"),
+			apnsKeyFile:        c.String("apns-key-file"),
+			apnsKeyId:          c.String("apns-key-id"),
+			apnsTeamId:         c.String("apns-team-id"),
+			apnsTopic:          c.String("apns-topic"),
+			apnsURL:            c.String("apns-api-url"),
+			outboxDir:          c.String("outbox-dir"),
+		},
+		breaker: breaker.Config{
+			Window:       c.Duration("breaker-window"),
+			MinRequests:  c.Int("breaker-min-requests"),
+			FailureRatio: c.Float("breaker-failure-ratio"),
+			Cooldown:     c.Duration("breaker-cooldown"),
 		},
-		KeyCharacters: tally.ValidCharacters{
-			Ranges:     tally.AlphanumericRange,
-			Characters: safeCharacters,
+		attachments: attachment.Config{
+			Schemes:              c.StringSlice("attachment-schemes"),
+			Hosts:                c.StringSlice("attachment-hosts"),
+			MaxSize:              int64(c.Int("attachment-max-size")),
+			ContentTypes:         c.StringSlice("attachment-content-types"),
+			Timeout:              c.Duration("attachment-fetch-timeout"),
+			AllowPrivateNetworks: c.Bool("attachment-allow-private-networks"),
+			S3Endpoint:           c.String("attachment-s3-endpoint"),
 		},
-		ValueCharacters: tally.ValidCharacters{
-			Ranges:     tally.AlphanumericRange,
-			Characters: safeCharacters,
+		payloads: payload.Config{
+			Store:      c.String("payload-store"),
+			Threshold:  c.Int("payload-offload-threshold"),
+			Dir:        c.String("payload-dir"),
+			Bucket:     c.String("payload-s3-bucket"),
+			Prefix:     c.String("payload-s3-prefix"),
+			S3Endpoint: c.String("payload-s3-endpoint"),
 		},
-		ReplacementCharacter: tally.DefaultReplacementCharacter,
+		tracing: tracing.Config{
+			Exporter:    c.String("trace-exporter"),
+			Endpoint:    c.String("otlp-endpoint"),
+			Insecure:    c.Bool("otlp-insecure"),
+			SampleRatio: c.Float("trace-sample-ratio"),
+		},
+		lifecycle: lifecycle.Config{
+			Delay:   c.Duration("drain-delay"),
+			Timeout: c.Duration("drain-timeout"),
+		},
+		sqsOutbox:            c.Bool("sqs-outbox"),
+		webhookSigningSecret: c.String("webhook-signing-secret"),
+		outboxDir:            c.String("outbox-dir"),
+		domainConfig:         cmp.Or(c.String("domain-config"), c.String("config")),
+		retentionSchedule:    c.String("retention-schedule"),
+		retentionDryRun:      c.Bool("retention-dry-run"),
+		encryptionKeyFile:    c.String("encryption-key-file"),
+		blindIndexKey:        c.String("blind-index-key"),
+		codecTokens:          c.StringSlice("codec-server-tokens"),
+		codecOrigins:         c.StringSlice("codec-cors-origins"),
+		lanes:                lanes,
+		name:                 c.Name,
+		description:          c.Description,
+		version:              c.Root().Version,
+		owner:                c.Root().Authors[0].(string),
 	}
-)
+	return communicationWorkerAction(ctx, args)
+}
@@ -61,6 +61,8 @@ func (m *Attachment) validate(all bool) error {
 
 	// no validation rules for Data
 
+	// no validation rules for ContentId
+
 	if m.Url != nil {
 		// no validation rules for Url
 	}
@@ -354,6 +356,109 @@ var _ interface {
 	ErrorName() string
 } = ResponseEventValidationError{}
 
+// Validate checks the field values on EmailAddress with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *EmailAddress) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EmailAddress with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in EmailAddressMultiError, or
+// nil if none found.
+func (m *EmailAddress) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EmailAddress) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Address
+
+	// no validation rules for Name
+
+	if len(errors) > 0 {
+		return EmailAddressMultiError(errors)
+	}
+
+	return nil
+}
+
+// EmailAddressMultiError is an error wrapping multiple validation errors
+// returned by EmailAddress.ValidateAll() if the designated constraints aren't met.
+type EmailAddressMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EmailAddressMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EmailAddressMultiError) AllErrors() []error { return m }
+
+// EmailAddressValidationError is the validation error returned by
+// EmailAddress.Validate if the designated constraints aren't met.
+type EmailAddressValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EmailAddressValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EmailAddressValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EmailAddressValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EmailAddressValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EmailAddressValidationError) ErrorName() string { return "EmailAddressValidationError" }
+
+// Error satisfies the builtin error interface
+func (e EmailAddressValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEmailAddress.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EmailAddressValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EmailAddressValidationError{}
+
 // Validate checks the field values on EmailRequest with the rules defined in
 // the proto definition for this message. If any rules are violated, the first
 // error encountered is returned, or nil if there are no violations.
@@ -418,6 +523,146 @@ func (m *EmailRequest) validate(all bool) error {
 
 	}
 
+	// no validation rules for FromName
+
+	for idx, item := range m.GetTo() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("To[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("To[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return EmailRequestValidationError{
+					field:  fmt.Sprintf("To[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetCc() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("Cc[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("Cc[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return EmailRequestValidationError{
+					field:  fmt.Sprintf("Cc[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetBcc() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("Bcc[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("Bcc[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return EmailRequestValidationError{
+					field:  fmt.Sprintf("Bcc[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetReplyTo() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("ReplyTo[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, EmailRequestValidationError{
+						field:  fmt.Sprintf("ReplyTo[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return EmailRequestValidationError{
+					field:  fmt.Sprintf("ReplyTo[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Text
+
 	if len(errors) > 0 {
 		return EmailRequestMultiError(errors)
 	}
@@ -789,118 +1034,3020 @@ var _ interface {
 	ErrorName() string
 } = PushRequestValidationError{}
 
-// Validate checks the field values on SendCommunicationRequest with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
-func (m *SendCommunicationRequest) Validate() error {
+// Validate checks the field values on DeliveryPolicy with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *DeliveryPolicy) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on SendCommunicationRequest with the
-// rules defined in the proto definition for this message. If any rules are
-// violated, the result is a list of violation errors wrapped in
-// SendCommunicationRequestMultiError, or nil if none found.
-func (m *SendCommunicationRequest) ValidateAll() error {
+// ValidateAll checks the field values on DeliveryPolicy with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in DeliveryPolicyMultiError,
+// or nil if none found.
+func (m *DeliveryPolicy) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *SendCommunicationRequest) validate(all bool) error {
+func (m *DeliveryPolicy) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for IsAsync
-
 	if all {
-		switch v := interface{}(m.GetSendAt()).(type) {
+		switch v := interface{}(m.GetAttemptTimeout()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, SendCommunicationRequestValidationError{
-					field:  "SendAt",
+				errors = append(errors, DeliveryPolicyValidationError{
+					field:  "AttemptTimeout",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, SendCommunicationRequestValidationError{
-					field:  "SendAt",
+				errors = append(errors, DeliveryPolicyValidationError{
+					field:  "AttemptTimeout",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		}
-	} else if v, ok := interface{}(m.GetSendAt()).(interface{ Validate() error }); ok {
+	} else if v, ok := interface{}(m.GetAttemptTimeout()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return SendCommunicationRequestValidationError{
-				field:  "SendAt",
+			return DeliveryPolicyValidationError{
+				field:  "AttemptTimeout",
 				reason: "embedded message failed validation",
 				cause:  err,
 			}
 		}
 	}
 
-	// no validation rules for Domain
+	// no validation rules for MaxAttempts
 
-	for idx, item := range m.GetResponseChannels() {
-		_, _ = idx, item
+	// no validation rules for BackoffCoefficient
 
-		if all {
-			switch v := interface{}(item).(type) {
-			case interface{ ValidateAll() error }:
-				if err := v.ValidateAll(); err != nil {
-					errors = append(errors, SendCommunicationRequestValidationError{
-						field:  fmt.Sprintf("ResponseChannels[%v]", idx),
-						reason: "embedded message failed validation",
-						cause:  err,
-					})
-				}
-			case interface{ Validate() error }:
-				if err := v.Validate(); err != nil {
-					errors = append(errors, SendCommunicationRequestValidationError{
-						field:  fmt.Sprintf("ResponseChannels[%v]", idx),
-						reason: "embedded message failed validation",
-						cause:  err,
-					})
-				}
+	if all {
+		switch v := interface{}(m.GetExpireAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, DeliveryPolicyValidationError{
+					field:  "ExpireAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
 			}
-		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				return SendCommunicationRequestValidationError{
-					field:  fmt.Sprintf("ResponseChannels[%v]", idx),
+				errors = append(errors, DeliveryPolicyValidationError{
+					field:  "ExpireAt",
 					reason: "embedded message failed validation",
 					cause:  err,
-				}
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExpireAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return DeliveryPolicyValidationError{
+				field:  "ExpireAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return DeliveryPolicyMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeliveryPolicyMultiError is an error wrapping multiple validation errors
+// returned by DeliveryPolicy.ValidateAll() if the designated constraints
+// aren't met.
+type DeliveryPolicyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeliveryPolicyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeliveryPolicyMultiError) AllErrors() []error { return m }
+
+// DeliveryPolicyValidationError is the validation error returned by
+// DeliveryPolicy.Validate if the designated constraints aren't met.
+type DeliveryPolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeliveryPolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeliveryPolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeliveryPolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeliveryPolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeliveryPolicyValidationError) ErrorName() string { return "DeliveryPolicyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e DeliveryPolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeliveryPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeliveryPolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeliveryPolicyValidationError{}
+
+// Validate checks the field values on DeliveryWindow with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *DeliveryWindow) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeliveryWindow with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in DeliveryWindowMultiError,
+// or nil if none found.
+func (m *DeliveryWindow) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeliveryWindow) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Start
+
+	// no validation rules for End
+
+	// no validation rules for Timezone
+
+	if len(errors) > 0 {
+		return DeliveryWindowMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeliveryWindowMultiError is an error wrapping multiple validation errors
+// returned by DeliveryWindow.ValidateAll() if the designated constraints
+// aren't met.
+type DeliveryWindowMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeliveryWindowMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeliveryWindowMultiError) AllErrors() []error { return m }
+
+// DeliveryWindowValidationError is the validation error returned by
+// DeliveryWindow.Validate if the designated constraints aren't met.
+type DeliveryWindowValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeliveryWindowValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeliveryWindowValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeliveryWindowValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeliveryWindowValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeliveryWindowValidationError) ErrorName() string { return "DeliveryWindowValidationError" }
+
+// Error satisfies the builtin error interface
+func (e DeliveryWindowValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeliveryWindow.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeliveryWindowValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeliveryWindowValidationError{}
+
+// Validate checks the field values on SendCommunicationRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SendCommunicationRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SendCommunicationRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SendCommunicationRequestMultiError, or nil if none found.
+func (m *SendCommunicationRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SendCommunicationRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for IsAsync
+
+	if all {
+		switch v := interface{}(m.GetSendAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "SendAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "SendAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetSendAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendCommunicationRequestValidationError{
+				field:  "SendAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Domain
+
+	for idx, item := range m.GetResponseChannels() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SendCommunicationRequestValidationError{
+						field:  fmt.Sprintf("ResponseChannels[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SendCommunicationRequestValidationError{
+						field:  fmt.Sprintf("ResponseChannels[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SendCommunicationRequestValidationError{
+					field:  fmt.Sprintf("ResponseChannels[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetEmail()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "Email",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "Email",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetEmail()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendCommunicationRequestValidationError{
+				field:  "Email",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetPush()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "Push",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "Push",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPush()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendCommunicationRequestValidationError{
+				field:  "Push",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetDeliveryPolicy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "DeliveryPolicy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "DeliveryPolicy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetDeliveryPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendCommunicationRequestValidationError{
+				field:  "DeliveryPolicy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for ExternalCustomerId
+
+	if all {
+		switch v := interface{}(m.GetDeliveryWindow()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "DeliveryWindow",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendCommunicationRequestValidationError{
+					field:  "DeliveryWindow",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetDeliveryWindow()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendCommunicationRequestValidationError{
+				field:  "DeliveryWindow",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Urgent
+
+	// no validation rules for Priority
+
+	// no validation rules for IdempotencyKey
+
+	// no validation rules for Test
+
+	if len(errors) > 0 {
+		return SendCommunicationRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SendCommunicationRequestMultiError is an error wrapping multiple validation
+// errors returned by SendCommunicationRequest.ValidateAll() if the designated
+// constraints aren't met.
+type SendCommunicationRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SendCommunicationRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SendCommunicationRequestMultiError) AllErrors() []error { return m }
+
+// SendCommunicationRequestValidationError is the validation error returned by
+// SendCommunicationRequest.Validate if the designated constraints aren't met.
+type SendCommunicationRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SendCommunicationRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SendCommunicationRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SendCommunicationRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SendCommunicationRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SendCommunicationRequestValidationError) ErrorName() string {
+	return "SendCommunicationRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SendCommunicationRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSendCommunicationRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SendCommunicationRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SendCommunicationRequestValidationError{}
+
+// Validate checks the field values on StreamCommunicationRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *StreamCommunicationRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on StreamCommunicationRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// StreamCommunicationRequestMultiError, or nil if none found.
+func (m *StreamCommunicationRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *StreamCommunicationRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Domain
+
+	if all {
+		switch v := interface{}(m.GetEmail()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, StreamCommunicationRequestValidationError{
+					field:  "Email",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, StreamCommunicationRequestValidationError{
+					field:  "Email",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetEmail()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return StreamCommunicationRequestValidationError{
+				field:  "Email",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetPush()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, StreamCommunicationRequestValidationError{
+					field:  "Push",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, StreamCommunicationRequestValidationError{
+					field:  "Push",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPush()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return StreamCommunicationRequestValidationError{
+				field:  "Push",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for ExternalCustomerId
+
+	// no validation rules for Priority
+
+	if len(errors) > 0 {
+		return StreamCommunicationRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// StreamCommunicationRequestMultiError is an error wrapping multiple
+// validation errors returned by StreamCommunicationRequest.ValidateAll() if
+// the designated constraints aren't met.
+type StreamCommunicationRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m StreamCommunicationRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m StreamCommunicationRequestMultiError) AllErrors() []error { return m }
+
+// StreamCommunicationRequestValidationError is the validation error returned
+// by StreamCommunicationRequest.Validate if the designated constraints aren't met.
+type StreamCommunicationRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e StreamCommunicationRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e StreamCommunicationRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e StreamCommunicationRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e StreamCommunicationRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e StreamCommunicationRequestValidationError) ErrorName() string {
+	return "StreamCommunicationRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e StreamCommunicationRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sStreamCommunicationRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = StreamCommunicationRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = StreamCommunicationRequestValidationError{}
+
+// Validate checks the field values on SendCommunicationResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SendCommunicationResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SendCommunicationResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SendCommunicationResponseMultiError, or nil if none found.
+func (m *SendCommunicationResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SendCommunicationResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return SendCommunicationResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// SendCommunicationResponseMultiError is an error wrapping multiple validation
+// errors returned by SendCommunicationResponse.ValidateAll() if the
+// designated constraints aren't met.
+type SendCommunicationResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SendCommunicationResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SendCommunicationResponseMultiError) AllErrors() []error { return m }
+
+// SendCommunicationResponseValidationError is the validation error returned by
+// SendCommunicationResponse.Validate if the designated constraints aren't met.
+type SendCommunicationResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SendCommunicationResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SendCommunicationResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SendCommunicationResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SendCommunicationResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SendCommunicationResponseValidationError) ErrorName() string {
+	return "SendCommunicationResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SendCommunicationResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSendCommunicationResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SendCommunicationResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SendCommunicationResponseValidationError{}
+
+// Validate checks the field values on StreamCommunicationResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *StreamCommunicationResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on StreamCommunicationResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// StreamCommunicationResponseMultiError, or nil if none found.
+func (m *StreamCommunicationResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *StreamCommunicationResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return StreamCommunicationResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// StreamCommunicationResponseMultiError is an error wrapping multiple
+// validation errors returned by StreamCommunicationResponse.ValidateAll() if
+// the designated constraints aren't met.
+type StreamCommunicationResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m StreamCommunicationResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m StreamCommunicationResponseMultiError) AllErrors() []error { return m }
+
+// StreamCommunicationResponseValidationError is the validation error returned
+// by StreamCommunicationResponse.Validate if the designated constraints
+// aren't met.
+type StreamCommunicationResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e StreamCommunicationResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e StreamCommunicationResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e StreamCommunicationResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e StreamCommunicationResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e StreamCommunicationResponseValidationError) ErrorName() string {
+	return "StreamCommunicationResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e StreamCommunicationResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sStreamCommunicationResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = StreamCommunicationResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = StreamCommunicationResponseValidationError{}
+
+// Validate checks the field values on GetStatusRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetStatusRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetStatusRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetStatusRequestMultiError, or nil if none found.
+func (m *GetStatusRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetStatusRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return GetStatusRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetStatusRequestMultiError is an error wrapping multiple validation errors
+// returned by GetStatusRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetStatusRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetStatusRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetStatusRequestMultiError) AllErrors() []error { return m }
+
+// GetStatusRequestValidationError is the validation error returned by
+// GetStatusRequest.Validate if the designated constraints aren't met.
+type GetStatusRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetStatusRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetStatusRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetStatusRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetStatusRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetStatusRequestValidationError) ErrorName() string { return "GetStatusRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetStatusRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetStatusRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetStatusRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetStatusRequestValidationError{}
+
+// Validate checks the field values on GetStatusResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetStatusResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetStatusResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetStatusResponseMultiError, or nil if none found.
+func (m *GetStatusResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetStatusResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Status
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return GetStatusResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetStatusResponseMultiError is an error wrapping multiple validation errors
+// returned by GetStatusResponse.ValidateAll() if the designated constraints
+// aren't met.
+type GetStatusResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetStatusResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetStatusResponseMultiError) AllErrors() []error { return m }
+
+// GetStatusResponseValidationError is the validation error returned by
+// GetStatusResponse.Validate if the designated constraints aren't met.
+type GetStatusResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetStatusResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetStatusResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetStatusResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetStatusResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetStatusResponseValidationError) ErrorName() string {
+	return "GetStatusResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetStatusResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetStatusResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetStatusResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetStatusResponseValidationError{}
+
+// Validate checks the field values on RegisterDeviceRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RegisterDeviceRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RegisterDeviceRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RegisterDeviceRequestMultiError, or nil if none found.
+func (m *RegisterDeviceRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RegisterDeviceRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for ExternalCustomerId
+
+	// no validation rules for TokenType
+
+	// no validation rules for Token
+
+	// no validation rules for Locale
+
+	if len(errors) > 0 {
+		return RegisterDeviceRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RegisterDeviceRequestMultiError is an error wrapping multiple validation
+// errors returned by RegisterDeviceRequest.ValidateAll() if the designated
+// constraints aren't met.
+type RegisterDeviceRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RegisterDeviceRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RegisterDeviceRequestMultiError) AllErrors() []error { return m }
+
+// RegisterDeviceRequestValidationError is the validation error returned by
+// RegisterDeviceRequest.Validate if the designated constraints aren't met.
+type RegisterDeviceRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegisterDeviceRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegisterDeviceRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegisterDeviceRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegisterDeviceRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegisterDeviceRequestValidationError) ErrorName() string {
+	return "RegisterDeviceRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RegisterDeviceRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegisterDeviceRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegisterDeviceRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegisterDeviceRequestValidationError{}
+
+// Validate checks the field values on RegisterDeviceResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RegisterDeviceResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RegisterDeviceResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RegisterDeviceResponseMultiError, or nil if none found.
+func (m *RegisterDeviceResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RegisterDeviceResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return RegisterDeviceResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// RegisterDeviceResponseMultiError is an error wrapping multiple validation
+// errors returned by RegisterDeviceResponse.ValidateAll() if the designated
+// constraints aren't met.
+type RegisterDeviceResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RegisterDeviceResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RegisterDeviceResponseMultiError) AllErrors() []error { return m }
+
+// RegisterDeviceResponseValidationError is the validation error returned by
+// RegisterDeviceResponse.Validate if the designated constraints aren't met.
+type RegisterDeviceResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegisterDeviceResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegisterDeviceResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegisterDeviceResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegisterDeviceResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegisterDeviceResponseValidationError) ErrorName() string {
+	return "RegisterDeviceResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RegisterDeviceResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegisterDeviceResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegisterDeviceResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegisterDeviceResponseValidationError{}
+
+// Validate checks the field values on UnregisterDeviceRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UnregisterDeviceRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UnregisterDeviceRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UnregisterDeviceRequestMultiError, or nil if none found.
+func (m *UnregisterDeviceRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UnregisterDeviceRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TokenType
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return UnregisterDeviceRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UnregisterDeviceRequestMultiError is an error wrapping multiple validation
+// errors returned by UnregisterDeviceRequest.ValidateAll() if the designated
+// constraints aren't met.
+type UnregisterDeviceRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UnregisterDeviceRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UnregisterDeviceRequestMultiError) AllErrors() []error { return m }
+
+// UnregisterDeviceRequestValidationError is the validation error returned by
+// UnregisterDeviceRequest.Validate if the designated constraints aren't met.
+type UnregisterDeviceRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UnregisterDeviceRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UnregisterDeviceRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UnregisterDeviceRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UnregisterDeviceRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UnregisterDeviceRequestValidationError) ErrorName() string {
+	return "UnregisterDeviceRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UnregisterDeviceRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUnregisterDeviceRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UnregisterDeviceRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UnregisterDeviceRequestValidationError{}
+
+// Validate checks the field values on UnregisterDeviceResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UnregisterDeviceResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UnregisterDeviceResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UnregisterDeviceResponseMultiError, or nil if none found.
+func (m *UnregisterDeviceResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UnregisterDeviceResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return UnregisterDeviceResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// UnregisterDeviceResponseMultiError is an error wrapping multiple validation
+// errors returned by UnregisterDeviceResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UnregisterDeviceResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UnregisterDeviceResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UnregisterDeviceResponseMultiError) AllErrors() []error { return m }
+
+// UnregisterDeviceResponseValidationError is the validation error returned by
+// UnregisterDeviceResponse.Validate if the designated constraints aren't met.
+type UnregisterDeviceResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UnregisterDeviceResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UnregisterDeviceResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UnregisterDeviceResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UnregisterDeviceResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UnregisterDeviceResponseValidationError) ErrorName() string {
+	return "UnregisterDeviceResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UnregisterDeviceResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUnregisterDeviceResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UnregisterDeviceResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UnregisterDeviceResponseValidationError{}
+
+// Validate checks the field values on DeviceSubscription with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeviceSubscription) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeviceSubscription with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeviceSubscriptionMultiError, or nil if none found.
+func (m *DeviceSubscription) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeviceSubscription) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TokenType
+
+	// no validation rules for Token
+
+	// no validation rules for Locale
+
+	if len(errors) > 0 {
+		return DeviceSubscriptionMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeviceSubscriptionMultiError is an error wrapping multiple validation errors
+// returned by DeviceSubscription.ValidateAll() if the designated constraints
+// aren't met.
+type DeviceSubscriptionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeviceSubscriptionMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeviceSubscriptionMultiError) AllErrors() []error { return m }
+
+// DeviceSubscriptionValidationError is the validation error returned by
+// DeviceSubscription.Validate if the designated constraints aren't met.
+type DeviceSubscriptionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeviceSubscriptionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeviceSubscriptionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeviceSubscriptionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeviceSubscriptionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeviceSubscriptionValidationError) ErrorName() string {
+	return "DeviceSubscriptionValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeviceSubscriptionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeviceSubscription.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeviceSubscriptionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeviceSubscriptionValidationError{}
+
+// Validate checks the field values on Contact with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Contact) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Contact with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in ContactMultiError, or nil if none found.
+func (m *Contact) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Contact) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for ExternalCustomerId
+
+	for idx, item := range m.GetDevices() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ContactValidationError{
+						field:  fmt.Sprintf("Devices[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ContactValidationError{
+						field:  fmt.Sprintf("Devices[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ContactValidationError{
+					field:  fmt.Sprintf("Devices[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Locale
+
+	// no validation rules for Timezone
+
+	if all {
+		switch v := interface{}(m.GetUpdatedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ContactValidationError{
+					field:  "UpdatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ContactValidationError{
+					field:  "UpdatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUpdatedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ContactValidationError{
+				field:  "UpdatedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ContactMultiError(errors)
+	}
+
+	return nil
+}
+
+// ContactMultiError is an error wrapping multiple validation errors returned
+// by Contact.ValidateAll() if the designated constraints aren't met.
+type ContactMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ContactMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ContactMultiError) AllErrors() []error { return m }
+
+// ContactValidationError is the validation error returned by Contact.Validate
+// if the designated constraints aren't met.
+type ContactValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ContactValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ContactValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ContactValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ContactValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ContactValidationError) ErrorName() string { return "ContactValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ContactValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sContact.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ContactValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ContactValidationError{}
+
+// Validate checks the field values on UpsertContactRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpsertContactRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpsertContactRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpsertContactRequestMultiError, or nil if none found.
+func (m *UpsertContactRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpsertContactRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetContact()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpsertContactRequestValidationError{
+					field:  "Contact",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpsertContactRequestValidationError{
+					field:  "Contact",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetContact()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpsertContactRequestValidationError{
+				field:  "Contact",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return UpsertContactRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpsertContactRequestMultiError is an error wrapping multiple validation
+// errors returned by UpsertContactRequest.ValidateAll() if the designated
+// constraints aren't met.
+type UpsertContactRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpsertContactRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpsertContactRequestMultiError) AllErrors() []error { return m }
+
+// UpsertContactRequestValidationError is the validation error returned by
+// UpsertContactRequest.Validate if the designated constraints aren't met.
+type UpsertContactRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpsertContactRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpsertContactRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpsertContactRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpsertContactRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpsertContactRequestValidationError) ErrorName() string {
+	return "UpsertContactRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpsertContactRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpsertContactRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpsertContactRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpsertContactRequestValidationError{}
+
+// Validate checks the field values on UpsertContactResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpsertContactResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpsertContactResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpsertContactResponseMultiError, or nil if none found.
+func (m *UpsertContactResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpsertContactResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return UpsertContactResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpsertContactResponseMultiError is an error wrapping multiple validation
+// errors returned by UpsertContactResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UpsertContactResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpsertContactResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpsertContactResponseMultiError) AllErrors() []error { return m }
+
+// UpsertContactResponseValidationError is the validation error returned by
+// UpsertContactResponse.Validate if the designated constraints aren't met.
+type UpsertContactResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpsertContactResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpsertContactResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpsertContactResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpsertContactResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpsertContactResponseValidationError) ErrorName() string {
+	return "UpsertContactResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpsertContactResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpsertContactResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpsertContactResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpsertContactResponseValidationError{}
+
+// Validate checks the field values on GetContactRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetContactRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetContactRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetContactRequestMultiError, or nil if none found.
+func (m *GetContactRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetContactRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for ExternalCustomerId
+
+	if len(errors) > 0 {
+		return GetContactRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetContactRequestMultiError is an error wrapping multiple validation errors
+// returned by GetContactRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetContactRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetContactRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetContactRequestMultiError) AllErrors() []error { return m }
+
+// GetContactRequestValidationError is the validation error returned by
+// GetContactRequest.Validate if the designated constraints aren't met.
+type GetContactRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetContactRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetContactRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetContactRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetContactRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetContactRequestValidationError) ErrorName() string {
+	return "GetContactRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetContactRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetContactRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetContactRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetContactRequestValidationError{}
+
+// Validate checks the field values on ImportContactsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ImportContactsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportContactsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportContactsResponseMultiError, or nil if none found.
+func (m *ImportContactsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportContactsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Imported
+
+	if len(errors) > 0 {
+		return ImportContactsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportContactsResponseMultiError is an error wrapping multiple validation
+// errors returned by ImportContactsResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ImportContactsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportContactsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportContactsResponseMultiError) AllErrors() []error { return m }
+
+// ImportContactsResponseValidationError is the validation error returned by
+// ImportContactsResponse.Validate if the designated constraints aren't met.
+type ImportContactsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportContactsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportContactsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportContactsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportContactsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportContactsResponseValidationError) ErrorName() string {
+	return "ImportContactsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportContactsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportContactsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportContactsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportContactsResponseValidationError{}
+
+// Validate checks the field values on DeleteRecipientDataRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteRecipientDataRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteRecipientDataRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteRecipientDataRequestMultiError, or nil if none found.
+func (m *DeleteRecipientDataRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteRecipientDataRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for ExternalCustomerId
+
+	// no validation rules for DryRun
+
+	if len(errors) > 0 {
+		return DeleteRecipientDataRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteRecipientDataRequestMultiError is an error wrapping multiple
+// validation errors returned by DeleteRecipientDataRequest.ValidateAll() if
+// the designated constraints aren't met.
+type DeleteRecipientDataRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteRecipientDataRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteRecipientDataRequestMultiError) AllErrors() []error { return m }
+
+// DeleteRecipientDataRequestValidationError is the validation error returned
+// by DeleteRecipientDataRequest.Validate if the designated constraints aren't met.
+type DeleteRecipientDataRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteRecipientDataRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteRecipientDataRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteRecipientDataRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteRecipientDataRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteRecipientDataRequestValidationError) ErrorName() string {
+	return "DeleteRecipientDataRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteRecipientDataRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteRecipientDataRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteRecipientDataRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteRecipientDataRequestValidationError{}
+
+// Validate checks the field values on DeleteRecipientDataResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteRecipientDataResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteRecipientDataResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteRecipientDataResponseMultiError, or nil if none found.
+func (m *DeleteRecipientDataResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteRecipientDataResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for ErasureId
+
+	// no validation rules for Communications
+
+	// no validation rules for ResponseChannels
+
+	// no validation rules for DeviceTokens
+
+	// no validation rules for DryRun
+
+	// no validation rules for Contacts
+
+	if len(errors) > 0 {
+		return DeleteRecipientDataResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteRecipientDataResponseMultiError is an error wrapping multiple
+// validation errors returned by DeleteRecipientDataResponse.ValidateAll() if
+// the designated constraints aren't met.
+type DeleteRecipientDataResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteRecipientDataResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteRecipientDataResponseMultiError) AllErrors() []error { return m }
+
+// DeleteRecipientDataResponseValidationError is the validation error returned
+// by DeleteRecipientDataResponse.Validate if the designated constraints
+// aren't met.
+type DeleteRecipientDataResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteRecipientDataResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteRecipientDataResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteRecipientDataResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteRecipientDataResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteRecipientDataResponseValidationError) ErrorName() string {
+	return "DeleteRecipientDataResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteRecipientDataResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteRecipientDataResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteRecipientDataResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteRecipientDataResponseValidationError{}
+
+// Validate checks the field values on AuditEvent with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *AuditEvent) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AuditEvent with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AuditEventMultiError, or
+// nil if none found.
+func (m *AuditEvent) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AuditEvent) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if all {
+		switch v := interface{}(m.GetOccurredAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AuditEventValidationError{
+					field:  "OccurredAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AuditEventValidationError{
+					field:  "OccurredAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetOccurredAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AuditEventValidationError{
+				field:  "OccurredAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Principal
+
+	// no validation rules for Peer
+
+	// no validation rules for Domain
+
+	// no validation rules for Method
+
+	// no validation rules for RequestHash
+
+	// no validation rules for Code
+
+	// no validation rules for WorkflowId
+
+	// no validation rules for ClaimedPrincipal
+
+	if len(errors) > 0 {
+		return AuditEventMultiError(errors)
+	}
+
+	return nil
+}
+
+// AuditEventMultiError is an error wrapping multiple validation errors
+// returned by AuditEvent.ValidateAll() if the designated constraints aren't met.
+type AuditEventMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AuditEventMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AuditEventMultiError) AllErrors() []error { return m }
+
+// AuditEventValidationError is the validation error returned by
+// AuditEvent.Validate if the designated constraints aren't met.
+type AuditEventValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AuditEventValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AuditEventValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AuditEventValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AuditEventValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AuditEventValidationError) ErrorName() string { return "AuditEventValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AuditEventValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAuditEvent.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AuditEventValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AuditEventValidationError{}
+
+// Validate checks the field values on ListAuditEventsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListAuditEventsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListAuditEventsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListAuditEventsRequestMultiError, or nil if none found.
+func (m *ListAuditEventsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListAuditEventsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Principal
+
+	// no validation rules for Domain
+
+	// no validation rules for Method
+
+	// no validation rules for Code
+
+	// no validation rules for WorkflowId
+
+	if all {
+		switch v := interface{}(m.GetStartTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ListAuditEventsRequestValidationError{
+					field:  "StartTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ListAuditEventsRequestValidationError{
+					field:  "StartTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStartTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ListAuditEventsRequestValidationError{
+				field:  "StartTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetEndTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ListAuditEventsRequestValidationError{
+					field:  "EndTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ListAuditEventsRequestValidationError{
+					field:  "EndTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetEndTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ListAuditEventsRequestValidationError{
+				field:  "EndTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for PageSize
+
+	// no validation rules for PageToken
+
+	if len(errors) > 0 {
+		return ListAuditEventsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListAuditEventsRequestMultiError is an error wrapping multiple validation
+// errors returned by ListAuditEventsRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ListAuditEventsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListAuditEventsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListAuditEventsRequestMultiError) AllErrors() []error { return m }
+
+// ListAuditEventsRequestValidationError is the validation error returned by
+// ListAuditEventsRequest.Validate if the designated constraints aren't met.
+type ListAuditEventsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListAuditEventsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListAuditEventsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListAuditEventsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListAuditEventsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListAuditEventsRequestValidationError) ErrorName() string {
+	return "ListAuditEventsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListAuditEventsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListAuditEventsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListAuditEventsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListAuditEventsRequestValidationError{}
+
+// Validate checks the field values on ListAuditEventsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListAuditEventsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListAuditEventsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListAuditEventsResponseMultiError, or nil if none found.
+func (m *ListAuditEventsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListAuditEventsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetEvents() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListAuditEventsResponseValidationError{
+						field:  fmt.Sprintf("Events[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListAuditEventsResponseValidationError{
+						field:  fmt.Sprintf("Events[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListAuditEventsResponseValidationError{
+					field:  fmt.Sprintf("Events[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
 			}
 		}
 
 	}
 
+	// no validation rules for NextPageToken
+
+	if len(errors) > 0 {
+		return ListAuditEventsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListAuditEventsResponseMultiError is an error wrapping multiple validation
+// errors returned by ListAuditEventsResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ListAuditEventsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListAuditEventsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListAuditEventsResponseMultiError) AllErrors() []error { return m }
+
+// ListAuditEventsResponseValidationError is the validation error returned by
+// ListAuditEventsResponse.Validate if the designated constraints aren't met.
+type ListAuditEventsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListAuditEventsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListAuditEventsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListAuditEventsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListAuditEventsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListAuditEventsResponseValidationError) ErrorName() string {
+	return "ListAuditEventsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListAuditEventsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListAuditEventsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListAuditEventsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListAuditEventsResponseValidationError{}
+
+// Validate checks the field values on Communication with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Communication) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Communication with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CommunicationMultiError, or
+// nil if none found.
+func (m *Communication) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Communication) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Domain
+
+	// no validation rules for Type
+
+	// no validation rules for Status
+
 	if all {
-		switch v := interface{}(m.GetEmail()).(type) {
+		switch v := interface{}(m.GetCreatedAt()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, SendCommunicationRequestValidationError{
-					field:  "Email",
+				errors = append(errors, CommunicationValidationError{
+					field:  "CreatedAt",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, SendCommunicationRequestValidationError{
-					field:  "Email",
+				errors = append(errors, CommunicationValidationError{
+					field:  "CreatedAt",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		}
-	} else if v, ok := interface{}(m.GetEmail()).(interface{ Validate() error }); ok {
+	} else if v, ok := interface{}(m.GetCreatedAt()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return SendCommunicationRequestValidationError{
-				field:  "Email",
+			return CommunicationValidationError{
+				field:  "CreatedAt",
 				reason: "embedded message failed validation",
 				cause:  err,
 			}
@@ -908,48 +4055,54 @@ func (m *SendCommunicationRequest) validate(all bool) error {
 	}
 
 	if all {
-		switch v := interface{}(m.GetPush()).(type) {
+		switch v := interface{}(m.GetSentAt()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, SendCommunicationRequestValidationError{
-					field:  "Push",
+				errors = append(errors, CommunicationValidationError{
+					field:  "SentAt",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, SendCommunicationRequestValidationError{
-					field:  "Push",
+				errors = append(errors, CommunicationValidationError{
+					field:  "SentAt",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		}
-	} else if v, ok := interface{}(m.GetPush()).(interface{ Validate() error }); ok {
+	} else if v, ok := interface{}(m.GetSentAt()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return SendCommunicationRequestValidationError{
-				field:  "Push",
+			return CommunicationValidationError{
+				field:  "SentAt",
 				reason: "embedded message failed validation",
 				cause:  err,
 			}
 		}
 	}
 
+	// no validation rules for ExternalId
+
+	// no validation rules for ErrorKind
+
+	// no validation rules for ErrorProvider
+
 	if len(errors) > 0 {
-		return SendCommunicationRequestMultiError(errors)
+		return CommunicationMultiError(errors)
 	}
 
 	return nil
 }
 
-// SendCommunicationRequestMultiError is an error wrapping multiple validation
-// errors returned by SendCommunicationRequest.ValidateAll() if the designated
-// constraints aren't met.
-type SendCommunicationRequestMultiError []error
+// CommunicationMultiError is an error wrapping multiple validation errors
+// returned by Communication.ValidateAll() if the designated constraints
+// aren't met.
+type CommunicationMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m SendCommunicationRequestMultiError) Error() string {
+func (m CommunicationMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -958,11 +4111,11 @@ func (m SendCommunicationRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m SendCommunicationRequestMultiError) AllErrors() []error { return m }
+func (m CommunicationMultiError) AllErrors() []error { return m }
 
-// SendCommunicationRequestValidationError is the validation error returned by
-// SendCommunicationRequest.Validate if the designated constraints aren't met.
-type SendCommunicationRequestValidationError struct {
+// CommunicationValidationError is the validation error returned by
+// Communication.Validate if the designated constraints aren't met.
+type CommunicationValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -970,24 +4123,22 @@ type SendCommunicationRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e SendCommunicationRequestValidationError) Field() string { return e.field }
+func (e CommunicationValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e SendCommunicationRequestValidationError) Reason() string { return e.reason }
+func (e CommunicationValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e SendCommunicationRequestValidationError) Cause() error { return e.cause }
+func (e CommunicationValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e SendCommunicationRequestValidationError) Key() bool { return e.key }
+func (e CommunicationValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e SendCommunicationRequestValidationError) ErrorName() string {
-	return "SendCommunicationRequestValidationError"
-}
+func (e CommunicationValidationError) ErrorName() string { return "CommunicationValidationError" }
 
 // Error satisfies the builtin error interface
-func (e SendCommunicationRequestValidationError) Error() string {
+func (e CommunicationValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -999,14 +4150,14 @@ func (e SendCommunicationRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sSendCommunicationRequest.%s: %s%s",
+		"invalid %sCommunication.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = SendCommunicationRequestValidationError{}
+var _ error = CommunicationValidationError{}
 
 var _ interface {
 	Field() string
@@ -1014,24 +4165,24 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = SendCommunicationRequestValidationError{}
+} = CommunicationValidationError{}
 
-// Validate checks the field values on StreamCommunicationRequest with the
-// rules defined in the proto definition for this message. If any rules are
+// Validate checks the field values on ListCommunicationsRequest with the rules
+// defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *StreamCommunicationRequest) Validate() error {
+func (m *ListCommunicationsRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on StreamCommunicationRequest with the
+// ValidateAll checks the field values on ListCommunicationsRequest with the
 // rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// StreamCommunicationRequestMultiError, or nil if none found.
-func (m *StreamCommunicationRequest) ValidateAll() error {
+// ListCommunicationsRequestMultiError, or nil if none found.
+func (m *ListCommunicationsRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *StreamCommunicationRequest) validate(all bool) error {
+func (m *ListCommunicationsRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
@@ -1040,29 +4191,31 @@ func (m *StreamCommunicationRequest) validate(all bool) error {
 
 	// no validation rules for Domain
 
+	// no validation rules for Status
+
 	if all {
-		switch v := interface{}(m.GetEmail()).(type) {
+		switch v := interface{}(m.GetStartTime()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, StreamCommunicationRequestValidationError{
-					field:  "Email",
+				errors = append(errors, ListCommunicationsRequestValidationError{
+					field:  "StartTime",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, StreamCommunicationRequestValidationError{
-					field:  "Email",
+				errors = append(errors, ListCommunicationsRequestValidationError{
+					field:  "StartTime",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		}
-	} else if v, ok := interface{}(m.GetEmail()).(interface{ Validate() error }); ok {
+	} else if v, ok := interface{}(m.GetStartTime()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return StreamCommunicationRequestValidationError{
-				field:  "Email",
+			return ListCommunicationsRequestValidationError{
+				field:  "StartTime",
 				reason: "embedded message failed validation",
 				cause:  err,
 			}
@@ -1070,48 +4223,52 @@ func (m *StreamCommunicationRequest) validate(all bool) error {
 	}
 
 	if all {
-		switch v := interface{}(m.GetPush()).(type) {
+		switch v := interface{}(m.GetEndTime()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, StreamCommunicationRequestValidationError{
-					field:  "Push",
+				errors = append(errors, ListCommunicationsRequestValidationError{
+					field:  "EndTime",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, StreamCommunicationRequestValidationError{
-					field:  "Push",
+				errors = append(errors, ListCommunicationsRequestValidationError{
+					field:  "EndTime",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		}
-	} else if v, ok := interface{}(m.GetPush()).(interface{ Validate() error }); ok {
+	} else if v, ok := interface{}(m.GetEndTime()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return StreamCommunicationRequestValidationError{
-				field:  "Push",
+			return ListCommunicationsRequestValidationError{
+				field:  "EndTime",
 				reason: "embedded message failed validation",
 				cause:  err,
 			}
 		}
 	}
 
+	// no validation rules for PageSize
+
+	// no validation rules for PageToken
+
 	if len(errors) > 0 {
-		return StreamCommunicationRequestMultiError(errors)
+		return ListCommunicationsRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// StreamCommunicationRequestMultiError is an error wrapping multiple
-// validation errors returned by StreamCommunicationRequest.ValidateAll() if
-// the designated constraints aren't met.
-type StreamCommunicationRequestMultiError []error
+// ListCommunicationsRequestMultiError is an error wrapping multiple validation
+// errors returned by ListCommunicationsRequest.ValidateAll() if the
+// designated constraints aren't met.
+type ListCommunicationsRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m StreamCommunicationRequestMultiError) Error() string {
+func (m ListCommunicationsRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1120,11 +4277,11 @@ func (m StreamCommunicationRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m StreamCommunicationRequestMultiError) AllErrors() []error { return m }
+func (m ListCommunicationsRequestMultiError) AllErrors() []error { return m }
 
-// StreamCommunicationRequestValidationError is the validation error returned
-// by StreamCommunicationRequest.Validate if the designated constraints aren't met.
-type StreamCommunicationRequestValidationError struct {
+// ListCommunicationsRequestValidationError is the validation error returned by
+// ListCommunicationsRequest.Validate if the designated constraints aren't met.
+type ListCommunicationsRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1132,24 +4289,24 @@ type StreamCommunicationRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e StreamCommunicationRequestValidationError) Field() string { return e.field }
+func (e ListCommunicationsRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e StreamCommunicationRequestValidationError) Reason() string { return e.reason }
+func (e ListCommunicationsRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e StreamCommunicationRequestValidationError) Cause() error { return e.cause }
+func (e ListCommunicationsRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e StreamCommunicationRequestValidationError) Key() bool { return e.key }
+func (e ListCommunicationsRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e StreamCommunicationRequestValidationError) ErrorName() string {
-	return "StreamCommunicationRequestValidationError"
+func (e ListCommunicationsRequestValidationError) ErrorName() string {
+	return "ListCommunicationsRequestValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e StreamCommunicationRequestValidationError) Error() string {
+func (e ListCommunicationsRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1161,14 +4318,14 @@ func (e StreamCommunicationRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sStreamCommunicationRequest.%s: %s%s",
+		"invalid %sListCommunicationsRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = StreamCommunicationRequestValidationError{}
+var _ error = ListCommunicationsRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -1176,46 +4333,80 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = StreamCommunicationRequestValidationError{}
+} = ListCommunicationsRequestValidationError{}
 
-// Validate checks the field values on SendCommunicationResponse with the rules
-// defined in the proto definition for this message. If any rules are
+// Validate checks the field values on ListCommunicationsResponse with the
+// rules defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *SendCommunicationResponse) Validate() error {
+func (m *ListCommunicationsResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on SendCommunicationResponse with the
+// ValidateAll checks the field values on ListCommunicationsResponse with the
 // rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// SendCommunicationResponseMultiError, or nil if none found.
-func (m *SendCommunicationResponse) ValidateAll() error {
+// ListCommunicationsResponseMultiError, or nil if none found.
+func (m *ListCommunicationsResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *SendCommunicationResponse) validate(all bool) error {
+func (m *ListCommunicationsResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Id
+	for idx, item := range m.GetCommunications() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListCommunicationsResponseValidationError{
+						field:  fmt.Sprintf("Communications[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListCommunicationsResponseValidationError{
+						field:  fmt.Sprintf("Communications[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListCommunicationsResponseValidationError{
+					field:  fmt.Sprintf("Communications[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for NextPageToken
 
 	if len(errors) > 0 {
-		return SendCommunicationResponseMultiError(errors)
+		return ListCommunicationsResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// SendCommunicationResponseMultiError is an error wrapping multiple validation
-// errors returned by SendCommunicationResponse.ValidateAll() if the
-// designated constraints aren't met.
-type SendCommunicationResponseMultiError []error
+// ListCommunicationsResponseMultiError is an error wrapping multiple
+// validation errors returned by ListCommunicationsResponse.ValidateAll() if
+// the designated constraints aren't met.
+type ListCommunicationsResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m SendCommunicationResponseMultiError) Error() string {
+func (m ListCommunicationsResponseMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1224,11 +4415,11 @@ func (m SendCommunicationResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m SendCommunicationResponseMultiError) AllErrors() []error { return m }
+func (m ListCommunicationsResponseMultiError) AllErrors() []error { return m }
 
-// SendCommunicationResponseValidationError is the validation error returned by
-// SendCommunicationResponse.Validate if the designated constraints aren't met.
-type SendCommunicationResponseValidationError struct {
+// ListCommunicationsResponseValidationError is the validation error returned
+// by ListCommunicationsResponse.Validate if the designated constraints aren't met.
+type ListCommunicationsResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1236,24 +4427,24 @@ type SendCommunicationResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e SendCommunicationResponseValidationError) Field() string { return e.field }
+func (e ListCommunicationsResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e SendCommunicationResponseValidationError) Reason() string { return e.reason }
+func (e ListCommunicationsResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e SendCommunicationResponseValidationError) Cause() error { return e.cause }
+func (e ListCommunicationsResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e SendCommunicationResponseValidationError) Key() bool { return e.key }
+func (e ListCommunicationsResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e SendCommunicationResponseValidationError) ErrorName() string {
-	return "SendCommunicationResponseValidationError"
+func (e ListCommunicationsResponseValidationError) ErrorName() string {
+	return "ListCommunicationsResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e SendCommunicationResponseValidationError) Error() string {
+func (e ListCommunicationsResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1265,14 +4456,14 @@ func (e SendCommunicationResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sSendCommunicationResponse.%s: %s%s",
+		"invalid %sListCommunicationsResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = SendCommunicationResponseValidationError{}
+var _ error = ListCommunicationsResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1280,24 +4471,24 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = SendCommunicationResponseValidationError{}
+} = ListCommunicationsResponseValidationError{}
 
-// Validate checks the field values on StreamCommunicationResponse with the
+// Validate checks the field values on CancelCommunicationRequest with the
 // rules defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *StreamCommunicationResponse) Validate() error {
+func (m *CancelCommunicationRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on StreamCommunicationResponse with the
+// ValidateAll checks the field values on CancelCommunicationRequest with the
 // rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// StreamCommunicationResponseMultiError, or nil if none found.
-func (m *StreamCommunicationResponse) ValidateAll() error {
+// CancelCommunicationRequestMultiError, or nil if none found.
+func (m *CancelCommunicationRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *StreamCommunicationResponse) validate(all bool) error {
+func (m *CancelCommunicationRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
@@ -1307,19 +4498,19 @@ func (m *StreamCommunicationResponse) validate(all bool) error {
 	// no validation rules for Id
 
 	if len(errors) > 0 {
-		return StreamCommunicationResponseMultiError(errors)
+		return CancelCommunicationRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// StreamCommunicationResponseMultiError is an error wrapping multiple
-// validation errors returned by StreamCommunicationResponse.ValidateAll() if
+// CancelCommunicationRequestMultiError is an error wrapping multiple
+// validation errors returned by CancelCommunicationRequest.ValidateAll() if
 // the designated constraints aren't met.
-type StreamCommunicationResponseMultiError []error
+type CancelCommunicationRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m StreamCommunicationResponseMultiError) Error() string {
+func (m CancelCommunicationRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1328,12 +4519,11 @@ func (m StreamCommunicationResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m StreamCommunicationResponseMultiError) AllErrors() []error { return m }
+func (m CancelCommunicationRequestMultiError) AllErrors() []error { return m }
 
-// StreamCommunicationResponseValidationError is the validation error returned
-// by StreamCommunicationResponse.Validate if the designated constraints
-// aren't met.
-type StreamCommunicationResponseValidationError struct {
+// CancelCommunicationRequestValidationError is the validation error returned
+// by CancelCommunicationRequest.Validate if the designated constraints aren't met.
+type CancelCommunicationRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1341,24 +4531,24 @@ type StreamCommunicationResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e StreamCommunicationResponseValidationError) Field() string { return e.field }
+func (e CancelCommunicationRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e StreamCommunicationResponseValidationError) Reason() string { return e.reason }
+func (e CancelCommunicationRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e StreamCommunicationResponseValidationError) Cause() error { return e.cause }
+func (e CancelCommunicationRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e StreamCommunicationResponseValidationError) Key() bool { return e.key }
+func (e CancelCommunicationRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e StreamCommunicationResponseValidationError) ErrorName() string {
-	return "StreamCommunicationResponseValidationError"
+func (e CancelCommunicationRequestValidationError) ErrorName() string {
+	return "CancelCommunicationRequestValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e StreamCommunicationResponseValidationError) Error() string {
+func (e CancelCommunicationRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1370,14 +4560,14 @@ func (e StreamCommunicationResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sStreamCommunicationResponse.%s: %s%s",
+		"invalid %sCancelCommunicationRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = StreamCommunicationResponseValidationError{}
+var _ error = CancelCommunicationRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -1385,24 +4575,24 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = StreamCommunicationResponseValidationError{}
+} = CancelCommunicationRequestValidationError{}
 
-// Validate checks the field values on GetStatusRequest with the rules defined
-// in the proto definition for this message. If any rules are violated, the
-// first error encountered is returned, or nil if there are no violations.
-func (m *GetStatusRequest) Validate() error {
+// Validate checks the field values on CancelCommunicationResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CancelCommunicationResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on GetStatusRequest with the rules
-// defined in the proto definition for this message. If any rules are
+// ValidateAll checks the field values on CancelCommunicationResponse with the
+// rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// GetStatusRequestMultiError, or nil if none found.
-func (m *GetStatusRequest) ValidateAll() error {
+// CancelCommunicationResponseMultiError, or nil if none found.
+func (m *CancelCommunicationResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *GetStatusRequest) validate(all bool) error {
+func (m *CancelCommunicationResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
@@ -1412,19 +4602,19 @@ func (m *GetStatusRequest) validate(all bool) error {
 	// no validation rules for Id
 
 	if len(errors) > 0 {
-		return GetStatusRequestMultiError(errors)
+		return CancelCommunicationResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// GetStatusRequestMultiError is an error wrapping multiple validation errors
-// returned by GetStatusRequest.ValidateAll() if the designated constraints
-// aren't met.
-type GetStatusRequestMultiError []error
+// CancelCommunicationResponseMultiError is an error wrapping multiple
+// validation errors returned by CancelCommunicationResponse.ValidateAll() if
+// the designated constraints aren't met.
+type CancelCommunicationResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m GetStatusRequestMultiError) Error() string {
+func (m CancelCommunicationResponseMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1433,11 +4623,12 @@ func (m GetStatusRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m GetStatusRequestMultiError) AllErrors() []error { return m }
+func (m CancelCommunicationResponseMultiError) AllErrors() []error { return m }
 
-// GetStatusRequestValidationError is the validation error returned by
-// GetStatusRequest.Validate if the designated constraints aren't met.
-type GetStatusRequestValidationError struct {
+// CancelCommunicationResponseValidationError is the validation error returned
+// by CancelCommunicationResponse.Validate if the designated constraints
+// aren't met.
+type CancelCommunicationResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1445,22 +4636,24 @@ type GetStatusRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e GetStatusRequestValidationError) Field() string { return e.field }
+func (e CancelCommunicationResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e GetStatusRequestValidationError) Reason() string { return e.reason }
+func (e CancelCommunicationResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e GetStatusRequestValidationError) Cause() error { return e.cause }
+func (e CancelCommunicationResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e GetStatusRequestValidationError) Key() bool { return e.key }
+func (e CancelCommunicationResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e GetStatusRequestValidationError) ErrorName() string { return "GetStatusRequestValidationError" }
+func (e CancelCommunicationResponseValidationError) ErrorName() string {
+	return "CancelCommunicationResponseValidationError"
+}
 
 // Error satisfies the builtin error interface
-func (e GetStatusRequestValidationError) Error() string {
+func (e CancelCommunicationResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1472,14 +4665,14 @@ func (e GetStatusRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sGetStatusRequest.%s: %s%s",
+		"invalid %sCancelCommunicationResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = GetStatusRequestValidationError{}
+var _ error = CancelCommunicationResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1487,46 +4680,48 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = GetStatusRequestValidationError{}
+} = CancelCommunicationResponseValidationError{}
 
-// Validate checks the field values on GetStatusResponse with the rules defined
-// in the proto definition for this message. If any rules are violated, the
-// first error encountered is returned, or nil if there are no violations.
-func (m *GetStatusResponse) Validate() error {
+// Validate checks the field values on RenderCommunicationResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RenderCommunicationResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on GetStatusResponse with the rules
-// defined in the proto definition for this message. If any rules are
+// ValidateAll checks the field values on RenderCommunicationResponse with the
+// rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// GetStatusResponseMultiError, or nil if none found.
-func (m *GetStatusResponse) ValidateAll() error {
+// RenderCommunicationResponseMultiError, or nil if none found.
+func (m *RenderCommunicationResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *GetStatusResponse) validate(all bool) error {
+func (m *RenderCommunicationResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Status
+	// no validation rules for EmailMime
+
+	// no validation rules for PushPayload
 
 	if len(errors) > 0 {
-		return GetStatusResponseMultiError(errors)
+		return RenderCommunicationResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// GetStatusResponseMultiError is an error wrapping multiple validation errors
-// returned by GetStatusResponse.ValidateAll() if the designated constraints
-// aren't met.
-type GetStatusResponseMultiError []error
+// RenderCommunicationResponseMultiError is an error wrapping multiple
+// validation errors returned by RenderCommunicationResponse.ValidateAll() if
+// the designated constraints aren't met.
+type RenderCommunicationResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m GetStatusResponseMultiError) Error() string {
+func (m RenderCommunicationResponseMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1535,11 +4730,12 @@ func (m GetStatusResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m GetStatusResponseMultiError) AllErrors() []error { return m }
+func (m RenderCommunicationResponseMultiError) AllErrors() []error { return m }
 
-// GetStatusResponseValidationError is the validation error returned by
-// GetStatusResponse.Validate if the designated constraints aren't met.
-type GetStatusResponseValidationError struct {
+// RenderCommunicationResponseValidationError is the validation error returned
+// by RenderCommunicationResponse.Validate if the designated constraints
+// aren't met.
+type RenderCommunicationResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1547,24 +4743,24 @@ type GetStatusResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e GetStatusResponseValidationError) Field() string { return e.field }
+func (e RenderCommunicationResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e GetStatusResponseValidationError) Reason() string { return e.reason }
+func (e RenderCommunicationResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e GetStatusResponseValidationError) Cause() error { return e.cause }
+func (e RenderCommunicationResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e GetStatusResponseValidationError) Key() bool { return e.key }
+func (e RenderCommunicationResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e GetStatusResponseValidationError) ErrorName() string {
-	return "GetStatusResponseValidationError"
+func (e RenderCommunicationResponseValidationError) ErrorName() string {
+	return "RenderCommunicationResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e GetStatusResponseValidationError) Error() string {
+func (e RenderCommunicationResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1576,14 +4772,14 @@ func (e GetStatusResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sGetStatusResponse.%s: %s%s",
+		"invalid %sRenderCommunicationResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = GetStatusResponseValidationError{}
+var _ error = RenderCommunicationResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1591,4 +4787,4 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = GetStatusResponseValidationError{}
+} = RenderCommunicationResponseValidationError{}
@@ -20,8 +20,19 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	UnicomService_SendCommunication_FullMethodName   = "/unicom.api.v1.UnicomService/SendCommunication"
+	UnicomService_RenderCommunication_FullMethodName = "/unicom.api.v1.UnicomService/RenderCommunication"
 	UnicomService_StreamCommunication_FullMethodName = "/unicom.api.v1.UnicomService/StreamCommunication"
 	UnicomService_GetStatus_FullMethodName           = "/unicom.api.v1.UnicomService/GetStatus"
+	UnicomService_ListCommunications_FullMethodName  = "/unicom.api.v1.UnicomService/ListCommunications"
+	UnicomService_CancelCommunication_FullMethodName = "/unicom.api.v1.UnicomService/CancelCommunication"
+	UnicomService_RegisterDevice_FullMethodName      = "/unicom.api.v1.UnicomService/RegisterDevice"
+	UnicomService_UnregisterDevice_FullMethodName    = "/unicom.api.v1.UnicomService/UnregisterDevice"
+	UnicomService_DeleteRecipientData_FullMethodName = "/unicom.api.v1.UnicomService/DeleteRecipientData"
+	UnicomService_UpsertContact_FullMethodName       = "/unicom.api.v1.UnicomService/UpsertContact"
+	UnicomService_ImportContacts_FullMethodName      = "/unicom.api.v1.UnicomService/ImportContacts"
+	UnicomService_GetContact_FullMethodName          = "/unicom.api.v1.UnicomService/GetContact"
+	UnicomService_ListAuditEvents_FullMethodName     = "/unicom.api.v1.UnicomService/ListAuditEvents"
+	UnicomService_ExportAuditEvents_FullMethodName   = "/unicom.api.v1.UnicomService/ExportAuditEvents"
 )
 
 // UnicomServiceClient is the client API for UnicomService service.
@@ -33,10 +44,36 @@ type UnicomServiceClient interface {
 	// Sends a communication (email or push notification).
 	// Returns the workflow ID for tracking.
 	SendCommunication(ctx context.Context, in *SendCommunicationRequest, opts ...grpc.CallOption) (*SendCommunicationResponse, error)
+	// Renders a communication as SendCommunication would send it, without
+	// sending it or recording it.
+	RenderCommunication(ctx context.Context, in *SendCommunicationRequest, opts ...grpc.CallOption) (*RenderCommunicationResponse, error)
 	// Bidirectional streaming endpoint for sending and receiving communications.
 	StreamCommunication(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamCommunicationRequest, StreamCommunicationResponse], error)
 	// Gets the status of a communication workflow by ID.
 	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// Lists communications matching a filter, newest first.
+	ListCommunications(ctx context.Context, in *ListCommunicationsRequest, opts ...grpc.CallOption) (*ListCommunicationsResponse, error)
+	// Cancels a communication which is waiting to be sent, e.g. one scheduled
+	// with send_at. A communication already sent is left as it is.
+	CancelCommunication(ctx context.Context, in *CancelCommunicationRequest, opts ...grpc.CallOption) (*CancelCommunicationResponse, error)
+	// Registers a device token used by the direct FCM and APNs push providers.
+	RegisterDevice(ctx context.Context, in *RegisterDeviceRequest, opts ...grpc.CallOption) (*RegisterDeviceResponse, error)
+	// Removes a previously registered device token.
+	UnregisterDevice(ctx context.Context, in *UnregisterDeviceRequest, opts ...grpc.CallOption) (*UnregisterDeviceResponse, error)
+	// Erases a recipient's data: redacts the communications sent to them and
+	// deletes their content and device tokens.
+	DeleteRecipientData(ctx context.Context, in *DeleteRecipientDataRequest, opts ...grpc.CallOption) (*DeleteRecipientDataResponse, error)
+	// Creates or replaces a contact in the contact directory.
+	UpsertContact(ctx context.Context, in *UpsertContactRequest, opts ...grpc.CallOption) (*UpsertContactResponse, error)
+	// Creates or replaces every contact sent on the stream.
+	ImportContacts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Contact, ImportContactsResponse], error)
+	// Looks up a contact through the configured contact provider.
+	GetContact(ctx context.Context, in *GetContactRequest, opts ...grpc.CallOption) (*Contact, error)
+	// Lists audit events matching a filter, newest first.
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// Streams every audit event matching a filter, newest first, ignoring paging.
+	// Over HTTP, GET /unicom/v1/audit-events:export returns them as NDJSON.
+	ExportAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AuditEvent], error)
 }
 
 type unicomServiceClient struct {
@@ -57,6 +94,16 @@ func (c *unicomServiceClient) SendCommunication(ctx context.Context, in *SendCom
 	return out, nil
 }
 
+func (c *unicomServiceClient) RenderCommunication(ctx context.Context, in *SendCommunicationRequest, opts ...grpc.CallOption) (*RenderCommunicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenderCommunicationResponse)
+	err := c.cc.Invoke(ctx, UnicomService_RenderCommunication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *unicomServiceClient) StreamCommunication(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamCommunicationRequest, StreamCommunicationResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &UnicomService_ServiceDesc.Streams[0], UnicomService_StreamCommunication_FullMethodName, cOpts...)
@@ -80,6 +127,118 @@ func (c *unicomServiceClient) GetStatus(ctx context.Context, in *GetStatusReques
 	return out, nil
 }
 
+func (c *unicomServiceClient) ListCommunications(ctx context.Context, in *ListCommunicationsRequest, opts ...grpc.CallOption) (*ListCommunicationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCommunicationsResponse)
+	err := c.cc.Invoke(ctx, UnicomService_ListCommunications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) CancelCommunication(ctx context.Context, in *CancelCommunicationRequest, opts ...grpc.CallOption) (*CancelCommunicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelCommunicationResponse)
+	err := c.cc.Invoke(ctx, UnicomService_CancelCommunication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) RegisterDevice(ctx context.Context, in *RegisterDeviceRequest, opts ...grpc.CallOption) (*RegisterDeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterDeviceResponse)
+	err := c.cc.Invoke(ctx, UnicomService_RegisterDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) UnregisterDevice(ctx context.Context, in *UnregisterDeviceRequest, opts ...grpc.CallOption) (*UnregisterDeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnregisterDeviceResponse)
+	err := c.cc.Invoke(ctx, UnicomService_UnregisterDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) DeleteRecipientData(ctx context.Context, in *DeleteRecipientDataRequest, opts ...grpc.CallOption) (*DeleteRecipientDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRecipientDataResponse)
+	err := c.cc.Invoke(ctx, UnicomService_DeleteRecipientData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) UpsertContact(ctx context.Context, in *UpsertContactRequest, opts ...grpc.CallOption) (*UpsertContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertContactResponse)
+	err := c.cc.Invoke(ctx, UnicomService_UpsertContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) ImportContacts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Contact, ImportContactsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UnicomService_ServiceDesc.Streams[1], UnicomService_ImportContacts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Contact, ImportContactsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UnicomService_ImportContactsClient = grpc.ClientStreamingClient[Contact, ImportContactsResponse]
+
+func (c *unicomServiceClient) GetContact(ctx context.Context, in *GetContactRequest, opts ...grpc.CallOption) (*Contact, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Contact)
+	err := c.cc.Invoke(ctx, UnicomService_GetContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, UnicomService_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unicomServiceClient) ExportAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AuditEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UnicomService_ServiceDesc.Streams[2], UnicomService_ExportAuditEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListAuditEventsRequest, AuditEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UnicomService_ExportAuditEventsClient = grpc.ServerStreamingClient[AuditEvent]
+
 // UnicomServiceServer is the server API for UnicomService service.
 // All implementations should embed UnimplementedUnicomServiceServer
 // for forward compatibility.
@@ -89,10 +248,36 @@ type UnicomServiceServer interface {
 	// Sends a communication (email or push notification).
 	// Returns the workflow ID for tracking.
 	SendCommunication(context.Context, *SendCommunicationRequest) (*SendCommunicationResponse, error)
+	// Renders a communication as SendCommunication would send it, without
+	// sending it or recording it.
+	RenderCommunication(context.Context, *SendCommunicationRequest) (*RenderCommunicationResponse, error)
 	// Bidirectional streaming endpoint for sending and receiving communications.
 	StreamCommunication(grpc.BidiStreamingServer[StreamCommunicationRequest, StreamCommunicationResponse]) error
 	// Gets the status of a communication workflow by ID.
 	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// Lists communications matching a filter, newest first.
+	ListCommunications(context.Context, *ListCommunicationsRequest) (*ListCommunicationsResponse, error)
+	// Cancels a communication which is waiting to be sent, e.g. one scheduled
+	// with send_at. A communication already sent is left as it is.
+	CancelCommunication(context.Context, *CancelCommunicationRequest) (*CancelCommunicationResponse, error)
+	// Registers a device token used by the direct FCM and APNs push providers.
+	RegisterDevice(context.Context, *RegisterDeviceRequest) (*RegisterDeviceResponse, error)
+	// Removes a previously registered device token.
+	UnregisterDevice(context.Context, *UnregisterDeviceRequest) (*UnregisterDeviceResponse, error)
+	// Erases a recipient's data: redacts the communications sent to them and
+	// deletes their content and device tokens.
+	DeleteRecipientData(context.Context, *DeleteRecipientDataRequest) (*DeleteRecipientDataResponse, error)
+	// Creates or replaces a contact in the contact directory.
+	UpsertContact(context.Context, *UpsertContactRequest) (*UpsertContactResponse, error)
+	// Creates or replaces every contact sent on the stream.
+	ImportContacts(grpc.ClientStreamingServer[Contact, ImportContactsResponse]) error
+	// Looks up a contact through the configured contact provider.
+	GetContact(context.Context, *GetContactRequest) (*Contact, error)
+	// Lists audit events matching a filter, newest first.
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	// Streams every audit event matching a filter, newest first, ignoring paging.
+	// Over HTTP, GET /unicom/v1/audit-events:export returns them as NDJSON.
+	ExportAuditEvents(*ListAuditEventsRequest, grpc.ServerStreamingServer[AuditEvent]) error
 }
 
 // UnimplementedUnicomServiceServer should be embedded to have
@@ -105,12 +290,45 @@ type UnimplementedUnicomServiceServer struct{}
 func (UnimplementedUnicomServiceServer) SendCommunication(context.Context, *SendCommunicationRequest) (*SendCommunicationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendCommunication not implemented")
 }
+func (UnimplementedUnicomServiceServer) RenderCommunication(context.Context, *SendCommunicationRequest) (*RenderCommunicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderCommunication not implemented")
+}
 func (UnimplementedUnicomServiceServer) StreamCommunication(grpc.BidiStreamingServer[StreamCommunicationRequest, StreamCommunicationResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamCommunication not implemented")
 }
 func (UnimplementedUnicomServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
 }
+func (UnimplementedUnicomServiceServer) ListCommunications(context.Context, *ListCommunicationsRequest) (*ListCommunicationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCommunications not implemented")
+}
+func (UnimplementedUnicomServiceServer) CancelCommunication(context.Context, *CancelCommunicationRequest) (*CancelCommunicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCommunication not implemented")
+}
+func (UnimplementedUnicomServiceServer) RegisterDevice(context.Context, *RegisterDeviceRequest) (*RegisterDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterDevice not implemented")
+}
+func (UnimplementedUnicomServiceServer) UnregisterDevice(context.Context, *UnregisterDeviceRequest) (*UnregisterDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterDevice not implemented")
+}
+func (UnimplementedUnicomServiceServer) DeleteRecipientData(context.Context, *DeleteRecipientDataRequest) (*DeleteRecipientDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRecipientData not implemented")
+}
+func (UnimplementedUnicomServiceServer) UpsertContact(context.Context, *UpsertContactRequest) (*UpsertContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertContact not implemented")
+}
+func (UnimplementedUnicomServiceServer) ImportContacts(grpc.ClientStreamingServer[Contact, ImportContactsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportContacts not implemented")
+}
+func (UnimplementedUnicomServiceServer) GetContact(context.Context, *GetContactRequest) (*Contact, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContact not implemented")
+}
+func (UnimplementedUnicomServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedUnicomServiceServer) ExportAuditEvents(*ListAuditEventsRequest, grpc.ServerStreamingServer[AuditEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportAuditEvents not implemented")
+}
 func (UnimplementedUnicomServiceServer) testEmbeddedByValue() {}
 
 // UnsafeUnicomServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -149,6 +367,24 @@ func _UnicomService_SendCommunication_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UnicomService_RenderCommunication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCommunicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).RenderCommunication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_RenderCommunication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).RenderCommunication(ctx, req.(*SendCommunicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UnicomService_StreamCommunication_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(UnicomServiceServer).StreamCommunication(&grpc.GenericServerStream[StreamCommunicationRequest, StreamCommunicationResponse]{ServerStream: stream})
 }
@@ -174,6 +410,168 @@ func _UnicomService_GetStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UnicomService_ListCommunications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommunicationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).ListCommunications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_ListCommunications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).ListCommunications(ctx, req.(*ListCommunicationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_CancelCommunication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCommunicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).CancelCommunication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_CancelCommunication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).CancelCommunication(ctx, req.(*CancelCommunicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_RegisterDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).RegisterDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_RegisterDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).RegisterDevice(ctx, req.(*RegisterDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_UnregisterDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).UnregisterDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_UnregisterDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).UnregisterDevice(ctx, req.(*UnregisterDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_DeleteRecipientData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRecipientDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).DeleteRecipientData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_DeleteRecipientData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).DeleteRecipientData(ctx, req.(*DeleteRecipientDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_UpsertContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).UpsertContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_UpsertContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).UpsertContact(ctx, req.(*UpsertContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_ImportContacts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UnicomServiceServer).ImportContacts(&grpc.GenericServerStream[Contact, ImportContactsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UnicomService_ImportContactsServer = grpc.ClientStreamingServer[Contact, ImportContactsResponse]
+
+func _UnicomService_GetContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).GetContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_GetContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).GetContact(ctx, req.(*GetContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnicomServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UnicomService_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnicomServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnicomService_ExportAuditEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListAuditEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UnicomServiceServer).ExportAuditEvents(m, &grpc.GenericServerStream[ListAuditEventsRequest, AuditEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UnicomService_ExportAuditEventsServer = grpc.ServerStreamingServer[AuditEvent]
+
 // UnicomService_ServiceDesc is the grpc.ServiceDesc for UnicomService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -185,10 +583,46 @@ var UnicomService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendCommunication",
 			Handler:    _UnicomService_SendCommunication_Handler,
 		},
+		{
+			MethodName: "RenderCommunication",
+			Handler:    _UnicomService_RenderCommunication_Handler,
+		},
 		{
 			MethodName: "GetStatus",
 			Handler:    _UnicomService_GetStatus_Handler,
 		},
+		{
+			MethodName: "ListCommunications",
+			Handler:    _UnicomService_ListCommunications_Handler,
+		},
+		{
+			MethodName: "CancelCommunication",
+			Handler:    _UnicomService_CancelCommunication_Handler,
+		},
+		{
+			MethodName: "RegisterDevice",
+			Handler:    _UnicomService_RegisterDevice_Handler,
+		},
+		{
+			MethodName: "UnregisterDevice",
+			Handler:    _UnicomService_UnregisterDevice_Handler,
+		},
+		{
+			MethodName: "DeleteRecipientData",
+			Handler:    _UnicomService_DeleteRecipientData_Handler,
+		},
+		{
+			MethodName: "UpsertContact",
+			Handler:    _UnicomService_UpsertContact_Handler,
+		},
+		{
+			MethodName: "GetContact",
+			Handler:    _UnicomService_GetContact_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _UnicomService_ListAuditEvents_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -197,6 +631,16 @@ var UnicomService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ImportContacts",
+			Handler:       _UnicomService_ImportContacts_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportAuditEvents",
+			Handler:       _UnicomService_ExportAuditEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "unicom/api/v1/service.proto",
 }
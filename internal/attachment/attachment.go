@@ -0,0 +1,308 @@
+// Package attachment fetches email attachments given by URL on the worker,
+// within limits on where they come from, how large they are and what they
+// contain.
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+// provider names attachment fetches in failure details.
+const provider = "attachment"
+
+const maxRedirects = 5
+
+// Config limits the attachments which may be sent.
+type Config struct {
+	// Schemes are the URL schemes attachments may be fetched with, out of
+	// http, https and s3.
+	Schemes []string
+	// Hosts are the hosts, or S3 buckets, attachments may be fetched from. An
+	// entry starting with a dot allows every subdomain. Empty allows any host.
+	Hosts []string
+	// MaxSize is the largest attachment in bytes.
+	MaxSize int64
+	// ContentTypes are the media types attachments may have, e.g. image/* or
+	// application/pdf. Empty allows any type.
+	ContentTypes []string
+	// Timeout bounds each fetch.
+	Timeout time.Duration
+	// AllowPrivateNetworks allows fetching from loopback, private and link
+	// local addresses, which is only safe in development.
+	AllowPrivateNetworks bool
+	// S3Endpoint overrides the S3 endpoint, e.g. for LocalStack.
+	S3Endpoint string
+}
+
+var DefaultConfig = Config{
+	Schemes:      []string{"https", "s3"},
+	MaxSize:      10 << 20,
+	ContentTypes: []string{"image/*", "application/pdf", "text/plain", "text/csv", "text/calendar"},
+	Timeout:      30 * time.Second,
+}
+
+// Object is a fetched attachment.
+type Object struct {
+	Data        []byte
+	ContentType string
+}
+
+// CheckURL reports whether an attachment may be fetched from rawURL.
+func (c Config) CheckURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.New("url is invalid")
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if !contains(c.Schemes, scheme) {
+		return nil, fmt.Errorf("url scheme must be one of %s", strings.Join(c.Schemes, ", "))
+	}
+	if u.Host == "" {
+		return nil, errors.New("url has no host")
+	}
+	if u.User != nil {
+		return nil, errors.New("url must not contain credentials")
+	}
+	if len(c.Hosts) > 0 && !hostAllowed(c.Hosts, u.Hostname()) {
+		return nil, fmt.Errorf("host %s is not allowed", u.Hostname())
+	}
+	return u, nil
+}
+
+// CheckContent reports whether an attachment of the given size and detected
+// content type may be sent.
+func (c Config) CheckContent(size int64, contentType string) error {
+	if c.MaxSize > 0 && size > c.MaxSize {
+		return fmt.Errorf("attachment is larger than %d bytes", c.MaxSize)
+	}
+	if len(c.ContentTypes) == 0 {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("content type %q is invalid", contentType)
+	}
+	for _, allowed := range c.ContentTypes {
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return nil
+		}
+	}
+	return fmt.Errorf("content type %s is not allowed", mediaType)
+}
+
+// DetectContentType returns the media type of an attachment from its content,
+// falling back to its file extension and then the declared type when the
+// content alone is ambiguous.
+func DetectContentType(name, declared string, data []byte) string {
+	sniffed := http.DetectContentType(data)
+	switch sniffed {
+	case "application/octet-stream", "application/zip", "text/plain; charset=utf-8":
+		if byExtension := mime.TypeByExtension(path.Ext(name)); byExtension != "" {
+			return byExtension
+		}
+		if declared != "" && sniffed == "application/octet-stream" {
+			return declared
+		}
+	}
+	return sniffed
+}
+
+// Fetcher downloads attachments.
+type Fetcher struct {
+	cfg    Config
+	client *http.Client
+	s3     *s3.Client
+}
+
+// NewFetcher creates a Fetcher. s3:// URLs are fetched with an S3 client using
+// the credentials and region of awsConfig, and cfg.S3Endpoint when set.
+func NewFetcher(cfg Config, awsConfig aws.Config) *Fetcher {
+	f := &Fetcher{cfg: cfg}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = refusePrivateAddresses
+	}
+	f.client = &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			// No proxy, so the dialer sees the address actually connected to.
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return failure.Errorf(failure.InvalidRequest, provider, "redirect to %s is not allowed", req.URL.Scheme)
+			}
+			if _, err := cfg.CheckURL(req.URL.String()); err != nil {
+				return failure.New(failure.InvalidRequest, provider, err)
+			}
+			return nil
+		},
+	}
+	f.s3 = s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		o.HTTPClient = awshttp.NewBuildableClient().WithTimeout(cfg.Timeout)
+		if cfg.S3Endpoint != "" {
+			// S3 compatible stores such as LocalStack or MinIO.
+			o.BaseEndpoint = aws.String(cfg.S3Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return f
+}
+
+// Fetch downloads the attachment called name from rawURL. Errors are
+// classified so limits and missing objects aren't retried.
+func (f *Fetcher) Fetch(ctx context.Context, name, rawURL string) (*Object, error) {
+	u, err := f.cfg.CheckURL(rawURL)
+	if err != nil {
+		return nil, failure.New(failure.InvalidRequest, provider, err)
+	}
+
+	var body io.ReadCloser
+	var contentLength int64
+	var declared string
+	if strings.EqualFold(u.Scheme, "s3") {
+		out, err := f.s3.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(u.Host),
+			Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+		})
+		if err != nil {
+			return nil, failure.FromAWS(provider, err)
+		}
+		body, contentLength, declared = out.Body, aws.ToInt64(out.ContentLength), aws.ToString(out.ContentType)
+	} else {
+		resp, err := f.get(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		body, contentLength, declared = resp.Body, resp.ContentLength, resp.Header.Get("Content-Type")
+	}
+	defer func() { _ = body.Close() }()
+
+	if f.cfg.MaxSize > 0 && contentLength > f.cfg.MaxSize {
+		return nil, failure.Errorf(failure.PayloadTooLarge, provider, "%s is larger than %d bytes", name, f.cfg.MaxSize)
+	}
+
+	reader := io.Reader(body)
+	if f.cfg.MaxSize > 0 {
+		reader = io.LimitReader(body, f.cfg.MaxSize+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, failure.New(failure.ProviderOutage, provider, err)
+	}
+
+	contentType := DetectContentType(name, declared, data)
+	if err := f.cfg.CheckContent(int64(len(data)), contentType); err != nil {
+		kind := failure.InvalidRequest
+		if f.cfg.MaxSize > 0 && int64(len(data)) > f.cfg.MaxSize {
+			kind = failure.PayloadTooLarge
+		}
+		return nil, failure.New(kind, provider, fmt.Errorf("%s: %w", name, err))
+	}
+	return &Object{
+		Data:        data,
+		ContentType: contentType,
+	}, nil
+}
+
+// get requests an http(s) attachment, returning the response when it
+// succeeded.
+func (f *Fetcher) get(ctx context.Context, u *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, failure.New(failure.InvalidRequest, provider, err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		// Refused redirects and addresses are already classified.
+		var classified *failure.Error
+		if errors.As(err, &classified) {
+			return nil, err
+		}
+		return nil, failure.New(failure.ProviderOutage, provider, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_ = resp.Body.Close()
+		return nil, failure.FromHTTPStatus(provider, resp.StatusCode, redactURL(u.String()))
+	}
+	return resp, nil
+}
+
+// refusePrivateAddresses stops attachment URLs reaching internal services by
+// refusing connections to non public addresses, after DNS resolution so a
+// public name resolving to a private address is refused too.
+func refusePrivateAddresses(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("address %s is not an IP", host)
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return failure.Errorf(failure.InvalidRequest, provider, "address %s is not public", ip)
+	}
+	return nil
+}
+
+// redactURL returns rawURL without its userinfo, query or fragment, which may
+// hold credentials such as the signature of a presigned URL, for errors which
+// reach callers and workflow histories.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "invalid url"
+	}
+	u.User = nil
+	u.RawQuery, u.ForceQuery = "", false
+	u.Fragment, u.RawFragment = "", ""
+	return u.String()
+}
+
+func hostAllowed(hosts []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range hosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
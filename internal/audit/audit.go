@@ -0,0 +1,189 @@
+// Package audit records every API call in an append-only audit log.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// Anonymous is the principal of calls which don't identify their caller.
+const Anonymous = "anonymous"
+
+type recorder interface {
+	InsertAuditEvent(ctx context.Context, event model.AuditEvent) error
+}
+
+// Interceptor records an audit event for each call it intercepts.
+type Interceptor struct {
+	recorder        recorder
+	logger          *zap.Logger
+	principalHeader string
+	trustHeader     bool
+}
+
+// NewInterceptor creates an Interceptor. principalHeader is the metadata key
+// callers, or the gateway in front of them, identify themselves with. Anyone
+// can set it, so it is only taken as the principal when trustHeader is set,
+// e.g. behind a gateway which authenticates callers and sets the header
+// itself. Otherwise it is recorded as the claimed principal. The same goes for
+// the client address the gateway forwards in x-forwarded-for.
+func NewInterceptor(recorder recorder, logger *zap.Logger, principalHeader string, trustHeader bool) *Interceptor {
+	return &Interceptor{
+		recorder:        recorder,
+		logger:          logger,
+		principalHeader: strings.ToLower(principalHeader),
+		trustHeader:     trustHeader,
+	}
+}
+
+// Unary records unary calls once they have been handled.
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		i.record(ctx, info.FullMethod, req, resp, err)
+		return resp, err
+	}
+}
+
+// Stream records streaming calls. Client streams get an event for each
+// response, as each request on them starts a communication of its own, and one
+// more if the stream fails. Server streams get a single event once they end.
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		stream := &auditedStream{ServerStream: ss, interceptor: i, method: info.FullMethod, perResponse: info.IsClientStream}
+		err := handler(srv, stream)
+		if err != nil || !info.IsClientStream {
+			i.record(ss.Context(), info.FullMethod, stream.lastReq, nil, err)
+		}
+		return err
+	}
+}
+
+func (i *Interceptor) record(ctx context.Context, method string, req, resp any, err error) {
+	principal, claimed := Principal(ctx, i.principalHeader, i.trustHeader)
+	event := model.AuditEvent{
+		OccurredAt:       time.Now(),
+		Principal:        principal,
+		ClaimedPrincipal: claimed,
+		Peer:             peerAddress(ctx, i.trustHeader),
+		Domain:           domainOf(req),
+		Method:           method,
+		RequestHash:      hashOf(req),
+		Code:             status.Code(err).String(),
+		WorkflowID:       workflowIDOf(req, resp),
+	}
+	// The call's context may already be cancelled, but it still happened.
+	if err := i.recorder.InsertAuditEvent(context.WithoutCancel(ctx), event); err != nil {
+		i.logger.Error("unable to record audit event", zap.Error(err), zap.String("method", method), zap.String("principal", event.Principal))
+	}
+}
+
+type auditedStream struct {
+	grpc.ServerStream
+	interceptor *Interceptor
+	method      string
+	perResponse bool
+	lastReq     any
+}
+
+func (s *auditedStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.lastReq = m
+	}
+	return err
+}
+
+func (s *auditedStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil && s.perResponse {
+		s.interceptor.record(s.Context(), s.method, s.lastReq, m, nil)
+	}
+	return err
+}
+
+// Principal identifies the caller: the subject of a verified client
+// certificate, otherwise the value of the principal header when trustHeader is
+// set, otherwise Anonymous. A principal header which wasn't relied on is
+// returned as claimed.
+func Principal(ctx context.Context, header string, trustHeader bool) (principal, claimed string) {
+	claimed = headerPrincipal(ctx, header)
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			return "cn:" + tlsInfo.State.VerifiedChains[0][0].Subject.CommonName, claimed
+		}
+	}
+	if trustHeader && claimed != "" {
+		return claimed, ""
+	}
+	return Anonymous, claimed
+}
+
+// headerPrincipal returns the value of the principal header, if any.
+func headerPrincipal(ctx context.Context, header string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && header != "" {
+		if values := md.Get(header); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// peerAddress returns the address of the caller. Behind a trusted gateway the
+// client address it forwards is preferred over the gateway's own, which any
+// other caller could forge.
+func peerAddress(ctx context.Context, trustGateway bool) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && trustGateway {
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			return strings.TrimSpace(strings.Split(values[0], ",")[0])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+func domainOf(req any) string {
+	if r, ok := req.(interface{ GetDomain() string }); ok {
+		return r.GetDomain()
+	}
+	return ""
+}
+
+// workflowIDOf returns the ID of the communication a call started or asked
+// about.
+func workflowIDOf(req, resp any) string {
+	for _, m := range []any{resp, req} {
+		if r, ok := m.(interface{ GetId() string }); ok && r.GetId() != "" {
+			return r.GetId()
+		}
+	}
+	return ""
+}
+
+func hashOf(req any) string {
+	m, ok := req.(proto.Message)
+	if !ok {
+		return ""
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,228 @@
+package audit_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/audit"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// fakeRecorder keeps audit events in memory.
+type fakeRecorder struct {
+	events []model.AuditEvent
+	err    error
+}
+
+func (f *fakeRecorder) InsertAuditEvent(_ context.Context, event model.AuditEvent) error {
+	f.events = append(f.events, event)
+	return f.err
+}
+
+// fakeStream receives the given requests and discards what is sent.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*pb.SendCommunicationRequest
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) RecvMsg(m any) error {
+	if len(f.requests) == 0 {
+		return errors.New("EOF")
+	}
+	proto.Merge(m.(*pb.SendCommunicationRequest), f.requests[0])
+	f.requests = f.requests[1:]
+	return nil
+}
+
+func (f *fakeStream) SendMsg(any) error { return nil }
+
+type AuditTestSuite struct {
+	suite.Suite
+	recorder    *fakeRecorder
+	interceptor *audit.Interceptor
+	ctx         context.Context
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	s.recorder = &fakeRecorder{}
+	s.interceptor = audit.NewInterceptor(s.recorder, zap.NewNop(), "X-Unicom-Principal", false)
+	s.ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+}
+
+func (s *AuditTestSuite) TestUnary_RecordsCall() {
+	ctx := metadata.NewIncomingContext(s.ctx, metadata.Pairs("x-unicom-principal", "billing-service"))
+	req := &pb.SendCommunicationRequest{Domain: "billing"}
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UnicomService_SendCommunication_FullMethodName}
+
+	resp, err := s.interceptor.Unary()(ctx, req, info, func(context.Context, any) (any, error) {
+		return &pb.SendCommunicationResponse{Id: "comm-1"}, nil
+	})
+	s.NoError(err)
+	s.Equal("comm-1", resp.(*pb.SendCommunicationResponse).Id)
+
+	s.Require().Len(s.recorder.events, 1)
+	event := s.recorder.events[0]
+	s.Equal(audit.Anonymous, event.Principal, "the header isn't trusted")
+	s.Equal("billing-service", event.ClaimedPrincipal)
+	s.Equal("10.0.0.1:5000", event.Peer)
+	s.Equal("billing", event.Domain)
+	s.Equal(pb.UnicomService_SendCommunication_FullMethodName, event.Method)
+	s.Equal(codes.OK.String(), event.Code)
+	s.Equal("comm-1", event.WorkflowID)
+	s.Len(event.RequestHash, 64)
+	s.False(event.OccurredAt.IsZero())
+}
+
+func (s *AuditTestSuite) TestUnary_TrustedHeader() {
+	interceptor := audit.NewInterceptor(s.recorder, zap.NewNop(), "X-Unicom-Principal", true)
+	ctx := metadata.NewIncomingContext(s.ctx, metadata.Pairs("x-unicom-principal", "billing-service", "x-forwarded-for", "203.0.113.7, 10.0.0.2"))
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UnicomService_SendCommunication_FullMethodName}
+
+	_, err := interceptor.Unary()(ctx, &pb.SendCommunicationRequest{}, info, func(context.Context, any) (any, error) {
+		return &pb.SendCommunicationResponse{}, nil
+	})
+	s.NoError(err)
+
+	s.Require().Len(s.recorder.events, 1)
+	s.Equal("billing-service", s.recorder.events[0].Principal)
+	s.Empty(s.recorder.events[0].ClaimedPrincipal)
+	s.Equal("203.0.113.7", s.recorder.events[0].Peer)
+}
+
+func (s *AuditTestSuite) TestUnary_ForgedForwardedFor() {
+	ctx := metadata.NewIncomingContext(s.ctx, metadata.Pairs("x-forwarded-for", "203.0.113.7"))
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UnicomService_SendCommunication_FullMethodName}
+
+	_, err := s.interceptor.Unary()(ctx, &pb.SendCommunicationRequest{}, info, func(context.Context, any) (any, error) {
+		return &pb.SendCommunicationResponse{}, nil
+	})
+	s.NoError(err)
+
+	s.Require().Len(s.recorder.events, 1)
+	s.Equal("10.0.0.1:5000", s.recorder.events[0].Peer, "a direct caller can't choose its address")
+}
+
+func (s *AuditTestSuite) TestPrincipal_CertificateWinsOverHeader() {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "billing"}}}},
+		}},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-unicom-principal", "payments"))
+
+	principal, claimed := audit.Principal(ctx, "x-unicom-principal", true)
+	s.Equal("cn:billing", principal)
+	s.Equal("payments", claimed)
+
+	principal, claimed = audit.Principal(s.ctx, "x-unicom-principal", false)
+	s.Equal(audit.Anonymous, principal)
+	s.Empty(claimed)
+}
+
+func (s *AuditTestSuite) TestUnary_RecordsFailure() {
+	req := &pb.GetStatusRequest{Id: "comm-2"}
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UnicomService_GetStatus_FullMethodName}
+
+	_, err := s.interceptor.Unary()(s.ctx, req, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	s.Equal(codes.NotFound, status.Code(err))
+
+	s.Require().Len(s.recorder.events, 1)
+	event := s.recorder.events[0]
+	s.Equal(audit.Anonymous, event.Principal)
+	s.Equal("10.0.0.1:5000", event.Peer)
+	s.Equal(codes.NotFound.String(), event.Code)
+	s.Equal("comm-2", event.WorkflowID)
+}
+
+func (s *AuditTestSuite) TestUnary_RecorderFailureDoesNotFailCall() {
+	s.recorder.err = errors.New("database unavailable")
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UnicomService_SendCommunication_FullMethodName}
+
+	_, err := s.interceptor.Unary()(s.ctx, &pb.SendCommunicationRequest{}, info, func(context.Context, any) (any, error) {
+		return &pb.SendCommunicationResponse{Id: "comm-1"}, nil
+	})
+	s.NoError(err)
+}
+
+func (s *AuditTestSuite) TestUnary_SameRequestSameHash() {
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UnicomService_SendCommunication_FullMethodName}
+	handler := func(context.Context, any) (any, error) { return nil, nil }
+
+	_, _ = s.interceptor.Unary()(s.ctx, &pb.SendCommunicationRequest{Domain: "billing"}, info, handler)
+	_, _ = s.interceptor.Unary()(s.ctx, &pb.SendCommunicationRequest{Domain: "billing"}, info, handler)
+	_, _ = s.interceptor.Unary()(s.ctx, &pb.SendCommunicationRequest{Domain: "marketing"}, info, handler)
+
+	s.Require().Len(s.recorder.events, 3)
+	s.Equal(s.recorder.events[0].RequestHash, s.recorder.events[1].RequestHash)
+	s.NotEqual(s.recorder.events[0].RequestHash, s.recorder.events[2].RequestHash)
+}
+
+func (s *AuditTestSuite) TestStream_ClientStreamRecordsEachResponse() {
+	stream := &fakeStream{ctx: s.ctx, requests: []*pb.SendCommunicationRequest{{Domain: "billing"}, {Domain: "marketing"}}}
+	info := &grpc.StreamServerInfo{FullMethod: pb.UnicomService_StreamCommunication_FullMethodName, IsClientStream: true, IsServerStream: true}
+
+	err := s.interceptor.Stream()(nil, stream, info, func(_ any, ss grpc.ServerStream) error {
+		for _, id := range []string{"comm-1", "comm-2"} {
+			req := &pb.SendCommunicationRequest{}
+			if err := ss.RecvMsg(req); err != nil {
+				return err
+			}
+			if err := ss.SendMsg(&pb.SendCommunicationResponse{Id: id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	s.NoError(err)
+
+	s.Require().Len(s.recorder.events, 2)
+	s.Equal("billing", s.recorder.events[0].Domain)
+	s.Equal("comm-1", s.recorder.events[0].WorkflowID)
+	s.Equal("marketing", s.recorder.events[1].Domain)
+	s.Equal("comm-2", s.recorder.events[1].WorkflowID)
+}
+
+func (s *AuditTestSuite) TestStream_ServerStreamRecordsOnce() {
+	stream := &fakeStream{ctx: s.ctx}
+	info := &grpc.StreamServerInfo{FullMethod: pb.UnicomService_ExportAuditEvents_FullMethodName, IsServerStream: true}
+
+	err := s.interceptor.Stream()(nil, stream, info, func(_ any, ss grpc.ServerStream) error {
+		for range 3 {
+			if err := ss.SendMsg(&pb.AuditEvent{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	s.NoError(err)
+
+	s.Require().Len(s.recorder.events, 1)
+	s.Equal(pb.UnicomService_ExportAuditEvents_FullMethodName, s.recorder.events[0].Method)
+	s.Equal(codes.OK.String(), s.recorder.events[0].Code)
+}
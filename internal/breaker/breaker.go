@@ -0,0 +1,213 @@
+// Package breaker tracks the recent health of downstream providers and stops
+// sending to one once it is failing, so traffic can fail over to a secondary.
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned when a call is rejected because the breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+type State string
+
+const (
+	// Closed lets every call through.
+	Closed State = "closed"
+	// Open rejects calls until the cooldown has passed.
+	Open State = "open"
+	// HalfOpen lets a single probe call through to test recovery.
+	HalfOpen State = "half-open"
+)
+
+// Config controls when a breaker trips and how long it stays open.
+type Config struct {
+	// Window is how far back results are considered when scoring health.
+	Window time.Duration
+	// MinRequests is the number of results required in the window before the
+	// breaker can trip, so a single early failure doesn't open it.
+	MinRequests int
+	// FailureRatio is the proportion of failed results in the window at which
+	// the breaker opens.
+	FailureRatio float64
+	// Cooldown is how long the breaker stays open before allowing a probe.
+	Cooldown time.Duration
+}
+
+// DefaultConfig is used for any zero valued Config fields.
+var DefaultConfig = Config{
+	Window:       time.Minute,
+	MinRequests:  5,
+	FailureRatio: 0.5,
+	Cooldown:     30 * time.Second,
+}
+
+type result struct {
+	at      time.Time
+	success bool
+}
+
+// Breaker is a circuit breaker for a single provider (or provider region).
+// It is safe for concurrent use.
+type Breaker struct {
+	name string
+	cfg  Config
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	openedAt time.Time
+	probing  bool
+	results  []result
+}
+
+func New(name string, cfg Config) *Breaker {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultConfig.Window
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = DefaultConfig.MinRequests
+	}
+	if cfg.FailureRatio <= 0 {
+		cfg.FailureRatio = DefaultConfig.FailureRatio
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultConfig.Cooldown
+	}
+	return &Breaker{
+		name:  name,
+		cfg:   cfg,
+		now:   time.Now,
+		state: Closed,
+	}
+}
+
+// SetClock replaces the breaker's time source, for tests.
+func (b *Breaker) SetClock(now func() time.Time) {
+	b.now = now
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow reports whether a call may be made. When the breaker is half-open
+// only the first caller is allowed through until its result is recorded.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case Closed:
+		return true
+	case HalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+// Record stores the outcome of a call allowed by Allow.
+func (b *Breaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	state := b.currentState()
+	b.probing = false
+
+	if state == HalfOpen {
+		if success {
+			b.state = Closed
+			b.results = nil
+		} else {
+			b.trip(now)
+		}
+		return
+	}
+
+	b.results = append(b.prune(now), result{at: now, success: success})
+	if state == Closed && !success {
+		failures := 0
+		for _, r := range b.results {
+			if !r.success {
+				failures++
+			}
+		}
+		if len(b.results) >= b.cfg.MinRequests && float64(failures)/float64(len(b.results)) >= b.cfg.FailureRatio {
+			b.trip(now)
+		}
+	}
+}
+
+// Snapshot is a point in time view of a breaker for status reporting.
+type Snapshot struct {
+	Name     string
+	State    State
+	Health   float64
+	Requests int
+	Failures int
+	OpenedAt time.Time
+}
+
+func (s Snapshot) String() string {
+	return fmt.Sprintf("state=%s health=%.2f requests=%d failures=%d", s.State, s.Health, s.Requests, s.Failures)
+}
+
+// Snapshot returns the breaker state and its health score, the proportion of
+// successful calls within the window (1 when there were none).
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.results = b.prune(b.now())
+	snapshot := Snapshot{
+		Name:     b.name,
+		State:    b.currentState(),
+		Requests: len(b.results),
+		Health:   1,
+		OpenedAt: b.openedAt,
+	}
+	for _, r := range b.results {
+		if !r.success {
+			snapshot.Failures++
+		}
+	}
+	if snapshot.Requests > 0 {
+		snapshot.Health = float64(snapshot.Requests-snapshot.Failures) / float64(snapshot.Requests)
+	}
+	return snapshot
+}
+
+// currentState moves an open breaker to half-open once the cooldown has
+// passed. b.mu must be held.
+func (b *Breaker) currentState() State {
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.cfg.Cooldown {
+		b.state = HalfOpen
+		b.probing = false
+	}
+	return b.state
+}
+
+func (b *Breaker) trip(now time.Time) {
+	b.state = Open
+	b.openedAt = now
+	b.results = nil
+}
+
+// prune drops results older than the window. b.mu must be held.
+func (b *Breaker) prune(now time.Time) []result {
+	cutoff := now.Add(-b.cfg.Window)
+	i := 0
+	for i < len(b.results) && b.results[i].at.Before(cutoff) {
+		i++
+	}
+	return b.results[i:]
+}
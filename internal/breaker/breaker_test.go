@@ -0,0 +1,147 @@
+package breaker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/breaker"
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+type BreakerTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestBreakerTestSuite(t *testing.T) {
+	suite.Run(t, new(BreakerTestSuite))
+}
+
+func (s *BreakerTestSuite) SetupTest() {
+	s.now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *BreakerTestSuite) newBreaker() *breaker.Breaker {
+	b := breaker.New("email/ses/eu-west-2", breaker.Config{
+		Window:       time.Minute,
+		MinRequests:  4,
+		FailureRatio: 0.5,
+		Cooldown:     30 * time.Second,
+	})
+	b.SetClock(func() time.Time { return s.now })
+	return b
+}
+
+func (s *BreakerTestSuite) TestBreaker_OpensAtFailureRatio() {
+	b := s.newBreaker()
+
+	b.Record(true)
+	b.Record(false)
+	b.Record(true)
+	s.Equal(breaker.Closed, b.Snapshot().State)
+
+	b.Record(false)
+	s.Equal(breaker.Open, b.Snapshot().State)
+	s.False(b.Allow())
+}
+
+func (s *BreakerTestSuite) TestBreaker_IgnoresResultsOutsideWindow() {
+	b := s.newBreaker()
+
+	b.Record(false)
+	b.Record(false)
+	b.Record(false)
+	s.now = s.now.Add(2 * time.Minute)
+	b.Record(false)
+
+	snapshot := b.Snapshot()
+	s.Equal(breaker.Closed, snapshot.State)
+	s.Equal(1, snapshot.Requests)
+	s.Equal(0.0, snapshot.Health)
+}
+
+func (s *BreakerTestSuite) TestBreaker_HalfOpenProbe() {
+	b := s.newBreaker()
+	for range 4 {
+		b.Record(false)
+	}
+	s.False(b.Allow())
+
+	s.now = s.now.Add(30 * time.Second)
+	s.True(b.Allow())
+	s.False(b.Allow(), "only one probe is allowed while half-open")
+
+	b.Record(false)
+	s.Equal(breaker.Open, b.Snapshot().State)
+
+	s.now = s.now.Add(30 * time.Second)
+	s.True(b.Allow())
+	b.Record(true)
+	s.Equal(breaker.Closed, b.Snapshot().State)
+	s.True(b.Allow())
+}
+
+func (s *BreakerTestSuite) TestFailover_SkipsOpenBreaker() {
+	primary := s.newBreaker()
+	for range 4 {
+		primary.Record(false)
+	}
+	secondary := breaker.New("email/smtp", breaker.Config{})
+
+	var called []string
+	send := func(name string) (*string, error) {
+		called = append(called, name)
+		return &name, nil
+	}
+	id, err := breaker.Failover([]breaker.Target[string]{
+		{Provider: "ses", Breaker: primary},
+		{Provider: "smtp", Breaker: secondary},
+	}, send)
+	s.NoError(err)
+	s.Equal("smtp", *id)
+	s.Equal([]string{"smtp"}, called)
+}
+
+func (s *BreakerTestSuite) TestFailover_ReturnsAllErrors() {
+	sendErr := errors.New("service unavailable")
+	id, err := breaker.Failover([]breaker.Target[string]{
+		{Provider: "ses", Breaker: breaker.New("email/ses/eu-west-2", breaker.Config{})},
+		{Provider: "ses", Breaker: breaker.New("email/ses/eu-west-1", breaker.Config{})},
+	}, func(string) (*string, error) {
+		return nil, sendErr
+	})
+	s.Nil(id)
+	s.ErrorIs(err, sendErr)
+	s.ErrorContains(err, "email/ses/eu-west-1")
+}
+
+func (s *BreakerTestSuite) TestFailover_DoesNotFailOverMessageErrors() {
+	primary := breaker.New("email/ses/eu-west-2", breaker.Config{})
+	sendErr := failure.Errorf(failure.InvalidRecipient, "ses", "address does not exist")
+
+	var called []string
+	_, err := breaker.Failover([]breaker.Target[string]{
+		{Provider: "ses", Breaker: primary},
+		{Provider: "smtp", Breaker: breaker.New("email/smtp", breaker.Config{})},
+	}, func(name string) (*string, error) {
+		called = append(called, name)
+		return nil, sendErr
+	})
+	s.ErrorIs(err, sendErr)
+	s.Equal([]string{"ses"}, called)
+	s.Equal(0, primary.Snapshot().Failures)
+}
+
+func (s *BreakerTestSuite) TestRegistry_Get() {
+	registry := breaker.NewRegistry(breaker.Config{})
+	b := registry.Get("push/onesignal")
+	s.Same(b, registry.Get("push/onesignal"))
+	registry.Get("email/ses/eu-west-2")
+
+	all := registry.All()
+	s.Len(all, 2)
+	s.Equal("email/ses/eu-west-2", all[0].Name())
+}
@@ -0,0 +1,87 @@
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+// Target is one provider in a failover chain guarded by its own breaker.
+type Target[P any] struct {
+	Provider P
+	Breaker  *Breaker
+}
+
+// Failover calls send with each target in order, skipping those whose breaker
+// is open, until one succeeds. Errors caused by the message rather than the
+// provider (see failure.Kind.ProviderFault) are returned straight away as the
+// next provider would reject it too. The errors of every failed or skipped
+// target are returned when none succeed.
+func Failover[P any](targets []Target[P], send func(P) (*string, error)) (*string, error) {
+	var errs error
+	for _, target := range targets {
+		if !target.Breaker.Allow() {
+			errs = errors.Join(errs, failure.New(failure.ProviderOutage, target.Breaker.Name(), ErrOpen))
+			continue
+		}
+		id, err := send(target.Provider)
+		if err == nil {
+			target.Breaker.Record(true)
+			return id, nil
+		}
+		if !failure.KindOf(err).ProviderFault() {
+			target.Breaker.Record(true)
+			return nil, err
+		}
+		target.Breaker.Record(false)
+		errs = errors.Join(errs, fmt.Errorf("%s: %w", target.Breaker.Name(), err))
+	}
+	return nil, errs
+}
+
+// Registry holds every breaker created by the process so their state can be
+// reported on the ops status page.
+type Registry struct {
+	cfg Config
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+func NewRegistry(cfg Config) *Registry {
+	return &Registry{
+		cfg:      cfg,
+		breakers: map[string]*Breaker{},
+	}
+}
+
+// Get returns the breaker with the given name, creating it if needed.
+func (r *Registry) Get(name string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	b := New(name, r.cfg)
+	r.breakers[name] = b
+	return b
+}
+
+// All returns every breaker ordered by name.
+func (r *Registry) All() []*Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	sort.Slice(breakers, func(i, j int) bool {
+		return breakers[i].name < breakers[j].name
+	})
+	return breakers
+}
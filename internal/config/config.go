@@ -0,0 +1,182 @@
+// Package config layers a YAML file under the command line flags and
+// environment variables, so a deployment can be described in one file. Any
+// flag can be set in the file by its name, and a flag given on the command
+// line or through its environment variable still wins.
+//
+//	temporal-server: temporal:7233
+//	email-provider: ses
+//	email-failover-provider: sendgrid
+//	email-domain-providers:
+//	  marketing: sendgrid
+//	priorities: [critical, transactional]
+//	default:
+//	  delivery:
+//	    max_attempts: 10
+//	domains:
+//	  billing:
+//	    senders: [billing@example.com]
+//
+// The default and domains sections are the per domain settings described by
+// domain.File, which are reloaded while the process runs.
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// domainKeys are the sections of the file holding the per domain settings
+// rather than flags.
+var domainKeys = []string{"default", "domains"}
+
+// File is a config file whose path is only known once the flag naming it has
+// been parsed. It is read the first time a flag looks a value up in it.
+type File struct {
+	path   *string
+	once   sync.Once
+	values map[string]any
+	err    error
+}
+
+// New returns the File at the path path points to, which may be empty when no
+// file is used.
+func New(path *string) *File {
+	return &File{
+		path: path,
+	}
+}
+
+func (f *File) load() {
+	f.once.Do(func() {
+		if *f.path == "" {
+			return
+		}
+		data, err := os.ReadFile(*f.path)
+		if err != nil {
+			f.err = err
+			return
+		}
+		if err := yaml.Unmarshal(data, &f.values); err != nil {
+			f.err = fmt.Errorf("parsing config file %s: %w", *f.path, err)
+		}
+	})
+}
+
+// Path returns the path of the file, empty when no file is used.
+func (f *File) Path() string {
+	return *f.path
+}
+
+// Source returns the value source of the flag named key.
+func (f *File) Source(key string) cli.ValueSource {
+	return &source{
+		file: f,
+		key:  key,
+	}
+}
+
+// Attach makes every flag of cmd and its subcommands fall back to the file
+// when neither the flag nor its environment variable is set. The flag naming
+// the file must come first so it is parsed before the others look it up.
+func (f *File) Attach(cmd *cli.Command) {
+	for _, flag := range cmd.Flags {
+		sources := reflect.ValueOf(flag).Elem().FieldByName("Sources")
+		if !sources.IsValid() {
+			continue
+		}
+		chain := sources.Addr().Interface().(*cli.ValueSourceChain)
+		chain.Append(cli.NewValueSourceChain(f.Source(flag.Names()[0])))
+	}
+	for _, sub := range cmd.Commands {
+		f.Attach(sub)
+	}
+}
+
+// Validate reports a file which couldn't be read, or which sets anything
+// other than the flags of cmd and its subcommands and the per domain
+// settings.
+func (f *File) Validate(cmd *cli.Command) error {
+	f.load()
+	if f.err != nil {
+		return f.err
+	}
+	known := flagNames(cmd, slices.Clone(domainKeys))
+	var unknown []string
+	for key := range f.values {
+		if !slices.Contains(known, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("config file %s: unknown settings %s, settings are named after flags", *f.path, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func flagNames(cmd *cli.Command, names []string) []string {
+	for _, flag := range cmd.Flags {
+		names = append(names, flag.Names()...)
+	}
+	for _, sub := range cmd.Commands {
+		names = flagNames(sub, names)
+	}
+	return names
+}
+
+// source looks a flag up in a File.
+type source struct {
+	file *File
+	key  string
+}
+
+func (s *source) Lookup() (string, bool) {
+	if *s.file.path == "" {
+		// The flag naming the file isn't set, or hasn't been parsed yet.
+		return "", false
+	}
+	s.file.load()
+	value, ok := s.file.values[s.key]
+	if !ok || value == nil {
+		return "", false
+	}
+	return flagValue(value), true
+}
+
+func (s *source) String() string {
+	return fmt.Sprintf("config file key %q", s.key)
+}
+
+func (s *source) GoString() string {
+	return fmt.Sprintf("&configSource{key:%[1]q}", s.key)
+}
+
+// flagValue formats a YAML value the way the flag it sets parses it: lists
+// as comma separated values and maps as comma separated key=value pairs.
+func flagValue(value any) string {
+	switch v := value.(type) {
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = flagValue(item)
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		pairs := make([]string, 0, len(v))
+		for key, item := range v {
+			pairs = append(pairs, key+"="+flagValue(item))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,115 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/urfave/cli/v3"
+
+	"github.com/anicoll/unicom/internal/config"
+)
+
+type ConfigTestSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+func (s *ConfigTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "unicom.yaml")
+	s.Require().NoError(os.WriteFile(s.path, []byte(`
+log-level: INFO
+ops-port: 9090
+priorities: [critical, transactional]
+email-domain-providers:
+  marketing: sendgrid
+  billing: ses
+domains:
+  billing:
+    senders: [billing@example.com]
+`), 0o600))
+}
+
+// run runs a command with a server subcommand, returning the values of the
+// subcommand's flags.
+func (s *ConfigTestSuite) run(args ...string) (*cli.Command, error) {
+	var configPath string
+	file := config.New(&configPath)
+	var got *cli.Command
+	app := &cli.Command{
+		Name: "unicom",
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			return ctx, file.Validate(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "config", Sources: cli.NewValueSourceChain(cli.EnvVar("UNICOM_TEST_CONFIG")), Destination: &configPath},
+			&cli.StringFlag{Name: "log-level", Sources: cli.NewValueSourceChain(cli.EnvVar("UNICOM_TEST_LOG_LEVEL")), Value: "DEBUG"},
+			&cli.IntFlag{Name: "ops-port", Value: 8081},
+		},
+		Commands: []*cli.Command{
+			{
+				Name: "server",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: "priorities"},
+					&cli.StringMapFlag{Name: "email-domain-providers"},
+				},
+				Action: func(_ context.Context, c *cli.Command) error {
+					got = c
+					return nil
+				},
+			},
+		},
+	}
+	file.Attach(app)
+	err := app.Run(context.Background(), append([]string{"unicom"}, args...))
+	return got, err
+}
+
+func (s *ConfigTestSuite) TestFileSetsFlags() {
+	c, err := s.run("--config", s.path, "server")
+	s.Require().NoError(err)
+	s.Equal("INFO", c.String("log-level"))
+	s.Equal(9090, c.Int("ops-port"))
+	s.Equal([]string{"critical", "transactional"}, c.StringSlice("priorities"))
+	s.Equal(map[string]string{"marketing": "sendgrid", "billing": "ses"}, c.StringMap("email-domain-providers"))
+}
+
+func (s *ConfigTestSuite) TestFlagsAndEnvOverrideFile() {
+	s.T().Setenv("UNICOM_TEST_CONFIG", s.path)
+	s.T().Setenv("UNICOM_TEST_LOG_LEVEL", "WARN")
+	c, err := s.run("--ops-port", "7070", "server")
+	s.Require().NoError(err)
+	s.Equal("WARN", c.String("log-level"))
+	s.Equal(7070, c.Int("ops-port"))
+	s.Equal([]string{"critical", "transactional"}, c.StringSlice("priorities"))
+}
+
+func (s *ConfigTestSuite) TestWithoutFile() {
+	c, err := s.run("server")
+	s.Require().NoError(err)
+	s.Equal("DEBUG", c.String("log-level"))
+	s.Equal(8081, c.Int("ops-port"))
+}
+
+func (s *ConfigTestSuite) TestValidate_UnknownSetting() {
+	s.Require().NoError(os.WriteFile(s.path, []byte("log-level: INFO\nlog-lvel: INFO\n"), 0o600))
+	_, err := s.run("--config", s.path, "server")
+	s.ErrorContains(err, "unknown settings log-lvel")
+}
+
+func (s *ConfigTestSuite) TestValidate_BadValue() {
+	s.Require().NoError(os.WriteFile(s.path, []byte("ops-port: abc\n"), 0o600))
+	_, err := s.run("--config", s.path, "server")
+	s.ErrorContains(err, `config file key "ops-port"`)
+}
+
+func (s *ConfigTestSuite) TestValidate_MissingFile() {
+	_, err := s.run("--config", filepath.Join(s.T().TempDir(), "missing.yaml"), "server")
+	s.ErrorIs(err, os.ErrNotExist)
+}
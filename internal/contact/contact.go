@@ -0,0 +1,88 @@
+// Package contact resolves external customer IDs to the contact details
+// communications are sent to.
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+var (
+	// ErrNotFound is returned when the directory has no contact for a customer.
+	ErrNotFound = errors.New("contact not found")
+	// ErrReadOnly is returned when contacts are owned by another service and
+	// can't be changed through unicom.
+	ErrReadOnly = errors.New("contacts are read only with this provider")
+)
+
+// Config selects where contacts are looked up.
+type Config struct {
+	// Provider is one of postgres or http.
+	Provider string
+	// URL is the endpoint of the http provider, called with the external
+	// customer ID appended as a path segment.
+	URL string
+	// Token is sent as a bearer token to the http provider.
+	Token string
+	// Timeout bounds each call to the http provider.
+	Timeout time.Duration
+}
+
+var DefaultConfig = Config{
+	Provider: "postgres",
+	Timeout:  5 * time.Second,
+}
+
+type postgres interface {
+	UpsertContact(ctx context.Context, contact model.Contact) error
+	GetContact(ctx context.Context, externalCustomerID string) (model.Contact, error)
+}
+
+// Directory looks up and stores contacts.
+type Directory interface {
+	Lookup(ctx context.Context, externalCustomerID string) (model.Contact, error)
+	Upsert(ctx context.Context, contact model.Contact) error
+}
+
+// New creates the Directory selected by cfg.
+func New(cfg Config, db postgres) (Directory, error) {
+	switch cfg.Provider {
+	case "", "postgres":
+		return NewPostgresDirectory(db), nil
+	case "http":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("the http contact provider needs a url")
+		}
+		return NewHTTPDirectory(&http.Client{Timeout: cfg.Timeout}, cfg.URL, cfg.Token), nil
+	default:
+		return nil, fmt.Errorf("unknown contact provider %q", cfg.Provider)
+	}
+}
+
+// PostgresDirectory keeps contacts in unicom's database.
+type PostgresDirectory struct {
+	db postgres
+}
+
+func NewPostgresDirectory(db postgres) *PostgresDirectory {
+	return &PostgresDirectory{db: db}
+}
+
+func (d *PostgresDirectory) Lookup(ctx context.Context, externalCustomerID string) (model.Contact, error) {
+	contact, err := d.db.GetContact(ctx, externalCustomerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return contact, ErrNotFound
+	}
+	return contact, err
+}
+
+func (d *PostgresDirectory) Upsert(ctx context.Context, contact model.Contact) error {
+	return d.db.UpsertContact(ctx, contact)
+}
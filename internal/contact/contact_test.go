@@ -0,0 +1,124 @@
+package contact_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/contact"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// fakePostgres keeps contacts in memory.
+type fakePostgres struct {
+	contacts map[string]model.Contact
+}
+
+func (f *fakePostgres) UpsertContact(_ context.Context, c model.Contact) error {
+	f.contacts[c.ExternalCustomerID] = c
+	return nil
+}
+
+func (f *fakePostgres) GetContact(_ context.Context, externalCustomerID string) (model.Contact, error) {
+	c, ok := f.contacts[externalCustomerID]
+	if !ok {
+		return c, pgx.ErrNoRows
+	}
+	return c, nil
+}
+
+type ContactTestSuite struct {
+	suite.Suite
+	srv *httptest.Server
+}
+
+func TestContactTestSuite(t *testing.T) {
+	suite.Run(t, new(ContactTestSuite))
+}
+
+func (s *ContactTestSuite) SetupTest() {
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/contacts/customer%2F1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"email_addresses": ["jane@example.com"],
+				"phone_numbers": ["+971500000000"],
+				"devices": [{"type": "FCM", "token": "token", "locale": "ar-AE"}],
+				"locale": "ar-AE",
+				"timezone": "Asia/Dubai"
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	s.T().Cleanup(s.srv.Close)
+}
+
+func (s *ContactTestSuite) TestPostgresDirectory() {
+	directory := contact.NewPostgresDirectory(&fakePostgres{contacts: map[string]model.Contact{}})
+
+	_, err := directory.Lookup(context.Background(), "customer-1")
+	s.ErrorIs(err, contact.ErrNotFound)
+
+	s.NoError(directory.Upsert(context.Background(), model.Contact{ExternalCustomerID: "customer-1", Timezone: "Europe/London"}))
+	found, err := directory.Lookup(context.Background(), "customer-1")
+	s.NoError(err)
+	s.Equal("Europe/London", found.Timezone)
+}
+
+func (s *ContactTestSuite) TestHTTPDirectory_Lookup() {
+	directory := contact.NewHTTPDirectory(s.srv.Client(), s.srv.URL+"/contacts/", "secret")
+
+	found, err := directory.Lookup(context.Background(), "customer/1")
+	s.NoError(err)
+	s.Equal(model.Contact{
+		ExternalCustomerID: "customer/1",
+		EmailAddresses:     []string{"jane@example.com"},
+		PhoneNumbers:       []string{"+971500000000"},
+		Devices:            []model.DeviceToken{{ExternalCustomerID: "customer/1", Type: model.FCM, Token: "token", Locale: "ar-AE"}},
+		Locale:             "ar-AE",
+		Timezone:           "Asia/Dubai",
+	}, found)
+}
+
+func (s *ContactTestSuite) TestHTTPDirectory_NotFound() {
+	directory := contact.NewHTTPDirectory(s.srv.Client(), s.srv.URL+"/contacts", "secret")
+
+	_, err := directory.Lookup(context.Background(), "customer-2")
+	s.ErrorIs(err, contact.ErrNotFound)
+}
+
+func (s *ContactTestSuite) TestHTTPDirectory_Failure() {
+	directory := contact.NewHTTPDirectory(s.srv.Client(), s.srv.URL+"/contacts", "wrong")
+
+	_, err := directory.Lookup(context.Background(), "customer/1")
+	s.Error(err)
+	s.NotErrorIs(err, contact.ErrNotFound)
+}
+
+func (s *ContactTestSuite) TestHTTPDirectory_IsReadOnly() {
+	directory := contact.NewHTTPDirectory(s.srv.Client(), s.srv.URL, "")
+
+	s.ErrorIs(directory.Upsert(context.Background(), model.Contact{ExternalCustomerID: "customer-1"}), contact.ErrReadOnly)
+}
+
+func (s *ContactTestSuite) TestNew() {
+	_, err := contact.New(contact.Config{Provider: "http"}, nil)
+	s.Error(err, "the http provider needs a url")
+
+	_, err = contact.New(contact.Config{Provider: "ldap"}, nil)
+	s.Error(err)
+
+	directory, err := contact.New(contact.DefaultConfig, &fakePostgres{})
+	s.NoError(err)
+	s.IsType(&contact.PostgresDirectory{}, directory)
+}
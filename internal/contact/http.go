@@ -0,0 +1,97 @@
+package contact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// maxResponseSize bounds the body read from the http provider.
+const maxResponseSize = 1 << 20
+
+// HTTPDirectory looks contacts up from the service which owns them, with a
+// GET of its URL followed by the external customer ID. The service answers
+// with a JSON contact, or 404 when there is none.
+type HTTPDirectory struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func NewHTTPDirectory(client *http.Client, url, token string) *HTTPDirectory {
+	return &HTTPDirectory{
+		client: client,
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+	}
+}
+
+type jsonDevice struct {
+	Type   model.DeviceTokenType `json:"type"`
+	Token  string                `json:"token"`
+	Locale string                `json:"locale"`
+}
+
+type jsonContact struct {
+	ExternalCustomerID string       `json:"external_customer_id"`
+	EmailAddresses     []string     `json:"email_addresses"`
+	PhoneNumbers       []string     `json:"phone_numbers"`
+	Devices            []jsonDevice `json:"devices"`
+	Locale             string       `json:"locale"`
+	Timezone           string       `json:"timezone"`
+}
+
+func (d *HTTPDirectory) Lookup(ctx context.Context, externalCustomerID string) (model.Contact, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url+"/"+url.PathEscape(externalCustomerID), nil)
+	if err != nil {
+		return model.Contact{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return model.Contact{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return model.Contact{}, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return model.Contact{}, fmt.Errorf("contact provider responded with %s", resp.Status)
+	}
+	body := jsonContact{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&body); err != nil {
+		return model.Contact{}, fmt.Errorf("decoding contact: %w", err)
+	}
+
+	contact := model.Contact{
+		ExternalCustomerID: externalCustomerID,
+		EmailAddresses:     body.EmailAddresses,
+		PhoneNumbers:       body.PhoneNumbers,
+		Locale:             body.Locale,
+		Timezone:           body.Timezone,
+	}
+	for _, device := range body.Devices {
+		contact.Devices = append(contact.Devices, model.DeviceToken{
+			ExternalCustomerID: externalCustomerID,
+			Type:               device.Type,
+			Token:              device.Token,
+			Locale:             device.Locale,
+		})
+	}
+	return contact, nil
+}
+
+// Upsert always fails, as the owning service is the source of truth.
+func (d *HTTPDirectory) Upsert(context.Context, model.Contact) error {
+	return ErrReadOnly
+}
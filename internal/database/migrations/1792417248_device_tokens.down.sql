@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS device_tokens;
+DROP TYPE IF EXISTS device_token_type;
+
+COMMIT;
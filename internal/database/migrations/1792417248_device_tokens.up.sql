@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TYPE device_token_type AS ENUM('FCM', 'APNS');
+
+CREATE TABLE IF NOT EXISTS device_tokens (
+  id TEXT NOT NULL,
+  external_customer_id TEXT NOT NULL,
+  "type" device_token_type NOT NULL,
+  token TEXT NOT NULL,
+  locale TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (id),
+  UNIQUE ("type", token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_external_customer_id ON device_tokens (external_customer_id);
+
+COMMIT;
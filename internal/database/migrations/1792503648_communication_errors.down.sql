@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE communications DROP COLUMN IF EXISTS error_message;
+ALTER TABLE communications DROP COLUMN IF EXISTS error_provider;
+ALTER TABLE communications DROP COLUMN IF EXISTS error_kind;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS error_kind TEXT DEFAULT NULL;
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS error_provider TEXT DEFAULT NULL;
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS error_message TEXT DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE communications DROP COLUMN IF EXISTS expire_at;
+ALTER TABLE communications DROP COLUMN IF EXISTS backoff_coefficient;
+ALTER TABLE communications DROP COLUMN IF EXISTS max_attempts;
+ALTER TABLE communications DROP COLUMN IF EXISTS attempt_timeout;
+
+-- postgres can't drop a value from an enum, so recreate it without EXPIRED.
+UPDATE communications SET "status" = 'FAILED' WHERE "status" = 'EXPIRED';
+ALTER TABLE communications ALTER COLUMN "status" DROP DEFAULT;
+ALTER TYPE communication_status RENAME TO communication_status_old;
+CREATE TYPE communication_status AS ENUM('PENDING', 'SUCCESS', 'FAILED');
+ALTER TABLE communications ALTER COLUMN "status" TYPE communication_status USING "status"::text::communication_status;
+ALTER TABLE communications ALTER COLUMN "status" SET DEFAULT 'PENDING';
+DROP TYPE communication_status_old;
+
+COMMIT;
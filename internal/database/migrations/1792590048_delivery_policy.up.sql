@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TYPE communication_status ADD VALUE IF NOT EXISTS 'EXPIRED';
+
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS attempt_timeout INTERVAL DEFAULT NULL;
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS max_attempts INTEGER DEFAULT NULL;
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS backoff_coefficient DOUBLE PRECISION DEFAULT NULL;
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS expire_at TIMESTAMPTZ DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS payloads (
+  key TEXT NOT NULL,
+  data BYTEA NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (key)
+);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_device_tokens_customer_hash;
+DROP INDEX IF EXISTS idx_device_tokens_type_token_hash;
+ALTER TABLE device_tokens ADD CONSTRAINT device_tokens_type_token_key UNIQUE ("type", token);
+
+ALTER TABLE device_tokens DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE device_tokens DROP COLUMN IF EXISTS customer_hash;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE device_tokens ADD COLUMN IF NOT EXISTS customer_hash BYTEA DEFAULT NULL;
+ALTER TABLE device_tokens ADD COLUMN IF NOT EXISTS token_hash BYTEA DEFAULT NULL;
+
+-- Encrypted tokens are unique per ciphertext, so uniqueness moves to the blind index.
+ALTER TABLE device_tokens DROP CONSTRAINT IF EXISTS device_tokens_type_token_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_tokens_type_token_hash ON device_tokens ("type", token_hash);
+CREATE INDEX IF NOT EXISTS idx_device_tokens_customer_hash ON device_tokens (customer_hash);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS erasures;
+
+DROP INDEX IF EXISTS idx_communications_retention;
+DROP INDEX IF EXISTS idx_communications_recipient_hashes;
+
+ALTER TABLE communications DROP COLUMN IF EXISTS redacted_at;
+ALTER TABLE communications DROP COLUMN IF EXISTS recipient_hashes;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS recipient_hashes BYTEA[] DEFAULT NULL;
+ALTER TABLE communications ADD COLUMN IF NOT EXISTS redacted_at TIMESTAMPTZ DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_communications_recipient_hashes ON communications USING GIN (recipient_hashes);
+CREATE INDEX IF NOT EXISTS idx_communications_retention ON communications (domain, created_at) WHERE redacted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS erasures (
+  id TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  domain TEXT NOT NULL DEFAULT '',
+  recipient_hashes BYTEA[] DEFAULT NULL,
+  dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+  communications INTEGER NOT NULL DEFAULT 0,
+  response_channels INTEGER NOT NULL DEFAULT 0,
+  device_tokens INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (id)
+);
+
+COMMIT;
@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL NOT NULL,
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  principal TEXT NOT NULL,
+  peer TEXT NOT NULL DEFAULT '',
+  domain TEXT NOT NULL DEFAULT '',
+  method TEXT NOT NULL,
+  request_hash TEXT NOT NULL DEFAULT '',
+  code TEXT NOT NULL,
+  workflow_id TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_principal ON audit_log (principal, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_domain ON audit_log (domain, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_workflow_id ON audit_log (workflow_id) WHERE workflow_id <> '';
+
+-- The audit log is append only.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+  BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE erasures DROP COLUMN IF EXISTS contacts;
+DROP TABLE IF EXISTS contacts;
+
+COMMIT;
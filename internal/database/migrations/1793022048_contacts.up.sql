@@ -0,0 +1,19 @@
+BEGIN;
+
+-- The customer ID, email addresses and phone numbers are encrypted, so
+-- contacts are looked up by the blind index of the customer ID.
+CREATE TABLE IF NOT EXISTS contacts (
+  customer_hash BYTEA NOT NULL,
+  external_customer_id TEXT NOT NULL,
+  email_addresses TEXT[] NOT NULL DEFAULT '{}',
+  phone_numbers TEXT[] NOT NULL DEFAULT '{}',
+  locale TEXT NOT NULL DEFAULT '',
+  timezone TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (customer_hash)
+);
+
+ALTER TABLE erasures ADD COLUMN IF NOT EXISTS contacts INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
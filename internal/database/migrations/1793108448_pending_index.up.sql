@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Backs the pending communications metric, which is read every 30 seconds.
+CREATE INDEX IF NOT EXISTS idx_communications_pending ON communications (domain) WHERE "status" = 'PENDING';
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_communications_created_at;
+
+-- postgres can't drop a value from an enum, so recreate it without CANCELLED.
+DROP INDEX IF EXISTS idx_communications_pending;
+UPDATE communications SET "status" = 'FAILED' WHERE "status" = 'CANCELLED';
+ALTER TABLE communications ALTER COLUMN "status" DROP DEFAULT;
+ALTER TYPE communication_status RENAME TO communication_status_old;
+CREATE TYPE communication_status AS ENUM('PENDING', 'SUCCESS', 'FAILED', 'EXPIRED');
+ALTER TABLE communications ALTER COLUMN "status" TYPE communication_status USING "status"::text::communication_status;
+ALTER TABLE communications ALTER COLUMN "status" SET DEFAULT 'PENDING';
+DROP TYPE communication_status_old;
+CREATE INDEX IF NOT EXISTS idx_communications_pending ON communications (domain) WHERE "status" = 'PENDING';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TYPE communication_status ADD VALUE IF NOT EXISTS 'CANCELLED';
+
+-- Backs listing communications newest first.
+CREATE INDEX IF NOT EXISTS idx_communications_created_at ON communications (created_at DESC, id DESC);
+
+COMMIT;
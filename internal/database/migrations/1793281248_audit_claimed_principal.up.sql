@@ -0,0 +1,6 @@
+BEGIN;
+
+-- The principal header of callers it isn't trusted for.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS claimed_principal TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- The fingerprint of the key blind indexes are computed with, so a server
+-- started with a different key refuses to run rather than finding nothing.
+-- Communications created before it was recorded may be indexed without a key.
+CREATE TABLE IF NOT EXISTS blind_index (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  fingerprint BYTEA NOT NULL,
+  recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -1,7 +1,10 @@
 package database
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,24 +13,47 @@ import (
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 
+	"github.com/anicoll/unicom/internal/encryption"
+	"github.com/anicoll/unicom/internal/failure"
 	"github.com/anicoll/unicom/internal/model"
 )
 
+// uniqueViolation is the SQLSTATE of an insert conflicting with a unique
+// constraint.
+const uniqueViolation = "23505"
+
+// blindIndexFingerprint is the value whose blind index identifies the key
+// rows are indexed with.
+const blindIndexFingerprint = "unicom blind index"
+
+var errBlindIndexKeyChanged = errors.New("rows were indexed with a different blind index key, which must never change")
+
 type Postgres struct {
-	pool   pool
-	logger *zap.Logger
+	pool      pool
+	logger    *zap.Logger
+	encryptor encryptor
 }
 
 type pool interface {
 	Ping(ctx context.Context) error
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// encryptor protects personal data stored in the database. Encrypted columns
+// which need to be looked up are paired with a blind index column.
+type encryptor interface {
+	EncryptString(ctx context.Context, plaintext string) (string, error)
+	DecryptString(ctx context.Context, value string) (string, error)
+	BlindIndex(value string) []byte
 }
 
-func New(pool pool, logger *zap.Logger) *Postgres {
+func New(pool pool, logger *zap.Logger, encryptor encryptor) *Postgres {
 	return &Postgres{
-		pool:   pool,
-		logger: logger,
+		pool:      pool,
+		logger:    logger,
+		encryptor: encryptor,
 	}
 }
 
@@ -35,6 +61,158 @@ func (p *Postgres) Ping(ctx context.Context) error {
 	return p.pool.Ping(ctx)
 }
 
+// CheckBlindIndex refuses a blind index key other than the one rows were
+// indexed with, as lookups would silently find nothing. The first time it
+// runs, contacts and device tokens indexed before the key was a required
+// setting are re-indexed with it and its fingerprint is recorded.
+func (p *Postgres) CheckBlindIndex(ctx context.Context) error {
+	fingerprint := p.encryptor.BlindIndex(blindIndexFingerprint)
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	// a server and worker starting together wait for whichever re-indexes first
+	if _, err := tx.Exec(ctx, `LOCK TABLE blind_index IN EXCLUSIVE MODE`); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	rows, err := tx.Query(ctx, `SELECT fingerprint FROM blind_index`)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	recorded, err := pgx.CollectRows(rows, pgx.RowTo[[]byte])
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	if len(recorded) > 0 {
+		_ = tx.Rollback(ctx)
+		if !bytes.Equal(recorded[0], fingerprint) {
+			return errBlindIndexKeyChanged
+		}
+		return nil
+	}
+
+	if err := p.reindexContacts(ctx, tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	if err := p.reindexDeviceTokens(ctx, tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO blind_index (fingerprint) VALUES ($1)`, fingerprint); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// reindexContacts re-indexes contacts indexed without a key.
+func (p *Postgres) reindexContacts(ctx context.Context, tx pgx.Tx) error {
+	rows, err := tx.Query(ctx, `SELECT customer_hash, external_customer_id FROM contacts`)
+	if err != nil {
+		return err
+	}
+	type contact struct {
+		customerHash []byte
+		customerID   string
+	}
+	contacts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (contact, error) {
+		c := contact{}
+		err := row.Scan(&c.customerHash, &c.customerID)
+		return c, err
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range contacts {
+		customerID, err := p.encryptor.DecryptString(ctx, c.customerID)
+		if err != nil {
+			return err
+		}
+		customerHash, changed, err := p.reindexed(c.customerHash, customerID)
+		if err != nil {
+			return fmt.Errorf("contacts: %w", err)
+		}
+		if !changed {
+			continue
+		}
+		_, err = tx.Exec(ctx, `UPDATE contacts SET customer_hash = $1 WHERE customer_hash = $2`, customerHash, c.customerHash)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexDeviceTokens re-indexes device tokens indexed without a key, and
+// indexes those written before encryption was introduced.
+func (p *Postgres) reindexDeviceTokens(ctx context.Context, tx pgx.Tx) error {
+	rows, err := tx.Query(ctx, `SELECT id, customer_hash, external_customer_id, token_hash, token FROM device_tokens`)
+	if err != nil {
+		return err
+	}
+	type deviceToken struct {
+		id           string
+		customerHash []byte
+		customerID   string
+		tokenHash    []byte
+		token        string
+	}
+	tokens, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (deviceToken, error) {
+		t := deviceToken{}
+		err := row.Scan(&t.id, &t.customerHash, &t.customerID, &t.tokenHash, &t.token)
+		return t, err
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		customerID, err := p.encryptor.DecryptString(ctx, t.customerID)
+		if err != nil {
+			return err
+		}
+		token, err := p.encryptor.DecryptString(ctx, t.token)
+		if err != nil {
+			return err
+		}
+		customerHash, customerChanged, err := p.reindexed(t.customerHash, customerID)
+		if err != nil {
+			return fmt.Errorf("device tokens: %w", err)
+		}
+		tokenHash, tokenChanged, err := p.reindexed(t.tokenHash, token)
+		if err != nil {
+			return fmt.Errorf("device tokens: %w", err)
+		}
+		if !customerChanged && !tokenChanged {
+			continue
+		}
+		_, err = tx.Exec(ctx, `UPDATE device_tokens SET customer_hash = $1, token_hash = $2 WHERE id = $3`, customerHash, tokenHash, t.id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexed returns the blind index of value, and whether it replaces index
+// because index is missing or was computed without a key.
+func (p *Postgres) reindexed(index []byte, value string) ([]byte, bool, error) {
+	want := p.encryptor.BlindIndex(value)
+	switch {
+	case bytes.Equal(index, want):
+		return want, false, nil
+	case index == nil || bytes.Equal(index, encryption.UnkeyedBlindIndex(value)):
+		return want, true, nil
+	default:
+		return nil, false, errBlindIndexKeyChanged
+	}
+}
+
+// CreateCommunication records a communication and its response channels,
+// returning model.ErrCommunicationExists if its ID is taken.
 func (p *Postgres) CreateCommunication(ctx context.Context, comm *model.Communication) error {
 	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -43,10 +221,16 @@ func (p *Postgres) CreateCommunication(ctx context.Context, comm *model.Communic
 	}
 
 	_, err = tx.Exec(ctx,
-		`INSERT INTO communications (id, domain, "type")
-		 VALUES ($1, $2, $3)`, comm.ID, comm.Domain, comm.Type)
+		`INSERT INTO communications (id, domain, "type", attempt_timeout, max_attempts, backoff_coefficient, expire_at, recipient_hashes)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`, comm.ID, comm.Domain, comm.Type,
+		comm.Policy.AttemptTimeout, comm.Policy.MaxAttempts, comm.Policy.BackoffCoefficient, comm.Policy.ExpireAt,
+		p.blindIndexes(comm.Recipients))
 	if err != nil {
 		_ = tx.Rollback(ctx)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return model.ErrCommunicationExists
+		}
 		return err
 	}
 
@@ -71,6 +255,20 @@ func (p *Postgres) SetCommunicationStatus(ctx context.Context, workflowId string
 	return err
 }
 
+// SetCommunicationError records why a communication failed. Provider error
+// messages can echo recipients back, so the message is encrypted.
+func (p *Postgres) SetCommunicationError(ctx context.Context, workflowId string, details failure.Details) error {
+	message, err := p.encryptor.EncryptString(ctx, details.Message)
+	if err != nil {
+		return err
+	}
+	_, err = p.pool.Exec(ctx,
+		`UPDATE communications
+		 SET error_kind = $2, error_provider = $3, error_message = $4
+		 WHERE id = $1`, workflowId, details.Kind, details.Provider, message)
+	return err
+}
+
 func (p *Postgres) CreateResponseChannel(ctx context.Context, channel model.ResponseChannel) error {
 	_, err := p.pool.Exec(ctx,
 		`INSERT INTO response_channels (id, communication_id, "type", "url")
@@ -85,3 +283,442 @@ func (p *Postgres) SetResponseChannelStatus(ctx context.Context, id, externalId
 		 WHERE id = $1`, id, status, externalId, time.Now())
 	return err
 }
+
+// UpsertDeviceToken registers a device token for a customer. Re-registering an
+// existing token moves it to the given customer and locale. The customer ID and
+// token are stored encrypted and looked up by their blind indexes.
+func (p *Postgres) UpsertDeviceToken(ctx context.Context, token model.DeviceToken) error {
+	customerID, err := p.encryptor.EncryptString(ctx, token.ExternalCustomerID)
+	if err != nil {
+		return err
+	}
+	value, err := p.encryptor.EncryptString(ctx, token.Token)
+	if err != nil {
+		return err
+	}
+
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	// Rows written before encryption was enabled hold the token in plaintext
+	// and would otherwise be left behind as duplicates.
+	_, err = tx.Exec(ctx,
+		`DELETE FROM device_tokens
+		 WHERE "type" = $1 AND token = $2`, token.Type, token.Token)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO device_tokens (id, external_customer_id, customer_hash, "type", token, token_hash, locale)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT ("type", token_hash)
+		 DO UPDATE SET external_customer_id = EXCLUDED.external_customer_id, customer_hash = EXCLUDED.customer_hash,
+		               token = EXCLUDED.token, locale = EXCLUDED.locale`,
+		uuid.NewString(), customerID, p.encryptor.BlindIndex(token.ExternalCustomerID),
+		token.Type, value, p.encryptor.BlindIndex(token.Token), token.Locale)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (p *Postgres) ListDeviceTokens(ctx context.Context, externalCustomerId string, tokenType model.DeviceTokenType) ([]model.DeviceToken, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT id, external_customer_id, "type", token, locale, created_at
+		 FROM device_tokens
+		 WHERE (customer_hash = $1 OR external_customer_id = $2) AND "type" = $3
+		 ORDER BY created_at`, p.encryptor.BlindIndex(externalCustomerId), externalCustomerId, tokenType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []model.DeviceToken{}
+	for rows.Next() {
+		token := model.DeviceToken{}
+		err := rows.Scan(&token.ID, &token.ExternalCustomerID, &token.Type, &token.Token, &token.Locale, &token.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if token.ExternalCustomerID, err = p.encryptor.DecryptString(ctx, token.ExternalCustomerID); err != nil {
+			return nil, err
+		}
+		if token.Token, err = p.encryptor.DecryptString(ctx, token.Token); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (p *Postgres) DeleteDeviceToken(ctx context.Context, tokenType model.DeviceTokenType, token string) error {
+	_, err := p.pool.Exec(ctx,
+		`DELETE FROM device_tokens
+		 WHERE "type" = $1 AND (token_hash = $2 OR token = $3)`, tokenType, p.encryptor.BlindIndex(token), token)
+	return err
+}
+
+// PutPayload stores a payload offloaded from a workflow, replacing any stored
+// under the same key.
+func (p *Postgres) PutPayload(ctx context.Context, key string, data []byte) error {
+	_, err := p.pool.Exec(ctx,
+		`INSERT INTO payloads (key, data)
+		 VALUES ($1, $2)
+		 ON CONFLICT (key) DO UPDATE SET data = EXCLUDED.data`, key, data)
+	return err
+}
+
+func (p *Postgres) GetPayload(ctx context.Context, key string) ([]byte, error) {
+	rows, err := p.pool.Query(ctx, `SELECT data FROM payloads WHERE key = $1`, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[[]byte])
+	if err != nil {
+		return nil, fmt.Errorf("payload %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (p *Postgres) DeletePayload(ctx context.Context, key string) error {
+	_, err := p.pool.Exec(ctx, `DELETE FROM payloads WHERE key = $1`, key)
+	return err
+}
+
+// DeletePayloads removes every payload whose key starts with prefix.
+func (p *Postgres) DeletePayloads(ctx context.Context, prefix string) error {
+	_, err := p.pool.Exec(ctx, `DELETE FROM payloads WHERE starts_with(key, $1)`, prefix)
+	return err
+}
+
+// ListCommunicationDomains returns every domain with communications which
+// haven't been redacted.
+func (p *Postgres) ListCommunicationDomains(ctx context.Context) ([]string, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT DISTINCT domain FROM communications WHERE redacted_at IS NULL ORDER BY domain`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// ListExpiredCommunications returns up to limit IDs, ordered and following
+// after, of a domain's finished communications created before the given time
+// which haven't been redacted. Communications still pending are included once
+// created before pendingBefore, as by then they are stuck.
+func (p *Postgres) ListExpiredCommunications(ctx context.Context, domain string, before, pendingBefore time.Time, after string, limit int) ([]string, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT id FROM communications
+		 WHERE domain = $1 AND redacted_at IS NULL AND id > $4
+		   AND (("status" <> 'PENDING' AND created_at < $2) OR ("status" = 'PENDING' AND created_at < $3))
+		 ORDER BY id
+		 LIMIT $5`, domain, before, pendingBefore, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// CountPendingCommunications returns how many communications of each domain
+// are waiting to be sent, including those scheduled for later.
+func (p *Postgres) CountPendingCommunications(ctx context.Context) (map[string]int64, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT domain, COUNT(*) FROM communications
+		 WHERE "status" = 'PENDING'
+		 GROUP BY domain`)
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{}
+	var domain string
+	var count int64
+	_, err = pgx.ForEachRow(rows, []any{&domain, &count}, func() error {
+		counts[domain] = count
+		return nil
+	})
+	return counts, err
+}
+
+// ListCommunications returns up to limit communications matching filter,
+// newest first.
+func (p *Postgres) ListCommunications(ctx context.Context, filter model.CommunicationFilter, limit int) ([]model.Communication, error) {
+	var beforeTime *time.Time
+	var beforeID string
+	if filter.Before != nil {
+		beforeTime, beforeID = &filter.Before.CreatedAt, filter.Before.ID
+	}
+	rows, err := p.pool.Query(ctx,
+		`SELECT id, domain, "type", "status", created_at, sent_at, external_id,
+		        COALESCE(error_kind, ''), COALESCE(error_provider, '')
+		 FROM communications
+		 WHERE ($1 = '' OR domain = $1)
+		   AND ($2 = '' OR "status"::TEXT = $2)
+		   AND ($3::TIMESTAMPTZ IS NULL OR created_at >= $3)
+		   AND ($4::TIMESTAMPTZ IS NULL OR created_at < $4)
+		   AND ($5::TIMESTAMPTZ IS NULL OR (created_at, id) < ($5, $6))
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $7`,
+		filter.Domain, filter.Status, filter.Since, filter.Until, beforeTime, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	var comm model.Communication
+	var comms []model.Communication
+	_, err = pgx.ForEachRow(rows, []any{&comm.ID, &comm.Domain, &comm.Type, &comm.Status, &comm.CreatedAt, &comm.SentAt, &comm.ExternalId, &comm.ErrorKind, &comm.ErrorProvider}, func() error {
+		comms = append(comms, comm)
+		return nil
+	})
+	return comms, err
+}
+
+// ListRecipientCommunications returns the IDs of the communications sent to
+// any of recipients which haven't been redacted. Communications can't be
+// re-indexed as their recipients aren't kept, so those created before the
+// blind index key was recorded are also matched without a key.
+func (p *Postgres) ListRecipientCommunications(ctx context.Context, recipients []string) ([]string, error) {
+	unkeyed := make([][]byte, len(recipients))
+	for i, recipient := range recipients {
+		unkeyed[i] = encryption.UnkeyedBlindIndex(recipient)
+	}
+	rows, err := p.pool.Query(ctx,
+		`SELECT id FROM communications
+		 WHERE (recipient_hashes && $1::BYTEA[]
+		        OR (recipient_hashes && $2::BYTEA[] AND created_at < (SELECT recorded_at FROM blind_index)))
+		   AND redacted_at IS NULL
+		 ORDER BY id`, p.blindIndexes(recipients), unkeyed)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// RedactCommunications removes the recipients, provider references, errors
+// and response channel URLs of communications, keeping what is needed for
+// reporting. A dry run only counts what would be redacted.
+func (p *Postgres) RedactCommunications(ctx context.Context, ids []string, dryRun bool) (model.Redaction, error) {
+	redaction := model.Redaction{}
+	if dryRun {
+		rows, err := p.pool.Query(ctx,
+			`SELECT (SELECT COUNT(*) FROM communications WHERE id = ANY($1)) AS communications,
+			        (SELECT COUNT(*) FROM response_channels WHERE communication_id = ANY($1)) AS response_channels`, ids)
+		if err != nil {
+			return redaction, err
+		}
+		return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.Redaction])
+	}
+
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return redaction, err
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE communications
+		 SET recipient_hashes = NULL, external_id = NULL, error_message = NULL, redacted_at = NOW()
+		 WHERE id = ANY($1)`, ids)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return redaction, err
+	}
+	redaction.Communications = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx,
+		`UPDATE response_channels
+		 SET "url" = '', external_id = NULL
+		 WHERE communication_id = ANY($1)`, ids)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return redaction, err
+	}
+	redaction.ResponseChannels = int(tag.RowsAffected())
+	return redaction, tx.Commit(ctx)
+}
+
+// DeleteCustomerDeviceTokens removes every device token of a customer,
+// returning how many there were. A dry run only counts them.
+func (p *Postgres) DeleteCustomerDeviceTokens(ctx context.Context, externalCustomerID string, dryRun bool) (int, error) {
+	customerHash := p.encryptor.BlindIndex(externalCustomerID)
+	if dryRun {
+		rows, err := p.pool.Query(ctx,
+			`SELECT COUNT(*) FROM device_tokens
+			 WHERE customer_hash = $1 OR external_customer_id = $2`, customerHash, externalCustomerID)
+		if err != nil {
+			return 0, err
+		}
+		return pgx.CollectExactlyOneRow(rows, pgx.RowTo[int])
+	}
+	tag, err := p.pool.Exec(ctx,
+		`DELETE FROM device_tokens
+		 WHERE customer_hash = $1 OR external_customer_id = $2`, customerHash, externalCustomerID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// CreateErasure writes the audit record of an erasure.
+func (p *Postgres) CreateErasure(ctx context.Context, erasure model.Erasure) error {
+	_, err := p.pool.Exec(ctx,
+		`INSERT INTO erasures (id, reason, domain, recipient_hashes, dry_run, communications, response_channels, device_tokens, contacts)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		erasure.ID, erasure.Reason, erasure.Domain, p.blindIndexes(erasure.Recipients), erasure.DryRun,
+		erasure.Communications, erasure.ResponseChannels, erasure.DeviceTokens, erasure.Contacts)
+	return err
+}
+
+// blindIndexes returns the blind index of each value, or nil for none.
+func (p *Postgres) blindIndexes(values []string) [][]byte {
+	if len(values) == 0 {
+		return nil
+	}
+	indexes := make([][]byte, len(values))
+	for i, value := range values {
+		indexes[i] = p.encryptor.BlindIndex(value)
+	}
+	return indexes
+}
+
+// UpsertContact creates or replaces a contact and registers its devices. The
+// customer ID, email addresses and phone numbers are stored encrypted.
+func (p *Postgres) UpsertContact(ctx context.Context, contact model.Contact) error {
+	customerID, err := p.encryptor.EncryptString(ctx, contact.ExternalCustomerID)
+	if err != nil {
+		return err
+	}
+	emailAddresses, err := p.encryptStrings(ctx, contact.EmailAddresses)
+	if err != nil {
+		return err
+	}
+	phoneNumbers, err := p.encryptStrings(ctx, contact.PhoneNumbers)
+	if err != nil {
+		return err
+	}
+	_, err = p.pool.Exec(ctx,
+		`INSERT INTO contacts (customer_hash, external_customer_id, email_addresses, phone_numbers, locale, timezone)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (customer_hash)
+		 DO UPDATE SET external_customer_id = EXCLUDED.external_customer_id, email_addresses = EXCLUDED.email_addresses,
+		               phone_numbers = EXCLUDED.phone_numbers, locale = EXCLUDED.locale, timezone = EXCLUDED.timezone,
+		               updated_at = NOW()`,
+		p.encryptor.BlindIndex(contact.ExternalCustomerID), customerID, emailAddresses, phoneNumbers, contact.Locale, contact.Timezone)
+	if err != nil {
+		return err
+	}
+	for _, device := range contact.Devices {
+		device.ExternalCustomerID = contact.ExternalCustomerID
+		if err := p.UpsertDeviceToken(ctx, device); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetContact returns a contact with its devices, or pgx.ErrNoRows if there is
+// none.
+func (p *Postgres) GetContact(ctx context.Context, externalCustomerID string) (model.Contact, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT external_customer_id, email_addresses, phone_numbers, locale, timezone, updated_at
+		 FROM contacts
+		 WHERE customer_hash = $1`, p.encryptor.BlindIndex(externalCustomerID))
+	if err != nil {
+		return model.Contact{}, err
+	}
+	contact, err := pgx.CollectExactlyOneRow(rows, func(row pgx.CollectableRow) (model.Contact, error) {
+		contact := model.Contact{}
+		err := row.Scan(&contact.ExternalCustomerID, &contact.EmailAddresses, &contact.PhoneNumbers, &contact.Locale, &contact.Timezone, &contact.UpdatedAt)
+		return contact, err
+	})
+	if err != nil {
+		return contact, err
+	}
+	if contact.ExternalCustomerID, err = p.encryptor.DecryptString(ctx, contact.ExternalCustomerID); err != nil {
+		return contact, err
+	}
+	if contact.EmailAddresses, err = p.decryptStrings(ctx, contact.EmailAddresses); err != nil {
+		return contact, err
+	}
+	if contact.PhoneNumbers, err = p.decryptStrings(ctx, contact.PhoneNumbers); err != nil {
+		return contact, err
+	}
+	for _, tokenType := range []model.DeviceTokenType{model.FCM, model.APNs} {
+		devices, err := p.ListDeviceTokens(ctx, externalCustomerID, tokenType)
+		if err != nil {
+			return contact, err
+		}
+		contact.Devices = append(contact.Devices, devices...)
+	}
+	return contact, nil
+}
+
+// DeleteContact removes a contact, returning how many there were. A dry run
+// only counts them. The contact's devices are left to
+// DeleteCustomerDeviceTokens.
+func (p *Postgres) DeleteContact(ctx context.Context, externalCustomerID string, dryRun bool) (int, error) {
+	customerHash := p.encryptor.BlindIndex(externalCustomerID)
+	if dryRun {
+		rows, err := p.pool.Query(ctx, `SELECT COUNT(*) FROM contacts WHERE customer_hash = $1`, customerHash)
+		if err != nil {
+			return 0, err
+		}
+		return pgx.CollectExactlyOneRow(rows, pgx.RowTo[int])
+	}
+	tag, err := p.pool.Exec(ctx, `DELETE FROM contacts WHERE customer_hash = $1`, customerHash)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (p *Postgres) encryptStrings(ctx context.Context, values []string) ([]string, error) {
+	encrypted := make([]string, len(values))
+	for i, value := range values {
+		var err error
+		if encrypted[i], err = p.encryptor.EncryptString(ctx, value); err != nil {
+			return nil, err
+		}
+	}
+	return encrypted, nil
+}
+
+func (p *Postgres) decryptStrings(ctx context.Context, values []string) ([]string, error) {
+	decrypted := make([]string, len(values))
+	for i, value := range values {
+		var err error
+		if decrypted[i], err = p.encryptor.DecryptString(ctx, value); err != nil {
+			return nil, err
+		}
+	}
+	return decrypted, nil
+}
+
+// InsertAuditEvent appends an event to the audit log.
+func (p *Postgres) InsertAuditEvent(ctx context.Context, event model.AuditEvent) error {
+	_, err := p.pool.Exec(ctx,
+		`INSERT INTO audit_log (occurred_at, principal, claimed_principal, peer, domain, method, request_hash, code, workflow_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		event.OccurredAt, event.Principal, event.ClaimedPrincipal, event.Peer, event.Domain, event.Method, event.RequestHash, event.Code, event.WorkflowID)
+	return err
+}
+
+// ListAuditEvents returns up to limit events matching filter, newest first.
+func (p *Postgres) ListAuditEvents(ctx context.Context, filter model.AuditFilter, limit int) ([]model.AuditEvent, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT id, occurred_at, principal, claimed_principal, peer, domain, method, request_hash, code, workflow_id
+		 FROM audit_log
+		 WHERE ($1 = '' OR principal = $1)
+		   AND ($2 = '' OR domain = $2)
+		   AND ($3 = '' OR method = $3)
+		   AND ($4 = '' OR code = $4)
+		   AND ($5 = '' OR workflow_id = $5)
+		   AND ($6::TIMESTAMPTZ IS NULL OR occurred_at >= $6)
+		   AND ($7::TIMESTAMPTZ IS NULL OR occurred_at < $7)
+		   AND ($8 = 0 OR id < $8)
+		 ORDER BY id DESC
+		 LIMIT $9`,
+		filter.Principal, filter.Domain, filter.Method, filter.Code, filter.WorkflowID, filter.Since, filter.Until, filter.Before, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.AuditEvent])
+}
@@ -3,10 +3,13 @@ package database_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go"
@@ -14,6 +17,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/anicoll/unicom/internal/database"
+	"github.com/anicoll/unicom/internal/encryption"
 	"github.com/anicoll/unicom/internal/model"
 )
 
@@ -62,7 +66,7 @@ func (s *PostgresUnitTestSuite) SetupSuite() {
 	s.conn, err = pgxpool.NewWithConfig(s.ctx, parsedCfg)
 	s.NoError(err)
 
-	s.postgres = database.New(s.conn, zap.NewNop())
+	s.postgres = database.New(s.conn, zap.NewNop(), encryption.NewEncryptor(nil, []byte("index-key")))
 	migrations := database.NewMigrations(s.dbdsn, database.MigrateUp)
 	err = migrations.Execute()
 	s.NoError(err)
@@ -113,3 +117,323 @@ func (s *PostgresUnitTestSuite) Test_CreateCommunication_Success() {
 		s.Fail("expected not equal", cmp.Diff(expectedCommRequest, got, cmpopts.IgnoreFields(model.Communication{}, "CreatedAt", "SentAt", "ID", "ResponseChannels")))
 	}
 }
+
+func (s *PostgresUnitTestSuite) Test_CreateCommunication_Exists() {
+	ctx := context.Background()
+
+	comm := &model.Communication{ID: "duplicate-1", Domain: "test-domain", Type: model.Email}
+	s.Require().NoError(s.postgres.CreateCommunication(ctx, comm))
+	s.ErrorIs(s.postgres.CreateCommunication(ctx, comm), model.ErrCommunicationExists)
+}
+
+func (s *PostgresUnitTestSuite) Test_CountPendingCommunications_Success() {
+	ctx := context.Background()
+
+	for _, id := range []string{"pending-1", "pending-2", "sent-1"} {
+		err := s.postgres.CreateCommunication(ctx, &model.Communication{ID: id, Domain: "count-domain", Type: model.Email})
+		s.Require().NoError(err)
+	}
+	s.Require().NoError(s.postgres.SetCommunicationStatus(ctx, "sent-1", model.Success, nil))
+
+	counts, err := s.postgres.CountPendingCommunications(ctx)
+	s.NoError(err)
+	s.Equal(int64(2), counts["count-domain"])
+}
+
+func (s *PostgresUnitTestSuite) Test_ListCommunications_Success() {
+	ctx := context.Background()
+
+	for _, id := range []string{"list-1", "list-2", "list-3"} {
+		err := s.postgres.CreateCommunication(ctx, &model.Communication{ID: id, Domain: "list-domain", Type: model.Email})
+		s.Require().NoError(err)
+	}
+	s.Require().NoError(s.postgres.SetCommunicationStatus(ctx, "list-2", model.Cancelled, nil))
+
+	comms, err := s.postgres.ListCommunications(ctx, model.CommunicationFilter{Domain: "list-domain"}, 2)
+	s.NoError(err)
+	s.Require().Len(comms, 2)
+	s.Equal("list-3", comms[0].ID, "newest first")
+	s.Equal("list-2", comms[1].ID)
+	s.Equal(model.Cancelled, comms[1].Status)
+
+	comms, err = s.postgres.ListCommunications(ctx, model.CommunicationFilter{
+		Domain: "list-domain",
+		Before: &model.CommunicationCursor{CreatedAt: comms[1].CreatedAt, ID: comms[1].ID},
+	}, 2)
+	s.NoError(err)
+	s.Require().Len(comms, 1)
+	s.Equal("list-1", comms[0].ID)
+
+	comms, err = s.postgres.ListCommunications(ctx, model.CommunicationFilter{Domain: "list-domain", Status: model.Cancelled}, 10)
+	s.NoError(err)
+	s.Require().Len(comms, 1)
+	s.Equal("list-2", comms[0].ID)
+}
+
+func (s *PostgresUnitTestSuite) Test_DeviceTokens_Success() {
+	ctx := context.Background()
+
+	token := model.DeviceToken{
+		ID:                 "device-token-id",
+		ExternalCustomerID: "customer-1",
+		Type:               model.FCM,
+		Token:              "fcm-token",
+		Locale:             "en",
+	}
+	s.NoError(s.postgres.UpsertDeviceToken(ctx, token))
+
+	token.Locale = "ar"
+	s.NoError(s.postgres.UpsertDeviceToken(ctx, token))
+
+	got, err := s.postgres.ListDeviceTokens(ctx, "customer-1", model.FCM)
+	s.NoError(err)
+	s.Len(got, 1)
+	s.Equal("ar", got[0].Locale)
+
+	s.NoError(s.postgres.DeleteDeviceToken(ctx, model.FCM, "fcm-token"))
+	got, err = s.postgres.ListDeviceTokens(ctx, "customer-1", model.FCM)
+	s.NoError(err)
+	s.Empty(got)
+}
+
+func (s *PostgresUnitTestSuite) Test_Erasure_Success() {
+	ctx := context.Background()
+
+	s.NoError(s.postgres.CreateCommunication(ctx, &model.Communication{
+		ID:         "erasure-1",
+		Domain:     "erasure-domain",
+		Type:       model.Email,
+		Recipients: []string{model.EmailRecipient("someone@example.com")},
+		ResponseChannels: []*model.ResponseChannel{
+			{ID: "erasure-channel-1", Type: model.Webhook, Url: "https://example.com/hook?customer=someone"},
+		},
+	}))
+	s.NoError(s.postgres.SetCommunicationStatus(ctx, "erasure-1", model.Success, nil))
+
+	ids, err := s.postgres.ListRecipientCommunications(ctx, []string{model.EmailRecipient("SOMEONE@example.com")})
+	s.NoError(err)
+	s.Equal([]string{"erasure-1"}, ids)
+
+	ids, err = s.postgres.ListExpiredCommunications(ctx, "erasure-domain", time.Now().Add(time.Hour), time.Now().Add(time.Hour), "", 10)
+	s.NoError(err)
+	s.Equal([]string{"erasure-1"}, ids)
+
+	redaction, err := s.postgres.RedactCommunications(ctx, ids, true)
+	s.NoError(err)
+	s.Equal(model.Redaction{Communications: 1, ResponseChannels: 1}, redaction)
+	ids, err = s.postgres.ListRecipientCommunications(ctx, []string{model.EmailRecipient("someone@example.com")})
+	s.NoError(err)
+	s.Len(ids, 1, "a dry run changes nothing")
+
+	redaction, err = s.postgres.RedactCommunications(ctx, ids, false)
+	s.NoError(err)
+	s.Equal(model.Redaction{Communications: 1, ResponseChannels: 1}, redaction)
+	ids, err = s.postgres.ListRecipientCommunications(ctx, []string{model.EmailRecipient("someone@example.com")})
+	s.NoError(err)
+	s.Empty(ids)
+
+	var url string
+	s.NoError(s.conn.QueryRow(ctx, `SELECT "url" FROM response_channels WHERE id = 'erasure-channel-1'`).Scan(&url))
+	s.Empty(url)
+
+	s.NoError(s.postgres.CreateErasure(ctx, model.Erasure{
+		ID:             "erasure-id",
+		Reason:         model.ErasureRequested,
+		Recipients:     []string{model.EmailRecipient("someone@example.com")},
+		Communications: 1,
+	}))
+
+	s.NoError(s.postgres.CreateCommunication(ctx, &model.Communication{
+		ID:     "erasure-pending",
+		Domain: "erasure-domain",
+		Type:   model.Email,
+	}))
+	ids, err = s.postgres.ListExpiredCommunications(ctx, "erasure-domain", time.Now().Add(time.Hour), time.Now().Add(-time.Hour), "", 10)
+	s.NoError(err)
+	s.Empty(ids, "a recent pending communication may be scheduled")
+	ids, err = s.postgres.ListExpiredCommunications(ctx, "erasure-domain", time.Now().Add(time.Hour), time.Now().Add(time.Hour), "", 10)
+	s.NoError(err)
+	s.Equal([]string{"erasure-pending"}, ids, "a pending communication past the grace window is stuck")
+}
+
+func (s *PostgresUnitTestSuite) Test_BlindIndex_Reindexed() {
+	ctx := context.Background()
+
+	// Before the blind index key was a required setting, rows were indexed
+	// without a key unless encryption was enabled.
+	unkeyed := database.New(s.conn, zap.NewNop(), encryption.NewEncryptor(nil, nil))
+	s.NoError(unkeyed.UpsertContact(ctx, model.Contact{
+		ExternalCustomerID: "reindexed-customer",
+		Devices:            []model.DeviceToken{{Type: model.FCM, Token: "reindexed-token"}},
+	}))
+	s.NoError(unkeyed.CreateCommunication(ctx, &model.Communication{
+		ID:         "reindexed-1",
+		Domain:     "reindexed-domain",
+		Type:       model.Email,
+		Recipients: []string{model.EmailRecipient("reindexed@example.com")},
+	}))
+
+	s.NoError(s.postgres.CheckBlindIndex(ctx))
+	s.NoError(s.postgres.CheckBlindIndex(ctx), "the same key is accepted once recorded")
+
+	_, err := s.postgres.GetContact(ctx, "reindexed-customer")
+	s.NoError(err)
+	var tokens int
+	s.NoError(s.conn.QueryRow(ctx, `SELECT COUNT(*) FROM device_tokens WHERE token_hash = $1`,
+		encryption.NewEncryptor(nil, []byte("index-key")).BlindIndex("reindexed-token")).Scan(&tokens))
+	s.Equal(1, tokens)
+	ids, err := s.postgres.ListRecipientCommunications(ctx, []string{model.EmailRecipient("reindexed@example.com")})
+	s.NoError(err)
+	s.Equal([]string{"reindexed-1"}, ids, "communications can't be re-indexed, so are still matched without a key")
+
+	changed := database.New(s.conn, zap.NewNop(), encryption.NewEncryptor(nil, []byte("another-key")))
+	s.Error(changed.CheckBlindIndex(ctx), "a different key is refused")
+	_, err = s.conn.Exec(ctx, `DELETE FROM blind_index`)
+	s.NoError(err)
+	s.Error(changed.CheckBlindIndex(ctx), "rows indexed with a different key are refused")
+
+	_, err = s.postgres.DeleteContact(ctx, "reindexed-customer", false)
+	s.NoError(err)
+	_, err = s.postgres.DeleteCustomerDeviceTokens(ctx, "reindexed-customer", false)
+	s.NoError(err)
+}
+
+func (s *PostgresUnitTestSuite) Test_Contacts_Success() {
+	ctx := context.Background()
+
+	_, err := s.postgres.GetContact(ctx, "contact-customer")
+	s.ErrorIs(err, pgx.ErrNoRows)
+
+	contact := model.Contact{
+		ExternalCustomerID: "contact-customer",
+		EmailAddresses:     []string{"jane@example.com"},
+		PhoneNumbers:       []string{"+447700900123"},
+		Devices:            []model.DeviceToken{{Type: model.FCM, Token: "contact-token", Locale: "en-GB"}},
+		Locale:             "en-GB",
+		Timezone:           "Europe/London",
+	}
+	s.NoError(s.postgres.UpsertContact(ctx, contact))
+	contact.Timezone = "Asia/Dubai"
+	s.NoError(s.postgres.UpsertContact(ctx, contact))
+
+	found, err := s.postgres.GetContact(ctx, "contact-customer")
+	s.NoError(err)
+	s.Equal([]string{"jane@example.com"}, found.EmailAddresses)
+	s.Equal([]string{"+447700900123"}, found.PhoneNumbers)
+	s.Equal("Asia/Dubai", found.Timezone)
+	s.Require().Len(found.Devices, 1)
+	s.Equal("contact-token", found.Devices[0].Token)
+
+	count, err := s.postgres.DeleteContact(ctx, "contact-customer", true)
+	s.NoError(err)
+	s.Equal(1, count)
+	count, err = s.postgres.DeleteContact(ctx, "contact-customer", false)
+	s.NoError(err)
+	s.Equal(1, count)
+	_, err = s.postgres.GetContact(ctx, "contact-customer")
+	s.ErrorIs(err, pgx.ErrNoRows)
+}
+
+func (s *PostgresUnitTestSuite) Test_AuditLog_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	for i, principal := range []string{"cn:billing", "cn:marketing", "cn:billing"} {
+		s.NoError(s.postgres.InsertAuditEvent(ctx, model.AuditEvent{
+			OccurredAt:       now.Add(time.Duration(i) * time.Second),
+			Principal:        principal,
+			ClaimedPrincipal: "billing-service",
+			Peer:             "10.0.0.1:5000",
+			Domain:           "audit-domain",
+			Method:           "/unicom.api.v1.UnicomService/SendCommunication",
+			RequestHash:      "hash",
+			Code:             "OK",
+			WorkflowID:       fmt.Sprintf("audit-%d", i),
+		}))
+	}
+
+	events, err := s.postgres.ListAuditEvents(ctx, model.AuditFilter{Principal: "cn:billing", Domain: "audit-domain"}, 10)
+	s.NoError(err)
+	s.Require().Len(events, 2)
+	s.Equal("audit-2", events[0].WorkflowID, "newest first")
+	s.Equal("billing-service", events[0].ClaimedPrincipal)
+	s.Equal("audit-0", events[1].WorkflowID)
+
+	events, err = s.postgres.ListAuditEvents(ctx, model.AuditFilter{Domain: "audit-domain", Before: events[0].ID}, 1)
+	s.NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal("audit-1", events[0].WorkflowID)
+
+	until := now.Add(500 * time.Millisecond)
+	events, err = s.postgres.ListAuditEvents(ctx, model.AuditFilter{Domain: "audit-domain", Until: &until}, 10)
+	s.NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal("audit-0", events[0].WorkflowID)
+
+	_, err = s.conn.Exec(ctx, `UPDATE audit_log SET principal = 'someone-else'`)
+	s.Error(err, "the audit log is append only")
+	_, err = s.conn.Exec(ctx, `DELETE FROM audit_log`)
+	s.Error(err, "the audit log is append only")
+}
+
+// staticKMS hands out the same data key every time.
+type staticKMS struct{}
+
+func (staticKMS) GenerateDataKey(context.Context) (encryption.DataKey, error) {
+	key := []byte(strings.Repeat("k", 32))
+	return encryption.DataKey{KeyID: "static", Plaintext: key, Encrypted: key}, nil
+}
+
+func (staticKMS) DecryptDataKey(_ context.Context, _ string, encrypted []byte) ([]byte, error) {
+	return encrypted, nil
+}
+
+func (s *PostgresUnitTestSuite) Test_DeviceTokens_Encrypted() {
+	ctx := context.Background()
+	encrypted := database.New(s.conn, zap.NewNop(), encryption.NewEncryptor(staticKMS{}, []byte("index-key")))
+
+	// A token registered before encryption was enabled is still found, and
+	// replaced by an encrypted row when it is registered again.
+	legacy := model.DeviceToken{ExternalCustomerID: "customer-2", Type: model.APNs, Token: "apns-token", Locale: "en"}
+	s.NoError(s.postgres.UpsertDeviceToken(ctx, legacy))
+	got, err := encrypted.ListDeviceTokens(ctx, "customer-2", model.APNs)
+	s.NoError(err)
+	s.Len(got, 1)
+
+	legacy.Locale = "fr"
+	s.NoError(encrypted.UpsertDeviceToken(ctx, legacy))
+
+	var customerID, token string
+	err = s.conn.QueryRow(ctx,
+		`SELECT external_customer_id, token FROM device_tokens WHERE "type" = 'APNS'`).Scan(&customerID, &token)
+	s.NoError(err, "the legacy row was replaced")
+	s.NotContains(customerID, "customer-2")
+	s.NotContains(token, "apns-token")
+
+	got, err = encrypted.ListDeviceTokens(ctx, "customer-2", model.APNs)
+	s.NoError(err)
+	s.Len(got, 1)
+	s.Equal("customer-2", got[0].ExternalCustomerID)
+	s.Equal("apns-token", got[0].Token)
+	s.Equal("fr", got[0].Locale)
+
+	s.NoError(encrypted.DeleteDeviceToken(ctx, model.APNs, "apns-token"))
+	got, err = encrypted.ListDeviceTokens(ctx, "customer-2", model.APNs)
+	s.NoError(err)
+	s.Empty(got)
+}
+
+func (s *PostgresUnitTestSuite) Test_Payloads_Success() {
+	ctx := context.Background()
+
+	s.NoError(s.postgres.PutPayload(ctx, "communication-id/html", []byte("<p>first</p>")))
+	s.NoError(s.postgres.PutPayload(ctx, "communication-id/html", []byte("<p>second</p>")))
+
+	got, err := s.postgres.GetPayload(ctx, "communication-id/html")
+	s.NoError(err)
+	s.Equal([]byte("<p>second</p>"), got)
+
+	s.NoError(s.postgres.DeletePayload(ctx, "communication-id/html"))
+	_, err = s.postgres.GetPayload(ctx, "communication-id/html")
+	s.Error(err)
+}
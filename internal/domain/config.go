@@ -0,0 +1,253 @@
+// Package domain holds the per domain configuration of the calling services,
+// such as how hard delivery of their communications should be attempted.
+package domain
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of a single domain.
+type Config struct {
+	Delivery Delivery `yaml:"delivery"`
+	// Senders are the verified From addresses the domain may send email as.
+	// An entry starting with @ allows every address at that mail domain.
+	Senders []string `yaml:"senders"`
+	// Retention is how long the domain's communications keep their recipients
+	// and content before they are redacted. Zero keeps them forever.
+	Retention time.Duration `yaml:"retention"`
+	// QuietHours defer the domain's communications which aren't urgent.
+	QuietHours QuietHours `yaml:"quiet_hours"`
+	// Priority is the lane the domain's communications are processed in when
+	// a request doesn't set one, transactional when empty.
+	Priority string `yaml:"priority"`
+	// RateLimit limits how many communications the domain may request of each
+	// server.
+	RateLimit RateLimit `yaml:"rate_limit"`
+	// ResponseChannels are notified of the outcome of the domain's
+	// asynchronous communications when a request doesn't name any.
+	ResponseChannels []ResponseChannel `yaml:"response_channels"`
+	// SeedList receives the domain's test sends.
+	SeedList SeedList `yaml:"seed_list"`
+	// Sandbox simulates the providers of the domain's communications instead
+	// of sending them.
+	Sandbox Sandbox `yaml:"sandbox"`
+}
+
+// File is the layout of the --domain-config YAML file. Domains without an
+// entry, and unset fields of those with one, use Default.
+//
+//	default:
+//	  delivery:
+//	    attempt_timeout: 30s
+//	    max_attempts: 10
+//	domains:
+//	  billing:
+//	    delivery:
+//	      max_attempts: 20
+//	      expire_after: 24h
+//	    senders:
+//	      - billing@example.com
+//	    retention: 2160h
+//	    quiet_hours:
+//	      start: "21:00"
+//	      end: "08:00"
+//	      timezone: Europe/London
+//	    rate_limit:
+//	      per_second: 50
+//	      burst: 100
+//	    response_channels:
+//	      - type: sqs
+//	        url: https://sqs.eu-west-2.amazonaws.com/123456789012/billing-outcomes
+//	    seed_list:
+//	      emails:
+//	        - billing-qa@example.com
+//	      push_customer: billing-qa
+//	  onboarding:
+//	    sandbox:
+//	      enabled: true
+//	      latency: 2s
+//	      recipients:
+//	        bounce@example.com: bounce
+//	  marketing:
+//	    priority: bulk
+type File struct {
+	Default Config            `yaml:"default"`
+	Domains map[string]Config `yaml:"domains"`
+}
+
+// Registry resolves the configuration of a domain. Its configuration can be
+// replaced while it is in use, see Watch.
+type Registry struct {
+	state atomic.Pointer[registryState]
+}
+
+type registryState struct {
+	defaults Config
+	domains  map[string]Config
+}
+
+// NewRegistry creates a Registry. Unset fields of defaults use the built in
+// DefaultDelivery.
+func NewRegistry(defaults Config, domains map[string]Config) *Registry {
+	if domains == nil {
+		domains = map[string]Config{}
+	}
+	defaults.Delivery = defaults.Delivery.merge(DefaultDelivery)
+	r := &Registry{}
+	r.state.Store(&registryState{
+		defaults: defaults,
+		domains:  domains,
+	})
+	return r
+}
+
+// Replace swaps in the configuration of other, which callers of r see from
+// their next call.
+func (r *Registry) Replace(other *Registry) {
+	r.state.Store(other.state.Load())
+}
+
+// Load reads a Registry from a YAML file, see File. An empty path returns the
+// built in defaults.
+func Load(path string) (*Registry, error) {
+	if path == "" {
+		return NewRegistry(Config{}, nil), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := File{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing domain config %s: %w", path, err)
+	}
+	registry := NewRegistry(file.Default, file.Domains)
+	defaults := registry.state.Load().defaults
+	for name := range file.Domains {
+		if err := registry.For(name).Delivery.validate(); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", name, err)
+		}
+	}
+	if err := defaults.Delivery.validate(); err != nil {
+		return nil, fmt.Errorf("default domain config: %w", err)
+	}
+	for name, config := range file.Domains {
+		if config.Retention < 0 {
+			return nil, fmt.Errorf("domain %s: retention must not be negative", name)
+		}
+	}
+	if file.Default.Retention < 0 {
+		return nil, fmt.Errorf("default domain config: retention must not be negative")
+	}
+	for name := range file.Domains {
+		if err := registry.For(name).validateQuietHours(); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", name, err)
+		}
+	}
+	if err := defaults.validateQuietHours(); err != nil {
+		return nil, fmt.Errorf("default domain config: %w", err)
+	}
+	for name := range file.Domains {
+		if _, err := registry.For(name).ParsePriority(); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", name, err)
+		}
+	}
+	if _, err := defaults.ParsePriority(); err != nil {
+		return nil, fmt.Errorf("default domain config: %w", err)
+	}
+	for name, config := range file.Domains {
+		if err := config.RateLimit.validate(); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", name, err)
+		}
+		if err := config.validateResponseChannels(); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", name, err)
+		}
+		if err := config.validateSeedList(); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", name, err)
+		}
+		if err := config.validateSandbox(); err != nil {
+			return nil, fmt.Errorf("domain %s: %w", name, err)
+		}
+	}
+	if err := defaults.RateLimit.validate(); err != nil {
+		return nil, fmt.Errorf("default domain config: %w", err)
+	}
+	if err := defaults.validateResponseChannels(); err != nil {
+		return nil, fmt.Errorf("default domain config: %w", err)
+	}
+	if err := defaults.validateSeedList(); err != nil {
+		return nil, fmt.Errorf("default domain config: %w", err)
+	}
+	if err := defaults.validateSandbox(); err != nil {
+		return nil, fmt.Errorf("default domain config: %w", err)
+	}
+	return registry, nil
+}
+
+// For returns the configuration of the named domain.
+func (r *Registry) For(name string) Config {
+	state := r.state.Load()
+	config, ok := state.domains[name]
+	if !ok {
+		return state.defaults
+	}
+	senders := config.Senders
+	if len(senders) == 0 {
+		senders = state.defaults.Senders
+	}
+	quietHours := config.QuietHours
+	if !quietHours.Enabled() {
+		quietHours = state.defaults.QuietHours
+	}
+	priority := config.Priority
+	if priority == "" {
+		priority = state.defaults.Priority
+	}
+	rateLimit := config.RateLimit
+	if !rateLimit.Enabled() {
+		rateLimit = state.defaults.RateLimit
+	}
+	responseChannels := config.ResponseChannels
+	if len(responseChannels) == 0 {
+		responseChannels = state.defaults.ResponseChannels
+	}
+	seedList := config.SeedList
+	if !seedList.Enabled() {
+		seedList = state.defaults.SeedList
+	}
+	return Config{
+		Delivery:         config.Delivery.merge(state.defaults.Delivery),
+		Senders:          senders,
+		Retention:        durationOr(config.Retention, state.defaults.Retention),
+		QuietHours:       quietHours,
+		Priority:         priority,
+		RateLimit:        rateLimit,
+		ResponseChannels: responseChannels,
+		SeedList:         seedList,
+		Sandbox:          config.Sandbox.merge(state.defaults.Sandbox),
+	}
+}
+
+// validateQuietHours checks configured quiet hours parse.
+func (c Config) validateQuietHours() error {
+	if !c.QuietHours.Enabled() {
+		return nil
+	}
+	if _, err := c.QuietHours.Window(""); err != nil {
+		return fmt.Errorf("quiet_hours %w", err)
+	}
+	return nil
+}
+
+// durationOr returns d, or fallback when d is unset.
+func durationOr(d, fallback time.Duration) time.Duration {
+	if d == 0 {
+		return fallback
+	}
+	return d
+}
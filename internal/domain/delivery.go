@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// minAttemptTimeout is the shortest attempt timeout a domain or request can
+// ask for, anything less can't complete a provider round trip.
+const minAttemptTimeout = time.Second
+
+// Delivery is a domain's default retry, timeout and expiry policy, along with
+// the limits requests overriding it must stay within.
+type Delivery struct {
+	AttemptTimeout     time.Duration `yaml:"attempt_timeout"`
+	MaxAttempts        int32         `yaml:"max_attempts"`
+	BackoffCoefficient float64       `yaml:"backoff_coefficient"`
+	// ExpireAfter expires communications not sent this long after their send
+	// time. Zero never expires them.
+	ExpireAfter time.Duration `yaml:"expire_after"`
+	Limits      Limits        `yaml:"limits"`
+}
+
+// Limits bound the policy a request may ask for.
+type Limits struct {
+	MaxAttemptTimeout     time.Duration `yaml:"max_attempt_timeout"`
+	MaxAttempts           int32         `yaml:"max_attempts"`
+	MaxBackoffCoefficient float64       `yaml:"max_backoff_coefficient"`
+	MaxExpireAfter        time.Duration `yaml:"max_expire_after"`
+}
+
+// DefaultDelivery matches the policy every communication used before it was
+// configurable.
+var DefaultDelivery = Delivery{
+	AttemptTimeout:     30 * time.Second,
+	MaxAttempts:        10,
+	BackoffCoefficient: 1.2,
+	Limits: Limits{
+		MaxAttemptTimeout:     10 * time.Minute,
+		MaxAttempts:           50,
+		MaxBackoffCoefficient: 10,
+		MaxExpireAfter:        30 * 24 * time.Hour,
+	},
+}
+
+// Resolve applies a request's overrides to the domain policy for a
+// communication due to be sent at sendAt, returning an error describing the
+// first override outside the domain's limits.
+func (d Delivery) Resolve(override model.DeliveryPolicy, sendAt time.Time) (model.DeliveryPolicy, error) {
+	policy := model.DeliveryPolicy{
+		AttemptTimeout:     d.AttemptTimeout,
+		MaxAttempts:        d.MaxAttempts,
+		BackoffCoefficient: d.BackoffCoefficient,
+	}
+	if d.ExpireAfter > 0 {
+		expireAt := sendAt.Add(d.ExpireAfter)
+		policy.ExpireAt = &expireAt
+	}
+
+	if override.AttemptTimeout != 0 {
+		if override.AttemptTimeout < minAttemptTimeout || override.AttemptTimeout > d.Limits.MaxAttemptTimeout {
+			return model.DeliveryPolicy{}, fmt.Errorf("attempt_timeout must be between %s and %s", minAttemptTimeout, d.Limits.MaxAttemptTimeout)
+		}
+		policy.AttemptTimeout = override.AttemptTimeout
+	}
+	if override.MaxAttempts != 0 {
+		if override.MaxAttempts < 1 || override.MaxAttempts > d.Limits.MaxAttempts {
+			return model.DeliveryPolicy{}, fmt.Errorf("max_attempts must be between 1 and %d", d.Limits.MaxAttempts)
+		}
+		policy.MaxAttempts = override.MaxAttempts
+	}
+	if override.BackoffCoefficient != 0 {
+		if override.BackoffCoefficient < 1 || override.BackoffCoefficient > d.Limits.MaxBackoffCoefficient {
+			return model.DeliveryPolicy{}, fmt.Errorf("backoff_coefficient must be between 1 and %g", d.Limits.MaxBackoffCoefficient)
+		}
+		policy.BackoffCoefficient = override.BackoffCoefficient
+	}
+	if override.ExpireAt != nil {
+		if !override.ExpireAt.After(sendAt) {
+			return model.DeliveryPolicy{}, errors.New("expire_at must be after the send time")
+		}
+		if override.ExpireAt.Sub(sendAt) > d.Limits.MaxExpireAfter {
+			return model.DeliveryPolicy{}, fmt.Errorf("expire_at must be within %s of the send time", d.Limits.MaxExpireAfter)
+		}
+		expireAt := *override.ExpireAt
+		policy.ExpireAt = &expireAt
+	}
+	return policy, nil
+}
+
+// merge fills the unset fields of d from fallback.
+func (d Delivery) merge(fallback Delivery) Delivery {
+	d.AttemptTimeout = durationOr(d.AttemptTimeout, fallback.AttemptTimeout)
+	if d.MaxAttempts == 0 {
+		d.MaxAttempts = fallback.MaxAttempts
+	}
+	if d.BackoffCoefficient == 0 {
+		d.BackoffCoefficient = fallback.BackoffCoefficient
+	}
+	d.ExpireAfter = durationOr(d.ExpireAfter, fallback.ExpireAfter)
+	d.Limits.MaxAttemptTimeout = durationOr(d.Limits.MaxAttemptTimeout, fallback.Limits.MaxAttemptTimeout)
+	if d.Limits.MaxAttempts == 0 {
+		d.Limits.MaxAttempts = fallback.Limits.MaxAttempts
+	}
+	if d.Limits.MaxBackoffCoefficient == 0 {
+		d.Limits.MaxBackoffCoefficient = fallback.Limits.MaxBackoffCoefficient
+	}
+	d.Limits.MaxExpireAfter = durationOr(d.Limits.MaxExpireAfter, fallback.Limits.MaxExpireAfter)
+	return d
+}
+
+// validate checks a configured policy is within its own limits.
+func (d Delivery) validate() error {
+	switch {
+	case d.AttemptTimeout < minAttemptTimeout || d.AttemptTimeout > d.Limits.MaxAttemptTimeout:
+		return fmt.Errorf("attempt_timeout must be between %s and %s", minAttemptTimeout, d.Limits.MaxAttemptTimeout)
+	case d.MaxAttempts < 1 || d.MaxAttempts > d.Limits.MaxAttempts:
+		return fmt.Errorf("max_attempts must be between 1 and %d", d.Limits.MaxAttempts)
+	case d.BackoffCoefficient < 1 || d.BackoffCoefficient > d.Limits.MaxBackoffCoefficient:
+		return fmt.Errorf("backoff_coefficient must be between 1 and %g", d.Limits.MaxBackoffCoefficient)
+	case d.ExpireAfter < 0 || d.ExpireAfter > d.Limits.MaxExpireAfter:
+		return fmt.Errorf("expire_after must be between 0 and %s", d.Limits.MaxExpireAfter)
+	}
+	return nil
+}
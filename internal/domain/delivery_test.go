@@ -0,0 +1,116 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+type DeliveryTestSuite struct {
+	suite.Suite
+	sendAt time.Time
+}
+
+func TestDeliveryTestSuite(t *testing.T) {
+	suite.Run(t, new(DeliveryTestSuite))
+}
+
+func (s *DeliveryTestSuite) SetupTest() {
+	s.sendAt = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+}
+
+func (s *DeliveryTestSuite) TestResolve_Defaults() {
+	policy, err := domain.NewRegistry(domain.Config{}, nil).For("unknown").Delivery.Resolve(model.DeliveryPolicy{}, s.sendAt)
+	s.NoError(err)
+	s.Equal(model.DeliveryPolicy{
+		AttemptTimeout:     30 * time.Second,
+		MaxAttempts:        10,
+		BackoffCoefficient: 1.2,
+	}, policy)
+}
+
+func (s *DeliveryTestSuite) TestResolve_DomainAndOverrides() {
+	registry := domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"billing": {Delivery: domain.Delivery{MaxAttempts: 20, ExpireAfter: time.Hour}},
+	})
+	expireAt := s.sendAt.Add(30 * time.Minute)
+
+	policy, err := registry.For("billing").Delivery.Resolve(model.DeliveryPolicy{
+		AttemptTimeout: time.Minute,
+		ExpireAt:       &expireAt,
+	}, s.sendAt)
+	s.NoError(err)
+	s.Equal(time.Minute, policy.AttemptTimeout)
+	s.Equal(int32(20), policy.MaxAttempts)
+	s.Equal(1.2, policy.BackoffCoefficient)
+	s.Equal(expireAt, *policy.ExpireAt)
+
+	policy, err = registry.For("billing").Delivery.Resolve(model.DeliveryPolicy{}, s.sendAt)
+	s.NoError(err)
+	s.Equal(s.sendAt.Add(time.Hour), *policy.ExpireAt)
+}
+
+func (s *DeliveryTestSuite) TestResolve_OutsideLimits() {
+	delivery := domain.NewRegistry(domain.Config{Delivery: domain.Delivery{
+		Limits: domain.Limits{MaxAttempts: 15},
+	}}, nil).For("any").Delivery
+	past := s.sendAt.Add(-time.Minute)
+	farFuture := s.sendAt.Add(365 * 24 * time.Hour)
+
+	for _, override := range []model.DeliveryPolicy{
+		{MaxAttempts: 16},
+		{AttemptTimeout: time.Millisecond},
+		{AttemptTimeout: time.Hour},
+		{BackoffCoefficient: 0.5},
+		{ExpireAt: &past},
+		{ExpireAt: &farFuture},
+	} {
+		_, err := delivery.Resolve(override, s.sendAt)
+		s.Error(err, "%+v", override)
+	}
+}
+
+func (s *DeliveryTestSuite) TestLoad() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+default:
+  delivery:
+    max_attempts: 5
+  retention: 8760h
+domains:
+  marketing:
+    delivery:
+      attempt_timeout: 2m
+      expire_after: 24h
+    retention: 720h
+`), 0o600))
+
+	registry, err := domain.Load(path)
+	s.NoError(err)
+	s.Equal(int32(5), registry.For("other").Delivery.MaxAttempts)
+	marketing := registry.For("marketing").Delivery
+	s.Equal(2*time.Minute, marketing.AttemptTimeout)
+	s.Equal(int32(5), marketing.MaxAttempts)
+	s.Equal(24*time.Hour, marketing.ExpireAfter)
+	s.Equal(720*time.Hour, registry.For("marketing").Retention)
+	s.Equal(8760*time.Hour, registry.For("other").Retention)
+}
+
+func (s *DeliveryTestSuite) TestLoad_InvalidPolicy() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+domains:
+  marketing:
+    delivery:
+      max_attempts: 500
+`), 0o600))
+
+	_, err := domain.Load(path)
+	s.ErrorContains(err, "marketing")
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "github.com/anicoll/unicom/internal/model"
+
+// ParsePriority returns the domain's priority, transactional when unset.
+func (c Config) ParsePriority() (model.Priority, error) {
+	if c.Priority == "" {
+		return model.PriorityTransactional, nil
+	}
+	return model.ParsePriority(c.Priority)
+}
@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+type PriorityTestSuite struct {
+	suite.Suite
+}
+
+func TestPriorityTestSuite(t *testing.T) {
+	suite.Run(t, new(PriorityTestSuite))
+}
+
+func (s *PriorityTestSuite) TestParsePriority_DefaultsToTransactional() {
+	priority, err := domain.NewRegistry(domain.Config{}, nil).For("billing").ParsePriority()
+	s.NoError(err)
+	s.Equal(model.PriorityTransactional, priority)
+}
+
+func (s *PriorityTestSuite) TestLoad_Priority() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+default:
+  priority: transactional
+domains:
+  marketing:
+    priority: bulk
+  billing:
+    retention: 720h
+`), 0o600))
+
+	registry, err := domain.Load(path)
+	s.Require().NoError(err)
+
+	priority, err := registry.For("marketing").ParsePriority()
+	s.NoError(err)
+	s.Equal(model.PriorityBulk, priority)
+
+	priority, err = registry.For("billing").ParsePriority()
+	s.NoError(err)
+	s.Equal(model.PriorityTransactional, priority)
+}
+
+func (s *PriorityTestSuite) TestLoad_InvalidPriority() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+domains:
+  marketing:
+    priority: whenever
+`), 0o600))
+
+	_, err := domain.Load(path)
+	s.ErrorContains(err, "marketing")
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+// RateLimit is a token bucket limiting how many communications a domain may
+// request of each server.
+type RateLimit struct {
+	// PerSecond is the sustained rate, zero leaves the domain unlimited.
+	PerSecond float64 `yaml:"per_second"`
+	// Burst is how many communications may be requested at once, PerSecond
+	// rounded up when unset.
+	Burst int `yaml:"burst"`
+}
+
+// Enabled reports whether the domain is rate limited.
+func (l RateLimit) Enabled() bool {
+	return l.PerSecond > 0
+}
+
+// BurstOrDefault returns the burst of the limit, defaulting it from
+// PerSecond.
+func (l RateLimit) BurstOrDefault() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return int(math.Ceil(l.PerSecond))
+}
+
+func (l RateLimit) validate() error {
+	if l.PerSecond < 0 || l.Burst < 0 {
+		return errors.New("rate_limit must not be negative")
+	}
+	return nil
+}
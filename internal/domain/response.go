@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// ResponseChannel is where the outcome of a communication is sent.
+type ResponseChannel struct {
+	// Type is sqs, webhook or event_bridge.
+	Type string `yaml:"type"`
+	URL  string `yaml:"url"`
+}
+
+// ParseType returns the model type of the channel.
+func (c ResponseChannel) ParseType() (model.ResponseChannelType, error) {
+	switch strings.ToLower(c.Type) {
+	case "sqs":
+		return model.Sqs, nil
+	case "webhook":
+		return model.Webhook, nil
+	case "event_bridge":
+		return model.EventBridge, nil
+	}
+	return "", fmt.Errorf("unknown response channel type %q, expected sqs, webhook or event_bridge", c.Type)
+}
+
+func (c Config) validateResponseChannels() error {
+	for i, channel := range c.ResponseChannels {
+		if _, err := channel.ParseType(); err != nil {
+			return fmt.Errorf("response_channels[%d]: %w", i, err)
+		}
+		if _, err := url.ParseRequestURI(channel.URL); err != nil {
+			return fmt.Errorf("response_channels[%d]: invalid url: %w", i, err)
+		}
+	}
+	return nil
+}
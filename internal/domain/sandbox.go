@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Outcomes a sandboxed send can be simulated with.
+const (
+	// SandboxSuccess accepts the send, as a provider would.
+	SandboxSuccess = "success"
+	// SandboxBounce rejects the recipient, failing the send without retries.
+	SandboxBounce = "bounce"
+	// SandboxThrottle rate limits the send, which is retried until the
+	// domain's delivery policy gives up.
+	SandboxThrottle = "throttle"
+)
+
+// Sandbox replaces the providers of a domain's communications with a
+// simulator. Everything else, from validation to the response channels, runs
+// as usual, so a domain can exercise its handling of outcomes in staging
+// without reaching real inboxes and devices.
+type Sandbox struct {
+	// Enabled turns sandbox mode on or off. A domain which leaves it unset
+	// follows the default.
+	Enabled *bool `yaml:"enabled"`
+	// Outcome is how sends are simulated, success when empty.
+	Outcome string `yaml:"outcome"`
+	// Latency is how long each simulated send takes.
+	Latency time.Duration `yaml:"latency"`
+	// Recipients override Outcome for sends to an email address or external
+	// customer ID, so a domain can script each of its outcomes.
+	Recipients map[string]string `yaml:"recipients"`
+}
+
+// IsEnabled reports whether sends are simulated.
+func (s Sandbox) IsEnabled() bool {
+	return s.Enabled != nil && *s.Enabled
+}
+
+// merge fills the unset fields of s from fallback.
+func (s Sandbox) merge(fallback Sandbox) Sandbox {
+	if s.Enabled == nil {
+		s.Enabled = fallback.Enabled
+	}
+	if s.Outcome == "" {
+		s.Outcome = fallback.Outcome
+	}
+	s.Latency = durationOr(s.Latency, fallback.Latency)
+	if len(s.Recipients) == 0 {
+		s.Recipients = fallback.Recipients
+	}
+	return s
+}
+
+// OutcomeFor returns the outcome of a send to recipient.
+func (s Sandbox) OutcomeFor(recipient string) string {
+	if outcome, ok := s.Recipients[recipient]; ok {
+		return outcome
+	}
+	if s.Outcome == "" {
+		return SandboxSuccess
+	}
+	return s.Outcome
+}
+
+func (c Config) validateSandbox() error {
+	if err := validateSandboxOutcome(c.Sandbox.Outcome, true); err != nil {
+		return fmt.Errorf("sandbox.outcome %w", err)
+	}
+	if c.Sandbox.Latency < 0 {
+		return fmt.Errorf("sandbox.latency must not be negative")
+	}
+	for recipient, outcome := range c.Sandbox.Recipients {
+		if err := validateSandboxOutcome(outcome, false); err != nil {
+			return fmt.Errorf("sandbox.recipients[%s] %w", recipient, err)
+		}
+	}
+	return nil
+}
+
+func validateSandboxOutcome(outcome string, allowEmpty bool) error {
+	switch outcome {
+	case SandboxSuccess, SandboxBounce, SandboxThrottle:
+		return nil
+	case "":
+		if allowEmpty {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q must be %s, %s or %s", outcome, SandboxSuccess, SandboxBounce, SandboxThrottle)
+}
@@ -0,0 +1,98 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/domain"
+)
+
+type SandboxTestSuite struct {
+	suite.Suite
+}
+
+func TestSandboxTestSuite(t *testing.T) {
+	suite.Run(t, new(SandboxTestSuite))
+}
+
+func (s *SandboxTestSuite) TestLoad_Sandbox() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+default:
+  sandbox:
+    enabled: true
+    outcome: throttle
+domains:
+  onboarding:
+    sandbox:
+      enabled: true
+      latency: 2s
+      recipients:
+        bounce@example.com: bounce
+  marketing:
+    priority: bulk
+`), 0o600))
+
+	registry, err := domain.Load(path)
+	s.Require().NoError(err)
+
+	onboarding := registry.For("onboarding").Sandbox
+	s.True(onboarding.IsEnabled())
+	s.Equal(2*time.Second, onboarding.Latency)
+	s.Equal(domain.SandboxBounce, onboarding.OutcomeFor("bounce@example.com"))
+	s.Equal(domain.SandboxThrottle, onboarding.OutcomeFor("jane@example.com"), "unset fields follow the default")
+	s.Equal(domain.SandboxThrottle, registry.For("marketing").Sandbox.OutcomeFor("jane@example.com"))
+	s.False(domain.NewRegistry(domain.Config{}, nil).For("billing").Sandbox.IsEnabled())
+}
+
+func (s *SandboxTestSuite) TestLoad_DomainOptsOut() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+default:
+  sandbox:
+    enabled: true
+    latency: 1s
+domains:
+  billing:
+    sandbox:
+      enabled: false
+  onboarding:
+    sandbox:
+      outcome: bounce
+`), 0o600))
+
+	registry, err := domain.Load(path)
+	s.Require().NoError(err)
+
+	s.False(registry.For("billing").Sandbox.IsEnabled())
+	onboarding := registry.For("onboarding").Sandbox
+	s.True(onboarding.IsEnabled(), "a domain which leaves enabled unset follows the default")
+	s.Equal(time.Second, onboarding.Latency)
+	s.Equal(domain.SandboxBounce, onboarding.OutcomeFor("jane@example.com"))
+}
+
+func (s *SandboxTestSuite) TestLoad_InvalidSandbox() {
+	for name, config := range map[string]string{
+		"sandbox.outcome":                   "outcome: lost",
+		"sandbox.latency":                   "latency: -1s",
+		"sandbox.recipients[a@example.com]": "recipients:\n        a@example.com: \"\"",
+	} {
+		s.Run(name, func() {
+			path := filepath.Join(s.T().TempDir(), "domains.yaml")
+			s.NoError(os.WriteFile(path, []byte(`
+domains:
+  onboarding:
+    sandbox:
+      enabled: true
+      `+config+`
+`), 0o600))
+
+			_, err := domain.Load(path)
+			s.ErrorContains(err, name)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+)
+
+// SeedList is where a domain's test sends are delivered instead of to their
+// recipients, so a communication can be checked in real inboxes and devices
+// before it goes out.
+type SeedList struct {
+	// Emails are the internal addresses test emails are sent to.
+	Emails []string `yaml:"emails"`
+	// PushCustomer is the external customer ID internal devices are
+	// registered for, which test push notifications are sent to.
+	PushCustomer string `yaml:"push_customer"`
+}
+
+// Enabled reports whether the seed list has anywhere to send to.
+func (s SeedList) Enabled() bool {
+	return len(s.Emails) > 0 || s.PushCustomer != ""
+}
+
+func (c Config) validateSeedList() error {
+	for i, address := range c.SeedList.Emails {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return fmt.Errorf("seed_list.emails[%d]: invalid address %q: %w", i, address, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/domain"
+)
+
+type SeedListTestSuite struct {
+	suite.Suite
+}
+
+func TestSeedListTestSuite(t *testing.T) {
+	suite.Run(t, new(SeedListTestSuite))
+}
+
+func (s *SeedListTestSuite) TestLoad_SeedList() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+default:
+  seed_list:
+    emails:
+      - qa@example.com
+domains:
+  billing:
+    seed_list:
+      emails:
+        - Billing QA <billing-qa@example.com>
+      push_customer: billing-qa
+  marketing:
+    priority: bulk
+`), 0o600))
+
+	registry, err := domain.Load(path)
+	s.Require().NoError(err)
+
+	s.Equal(domain.SeedList{Emails: []string{"Billing QA <billing-qa@example.com>"}, PushCustomer: "billing-qa"}, registry.For("billing").SeedList)
+	s.Equal([]string{"qa@example.com"}, registry.For("marketing").SeedList.Emails)
+	s.False(domain.NewRegistry(domain.Config{}, nil).For("billing").SeedList.Enabled())
+}
+
+func (s *SeedListTestSuite) TestLoad_InvalidSeedAddress() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+domains:
+  billing:
+    seed_list:
+      emails:
+        - not an address
+`), 0o600))
+
+	_, err := domain.Load(path)
+	s.ErrorContains(err, "seed_list.emails[0]")
+}
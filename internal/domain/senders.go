@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VerifySender checks the named domain may send email from address, a bare
+// RFC 5322 address. An address verified for another domain is always refused
+// so domains can't spoof each other. Otherwise a domain without any verified
+// senders may use any address.
+func (r *Registry) VerifySender(name, address string) error {
+	address = strings.ToLower(strings.TrimSpace(address))
+	senders := r.For(name).Senders
+	if matchSender(senders, address) {
+		return nil
+	}
+	for other, config := range r.state.Load().domains {
+		if other != name && matchSender(config.Senders, address) {
+			return fmt.Errorf("%s is a verified sender of another domain", address)
+		}
+	}
+	if len(senders) > 0 {
+		return fmt.Errorf("%s is not a verified sender for domain %s", address, name)
+	}
+	return nil
+}
+
+func matchSender(senders []string, address string) bool {
+	for _, sender := range senders {
+		sender = strings.ToLower(sender)
+		if strings.HasPrefix(sender, "@") {
+			if strings.HasSuffix(address, sender) {
+				return true
+			}
+		} else if sender == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/domain"
+)
+
+type SendersTestSuite struct {
+	suite.Suite
+	registry *domain.Registry
+}
+
+func TestSendersTestSuite(t *testing.T) {
+	suite.Run(t, new(SendersTestSuite))
+}
+
+func (s *SendersTestSuite) SetupTest() {
+	s.registry = domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"billing":   {Senders: []string{"billing@example.com"}},
+		"marketing": {Senders: []string{"@news.example.com"}},
+	})
+}
+
+func (s *SendersTestSuite) TestVerifySender_OwnSender() {
+	s.NoError(s.registry.VerifySender("billing", "Billing@Example.com"))
+	s.NoError(s.registry.VerifySender("marketing", "offers@news.example.com"))
+}
+
+func (s *SendersTestSuite) TestVerifySender_UnverifiedSender() {
+	s.ErrorContains(s.registry.VerifySender("billing", "someone@example.com"), "not a verified sender")
+	s.Error(s.registry.VerifySender("marketing", "offers@example.com"))
+}
+
+func (s *SendersTestSuite) TestVerifySender_AnotherDomainsSender() {
+	s.ErrorContains(s.registry.VerifySender("unconfigured", "billing@example.com"), "another domain")
+	s.ErrorContains(s.registry.VerifySender("billing", "offers@news.example.com"), "another domain")
+}
+
+func (s *SendersTestSuite) TestVerifySender_UnconfiguredDomain() {
+	s.NoError(s.registry.VerifySender("unconfigured", "noreply@other.example.com"))
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WatchInterval is how often Watch checks the file for changes.
+const WatchInterval = time.Second * 10
+
+// Watch reloads registry from the file at path whenever the process receives
+// SIGHUP or the file changes, checking every interval, until ctx is done. A
+// file which fails to load is logged and the configuration in use kept.
+func Watch(ctx context.Context, path string, registry *Registry, interval time.Duration, logger *zap.Logger) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, _ := os.Stat(path)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			logger.Info("reloading domain config on SIGHUP", zap.String("path", path))
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || (last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+				continue
+			}
+			last = info
+			logger.Info("reloading changed domain config", zap.String("path", path))
+		}
+		reloaded, err := Load(path)
+		if err != nil {
+			logger.Error("unable to reload domain config, keeping the current one", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		registry.Replace(reloaded)
+	}
+}
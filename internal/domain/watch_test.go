@@ -0,0 +1,150 @@
+package domain_test
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+type WatchTestSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestWatchTestSuite(t *testing.T) {
+	suite.Run(t, new(WatchTestSuite))
+}
+
+func (s *WatchTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "unicom.yaml")
+}
+
+func (s *WatchTestSuite) write(content string) {
+	s.Require().NoError(os.WriteFile(s.path, []byte(content), 0o600))
+}
+
+func (s *WatchTestSuite) TestLoad_RateLimitsAndResponseChannels() {
+	s.write(`
+grpc-port: 9090
+default:
+  rate_limit:
+    per_second: 10
+domains:
+  billing:
+    rate_limit:
+      per_second: 2.5
+    response_channels:
+      - type: sqs
+        url: https://sqs.eu-west-2.amazonaws.com/123456789012/billing
+`)
+
+	registry, err := domain.Load(s.path)
+	s.Require().NoError(err)
+
+	billing := registry.For("billing")
+	s.Equal(domain.RateLimit{PerSecond: 2.5}, billing.RateLimit)
+	s.Equal(3, billing.RateLimit.BurstOrDefault())
+	s.Require().Len(billing.ResponseChannels, 1)
+	channelType, err := billing.ResponseChannels[0].ParseType()
+	s.NoError(err)
+	s.Equal(model.Sqs, channelType)
+
+	s.Equal(10.0, registry.For("marketing").RateLimit.PerSecond)
+	s.Empty(registry.For("marketing").ResponseChannels)
+}
+
+func (s *WatchTestSuite) TestLoad_InvalidResponseChannel() {
+	s.write(`
+domains:
+  billing:
+    response_channels:
+      - type: pigeon
+        url: https://example.com
+`)
+
+	_, err := domain.Load(s.path)
+	s.ErrorContains(err, "domain billing: response_channels[0]: unknown response channel type \"pigeon\"")
+}
+
+func (s *WatchTestSuite) TestLoad_NegativeRateLimit() {
+	s.write(`
+default:
+  rate_limit:
+    per_second: -1
+`)
+
+	_, err := domain.Load(s.path)
+	s.ErrorContains(err, "default domain config: rate_limit must not be negative")
+}
+
+func (s *WatchTestSuite) TestWatch_ReloadsOnChange() {
+	s.write(`
+domains:
+  billing:
+    senders: [billing@example.com]
+`)
+	registry, err := domain.Load(s.path)
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go domain.Watch(ctx, s.path, registry, time.Millisecond*10, zap.NewNop())
+
+	// An invalid file is ignored.
+	s.write(`
+domains:
+  billing:
+    priority: whenever
+`)
+	time.Sleep(time.Millisecond * 50)
+	s.Equal([]string{"billing@example.com"}, registry.For("billing").Senders)
+
+	s.write(`
+domains:
+  billing:
+    senders: [invoices@example.com]
+`)
+	s.Eventually(func() bool {
+		return slices.Equal(registry.For("billing").Senders, []string{"invoices@example.com"})
+	}, time.Second, time.Millisecond*10)
+}
+
+func (s *WatchTestSuite) TestWatch_ReloadsOnSIGHUP() {
+	s.write(`
+domains:
+  billing:
+    senders: [billing@example.com]
+`)
+	registry, err := domain.Load(s.path)
+	s.Require().NoError(err)
+
+	// Keeps SIGHUP from stopping the tests before Watch is listening for it.
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go domain.Watch(ctx, s.path, registry, time.Hour, zap.NewNop())
+
+	s.write(`
+domains:
+  billing:
+    senders: [receipts@example.com]
+`)
+	s.Eventually(func() bool {
+		s.Require().NoError(syscall.Kill(os.Getpid(), syscall.SIGHUP))
+		return slices.Equal(registry.For("billing").Senders, []string{"receipts@example.com"})
+	}, time.Second, time.Millisecond*10)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// QuietHours are the time of day a domain's communications are deferred
+// through, unless they are urgent.
+type QuietHours struct {
+	// Start and End are 24 hour times, e.g. "21:00" and "08:00".
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// Timezone is used when the recipient's time zone isn't known, UTC when
+	// empty.
+	Timezone string `yaml:"timezone"`
+}
+
+// Enabled reports whether quiet hours are configured.
+func (q QuietHours) Enabled() bool {
+	return q.Start != "" || q.End != ""
+}
+
+// Window returns the delivery window outside the quiet hours, in the given
+// time zone or the configured one when it is empty.
+func (q QuietHours) Window(timezone string) (model.DeliveryWindow, error) {
+	if timezone == "" {
+		timezone = q.Timezone
+	}
+	// Deliveries may happen from the end of the quiet hours to their start.
+	return ParseWindow(q.End, q.Start, timezone)
+}
+
+// ParseWindow parses a delivery window between two 24 hour times, e.g. "08:00"
+// and "21:00", in an IANA time zone. The end may be before the start, for a
+// window spanning midnight.
+func ParseWindow(start, end, timezone string) (model.DeliveryWindow, error) {
+	startOffset, err := parseTimeOfDay(start)
+	if err != nil {
+		return model.DeliveryWindow{}, fmt.Errorf("start: %w", err)
+	}
+	endOffset, err := parseTimeOfDay(end)
+	if err != nil {
+		return model.DeliveryWindow{}, fmt.Errorf("end: %w", err)
+	}
+	if startOffset == endOffset {
+		return model.DeliveryWindow{}, fmt.Errorf("start and end must differ")
+	}
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+		return model.DeliveryWindow{}, fmt.Errorf("timezone %q is not an IANA time zone", timezone)
+	}
+	return model.DeliveryWindow{Start: startOffset, End: endOffset, Timezone: timezone}, nil
+}
+
+// parseTimeOfDay parses a 24 hour time as an offset from midnight.
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a 24 hour time such as 08:00", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
@@ -0,0 +1,143 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+type WindowTestSuite struct {
+	suite.Suite
+	london *time.Location
+}
+
+func TestWindowTestSuite(t *testing.T) {
+	suite.Run(t, new(WindowTestSuite))
+}
+
+func (s *WindowTestSuite) SetupTest() {
+	var err error
+	s.london, err = time.LoadLocation("Europe/London")
+	s.Require().NoError(err)
+}
+
+func (s *WindowTestSuite) TestParseWindow() {
+	window, err := domain.ParseWindow("08:00", "21:30", "Europe/London")
+	s.NoError(err)
+	s.Equal(model.DeliveryWindow{Start: 8 * time.Hour, End: 21*time.Hour + 30*time.Minute, Timezone: "Europe/London"}, window)
+
+	for _, invalid := range [][3]string{
+		{"8am", "21:00", ""},
+		{"08:00", "24:00", ""},
+		{"08:00", "08:00", ""},
+		{"08:00", "21:00", "Mars/Olympus_Mons"},
+		{"08:00", "21:00", "Local"},
+	} {
+		_, err := domain.ParseWindow(invalid[0], invalid[1], invalid[2])
+		s.Error(err, "%v", invalid)
+	}
+}
+
+func (s *WindowTestSuite) TestNext() {
+	window, err := domain.ParseWindow("08:00", "21:00", "Europe/London")
+	s.Require().NoError(err)
+
+	inside := time.Date(2026, 6, 1, 12, 0, 0, 0, s.london)
+	next, err := window.Next(inside)
+	s.NoError(err)
+	s.Equal(inside, next)
+
+	evening := time.Date(2026, 6, 1, 22, 0, 0, 0, s.london)
+	next, err = window.Next(evening)
+	s.NoError(err)
+	s.Equal(time.Date(2026, 6, 2, 8, 0, 0, 0, s.london), next)
+
+	early := time.Date(2026, 6, 1, 6, 0, 0, 0, s.london)
+	next, err = window.Next(early)
+	s.NoError(err)
+	s.Equal(time.Date(2026, 6, 1, 8, 0, 0, 0, s.london), next)
+}
+
+func (s *WindowTestSuite) TestNext_DaylightSaving() {
+	window, err := domain.ParseWindow("08:00", "21:00", "Europe/London")
+	s.Require().NoError(err)
+
+	// the clocks go forward at 01:00 UTC on 29 March 2026.
+	next, err := window.Next(time.Date(2026, 3, 28, 23, 0, 0, 0, time.UTC))
+	s.NoError(err)
+	s.Equal(time.Date(2026, 3, 29, 7, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func (s *WindowTestSuite) TestQuietHours_SpanMidnight() {
+	quietHours := domain.QuietHours{Start: "21:00", End: "08:00", Timezone: "Asia/Dubai"}
+	window, err := quietHours.Window("")
+	s.Require().NoError(err)
+
+	late := time.Date(2026, 6, 1, 23, 0, 0, 0, time.UTC) // 03:00 in Dubai
+	next, err := window.Next(late)
+	s.NoError(err)
+	s.Equal(time.Date(2026, 6, 2, 4, 0, 0, 0, time.UTC), next.UTC())
+
+	window, err = quietHours.Window("Europe/London")
+	s.Require().NoError(err)
+	s.Equal("Europe/London", window.Timezone, "the recipient's time zone wins")
+}
+
+func (s *WindowTestSuite) TestNextDeliveryTime() {
+	window, err := domain.ParseWindow("18:00", "20:00", "UTC")
+	s.Require().NoError(err)
+	quietHours, err := domain.QuietHours{Start: "19:00", End: "07:00"}.Window("")
+	s.Require().NoError(err)
+
+	next, err := model.NextDeliveryTime(time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC), []model.DeliveryWindow{window, quietHours})
+	s.NoError(err)
+	s.Equal(time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC), next)
+
+	late, err := domain.ParseWindow("22:00", "23:00", "UTC")
+	s.Require().NoError(err)
+	_, err = model.NextDeliveryTime(time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC), []model.DeliveryWindow{late, quietHours})
+	s.Error(err, "the windows never overlap")
+}
+
+func (s *WindowTestSuite) TestLoad_QuietHours() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+default:
+  quiet_hours:
+    start: "21:00"
+    end: "08:00"
+domains:
+  marketing:
+    quiet_hours:
+      start: "20:00"
+      end: "09:00"
+      timezone: Europe/London
+  billing:
+    retention: 720h
+`), 0o600))
+
+	registry, err := domain.Load(path)
+	s.NoError(err)
+	s.Equal(domain.QuietHours{Start: "21:00", End: "08:00"}, registry.For("billing").QuietHours)
+	s.Equal("Europe/London", registry.For("marketing").QuietHours.Timezone)
+}
+
+func (s *WindowTestSuite) TestLoad_InvalidQuietHours() {
+	path := filepath.Join(s.T().TempDir(), "domains.yaml")
+	s.NoError(os.WriteFile(path, []byte(`
+domains:
+  marketing:
+    quiet_hours:
+      start: "9pm"
+      end: "08:00"
+`), 0o600))
+
+	_, err := domain.Load(path)
+	s.ErrorContains(err, "marketing")
+}
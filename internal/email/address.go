@@ -0,0 +1,26 @@
+package email
+
+import (
+	"net/mail"
+)
+
+// FormatAddress formats an address and an optional display name as an RFC 5322
+// mailbox, the form Request addresses are held in.
+func FormatAddress(address, name string) string {
+	return NewMessage().FormatAddress(address, name)
+}
+
+// ParseAddress parses a single RFC 5322 mailbox, e.g. `"Jane" <jane@example.com>`.
+func ParseAddress(address string) (*mail.Address, error) {
+	return mail.ParseAddress(address)
+}
+
+// bareAddress strips the display name from a mailbox, for SMTP envelopes and
+// APIs which take the name separately.
+func bareAddress(address string) string {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsed.Address
+}
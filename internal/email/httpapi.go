@@ -0,0 +1,145 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+// HTTPAPIConfig holds the details of a SendGrid v3 compatible mail API.
+type HTTPAPIConfig struct {
+	BaseURL string
+	APIKey  string
+}
+
+// HTTPAPIProvider sends messages through a SendGrid v3 style JSON API
+// (POST {BaseURL}/v3/mail/send).
+type HTTPAPIProvider struct {
+	cfg    HTTPAPIConfig
+	client *http.Client
+}
+
+func NewHTTPAPIProvider(client *http.Client, cfg HTTPAPIConfig) *HTTPAPIProvider {
+	return &HTTPAPIProvider{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+type httpAPIAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type httpAPIPersonalization struct {
+	To  []httpAPIAddress `json:"to"`
+	Cc  []httpAPIAddress `json:"cc,omitempty"`
+	Bcc []httpAPIAddress `json:"bcc,omitempty"`
+}
+
+type httpAPIContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type httpAPIAttachment struct {
+	Content     string `json:"content"`
+	Filename    string `json:"filename"`
+	Type        string `json:"type,omitempty"`
+	Disposition string `json:"disposition"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+type httpAPIMessage struct {
+	Personalizations []httpAPIPersonalization `json:"personalizations"`
+	From             httpAPIAddress           `json:"from"`
+	ReplyToList      []httpAPIAddress         `json:"reply_to_list,omitempty"`
+	Subject          string                   `json:"subject"`
+	Content          []httpAPIContent         `json:"content"`
+	Attachments      []httpAPIAttachment      `json:"attachments,omitempty"`
+}
+
+func (p *HTTPAPIProvider) Send(ctx context.Context, args Request) (*string, error) {
+	data, err := json.Marshal(newHTTPAPIMessage(args))
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.cfg.BaseURL, "/")+"/v3/mail/send", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	response, err := p.client.Do(httpRequest)
+	if err != nil {
+		return nil, failure.New(failure.ProviderOutage, "sendgrid", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		return nil, failure.FromHTTPStatus("sendgrid", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	messageId := response.Header.Get("X-Message-Id")
+	return &messageId, nil
+}
+
+func newHTTPAPIMessage(args Request) httpAPIMessage {
+	msg := httpAPIMessage{
+		Personalizations: []httpAPIPersonalization{{
+			To:  httpAPIAddresses(args.ToAddresses),
+			Cc:  httpAPIAddresses(args.CcAddresses),
+			Bcc: httpAPIAddresses(args.BccAddresses),
+		}},
+		From:        newHTTPAPIAddress(args.FromAddress),
+		ReplyToList: httpAPIAddresses(args.ReplyToAddresses),
+		Subject:     args.Subject,
+		// The API requires text/plain to come first.
+		Content: []httpAPIContent{{Type: "text/plain", Value: args.textBody()}},
+	}
+	if args.HtmlBody != "" {
+		msg.Content = append(msg.Content, httpAPIContent{Type: "text/html", Value: args.HtmlBody})
+	}
+	for _, attachment := range args.Attachments {
+		disposition := "attachment"
+		if attachment.ContentID != "" {
+			disposition = "inline"
+		}
+		msg.Attachments = append(msg.Attachments, httpAPIAttachment{
+			Content:     base64.StdEncoding.EncodeToString(attachment.Data),
+			Filename:    attachment.Name,
+			Type:        attachment.contentType(),
+			Disposition: disposition,
+			ContentID:   attachment.ContentID,
+		})
+	}
+	return msg
+}
+
+func httpAPIAddresses(addresses []string) []httpAPIAddress {
+	if len(addresses) == 0 {
+		return nil
+	}
+	resp := make([]httpAPIAddress, len(addresses))
+	for i, address := range addresses {
+		resp[i] = newHTTPAPIAddress(address)
+	}
+	return resp
+}
+
+func newHTTPAPIAddress(address string) httpAPIAddress {
+	parsed, err := ParseAddress(address)
+	if err != nil {
+		return httpAPIAddress{Email: address}
+	}
+	return httpAPIAddress{Email: parsed.Address, Name: parsed.Name}
+}
@@ -12,6 +12,7 @@ type Message struct {
 	header      header
 	parts       []*part
 	attachments []*file
+	embedded    []*file
 	charset     string
 	encoding    Encoding
 	hEncoder    mimeEncoder
@@ -54,6 +55,7 @@ func (m *Message) Reset() {
 	}
 	m.parts = nil
 	m.attachments = nil
+	m.embedded = nil
 }
 
 func (m *Message) applySettings(settings []MessageSetting) {
@@ -291,3 +293,9 @@ func (m *Message) appendFile(list []*file, name string, data []byte, settings []
 func (m *Message) Attach(filename string, data []byte, settings ...FileSetting) {
 	m.attachments = m.appendFile(m.attachments, filename, data, settings)
 }
+
+// Embed embeds the images in the email so the HTML body can reference them as
+// cid:<filename>, or by the Content-ID set with SetHeader.
+func (m *Message) Embed(filename string, data []byte, settings ...FileSetting) {
+	m.embedded = m.appendFile(m.embedded, filename, data, settings)
+}
@@ -0,0 +1,117 @@
+package email_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ses "github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/email"
+)
+
+type fakeSESClient struct {
+	raw     []byte
+	account *ses.GetAccountOutput
+	err     error
+}
+
+func (c *fakeSESClient) SendEmail(_ context.Context, params *ses.SendEmailInput, _ ...func(*ses.Options)) (*ses.SendEmailOutput, error) {
+	c.raw = params.Content.Raw.Data
+	return &ses.SendEmailOutput{MessageId: aws.String("message-id")}, nil
+}
+
+func (c *fakeSESClient) GetAccount(context.Context, *ses.GetAccountInput, ...func(*ses.Options)) (*ses.GetAccountOutput, error) {
+	return c.account, c.err
+}
+
+type MessageTestSuite struct {
+	suite.Suite
+}
+
+func TestMessageTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageTestSuite))
+}
+
+// parts returns the media type of every leaf part of a MIME entity, keyed by
+// the nesting of multipart types it is found in.
+func (s *MessageTestSuite) parts(header map[string][]string, body io.Reader, path string, out map[string][]string) {
+	mediaType, params, err := mime.ParseMediaType(mail.Header(header).Get("Content-Type"))
+	s.Require().NoError(err)
+	if len(mediaType) < 10 || mediaType[:10] != "multipart/" {
+		out[path] = append(out[path], mediaType+" "+mail.Header(header).Get("Content-Id"))
+		return
+	}
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return
+		}
+		s.Require().NoError(err)
+		s.parts(part.Header, part, path+"/"+mediaType[10:], out)
+	}
+}
+
+func (s *MessageTestSuite) send(req email.Request) map[string][]string {
+	client := &fakeSESClient{}
+	_, err := email.NewSESProvider(client).Send(context.Background(), req)
+	s.Require().NoError(err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(client.raw))
+	s.Require().NoError(err)
+	out := map[string][]string{}
+	s.parts(msg.Header, msg.Body, "", out)
+	return out
+}
+
+func (s *MessageTestSuite) TestSend_TextAlternative() {
+	got := s.send(email.Request{
+		FromAddress: "noreply@example.com",
+		ToAddresses: []string{"to@example.com"},
+		Subject:     "subject",
+		HtmlBody:    "<p>hello</p>",
+	})
+	s.Equal(map[string][]string{
+		"/alternative": {"text/plain ", "text/html "},
+	}, got)
+}
+
+func (s *MessageTestSuite) TestSend_InlineImagesAndAttachments() {
+	got := s.send(email.Request{
+		FromAddress: "noreply@example.com",
+		ToAddresses: []string{"to@example.com"},
+		Subject:     "subject",
+		HtmlBody:    `<p>hello</p><img src="cid:logo">`,
+		TextBody:    "hello",
+		Attachments: []email.Attachment{
+			{Name: "logo.png", Data: []byte("png"), ContentID: "logo"},
+			{Name: "invoice.pdf", Data: []byte("pdf")},
+		},
+	})
+	s.Equal(map[string][]string{
+		"/mixed/related/alternative": {"text/plain ", "text/html "},
+		"/mixed/related":             {"image/png <logo>"},
+		"/mixed":                     {"application/pdf "},
+	}, got)
+}
+
+func (s *MessageTestSuite) TestHTMLToText() {
+	tests := map[string]string{
+		"<p>Hello <b>Jane</b>,</p><p>Your order   has shipped.</p>":                            "Hello Jane,\n\nYour order has shipped.",
+		"<html><head><title>t</title><style>p{}</style></head><body>Hi<br>there</body></html>": "Hi\nthere",
+		`<p>Track it <a href="https://example.com/t">here</a>.</p>`:                            "Track it here (https://example.com/t).",
+		`<a href="https://example.com">https://example.com</a>`:                                "https://example.com",
+		"<ul><li>one</li><li>two</li></ul>":                                                    "- one\n- two",
+		`<img src="cid:logo" alt="Example"> &amp; co`:                                          "Example & co",
+	}
+	for in, want := range tests {
+		s.Equal(want, email.HTMLToText(in), in)
+	}
+}
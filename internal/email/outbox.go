@@ -0,0 +1,41 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/anicoll/unicom/internal/outbox"
+)
+
+// OutboxProvider writes each message to an outbox as an .eml file instead of
+// sending it, standing in for SES when running locally.
+type OutboxProvider struct {
+	outbox *outbox.Outbox
+}
+
+func NewOutboxProvider(box *outbox.Outbox) *OutboxProvider {
+	return &OutboxProvider{
+		outbox: box,
+	}
+}
+
+func (p *OutboxProvider) Send(ctx context.Context, args Request) (*string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewString()
+	msg := buildMessage(args)
+	msg.SetHeader("Message-ID", fmt.Sprintf("<%s@outbox>", id))
+
+	emailRaw, err := rawMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.outbox.Write(outbox.Email, id, "eml", emailRaw); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,45 @@
+package email_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/outbox"
+)
+
+type OutboxProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestOutboxProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxProviderTestSuite))
+}
+
+func (s *OutboxProviderTestSuite) TestSend_WritesEml() {
+	box, err := outbox.New(s.T().TempDir())
+	s.Require().NoError(err)
+
+	id, err := email.NewOutboxProvider(box).Send(context.Background(), email.Request{
+		FromAddress: "noreply@example.com",
+		ToAddresses: []string{"customer@example.com"},
+		Subject:     "Your bill",
+		HtmlBody:    "<p>Hello</p>",
+	})
+	s.Require().NoError(err)
+
+	messages, err := box.List()
+	s.Require().NoError(err)
+	s.Require().Len(messages, 1)
+	s.Equal(*id, messages[0].ID)
+	s.Equal(outbox.Email, messages[0].Kind)
+	s.Equal(".eml", messages[0].Name[len(messages[0].Name)-4:])
+
+	raw, err := box.Read(messages[0].Name)
+	s.Require().NoError(err)
+	s.Contains(string(raw), "Subject: Your bill")
+	s.Contains(string(raw), "To: customer@example.com")
+	s.Contains(string(raw), "<p>Hello</p>")
+}
@@ -0,0 +1,162 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"path/filepath"
+)
+
+// Provider delivers a fully described email request through a single backend
+// (SES, SMTP, an HTTP API, ...) and returns the provider's message ID.
+type Provider interface {
+	Send(ctx context.Context, args Request) (*string, error)
+}
+
+type Service struct {
+	defaultProvider Provider
+	domainProviders map[string]Provider
+}
+
+// Request is an email to send. Addresses are RFC 5322 mailboxes and may
+// include a display name, see FormatAddress.
+type Request struct {
+	Domain           string
+	FromAddress      string
+	Subject          string
+	ReplyToAddresses []string
+	CcAddresses      []string
+	BccAddresses     []string
+	ToAddresses      []string
+	HtmlBody         string
+	// TextBody is the plain text alternative of HtmlBody. It is generated from
+	// HtmlBody when empty.
+	TextBody    string
+	Attachments []Attachment
+	// HtmlBodyRef and TextBodyRef reference bodies offloaded to the payload
+	// store, see the payload package. They are resolved before sending.
+	HtmlBodyRef string
+	TextBodyRef string
+}
+
+// Attachment is a file sent with the email. Attachments with a ContentID are
+// embedded inline so the HTML body can reference them as cid:<ContentID>.
+type Attachment struct {
+	Name      string
+	Data      []byte
+	ContentID string
+	// URL is fetched by the worker to fill in Data, keeping the file out of
+	// the workflow's history.
+	URL string
+	// DataRef references Data offloaded to the payload store.
+	DataRef string
+	// ContentType is detected from Data when the attachment is accepted or
+	// fetched, and guessed from Name when empty.
+	ContentType string
+}
+
+// contentType returns the media type of the attachment.
+func (a Attachment) contentType() string {
+	if a.ContentType != "" {
+		return a.ContentType
+	}
+	return mime.TypeByExtension(filepath.Ext(a.Name))
+}
+
+// textBody returns the plain text alternative of the request.
+func (r Request) textBody() string {
+	if r.TextBody != "" {
+		return r.TextBody
+	}
+	return HTMLToText(r.HtmlBody)
+}
+
+// NewService creates a Service which sends through defaultProvider unless the
+// request's domain has an entry in domainProviders.
+func NewService(defaultProvider Provider, domainProviders map[string]Provider) *Service {
+	if domainProviders == nil {
+		domainProviders = map[string]Provider{}
+	}
+	return &Service{
+		defaultProvider: defaultProvider,
+		domainProviders: domainProviders,
+	}
+}
+
+func (es *Service) Send(ctx context.Context, args Request) (*string, error) {
+	return es.providerFor(args.Domain).Send(ctx, args)
+}
+
+func (es *Service) providerFor(domain string) Provider {
+	if provider, ok := es.domainProviders[domain]; ok {
+		return provider
+	}
+	return es.defaultProvider
+}
+
+// buildMessage converts a Request into a MIME Message.
+func buildMessage(args Request) *Message {
+	msg := NewMessage()
+	msg.SetHeader("From", args.FromAddress)
+	msg.SetHeader("To", args.ToAddresses...)
+	if len(args.CcAddresses) > 0 {
+		msg.SetHeader("Cc", args.CcAddresses...)
+	}
+	if len(args.BccAddresses) > 0 {
+		msg.SetHeader("Bcc", args.BccAddresses...)
+	}
+	if len(args.ReplyToAddresses) > 0 {
+		msg.SetHeader("Reply-To", args.ReplyToAddresses...)
+	}
+	msg.SetHeader("Subject", args.Subject)
+	msg.SetBody("text/plain", args.textBody())
+	if args.HtmlBody != "" {
+		msg.AddAlternative("text/html", args.HtmlBody)
+	}
+
+	for _, attachment := range args.Attachments {
+		var settings []FileSetting
+		if contentType := attachment.contentType(); contentType != "" {
+			settings = append(settings, SetHeader(map[string][]string{
+				"Content-Type": {contentType + `; name="` + filepath.Base(attachment.Name) + `"`},
+			}))
+		}
+		if attachment.ContentID != "" {
+			settings = append(settings, SetHeader(map[string][]string{
+				"Content-ID": {"<" + attachment.ContentID + ">"},
+			}))
+			msg.Embed(attachment.Name, attachment.Data, settings...)
+			continue
+		}
+		msg.Attach(attachment.Name, attachment.Data, settings...)
+	}
+	return msg
+}
+
+// Render returns the raw RFC 5322 message args is sent as, without sending
+// it. Every provider builds the same message, so it doesn't need one.
+func Render(args Request) ([]byte, error) {
+	return rawMessage(buildMessage(args))
+}
+
+// rawMessage renders the request as a raw RFC 5322 message.
+func rawMessage(msg *Message) ([]byte, error) {
+	var emailRaw bytes.Buffer
+	_, err := msg.WriteTo(&emailRaw)
+	if err != nil {
+		return nil, err
+	}
+	return emailRaw.Bytes(), nil
+}
+
+// recipients returns the bare address of every envelope recipient of the
+// request, including Bcc.
+func recipients(args Request) []string {
+	all := make([]string, 0, len(args.ToAddresses)+len(args.CcAddresses)+len(args.BccAddresses))
+	for _, addresses := range [][]string{args.ToAddresses, args.CcAddresses, args.BccAddresses} {
+		for _, address := range addresses {
+			all = append(all, bareAddress(address))
+		}
+	}
+	return all
+}
@@ -0,0 +1,106 @@
+package email_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/email"
+)
+
+type recordingProvider struct {
+	id       string
+	requests []email.Request
+}
+
+func (p *recordingProvider) Send(_ context.Context, args email.Request) (*string, error) {
+	p.requests = append(p.requests, args)
+	return &p.id, nil
+}
+
+type ServiceTestSuite struct {
+	suite.Suite
+}
+
+func TestServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceTestSuite))
+}
+
+func (s *ServiceTestSuite) TestService_Send_UsesDomainProvider() {
+	defaultProvider := &recordingProvider{id: "default"}
+	smtpProvider := &recordingProvider{id: "smtp"}
+	svc := email.NewService(defaultProvider, map[string]email.Provider{
+		"local-dev": smtpProvider,
+	})
+
+	id, err := svc.Send(context.Background(), email.Request{Domain: "local-dev"})
+	s.NoError(err)
+	s.Equal("smtp", *id)
+
+	id, err = svc.Send(context.Background(), email.Request{Domain: "other"})
+	s.NoError(err)
+	s.Equal("default", *id)
+
+	s.Len(smtpProvider.requests, 1)
+	s.Len(defaultProvider.requests, 1)
+}
+
+func (s *ServiceTestSuite) TestRender() {
+	raw, err := email.Render(email.Request{
+		FromAddress: "noreply@example.com",
+		ToAddresses: []string{"to@example.com"},
+		Subject:     "subject",
+		HtmlBody:    "<p>hello</p>",
+		Attachments: []email.Attachment{{Name: "a.txt", Data: []byte("data")}},
+	})
+	s.NoError(err)
+	s.Contains(string(raw), "Subject: subject\r\n")
+	s.Contains(string(raw), "To: to@example.com\r\n")
+	s.Contains(string(raw), "Content-Type: text/html")
+	s.Contains(string(raw), `filename="a.txt"`)
+}
+
+func (s *ServiceTestSuite) TestHTTPAPIProvider_Send_Success() {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/v3/mail/send", r.URL.Path)
+		s.Equal("Bearer secret", r.Header.Get("Authorization"))
+		s.NoError(json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("X-Message-Id", "message-id")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	provider := email.NewHTTPAPIProvider(srv.Client(), email.HTTPAPIConfig{BaseURL: srv.URL, APIKey: "secret"})
+	id, err := provider.Send(context.Background(), email.Request{
+		FromAddress:  email.FormatAddress("noreply@example.com", "Example Ltd"),
+		ToAddresses:  []string{email.FormatAddress("to@example.com", "Jane Doe")},
+		BccAddresses: []string{"bcc@example.com"},
+		Subject:      "subject",
+		HtmlBody:     "<p>hello</p>",
+		Attachments:  []email.Attachment{{Name: "a.txt", Data: []byte("data")}},
+	})
+	s.NoError(err)
+	s.Equal("message-id", *id)
+	s.Equal("subject", got["subject"])
+	s.Equal(map[string]any{"email": "noreply@example.com", "name": "Example Ltd"}, got["from"])
+	to := got["personalizations"].([]any)[0].(map[string]any)["to"]
+	s.Equal([]any{map[string]any{"email": "to@example.com", "name": "Jane Doe"}}, to)
+	s.Len(got["attachments"], 1)
+}
+
+func (s *ServiceTestSuite) TestHTTPAPIProvider_Send_ErrorStatus() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	provider := email.NewHTTPAPIProvider(srv.Client(), email.HTTPAPIConfig{BaseURL: srv.URL})
+	id, err := provider.Send(context.Background(), email.Request{})
+	s.Nil(id)
+	s.ErrorContains(err, "400")
+}
@@ -1,68 +1,100 @@
 package email
 
 import (
-	"bytes"
 	"context"
+	"fmt"
 
 	ses "github.com/aws/aws-sdk-go-v2/service/sesv2"
 	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
-)
 
-type Service struct {
-	sesClient *ses.Client
-}
+	"github.com/anicoll/unicom/internal/failure"
+)
 
-type Request struct {
-	FromAddress      string
-	Subject          string
-	ReplyToAddresses []string
-	CcAddresses      []string
-	BccAddresses     []string
-	ToAddresses      []string
-	HtmlBody         string
-	Attachments      []Attachment
+type sesClient interface {
+	SendEmail(ctx context.Context, params *ses.SendEmailInput, optFns ...func(*ses.Options)) (*ses.SendEmailOutput, error)
+	GetAccount(ctx context.Context, params *ses.GetAccountInput, optFns ...func(*ses.Options)) (*ses.GetAccountOutput, error)
 }
 
-type Attachment struct {
-	Name string
-	Data []byte
+// SESProvider sends raw MIME messages through Amazon SES v2.
+type SESProvider struct {
+	sesClient sesClient
 }
 
-func NewService(client *ses.Client) *Service {
-	return &Service{
+func NewSESProvider(client sesClient) *SESProvider {
+	return &SESProvider{
 		sesClient: client,
 	}
 }
 
-func (es *Service) Send(ctx context.Context, args Request) (*string, error) {
-	msg := NewMessage()
-	msg.SetHeader("From", args.FromAddress)
-	msg.SetHeader("To", args.ToAddresses...)
-	msg.SetHeader("Cc", args.CcAddresses...)
-	msg.SetHeader("Bcc", args.BccAddresses...)
-	msg.SetHeader("Subject", args.Subject)
-	msg.SetBody("text/html", args.HtmlBody)
-
-	for _, attachment := range args.Attachments {
-		msg.Attach(attachment.Name, attachment.Data)
-	}
-
-	var emailRaw bytes.Buffer
-	_, err := msg.WriteTo(&emailRaw)
+func (p *SESProvider) Send(ctx context.Context, args Request) (*string, error) {
+	emailRaw, err := rawMessage(buildMessage(args))
 	if err != nil {
 		return nil, err
 	}
 
-	output, err := es.sesClient.SendEmail(ctx, &ses.SendEmailInput{
+	output, err := p.sesClient.SendEmail(ctx, &ses.SendEmailInput{
+		Destination: &types.Destination{
+			ToAddresses:  args.ToAddresses,
+			CcAddresses:  args.CcAddresses,
+			BccAddresses: args.BccAddresses,
+		},
 		Content: &types.EmailContent{
 			Raw: &types.RawMessage{
-				Data: emailRaw.Bytes(),
+				Data: emailRaw,
 			},
 		},
 	})
 	if err != nil {
-		return nil, err
+		return nil, failure.FromAWS("ses", err)
 	}
 
 	return output.MessageId, nil
 }
+
+// SESAccount is the sending state of the SES account in a region.
+type SESAccount struct {
+	SendingEnabled bool
+	// EnforcementStatus is HEALTHY, PROBATION or SHUTDOWN.
+	EnforcementStatus string
+	// Max24HourSend is the number of emails the account may send in 24 hours,
+	// or -1 when it is unlimited.
+	Max24HourSend   float64
+	SentLast24Hours float64
+}
+
+// QuotaUsed returns the share of the daily sending quota used, or 0 when the
+// quota is unlimited.
+func (a SESAccount) QuotaUsed() float64 {
+	if a.Max24HourSend <= 0 {
+		return 0
+	}
+	return a.SentLast24Hours / a.Max24HourSend
+}
+
+func (a SESAccount) String() string {
+	sending := "sending enabled"
+	if !a.SendingEnabled {
+		sending = "sending paused"
+	}
+	return fmt.Sprintf("%s, %s, sent %.0f of %.0f in the last 24 hours", sending, a.EnforcementStatus, a.SentLast24Hours, a.Max24HourSend)
+}
+
+// Account returns the sending state of the SES account, which also verifies
+// the credentials and connectivity of the provider.
+func (p *SESProvider) Account(ctx context.Context) (SESAccount, error) {
+	output, err := p.sesClient.GetAccount(ctx, &ses.GetAccountInput{})
+	if err != nil {
+		return SESAccount{}, failure.FromAWS("ses", err)
+	}
+	account := SESAccount{
+		SendingEnabled: output.SendingEnabled,
+	}
+	if output.EnforcementStatus != nil {
+		account.EnforcementStatus = *output.EnforcementStatus
+	}
+	if output.SendQuota != nil {
+		account.Max24HourSend = output.SendQuota.Max24HourSend
+		account.SentLast24Hours = output.SendQuota.SentLast24Hours
+	}
+	return account, nil
+}
@@ -0,0 +1,57 @@
+package email_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ses "github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+type SESTestSuite struct {
+	suite.Suite
+}
+
+func TestSESTestSuite(t *testing.T) {
+	suite.Run(t, new(SESTestSuite))
+}
+
+func (s *SESTestSuite) TestAccount() {
+	client := &fakeSESClient{account: &ses.GetAccountOutput{
+		SendingEnabled:    true,
+		EnforcementStatus: aws.String("HEALTHY"),
+		SendQuota: &types.SendQuota{
+			Max24HourSend:   50000,
+			SentLast24Hours: 45000,
+		},
+	}}
+
+	account, err := email.NewSESProvider(client).Account(context.Background())
+	s.Require().NoError(err)
+	s.Equal(email.SESAccount{
+		SendingEnabled:    true,
+		EnforcementStatus: "HEALTHY",
+		Max24HourSend:     50000,
+		SentLast24Hours:   45000,
+	}, account)
+	s.InDelta(0.9, account.QuotaUsed(), 0.0001)
+	s.Equal("sending enabled, HEALTHY, sent 45000 of 50000 in the last 24 hours", account.String())
+}
+
+func (s *SESTestSuite) TestAccount_UnlimitedQuota() {
+	account := email.SESAccount{Max24HourSend: -1, SentLast24Hours: 100}
+	s.Zero(account.QuotaUsed())
+}
+
+func (s *SESTestSuite) TestAccount_Error() {
+	client := &fakeSESClient{err: &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "denied"}}
+
+	_, err := email.NewSESProvider(client).Account(context.Background())
+	s.Equal(failure.Auth, failure.KindOf(err))
+}
@@ -0,0 +1,123 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+// SMTPConfig holds the connection details of an SMTP relay.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// SMTPProvider sends messages through a plain SMTP relay. It is useful for
+// local development against MailHog or smtp4dev.
+type SMTPProvider struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPProvider(cfg SMTPConfig) *SMTPProvider {
+	return &SMTPProvider{
+		cfg: cfg,
+	}
+}
+
+func (p *SMTPProvider) Send(ctx context.Context, args Request) (*string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	messageId := fmt.Sprintf("<%s@%s>", uuid.NewString(), p.cfg.Host)
+	msg := buildMessage(args)
+	msg.SetHeader("Message-ID", messageId)
+
+	emailRaw, err := rawMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth smtp.Auth
+	if p.cfg.Username != "" {
+		auth = smtp.PlainAuth("", p.cfg.Username, p.cfg.Password, p.cfg.Host)
+	}
+
+	err = p.sendMail(ctx, auth, bareAddress(args.FromAddress), recipients(args), emailRaw)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, failure.FromSMTP("smtp", err)
+	}
+	return &messageId, nil
+}
+
+// sendMail does what smtp.SendMail does over a connection bound to ctx: the
+// connection is dialled with ctx, gets its deadline and is closed when ctx is
+// cancelled, so a hung relay can't hold the activity past its timeout.
+func (p *SMTPProvider) sendMail(ctx context.Context, auth smtp.Auth, from string, to []string, msg []byte) error {
+	addr := net.JoinHostPort(p.cfg.Host, strconv.Itoa(p.cfg.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	c, err := smtp.NewClient(conn, p.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: p.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, address := range to {
+		if err := c.Rcpt(address); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
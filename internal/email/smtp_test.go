@@ -0,0 +1,138 @@
+package email_test
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+type SMTPTestSuite struct {
+	suite.Suite
+	listener net.Listener
+}
+
+func TestSMTPTestSuite(t *testing.T) {
+	suite.Run(t, new(SMTPTestSuite))
+}
+
+func (s *SMTPTestSuite) SetupTest() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	s.listener = listener
+}
+
+func (s *SMTPTestSuite) TearDownTest() {
+	_ = s.listener.Close()
+}
+
+func (s *SMTPTestSuite) provider() *email.SMTPProvider {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return email.NewSMTPProvider(email.SMTPConfig{Host: addr.IP.String(), Port: addr.Port})
+}
+
+// serve answers a single SMTP session, replying to RCPT with rcptReply, and
+// returns the commands and message it received.
+func (s *SMTPTestSuite) serve(rcptReply string) <-chan []string {
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		var commands []string
+		defer func() { received <- commands }()
+		_ = text.PrintfLine("220 localhost ready")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			commands = append(commands, line)
+			switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+			case "EHLO":
+				_ = text.PrintfLine("250 localhost")
+			case "RCPT":
+				_ = text.PrintfLine("%s", rcptReply)
+			case "DATA":
+				_ = text.PrintfLine("354 go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				commands = append(commands, string(data))
+				_ = text.PrintfLine("250 queued")
+			case "QUIT":
+				_ = text.PrintfLine("221 bye")
+				return
+			default:
+				_ = text.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return received
+}
+
+func (s *SMTPTestSuite) TestSend() {
+	received := s.serve("250 ok")
+
+	id, err := s.provider().Send(context.Background(), email.Request{
+		FromAddress: "Billing <billing@example.com>",
+		ToAddresses: []string{"jane@example.com"},
+		Subject:     "Your receipt",
+		HtmlBody:    "<p>Thanks</p>",
+	})
+	s.Require().NoError(err)
+	s.Contains(*id, "@127.0.0.1>")
+
+	commands := <-received
+	s.Contains(commands, "MAIL FROM:<billing@example.com>")
+	s.Contains(commands, "RCPT TO:<jane@example.com>")
+	s.Contains(strings.Join(commands, "\n"), "Subject: Your receipt")
+	s.Equal("QUIT", commands[len(commands)-1])
+}
+
+func (s *SMTPTestSuite) TestSend_RejectedRecipient() {
+	s.serve("550 no such user")
+
+	_, err := s.provider().Send(context.Background(), email.Request{
+		FromAddress: "billing@example.com",
+		ToAddresses: []string{"nobody@example.com"},
+	})
+	s.Equal(failure.InvalidRecipient, failure.KindOf(err))
+}
+
+func (s *SMTPTestSuite) TestSend_HungServer() {
+	// the connection is accepted but the server never greets the client.
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := s.listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := s.provider().Send(ctx, email.Request{
+		FromAddress: "billing@example.com",
+		ToAddresses: []string{"jane@example.com"},
+	})
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Less(time.Since(start), 5*time.Second)
+	select {
+	case conn := <-accepted:
+		_ = conn.Close()
+	default:
+	}
+}
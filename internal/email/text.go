@@ -0,0 +1,165 @@
+package email
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// HTMLToText renders an HTML body as plain text for the text/plain
+// alternative. Block elements start new lines, links keep their URL and
+// images are replaced by their alt text.
+func HTMLToText(body string) string {
+	t := &textWriter{}
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	skip := 0
+	var hrefs []string
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// io.EOF, or a read error which can't happen on a strings.Reader.
+			break
+		}
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case html.TextToken:
+			if skip == 0 {
+				t.text(token.Data)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch token.DataAtom {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				if tokenType == html.StartTagToken {
+					skip++
+				}
+			case atom.Br:
+				t.newline()
+			case atom.Hr:
+				t.paragraph()
+				t.text("----")
+				t.paragraph()
+			case atom.Li:
+				t.newline()
+				t.text("- ")
+			case atom.Td, atom.Th:
+				t.text(" ")
+			case atom.Img:
+				if alt := attr(token, "alt"); alt != "" && skip == 0 {
+					t.text(alt)
+				}
+			case atom.A:
+				if tokenType == html.StartTagToken {
+					hrefs = append(hrefs, attr(token, "href"))
+				}
+			default:
+				if isBlock(token.DataAtom) {
+					t.paragraph()
+				}
+			}
+		case html.EndTagToken:
+			switch token.DataAtom {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				if skip > 0 {
+					skip--
+				}
+			case atom.Li, atom.Tr:
+				t.newline()
+			case atom.A:
+				if len(hrefs) == 0 {
+					continue
+				}
+				href := hrefs[len(hrefs)-1]
+				hrefs = hrefs[:len(hrefs)-1]
+				if linkable(href) && !strings.HasSuffix(t.lastText, href) {
+					t.text(" (" + href + ")")
+				}
+			default:
+				if isBlock(token.DataAtom) {
+					t.paragraph()
+				}
+			}
+		}
+	}
+	return t.String()
+}
+
+func attr(token html.Token, key string) string {
+	for _, a := range token.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// linkable reports whether href is worth showing to a plain text reader.
+func linkable(href string) bool {
+	return href != "" && !strings.HasPrefix(href, "#") && !strings.HasPrefix(strings.ToLower(href), "javascript:")
+}
+
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Table, atom.Tr, atom.Blockquote, atom.Pre,
+		atom.Section, atom.Article, atom.Header, atom.Footer, atom.Body:
+		return true
+	}
+	return false
+}
+
+// textWriter collapses whitespace the way a browser would while keeping the
+// line breaks implied by the markup.
+type textWriter struct {
+	sb strings.Builder
+	// pending is the number of line breaks owed before the next text.
+	pending  int
+	space    bool
+	lastText string
+}
+
+func (t *textWriter) text(s string) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" {
+			t.space = true
+		}
+		return
+	}
+	if t.sb.Len() > 0 {
+		switch {
+		case t.pending > 0:
+			t.sb.WriteString(strings.Repeat("\n", t.pending))
+		case t.space || startsWithSpace(s):
+			t.sb.WriteString(" ")
+		}
+	}
+	t.pending = 0
+	t.lastText = strings.Join(words, " ")
+	t.sb.WriteString(t.lastText)
+	t.space = endsWithSpace(s)
+}
+
+func (t *textWriter) newline() {
+	if t.pending < 1 {
+		t.pending = 1
+	}
+}
+
+func (t *textWriter) paragraph() {
+	t.pending = 2
+}
+
+func (t *textWriter) String() string {
+	return t.sb.String()
+}
+
+func startsWithSpace(s string) bool {
+	return s != strings.TrimLeft(s, " \t\r\n")
+}
+
+func endsWithSpace(s string) bool {
+	return s != strings.TrimRight(s, " \t\r\n")
+}
@@ -34,6 +34,10 @@ func (w *messageWriter) writeMessage(m *Message) {
 		w.openMultipart("mixed")
 	}
 
+	if m.hasRelatedPart() {
+		w.openMultipart("related")
+	}
+
 	if m.hasAlternativePart() {
 		w.openMultipart("alternative")
 	}
@@ -44,6 +48,11 @@ func (w *messageWriter) writeMessage(m *Message) {
 		w.closeMultipart()
 	}
 
+	w.addFiles(m.embedded, false)
+	if m.hasRelatedPart() {
+		w.closeMultipart()
+	}
+
 	w.addFiles(m.attachments, true)
 	if m.hasMixedPart() {
 		w.closeMultipart()
@@ -51,7 +60,11 @@ func (w *messageWriter) writeMessage(m *Message) {
 }
 
 func (m *Message) hasMixedPart() bool {
-	return (len(m.parts) > 0 && len(m.attachments) > 0) || len(m.attachments) > 1
+	return ((len(m.parts) > 0 || len(m.embedded) > 0) && len(m.attachments) > 0) || len(m.attachments) > 1
+}
+
+func (m *Message) hasRelatedPart() bool {
+	return (len(m.parts) > 0 && len(m.embedded) > 0) || len(m.embedded) > 1
 }
 
 func (m *Message) hasAlternativePart() bool {
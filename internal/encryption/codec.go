@@ -0,0 +1,122 @@
+package encryption
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+// MetadataEncodingEncrypted is the encoding of payloads encrypted by Codec.
+const MetadataEncodingEncrypted = "binary/encrypted"
+
+// Codec is a Temporal payload codec which encrypts workflow inputs, activity
+// arguments and results, so personal data in them never reaches the Temporal
+// server or its history in plaintext.
+type Codec struct {
+	encryptor *Encryptor
+}
+
+var _ converter.PayloadCodec = (*Codec)(nil)
+
+func NewCodec(encryptor *Encryptor) *Codec {
+	return &Codec{encryptor: encryptor}
+}
+
+// DataConverter returns Temporal's default data converter with payloads
+// encrypted by codec.
+func DataConverter(codec *Codec) converter.DataConverter {
+	return converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec)
+}
+
+func (c *Codec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	if !c.encryptor.Enabled() {
+		return payloads, nil
+	}
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		data, err := proto.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		encrypted, err := c.encryptor.Encrypt(context.Background(), data)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				converter.MetadataEncoding: []byte(MetadataEncodingEncrypted),
+			},
+			Data: encrypted,
+		}
+	}
+	return result, nil
+}
+
+// Decode decrypts payloads encoded by Encode, passing through payloads written
+// before encryption was enabled.
+func (c *Codec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		if string(p.GetMetadata()[converter.MetadataEncoding]) != MetadataEncodingEncrypted {
+			result[i] = p
+			continue
+		}
+		data, err := c.encryptor.Decrypt(context.Background(), p.GetData())
+		if err != nil {
+			return nil, err
+		}
+		result[i] = &commonpb.Payload{}
+		if err := proto.Unmarshal(data, result[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// NewCodecHandler returns a codec server for the Temporal UI and CLI, which
+// decrypts payloads for display. Requests must carry one of tokens as a
+// bearer token; without any tokens every request is refused. allowedOrigins
+// are the origins of Temporal UIs which may call it from the browser with
+// their credentials, so only those listed exactly are allowed: "*" allows
+// none.
+func NewCodecHandler(codec *Codec, tokens, allowedOrigins []string) http.Handler {
+	codecHandler := converter.NewPayloadCodecHTTPHandler(codec)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && origin != "*" && slices.Contains(allowedOrigins, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization,Content-Type,X-Namespace")
+			w.Header().Set("Access-Control-Allow-Methods", "POST,OPTIONS")
+			w.Header().Add("Vary", "Origin")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !authorised(r, tokens) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		codecHandler.ServeHTTP(w, r)
+	})
+}
+
+func authorised(r *http.Request, tokens []string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
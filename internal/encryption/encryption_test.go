@@ -0,0 +1,233 @@
+package encryption_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+
+	"github.com/anicoll/unicom/internal/encryption"
+)
+
+type EncryptionTestSuite struct {
+	suite.Suite
+	dir       string
+	keys      map[string]string
+	indexKey  string
+	encryptor *encryption.Encryptor
+}
+
+func TestEncryptionTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptionTestSuite))
+}
+
+func (s *EncryptionTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.keys = map[string]string{"2026-10": newKey()}
+	s.indexKey = newKey()
+	s.encryptor = s.load("2026-10")
+}
+
+func newKey() string {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// load writes a key file with every key in s.keys and loads an Encryptor
+// from it.
+func (s *EncryptionTestSuite) load(active string) *encryption.Encryptor {
+	var file strings.Builder
+	fmt.Fprintf(&file, "active: %q\nkeys:\n", active)
+	for id, key := range s.keys {
+		fmt.Fprintf(&file, "  %q: %s\n", id, key)
+	}
+
+	path := filepath.Join(s.dir, "keys.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte(file.String()), 0o600))
+	encryptor, err := encryption.Load(path, s.indexKey)
+	s.Require().NoError(err)
+	return encryptor
+}
+
+func (s *EncryptionTestSuite) TestEncrypt_RoundTrip() {
+	ctx := context.Background()
+	plaintext := []byte("someone@example.com")
+
+	first, err := s.encryptor.Encrypt(ctx, plaintext)
+	s.Require().NoError(err)
+	second, err := s.encryptor.Encrypt(ctx, plaintext)
+	s.Require().NoError(err)
+	s.NotContains(string(first), string(plaintext))
+	s.NotEqual(first, second, "every value gets its own nonce")
+
+	got, err := s.encryptor.Decrypt(ctx, first)
+	s.Require().NoError(err)
+	s.Equal(plaintext, got)
+
+	first[len(first)-1] ^= 1
+	_, err = s.encryptor.Decrypt(ctx, first)
+	s.Error(err, "tampered values are refused")
+}
+
+func (s *EncryptionTestSuite) TestEncryptString_RoundTrip() {
+	ctx := context.Background()
+
+	encrypted, err := s.encryptor.EncryptString(ctx, "customer-1")
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(encrypted, "enc:v1:"))
+
+	got, err := s.encryptor.DecryptString(ctx, encrypted)
+	s.Require().NoError(err)
+	s.Equal("customer-1", got)
+
+	got, err = s.encryptor.DecryptString(ctx, "written before encryption")
+	s.Require().NoError(err)
+	s.Equal("written before encryption", got)
+}
+
+func (s *EncryptionTestSuite) TestDecrypt_AfterRotation() {
+	ctx := context.Background()
+	encrypted, err := s.encryptor.EncryptString(ctx, "customer-1")
+	s.Require().NoError(err)
+
+	s.keys["2027-04"] = newKey()
+	rotated := s.load("2027-04")
+
+	got, err := rotated.DecryptString(ctx, encrypted)
+	s.Require().NoError(err)
+	s.Equal("customer-1", got)
+	s.Equal(s.encryptor.BlindIndex("customer-1"), rotated.BlindIndex("customer-1"))
+
+	delete(s.keys, "2026-10")
+	retired := s.load("2027-04")
+	_, err = retired.DecryptString(ctx, encrypted)
+	s.Error(err)
+}
+
+func (s *EncryptionTestSuite) TestLoad_InvalidKeyFile() {
+	path := filepath.Join(s.dir, "keys.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte("active: missing\nkeys:\n  other: "+newKey()+"\n"), 0o600))
+	_, err := encryption.Load(path, s.indexKey)
+	s.Error(err)
+
+	s.Require().NoError(os.WriteFile(path, []byte("active: short\nkeys:\n  short: c2hvcnQ=\n"), 0o600))
+	_, err = encryption.Load(path, s.indexKey)
+	s.Error(err)
+}
+
+func (s *EncryptionTestSuite) TestLoad_RequiresIndexKey() {
+	_, err := encryption.Load("", "")
+	s.Error(err, "blind indexes are written even without encryption")
+
+	_, err = encryption.Load("", "c2hvcnQ=")
+	s.Error(err)
+
+	disabled, err := encryption.Load("", s.indexKey)
+	s.Require().NoError(err)
+	s.Equal(s.encryptor.BlindIndex("customer-1"), disabled.BlindIndex("customer-1"), "enabling encryption keeps the blind indexes")
+	s.NotEqual(encryption.UnkeyedBlindIndex("customer-1"), disabled.BlindIndex("customer-1"))
+}
+
+func (s *EncryptionTestSuite) TestDisabled_LeavesPlaintext() {
+	ctx := context.Background()
+	encryptor, err := encryption.Load("", s.indexKey)
+	s.Require().NoError(err)
+	s.False(encryptor.Enabled())
+
+	got, err := encryptor.EncryptString(ctx, "customer-1")
+	s.Require().NoError(err)
+	s.Equal("customer-1", got)
+
+	encrypted, err := s.encryptor.Encrypt(ctx, []byte("customer-1"))
+	s.Require().NoError(err)
+	_, err = encryptor.Decrypt(ctx, encrypted)
+	s.Error(err, "encrypted values can't be read without keys")
+}
+
+func (s *EncryptionTestSuite) TestCodec_RoundTrip() {
+	codec := encryption.NewCodec(s.encryptor)
+	payload, err := converter.GetDefaultDataConverter().ToPayload("someone@example.com")
+	s.Require().NoError(err)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{payload})
+	s.Require().NoError(err)
+	s.Require().Len(encoded, 1)
+	s.Equal(encryption.MetadataEncodingEncrypted, string(encoded[0].Metadata[converter.MetadataEncoding]))
+	s.NotContains(string(encoded[0].Data), "someone@example.com")
+
+	decoded, err := codec.Decode(append(encoded, payload))
+	s.Require().NoError(err)
+	s.Require().Len(decoded, 2)
+	var got string
+	s.Require().NoError(converter.GetDefaultDataConverter().FromPayload(decoded[0], &got))
+	s.Equal("someone@example.com", got)
+	s.Same(payload, decoded[1], "payloads written before encryption are passed through")
+}
+
+func (s *EncryptionTestSuite) TestCodecHandler_RequiresToken() {
+	codec := encryption.NewCodec(s.encryptor)
+	payload, err := converter.GetDefaultDataConverter().ToPayload("someone@example.com")
+	s.Require().NoError(err)
+	encoded, err := codec.Encode([]*commonpb.Payload{payload})
+	s.Require().NoError(err)
+
+	handler := encryption.NewCodecHandler(codec, []string{"secret"}, []string{"https://temporal.example.com"})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := converter.NewRemotePayloadCodec(converter.RemotePayloadCodecOptions{
+		Endpoint: server.URL + "/codec",
+		ModifyRequest: func(r *http.Request) error {
+			r.Header.Set("Authorization", "Bearer secret")
+			return nil
+		},
+	})
+	decoded, err := client.Decode(encoded)
+	s.Require().NoError(err)
+	s.Equal(payload.Data, decoded[0].Data)
+
+	unauthorised := converter.NewRemotePayloadCodec(converter.RemotePayloadCodecOptions{
+		Endpoint: server.URL + "/codec",
+		ModifyRequest: func(r *http.Request) error {
+			r.Header.Set("Authorization", "Bearer wrong")
+			return nil
+		},
+	})
+	_, err = unauthorised.Decode(encoded)
+	s.Error(err)
+
+	preflight, err := http.NewRequest(http.MethodOptions, server.URL+"/codec/decode", nil)
+	s.Require().NoError(err)
+	preflight.Header.Set("Origin", "https://temporal.example.com")
+	resp, err := http.DefaultClient.Do(preflight)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+	s.Equal("https://temporal.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func (s *EncryptionTestSuite) TestCodecHandler_OnlyListedOrigins() {
+	handler := encryption.NewCodecHandler(encryption.NewCodec(s.encryptor), []string{"secret"}, []string{"*", "https://temporal.example.com"})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	preflight, err := http.NewRequest(http.MethodOptions, server.URL+"/codec/decode", nil)
+	s.Require().NoError(err)
+	preflight.Header.Set("Origin", "https://attacker.example.com")
+	resp, err := http.DefaultClient.Do(preflight)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Empty(resp.Header.Get("Access-Control-Allow-Origin"), "a wildcard doesn't allow every origin")
+	s.Empty(resp.Header.Get("Access-Control-Allow-Credentials"))
+}
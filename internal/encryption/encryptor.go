@@ -0,0 +1,234 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// magic prefixes every envelope so values written before encryption was
+// enabled are recognised and returned as they are.
+var magic = []byte("UCE1")
+
+// stringPrefix marks an envelope stored in a text column.
+const stringPrefix = "enc:v1:"
+
+const (
+	// dataKeyTTL and dataKeyUses bound how long a data key is reused before
+	// a new one is requested from the KMS.
+	dataKeyTTL  = time.Hour
+	dataKeyUses = 100_000
+	// maxCachedKeys bounds the decrypted data keys kept in memory.
+	maxCachedKeys = 1000
+)
+
+// Encryptor encrypts values into self describing envelopes. An Encryptor
+// without a KMS leaves values in plaintext, for development.
+type Encryptor struct {
+	kms      KMS
+	indexKey []byte
+
+	mu        sync.Mutex
+	current   *DataKey
+	createdAt time.Time
+	uses      int
+	decrypted map[string][]byte
+}
+
+// NewEncryptor creates an Encryptor. indexKey keys the blind indexes of
+// encrypted values which need to be looked up.
+func NewEncryptor(kms KMS, indexKey []byte) *Encryptor {
+	return &Encryptor{
+		kms:       kms,
+		indexKey:  indexKey,
+		decrypted: map[string][]byte{},
+	}
+}
+
+// Load creates an Encryptor with the keys of the key file at path and the
+// base64 encoded blind index key. Without a key file values are left in
+// plaintext, but the index key is still required: blind indexes are written
+// either way, and must not change once encryption is enabled.
+func Load(path string, indexKey string) (*Encryptor, error) {
+	if indexKey == "" {
+		return nil, errors.New("a blind index key is required")
+	}
+	key, err := decodeKey(indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+	if path == "" {
+		return NewEncryptor(nil, key), nil
+	}
+	kf, err := LoadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptor(kf, key), nil
+}
+
+// Enabled reports whether values are encrypted.
+func (e *Encryptor) Enabled() bool {
+	return e.kms != nil
+}
+
+// Encrypt returns an envelope holding plaintext.
+func (e *Encryptor) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if !e.Enabled() {
+		return plaintext, nil
+	}
+	key, err := e.dataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(key.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := seal(aead, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope bytes.Buffer
+	envelope.Write(magic)
+	writeField(&envelope, []byte(key.KeyID))
+	writeField(&envelope, key.Encrypted)
+	envelope.Write(sealed)
+	return envelope.Bytes(), nil
+}
+
+// Decrypt returns the plaintext of an envelope. Data which isn't an envelope
+// was written before encryption was enabled and is returned as it is.
+func (e *Encryptor) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, magic) {
+		return data, nil
+	}
+	if !e.Enabled() {
+		return nil, errors.New("encrypted data found but no encryption keys are configured")
+	}
+	rest := data[len(magic):]
+	keyID, rest, err := readField(rest)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, sealed, err := readField(rest)
+	if err != nil {
+		return nil, err
+	}
+	key, err := e.decryptDataKey(ctx, string(keyID), encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, sealed)
+}
+
+// EncryptString encrypts a value stored in a text column.
+func (e *Encryptor) EncryptString(ctx context.Context, plaintext string) (string, error) {
+	if !e.Enabled() || plaintext == "" {
+		return plaintext, nil
+	}
+	envelope, err := e.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return stringPrefix + base64.StdEncoding.EncodeToString(envelope), nil
+}
+
+// DecryptString decrypts the output of EncryptString, returning values
+// written before encryption was enabled as they are.
+func (e *Encryptor) DecryptString(ctx context.Context, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, stringPrefix)
+	if !ok {
+		return value, nil
+	}
+	envelope, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := e.Decrypt(ctx, envelope)
+	return string(plaintext), err
+}
+
+// BlindIndex returns a keyed hash of value, so encrypted values can be looked
+// up by equality without decrypting them.
+func (e *Encryptor) BlindIndex(value string) []byte {
+	mac := hmac.New(sha256.New, e.indexKey)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// UnkeyedBlindIndex returns the blind index value had before the index key
+// was a required setting, when it was computed without a key.
+func UnkeyedBlindIndex(value string) []byte {
+	mac := hmac.New(sha256.New, nil)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// dataKey returns the data key to encrypt with, rotating it once it has been
+// used for long enough.
+func (e *Encryptor) dataKey(ctx context.Context) (*DataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.current == nil || time.Since(e.createdAt) > dataKeyTTL || e.uses >= dataKeyUses {
+		key, err := e.kms.GenerateDataKey(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("generating data key: %w", err)
+		}
+		e.current, e.createdAt, e.uses = &key, time.Now(), 0
+	}
+	e.uses++
+	return e.current, nil
+}
+
+func (e *Encryptor) decryptDataKey(ctx context.Context, keyID string, encrypted []byte) ([]byte, error) {
+	cacheKey := keyID + "/" + string(encrypted)
+	e.mu.Lock()
+	key, ok := e.decrypted[cacheKey]
+	e.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	key, err := e.kms.DecryptDataKey(ctx, keyID, encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting data key: %w", err)
+	}
+	e.mu.Lock()
+	if len(e.decrypted) >= maxCachedKeys {
+		clear(e.decrypted)
+	}
+	e.decrypted[cacheKey] = key
+	e.mu.Unlock()
+	return key, nil
+}
+
+func writeField(buf *bytes.Buffer, field []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(field)))
+	buf.Write(field)
+}
+
+func readField(data []byte) ([]byte, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, errors.New("envelope is truncated")
+	}
+	size := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+size {
+		return nil, nil, errors.New("envelope is truncated")
+	}
+	return data[2 : 2+size], data[2+size:], nil
+}
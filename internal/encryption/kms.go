@@ -0,0 +1,145 @@
+// Package encryption protects personal data at rest with envelope encryption:
+// each value is encrypted with AES-GCM under a data key, which is itself
+// encrypted by a master key held in a KMS.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const keySize = 32
+
+// DataKey is a freshly generated AES-256 key, along with the same key
+// encrypted under the KMS master key KeyID.
+type DataKey struct {
+	KeyID     string
+	Plaintext []byte
+	Encrypted []byte
+}
+
+// KMS holds the master keys data keys are encrypted with.
+type KMS interface {
+	GenerateDataKey(ctx context.Context) (DataKey, error)
+	DecryptDataKey(ctx context.Context, keyID string, encrypted []byte) ([]byte, error)
+}
+
+// KeyFile is a KMS backed by master keys read from a local YAML file, for
+// deployments without a managed KMS and for development:
+//
+//	active: "2026-10"
+//	keys:
+//	  "2026-10": <base64 encoded 32 byte key>
+//	  "2025-04": <base64 encoded 32 byte key>
+//
+// New data keys are encrypted with the active key. Older keys are kept so
+// existing data can still be decrypted after rotation.
+type KeyFile struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+type keyFile struct {
+	Active string            `yaml:"active"`
+	Keys   map[string]string `yaml:"keys"`
+}
+
+// LoadKeyFile reads a KeyFile.
+func LoadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := keyFile{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing key file %s: %w", path, err)
+	}
+	if _, ok := file.Keys[file.Active]; !ok {
+		return nil, fmt.Errorf("key file %s: active key %q is not one of its keys", path, file.Active)
+	}
+
+	kf := &KeyFile{
+		active: file.Active,
+		keys:   map[string]cipher.AEAD{},
+	}
+	for id, encoded := range file.Keys {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key file %s: key %s: %w", path, id, err)
+		}
+		kf.keys[id], err = newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return kf, nil
+}
+
+func (kf *KeyFile) GenerateDataKey(_ context.Context) (DataKey, error) {
+	plaintext := make([]byte, keySize)
+	if _, err := rand.Read(plaintext); err != nil {
+		return DataKey{}, err
+	}
+	encrypted, err := seal(kf.keys[kf.active], plaintext)
+	if err != nil {
+		return DataKey{}, err
+	}
+	return DataKey{
+		KeyID:     kf.active,
+		Plaintext: plaintext,
+		Encrypted: encrypted,
+	}, nil
+}
+
+func (kf *KeyFile) DecryptDataKey(_ context.Context, keyID string, encrypted []byte) ([]byte, error) {
+	aead, ok := kf.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key %q", keyID)
+	}
+	return open(aead, encrypted)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext, prefixing the random nonce.
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the output of seal.
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,169 @@
+// Package erasure removes recipients' personal data from communications, on
+// their request or once a domain's retention window has passed, and keeps an
+// audit record of each erasure.
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// batchSize is how many communications are redacted at a time.
+const batchSize = 500
+
+// pendingGrace is how much longer than its domain's retention window a
+// communication may stay pending, e.g. while scheduled for later, before it is
+// taken to be stuck, such as when its workflow never started, and redacted.
+const pendingGrace = 30 * 24 * time.Hour
+
+type postgres interface {
+	ListCommunicationDomains(ctx context.Context) ([]string, error)
+	ListExpiredCommunications(ctx context.Context, domain string, before, pendingBefore time.Time, after string, limit int) ([]string, error)
+	ListRecipientCommunications(ctx context.Context, recipients []string) ([]string, error)
+	RedactCommunications(ctx context.Context, ids []string, dryRun bool) (model.Redaction, error)
+	DeleteCustomerDeviceTokens(ctx context.Context, externalCustomerID string, dryRun bool) (int, error)
+	DeleteContact(ctx context.Context, externalCustomerID string, dryRun bool) (int, error)
+	CreateErasure(ctx context.Context, erasure model.Erasure) error
+}
+
+type payloadStore interface {
+	DeleteCommunication(ctx context.Context, communicationID string) error
+}
+
+// Recipient identifies whose data to erase.
+type Recipient struct {
+	EmailAddresses     []string
+	ExternalCustomerID string
+}
+
+// Eraser redacts communications and deletes their offloaded payloads. A dry
+// run changes nothing but still records what would have been erased.
+type Eraser struct {
+	db       postgres
+	payloads payloadStore
+}
+
+func NewEraser(db postgres, payloads payloadStore) *Eraser {
+	return &Eraser{
+		db:       db,
+		payloads: payloads,
+	}
+}
+
+// EraseRecipient redacts every communication sent to the recipient, and
+// deletes the device tokens and contact of their external customer ID.
+func (e *Eraser) EraseRecipient(ctx context.Context, recipient Recipient, dryRun bool) (model.Erasure, error) {
+	erasure := model.Erasure{
+		ID:     uuid.NewString(),
+		Reason: model.ErasureRequested,
+		DryRun: dryRun,
+	}
+	for _, address := range recipient.EmailAddresses {
+		erasure.Recipients = append(erasure.Recipients, model.EmailRecipient(address))
+	}
+	if recipient.ExternalCustomerID != "" {
+		erasure.Recipients = append(erasure.Recipients, model.CustomerRecipient(recipient.ExternalCustomerID))
+	}
+	if len(erasure.Recipients) == 0 {
+		return erasure, fmt.Errorf("recipient has no email address or external customer id")
+	}
+
+	ids, err := e.db.ListRecipientCommunications(ctx, erasure.Recipients)
+	if err != nil {
+		return erasure, err
+	}
+	for batch := range slices.Chunk(ids, batchSize) {
+		if err := e.redact(ctx, &erasure, batch); err != nil {
+			return erasure, err
+		}
+	}
+	if recipient.ExternalCustomerID != "" {
+		erasure.DeviceTokens, err = e.db.DeleteCustomerDeviceTokens(ctx, recipient.ExternalCustomerID, dryRun)
+		if err != nil {
+			return erasure, err
+		}
+		erasure.Contacts, err = e.db.DeleteContact(ctx, recipient.ExternalCustomerID, dryRun)
+		if err != nil {
+			return erasure, err
+		}
+	}
+	return erasure, e.db.CreateErasure(ctx, erasure)
+}
+
+// Domains returns every domain with communications which haven't been
+// redacted.
+func (e *Eraser) Domains(ctx context.Context) ([]string, error) {
+	return e.db.ListCommunicationDomains(ctx)
+}
+
+// Progress is how far EraseExpired has got through a domain. It is reported
+// after every batch, so a run which was interrupted can resume from it.
+type Progress struct {
+	// Erasure counts what has been erased so far.
+	Erasure model.Erasure
+	// After is the ID of the last communication erased.
+	After string
+}
+
+// EraseExpired redacts a domain's finished communications created before the
+// given time, and those stuck pending for pendingGrace longer. An audit record is only written when there was something to
+// erase. It resumes from resume when that is the progress of the same domain,
+// and reports its progress to progress, if set, after every batch.
+func (e *Eraser) EraseExpired(ctx context.Context, domain string, before time.Time, dryRun bool, resume Progress, progress func(Progress)) (model.Erasure, error) {
+	current := Progress{
+		Erasure: model.Erasure{
+			ID:     uuid.NewString(),
+			Reason: model.ErasureRetention,
+			Domain: domain,
+			DryRun: dryRun,
+		},
+	}
+	if resume.Erasure.Domain == domain && resume.Erasure.DryRun == dryRun {
+		current = resume
+	}
+	for {
+		ids, err := e.db.ListExpiredCommunications(ctx, domain, before, before.Add(-pendingGrace), current.After, batchSize)
+		if err != nil {
+			return current.Erasure, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		if err := e.redact(ctx, &current.Erasure, ids); err != nil {
+			return current.Erasure, err
+		}
+		current.After = ids[len(ids)-1]
+		if progress != nil {
+			progress(current)
+		}
+	}
+	if current.Erasure.Communications == 0 {
+		return current.Erasure, nil
+	}
+	return current.Erasure, e.db.CreateErasure(ctx, current.Erasure)
+}
+
+// redact deletes the payloads of communications before redacting them, so a
+// failure leaves them to be found again on a retry.
+func (e *Eraser) redact(ctx context.Context, erasure *model.Erasure, ids []string) error {
+	if !erasure.DryRun {
+		for _, id := range ids {
+			if err := e.payloads.DeleteCommunication(ctx, id); err != nil {
+				return fmt.Errorf("deleting payloads of %s: %w", id, err)
+			}
+		}
+	}
+	redaction, err := e.db.RedactCommunications(ctx, ids, erasure.DryRun)
+	if err != nil {
+		return err
+	}
+	erasure.Communications += redaction.Communications
+	erasure.ResponseChannels += redaction.ResponseChannels
+	return nil
+}
@@ -0,0 +1,236 @@
+package erasure_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/erasure"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+type communication struct {
+	id         string
+	domain     string
+	createdAt  time.Time
+	pending    bool
+	recipients []string
+	redacted   bool
+}
+
+// fakePostgres keeps communications in memory.
+type fakePostgres struct {
+	communications []*communication
+	deviceTokens   map[string]int
+	contacts       map[string]bool
+	erasures       []model.Erasure
+}
+
+func (f *fakePostgres) ListCommunicationDomains(context.Context) ([]string, error) {
+	var domains []string
+	for _, c := range f.communications {
+		if !c.redacted && !slices.Contains(domains, c.domain) {
+			domains = append(domains, c.domain)
+		}
+	}
+	return domains, nil
+}
+
+func (f *fakePostgres) ListExpiredCommunications(_ context.Context, domain string, before, pendingBefore time.Time, after string, limit int) ([]string, error) {
+	var ids []string
+	for _, c := range f.communications {
+		expired := c.createdAt.Before(before)
+		if c.pending {
+			expired = c.createdAt.Before(pendingBefore)
+		}
+		if c.domain == domain && expired && !c.redacted && c.id > after && len(ids) < limit {
+			ids = append(ids, c.id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakePostgres) ListRecipientCommunications(_ context.Context, recipients []string) ([]string, error) {
+	var ids []string
+	for _, c := range f.communications {
+		for _, recipient := range recipients {
+			if !c.redacted && slices.Contains(c.recipients, recipient) {
+				ids = append(ids, c.id)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakePostgres) RedactCommunications(_ context.Context, ids []string, dryRun bool) (model.Redaction, error) {
+	redaction := model.Redaction{}
+	for _, c := range f.communications {
+		if slices.Contains(ids, c.id) {
+			redaction.Communications++
+			c.redacted = c.redacted || !dryRun
+		}
+	}
+	return redaction, nil
+}
+
+func (f *fakePostgres) DeleteCustomerDeviceTokens(_ context.Context, externalCustomerID string, dryRun bool) (int, error) {
+	count := f.deviceTokens[externalCustomerID]
+	if !dryRun {
+		delete(f.deviceTokens, externalCustomerID)
+	}
+	return count, nil
+}
+
+func (f *fakePostgres) DeleteContact(_ context.Context, externalCustomerID string, dryRun bool) (int, error) {
+	if !f.contacts[externalCustomerID] {
+		return 0, nil
+	}
+	if !dryRun {
+		delete(f.contacts, externalCustomerID)
+	}
+	return 1, nil
+}
+
+func (f *fakePostgres) CreateErasure(_ context.Context, erasure model.Erasure) error {
+	f.erasures = append(f.erasures, erasure)
+	return nil
+}
+
+type recordingPayloads struct {
+	deleted []string
+}
+
+func (r *recordingPayloads) DeleteCommunication(_ context.Context, communicationID string) error {
+	r.deleted = append(r.deleted, communicationID)
+	return nil
+}
+
+type ErasureTestSuite struct {
+	suite.Suite
+	now      time.Time
+	db       *fakePostgres
+	payloads *recordingPayloads
+	eraser   *erasure.Eraser
+}
+
+func TestErasureTestSuite(t *testing.T) {
+	suite.Run(t, new(ErasureTestSuite))
+}
+
+func (s *ErasureTestSuite) SetupTest() {
+	s.now = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	s.db = &fakePostgres{
+		communications: []*communication{
+			{id: "1", domain: "billing", createdAt: s.now.AddDate(0, -6, 0), recipients: []string{"email:someone@example.com"}},
+			{id: "2", domain: "billing", createdAt: s.now.AddDate(0, 0, -1), recipients: []string{"email:someone@example.com"}},
+			{id: "3", domain: "marketing", createdAt: s.now.AddDate(0, -6, 0), recipients: []string{"customer:customer-1"}},
+			{id: "4", domain: "billing", createdAt: s.now.AddDate(0, -6, 0), recipients: []string{"email:other@example.com"}},
+		},
+		deviceTokens: map[string]int{"customer-1": 2},
+		contacts:     map[string]bool{"customer-1": true},
+	}
+	s.payloads = &recordingPayloads{}
+	s.eraser = erasure.NewEraser(s.db, s.payloads)
+}
+
+func (s *ErasureTestSuite) TestEraseRecipient() {
+	erased, err := s.eraser.EraseRecipient(context.Background(), erasure.Recipient{
+		EmailAddresses:     []string{"Someone@Example.com"},
+		ExternalCustomerID: "customer-1",
+	}, false)
+	s.Require().NoError(err)
+
+	s.Equal(model.ErasureRequested, erased.Reason)
+	s.Equal([]string{"email:someone@example.com", "customer:customer-1"}, erased.Recipients)
+	s.Equal(3, erased.Communications)
+	s.Equal(2, erased.DeviceTokens)
+	s.Equal(1, erased.Contacts)
+	s.Empty(s.db.contacts)
+	s.Equal([]string{"1", "2", "3"}, s.payloads.deleted)
+	s.Equal([]model.Erasure{erased}, s.db.erasures)
+	s.False(s.db.communications[3].redacted)
+}
+
+func (s *ErasureTestSuite) TestEraseRecipient_DryRunChangesNothing() {
+	erased, err := s.eraser.EraseRecipient(context.Background(), erasure.Recipient{ExternalCustomerID: "customer-1"}, true)
+	s.Require().NoError(err)
+
+	s.True(erased.DryRun)
+	s.Equal(1, erased.Communications)
+	s.Equal(2, erased.DeviceTokens)
+	s.Empty(s.payloads.deleted)
+	s.False(s.db.communications[2].redacted)
+	s.Equal(2, s.db.deviceTokens["customer-1"])
+	s.True(s.db.contacts["customer-1"])
+	s.Len(s.db.erasures, 1, "dry runs are recorded too")
+}
+
+func (s *ErasureTestSuite) TestEraseRecipient_NeedsARecipient() {
+	_, err := s.eraser.EraseRecipient(context.Background(), erasure.Recipient{}, false)
+	s.Error(err)
+	s.Empty(s.db.erasures)
+}
+
+func (s *ErasureTestSuite) TestEraseExpired() {
+	erased, err := s.eraser.EraseExpired(context.Background(), "billing", s.now.AddDate(0, -1, 0), false, erasure.Progress{}, nil)
+	s.Require().NoError(err)
+
+	s.Equal(model.ErasureRetention, erased.Reason)
+	s.Equal("billing", erased.Domain)
+	s.Equal(2, erased.Communications)
+	s.Equal([]string{"1", "4"}, s.payloads.deleted)
+	s.False(s.db.communications[1].redacted, "communications inside the window are kept")
+
+	erased, err = s.eraser.EraseExpired(context.Background(), "billing", s.now.AddDate(0, -1, 0), false, erasure.Progress{}, nil)
+	s.Require().NoError(err)
+	s.Zero(erased.Communications)
+	s.Len(s.db.erasures, 1, "nothing left to erase isn't recorded")
+}
+
+func (s *ErasureTestSuite) TestEraseExpired_StuckPending() {
+	s.db.communications = append(s.db.communications,
+		&communication{id: "5", domain: "billing", createdAt: s.now.AddDate(0, -6, 0), pending: true},
+		&communication{id: "6", domain: "billing", createdAt: s.now.AddDate(0, -1, -10), pending: true},
+	)
+
+	erased, err := s.eraser.EraseExpired(context.Background(), "billing", s.now.AddDate(0, -1, 0), false, erasure.Progress{}, nil)
+	s.Require().NoError(err)
+
+	s.Equal(3, erased.Communications)
+	s.True(s.db.communications[4].redacted, "a communication pending past the grace window is stuck")
+	s.False(s.db.communications[5].redacted, "a communication pending inside the grace window may be scheduled")
+}
+
+func (s *ErasureTestSuite) TestEraseExpired_DryRunPagesThroughEverything() {
+	var reported []erasure.Progress
+	erased, err := s.eraser.EraseExpired(context.Background(), "billing", s.now, true, erasure.Progress{}, func(progress erasure.Progress) {
+		reported = append(reported, progress)
+	})
+	s.Require().NoError(err)
+
+	s.Equal(3, erased.Communications)
+	s.Empty(s.payloads.deleted)
+	s.False(s.db.communications[0].redacted)
+	s.Require().NotEmpty(reported)
+	s.Equal(erased, reported[len(reported)-1].Erasure)
+	s.Equal("4", reported[len(reported)-1].After)
+}
+
+func (s *ErasureTestSuite) TestEraseExpired_Resumes() {
+	resume := erasure.Progress{
+		Erasure: model.Erasure{ID: "erasure-1", Reason: model.ErasureRetention, Domain: "billing", DryRun: true, Communications: 5},
+		After:   "1",
+	}
+	erased, err := s.eraser.EraseExpired(context.Background(), "billing", s.now, true, resume, nil)
+	s.Require().NoError(err)
+	s.Equal("erasure-1", erased.ID)
+	s.Equal(7, erased.Communications, "communications up to the cursor were counted before")
+
+	erased, err = s.eraser.EraseExpired(context.Background(), "marketing", s.now, true, resume, nil)
+	s.Require().NoError(err)
+	s.NotEqual("erasure-1", erased.ID, "the progress of another domain is ignored")
+}
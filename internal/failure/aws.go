@@ -0,0 +1,40 @@
+package failure
+
+import (
+	"errors"
+	"strings"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+// FromAWS classifies an error returned by an AWS SDK v2 client (SES, SQS, S3).
+func FromAWS(provider string, err error) *Error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "TooManyRequestsException", "LimitExceededException", "Throttling", "ThrottlingException",
+			"RequestThrottled", "KMSThrottlingException", "SlowDown":
+			return New(Throttled, provider, err)
+		case "AccountSuspendedException", "SendingPausedException", "MailFromDomainNotVerifiedException",
+			"AccessDenied", "AccessDeniedException", "InvalidClientTokenId", "UnrecognizedClientException",
+			"SignatureDoesNotMatch", "ExpiredToken", "ExpiredTokenException":
+			return New(Auth, provider, err)
+		case "AWS.SimpleQueueService.NonExistentQueue", "QueueDoesNotExist", "NotFoundException":
+			return New(InvalidRecipient, provider, err)
+		case "MessageRejected":
+			// SES reports oversized messages as a generic rejection.
+			if strings.Contains(strings.ToLower(apiErr.ErrorMessage()), "length") {
+				return New(PayloadTooLarge, provider, err)
+			}
+			return New(InvalidRecipient, provider, err)
+		}
+	}
+
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) {
+		return New(kindFromHTTPStatus(responseErr.HTTPStatusCode()), provider, err)
+	}
+	// no response at all, the provider couldn't be reached.
+	return New(ProviderOutage, provider, err)
+}
@@ -0,0 +1,157 @@
+// Package failure classifies errors returned by providers so the workflow can
+// decide whether a send is worth retrying and record why it failed.
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type Kind string
+
+const (
+	// InvalidRecipient means the address, device token or queue does not
+	// exist or refuses the message.
+	InvalidRecipient Kind = "INVALID_RECIPIENT"
+	// InvalidRequest means the provider rejected the message itself.
+	InvalidRequest Kind = "INVALID_REQUEST"
+	// Auth means our credentials or sender identity were refused.
+	Auth Kind = "AUTH"
+	// Throttled means the provider rate limited us.
+	Throttled Kind = "THROTTLED"
+	// ProviderOutage means the provider was unreachable or failed internally.
+	ProviderOutage Kind = "PROVIDER_OUTAGE"
+	// PayloadTooLarge means the message exceeds the provider's size limit.
+	PayloadTooLarge Kind = "PAYLOAD_TOO_LARGE"
+	// Unknown is used for errors which were never classified.
+	Unknown Kind = "UNKNOWN"
+)
+
+// Retryable reports whether retrying a send which failed with kind may succeed.
+func (k Kind) Retryable() bool {
+	switch k {
+	case Throttled, ProviderOutage, Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProviderFault reports whether kind reflects the health of the provider
+// rather than a problem with the message, i.e. whether another provider could
+// succeed where this one failed.
+func (k Kind) ProviderFault() bool {
+	switch k {
+	case Auth, Throttled, ProviderOutage, Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// Error is a classified provider error.
+type Error struct {
+	Kind     Kind
+	Provider string
+	Err      error
+}
+
+func New(kind Kind, provider string, err error) *Error {
+	return &Error{
+		Kind:     kind,
+		Provider: provider,
+		Err:      err,
+	}
+}
+
+// Errorf creates a classified error with a formatted message.
+func Errorf(kind Kind, provider, format string, args ...any) *Error {
+	return New(kind, provider, fmt.Errorf(format, args...))
+}
+
+func (e *Error) Error() string {
+	if e.Provider == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Provider, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Details is the serialisable form of an Error, attached to temporal
+// application errors and persisted on the communication.
+type Details struct {
+	Kind     Kind
+	Provider string
+	Message  string
+}
+
+// DetailsOf returns the classification of the first Error in err's tree, or
+// Unknown when err was never classified.
+func DetailsOf(err error) Details {
+	var classified *Error
+	if errors.As(err, &classified) {
+		return Details{
+			Kind:     classified.Kind,
+			Provider: classified.Provider,
+			Message:  err.Error(),
+		}
+	}
+	return Details{
+		Kind:    Unknown,
+		Message: err.Error(),
+	}
+}
+
+// Join joins the errors of a send which failed for several recipients, such as
+// each device of a customer. The retryable errors come first, so the result is
+// classified as retryable when any of them is and the recipients which may
+// still succeed are retried. Join returns nil when every error is nil.
+func Join(errs ...error) error {
+	var retryable, permanent []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !KindOf(err).Retryable() {
+			permanent = append(permanent, err)
+			continue
+		}
+		var classified *Error
+		if !errors.As(err, &classified) {
+			err = New(Unknown, "", err)
+		}
+		retryable = append(retryable, err)
+	}
+	return errors.Join(append(retryable, permanent...)...)
+}
+
+// KindOf returns the kind of the first Error in err's tree.
+func KindOf(err error) Kind {
+	return DetailsOf(err).Kind
+}
+
+// FromHTTPStatus classifies an unsuccessful HTTP response from a provider API.
+func FromHTTPStatus(provider string, statusCode int, detail string) *Error {
+	return Errorf(kindFromHTTPStatus(statusCode), provider, "responded with %d: %s", statusCode, detail)
+}
+
+func kindFromHTTPStatus(statusCode int) Kind {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return Auth
+	case statusCode == http.StatusRequestEntityTooLarge:
+		return PayloadTooLarge
+	case statusCode == http.StatusTooManyRequests:
+		return Throttled
+	case statusCode == http.StatusRequestTimeout || statusCode >= 500:
+		return ProviderOutage
+	case statusCode >= 400:
+		return InvalidRequest
+	default:
+		return Unknown
+	}
+}
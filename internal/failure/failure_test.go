@@ -0,0 +1,85 @@
+package failure_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+type FailureTestSuite struct {
+	suite.Suite
+}
+
+func TestFailureTestSuite(t *testing.T) {
+	suite.Run(t, new(FailureTestSuite))
+}
+
+func (s *FailureTestSuite) TestFromHTTPStatus() {
+	for statusCode, kind := range map[int]failure.Kind{
+		http.StatusBadRequest:            failure.InvalidRequest,
+		http.StatusUnauthorized:          failure.Auth,
+		http.StatusForbidden:             failure.Auth,
+		http.StatusRequestEntityTooLarge: failure.PayloadTooLarge,
+		http.StatusTooManyRequests:       failure.Throttled,
+		http.StatusBadGateway:            failure.ProviderOutage,
+	} {
+		s.Equal(kind, failure.FromHTTPStatus("sendgrid", statusCode, "").Kind, statusCode)
+	}
+}
+
+func (s *FailureTestSuite) TestFromSMTP() {
+	s.Equal(failure.InvalidRecipient, failure.FromSMTP("smtp", &textproto.Error{Code: 550, Msg: "no such user"}).Kind)
+	s.Equal(failure.Auth, failure.FromSMTP("smtp", &textproto.Error{Code: 535, Msg: "bad credentials"}).Kind)
+	s.Equal(failure.Throttled, failure.FromSMTP("smtp", &textproto.Error{Code: 421, Msg: "try again later"}).Kind)
+	s.Equal(failure.PayloadTooLarge, failure.FromSMTP("smtp", &textproto.Error{Code: 552, Msg: "too big"}).Kind)
+	s.Equal(failure.ProviderOutage, failure.FromSMTP("smtp", errors.New("connection refused")).Kind)
+}
+
+func (s *FailureTestSuite) TestFromAWS() {
+	throttled := &smithy.GenericAPIError{Code: "TooManyRequestsException", Message: "slow down"}
+	s.Equal(failure.Throttled, failure.FromAWS("ses", throttled).Kind)
+
+	tooLarge := &smithy.GenericAPIError{Code: "MessageRejected", Message: "Message length is more than 10 megabytes"}
+	s.Equal(failure.PayloadTooLarge, failure.FromAWS("ses", tooLarge).Kind)
+
+	outage := &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+		Err:      errors.New("unavailable"),
+	}}
+	s.Equal(failure.ProviderOutage, failure.FromAWS("ses", outage).Kind)
+}
+
+func (s *FailureTestSuite) TestDetailsOf() {
+	err := fmt.Errorf("sending: %w", failure.Errorf(failure.InvalidRecipient, "fcm", "no fcm devices registered"))
+	details := failure.DetailsOf(err)
+	s.Equal(failure.InvalidRecipient, details.Kind)
+	s.Equal("fcm", details.Provider)
+	s.Equal("sending: fcm: no fcm devices registered", details.Message)
+
+	s.Equal(failure.Unknown, failure.KindOf(errors.New("boom")))
+	s.True(failure.Unknown.Retryable())
+	s.False(failure.InvalidRecipient.Retryable())
+}
+
+func (s *FailureTestSuite) TestJoin_MostRetryableKind() {
+	invalid := failure.Errorf(failure.InvalidRecipient, "fcm", "token unregistered")
+	throttled := failure.Errorf(failure.Throttled, "fcm", "quota exceeded")
+
+	err := failure.Join(invalid, nil, throttled)
+	s.Equal(failure.Throttled, failure.KindOf(err))
+	s.ErrorIs(err, invalid)
+	s.ErrorContains(err, "token unregistered")
+
+	s.Equal(failure.Unknown, failure.KindOf(failure.Join(invalid, errors.New("connection reset"))))
+	s.Equal(failure.InvalidRecipient, failure.KindOf(failure.Join(invalid, invalid)))
+	s.NoError(failure.Join(nil, nil))
+}
@@ -0,0 +1,29 @@
+package failure
+
+import (
+	"errors"
+	"net/textproto"
+)
+
+// FromSMTP classifies an error returned while talking to an SMTP server.
+func FromSMTP(provider string, err error) *Error {
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) {
+		return New(ProviderOutage, provider, err)
+	}
+	switch protoErr.Code {
+	case 530, 534, 535, 538:
+		return New(Auth, provider, err)
+	case 421, 450, 451, 452:
+		return New(Throttled, provider, err)
+	case 552:
+		return New(PayloadTooLarge, provider, err)
+	case 550, 551, 553:
+		return New(InvalidRecipient, provider, err)
+	default:
+		if protoErr.Code >= 500 {
+			return New(InvalidRequest, provider, err)
+		}
+		return New(ProviderOutage, provider, err)
+	}
+}
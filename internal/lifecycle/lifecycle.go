@@ -0,0 +1,199 @@
+// Package lifecycle runs the servers of a process until it is signalled to
+// stop, then drains them in order so rolling deploys don't drop requests in
+// flight.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/utilitywarehouse/go-operational/op"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+// Config is how a process drains once signalled to stop.
+type Config struct {
+	// Delay is how long the process reports unready before draining, so load
+	// balancers stop routing new requests to it.
+	Delay time.Duration
+	// Timeout bounds how long the servers have, in total, to finish the work
+	// in flight before they are stopped.
+	Timeout time.Duration
+}
+
+var DefaultConfig = Config{
+	Delay:   time.Second * 5,
+	Timeout: time.Second * 30,
+}
+
+// SignalContext returns a context which is done once the process receives
+// SIGINT or SIGTERM.
+func SignalContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// Server is served until the process drains.
+type Server interface {
+	// Serve blocks until the server fails or is shut down, when it returns
+	// nil.
+	Serve() error
+	// Shutdown stops the server taking new work and waits for the work in
+	// flight until ctx is done.
+	Shutdown(ctx context.Context) error
+}
+
+// Lifecycle runs a process's servers and tracks whether it is draining.
+type Lifecycle struct {
+	cfg      Config
+	logger   *zap.Logger
+	draining atomic.Bool
+}
+
+func New(cfg Config, logger *zap.Logger) *Lifecycle {
+	return &Lifecycle{
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Draining reports whether the process has started draining, when it should
+// report itself unready.
+func (l *Lifecycle) Draining() bool {
+	return l.draining.Load()
+}
+
+// ReadyUseHealthCheck makes status report ready like op's ReadyUseHealthCheck,
+// while no check is unhealthy, until the process starts draining. Each of
+// checks, such as whether a worker polls its task queues yet, must also pass.
+func (l *Lifecycle) ReadyUseHealthCheck(status *op.Status, checks ...func() bool) *op.Status {
+	return status.Ready(func() bool {
+		if l.Draining() {
+			return false
+		}
+		for _, check := range checks {
+			if !check() {
+				return false
+			}
+		}
+		return status.Check().Health != "unhealthy"
+	})
+}
+
+// Run serves every server until ctx is done or one of them fails. It then
+// reports draining, waits for the configured delay when stopping was asked
+// for, and shuts the servers down one after the other in the order given.
+// Servers which depend on others, such as a gateway in front of a gRPC
+// server, should come first.
+func (l *Lifecycle) Run(ctx context.Context, servers ...Server) error {
+	eg, egCtx := errgroup.WithContext(ctx)
+	for _, server := range servers {
+		eg.Go(server.Serve)
+	}
+	eg.Go(func() error {
+		<-egCtx.Done()
+		l.draining.Store(true)
+		if ctx.Err() != nil {
+			l.logger.Info("draining", zap.Duration("delay", l.cfg.Delay), zap.Duration("timeout", l.cfg.Timeout))
+			time.Sleep(l.cfg.Delay)
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.cfg.Timeout)
+		defer cancel()
+		for _, server := range servers {
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				l.logger.Warn("server did not drain in time", zap.Error(err))
+			}
+		}
+		return nil
+	})
+	return eg.Wait()
+}
+
+// HTTPServer serves srv on its address.
+func HTTPServer(srv *http.Server) Server {
+	return httpServer{srv}
+}
+
+type httpServer struct {
+	*http.Server
+}
+
+func (s httpServer) Serve() error {
+	if err := s.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// GRPCServer serves srv on lis. Calls still running once the drain times out
+// are cancelled.
+func GRPCServer(srv *grpc.Server, lis net.Listener) Server {
+	return grpcServer{
+		srv: srv,
+		lis: lis,
+	}
+}
+
+type grpcServer struct {
+	srv *grpc.Server
+	lis net.Listener
+}
+
+func (s grpcServer) Serve() error {
+	return s.srv.Serve(s.lis)
+}
+
+func (s grpcServer) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// Func adapts a function which runs until stop is closed, such as a set of
+// Temporal workers, to a Server.
+func Func(run func(stop <-chan struct{}) error) Server {
+	return &funcServer{
+		run:  run,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+type funcServer struct {
+	run  func(stop <-chan struct{}) error
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (s *funcServer) Serve() error {
+	defer close(s.done)
+	return s.run(s.stop)
+}
+
+func (s *funcServer) Shutdown(ctx context.Context) error {
+	close(s.stop)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
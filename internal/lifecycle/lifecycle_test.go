@@ -0,0 +1,131 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/utilitywarehouse/go-operational/op"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/lifecycle"
+)
+
+// fakeServer serves until it is shut down, recording the order servers are
+// shut down in.
+type fakeServer struct {
+	name     string
+	serveErr error
+	stopped  chan struct{}
+	mu       *sync.Mutex
+	order    *[]string
+}
+
+func (s *fakeServer) Serve() error {
+	if s.serveErr != nil {
+		return s.serveErr
+	}
+	<-s.stopped
+	return nil
+}
+
+func (s *fakeServer) Shutdown(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.order = append(*s.order, s.name)
+	close(s.stopped)
+	return nil
+}
+
+type LifecycleTestSuite struct {
+	suite.Suite
+	mu    sync.Mutex
+	order []string
+}
+
+func TestLifecycleTestSuite(t *testing.T) {
+	suite.Run(t, new(LifecycleTestSuite))
+}
+
+func (s *LifecycleTestSuite) SetupTest() {
+	s.order = nil
+}
+
+func (s *LifecycleTestSuite) server(name string) *fakeServer {
+	return &fakeServer{name: name, stopped: make(chan struct{}), mu: &s.mu, order: &s.order}
+}
+
+func (s *LifecycleTestSuite) TestRun_DrainsInOrderOnceStopped() {
+	lc := lifecycle.New(lifecycle.Config{Delay: time.Millisecond * 50, Timeout: time.Second}, zap.NewNop())
+	status := op.NewStatus("unicom", "test").AddChecker("database", func(cr *op.CheckResponse) {
+		cr.Healthy("healthy")
+	})
+	handler := op.NewHandler(lc.ReadyUseHealthCheck(status))
+	ready := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/__/ready", nil))
+		return recorder.Code
+	}
+	s.Equal(http.StatusOK, ready())
+
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- lc.Run(ctx, s.server("gateway"), s.server("grpc"), s.server("ops"))
+	}()
+	stop()
+
+	s.Eventually(lc.Draining, time.Second, time.Millisecond)
+	s.Equal(http.StatusServiceUnavailable, ready())
+	s.Require().NoError(<-done)
+	s.Equal([]string{"gateway", "grpc", "ops"}, s.order)
+}
+
+func (s *LifecycleTestSuite) TestRun_ServerFails() {
+	lc := lifecycle.New(lifecycle.Config{Delay: time.Hour, Timeout: time.Second}, zap.NewNop())
+	failing := s.server("grpc")
+	failing.serveErr = errors.New("address already in use")
+
+	err := lc.Run(context.Background(), s.server("gateway"), failing)
+	s.ErrorIs(err, failing.serveErr)
+	s.Equal([]string{"gateway", "grpc"}, s.order, "the others are shut down without waiting for the delay")
+}
+
+func (s *LifecycleTestSuite) TestReadyUseHealthCheck_Checks() {
+	lc := lifecycle.New(lifecycle.DefaultConfig, zap.NewNop())
+	status := op.NewStatus("unicom", "test").AddChecker("database", func(cr *op.CheckResponse) {
+		cr.Healthy("healthy")
+	})
+	polling := false
+	handler := op.NewHandler(lc.ReadyUseHealthCheck(status, func() bool { return polling }))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/__/ready", nil))
+	s.Equal(http.StatusServiceUnavailable, recorder.Code)
+
+	polling = true
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/__/ready", nil))
+	s.Equal(http.StatusOK, recorder.Code)
+}
+
+func (s *LifecycleTestSuite) TestFunc_StopsAndWaits() {
+	stopped := false
+	server := lifecycle.Func(func(stop <-chan struct{}) error {
+		<-stop
+		time.Sleep(time.Millisecond * 10)
+		stopped = true
+		return nil
+	})
+	done := make(chan error)
+	go func() { done <- server.Serve() }()
+
+	s.Require().NoError(server.Shutdown(context.Background()))
+	s.True(stopped)
+	s.NoError(<-done)
+}
@@ -0,0 +1,89 @@
+// Package metrics names the business metrics of unicom and helps record them.
+// Metrics are recorded through Temporal's metrics handlers, which skip
+// workflow metrics while a workflow replays, and are exported to Prometheus
+// on the ops port of each binary.
+package metrics
+
+import (
+	"context"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+const (
+	// CommunicationsRequested counts communications the server accepted, by
+	// domain, channel and priority.
+	CommunicationsRequested = "communications_requested"
+	// Communications counts communications once their outcome is known, by
+	// domain, channel and status: SUCCESS, FAILED, EXPIRED or CANCELLED.
+	Communications = "communications"
+	// CommunicationsDeferred counts communications held back by a delivery
+	// window or quiet hours, by domain.
+	CommunicationsDeferred = "communications_deferred"
+	// SendAttempts counts every attempt at sending, by domain, channel and
+	// outcome.
+	SendAttempts = "send_attempts"
+	// SendRetries counts the attempts at sending which were retries, by domain
+	// and channel.
+	SendRetries = "send_retries"
+	// ProviderLatency times each call to a provider, by channel, provider and
+	// outcome.
+	ProviderLatency = "provider_latency"
+	// ResponseChannelDeliveries counts deliveries to response channels, by
+	// type and outcome.
+	ResponseChannelDeliveries = "response_channel_deliveries"
+	// PendingCommunications is the number of communications waiting to be
+	// sent, including those scheduled for later, by domain.
+	PendingCommunications = "communications_pending"
+)
+
+// Tags of the metrics.
+const (
+	DomainTag   = "domain"
+	ChannelTag  = "channel"
+	StatusTag   = "status"
+	OutcomeTag  = "outcome"
+	PriorityTag = "priority"
+	ProviderTag = "provider"
+	TypeTag     = "type"
+)
+
+// Success is the outcome of calls which succeeded. Failed calls have their
+// failure.Kind as the outcome.
+const Success = "SUCCESS"
+
+// Outcome returns the outcome tag of a call which returned err.
+func Outcome(err error) string {
+	if err == nil {
+		return Success
+	}
+	return string(failure.KindOf(err))
+}
+
+// Channel returns the channel tag of a notification type.
+func Channel(notificationType model.NotificationType) string {
+	return strings.ToLower(string(notificationType))
+}
+
+// Activity returns the metrics handler of the activity running with ctx, or
+// one which drops everything when ctx isn't an activity's.
+func Activity(ctx context.Context) client.MetricsHandler {
+	if !activity.IsActivity(ctx) {
+		return client.MetricsNopHandler
+	}
+	return activity.GetMetricsHandler(ctx)
+}
+
+// Attempt returns which attempt at the activity running with ctx this is,
+// or 1 when ctx isn't an activity's.
+func Attempt(ctx context.Context) int32 {
+	if !activity.IsActivity(ctx) {
+		return 1
+	}
+	return activity.GetInfo(ctx).Attempt
+}
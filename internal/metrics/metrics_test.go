@@ -0,0 +1,67 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/suite"
+	sdktally "go.temporal.io/sdk/contrib/tally"
+
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/metrics"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (s *MetricsTestSuite) TestOutcome() {
+	s.Equal(metrics.Success, metrics.Outcome(nil))
+	s.Equal("THROTTLED", metrics.Outcome(failure.Errorf(failure.Throttled, "email/ses", "rate exceeded")))
+	s.Equal("UNKNOWN", metrics.Outcome(errors.New("boom")))
+}
+
+func (s *MetricsTestSuite) TestChannel() {
+	s.Equal("email", metrics.Channel(model.Email))
+	s.Equal("push", metrics.Channel(model.Push))
+}
+
+func (s *MetricsTestSuite) TestActivity_OutsideActivity() {
+	s.NotPanics(func() {
+		metrics.Activity(context.Background()).Counter(metrics.SendAttempts).Inc(1)
+	})
+	s.Equal(int32(1), metrics.Attempt(context.Background()))
+}
+
+func (s *MetricsTestSuite) TestPrometheusScope_ServesRegistry() {
+	registry := prom.NewRegistry()
+	other := prom.NewCounter(prom.CounterOpts{Name: "grpc_server_started_total"})
+	registry.MustRegister(other)
+	other.Inc()
+
+	scope, handler, err := metrics.NewPrometheusScope(registry, "unicom", func(err error) {
+		s.Fail(err.Error())
+	})
+	s.Require().NoError(err)
+	sdktally.NewMetricsHandler(scope).WithTags(map[string]string{
+		metrics.DomainTag:  "billing",
+		metrics.ChannelTag: "email",
+		metrics.StatusTag:  "SUCCESS",
+	}).Counter(metrics.Communications).Inc(1)
+	s.Require().NoError(scope.(io.Closer).Close())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	s.Contains(body, `unicom_communications{channel="email",domain="billing",status="SUCCESS"} 1`)
+	s.Contains(body, "grpc_server_started_total 1")
+}
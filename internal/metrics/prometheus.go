@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/uber-go/tally/v4"
+	"github.com/uber-go/tally/v4/prometheus"
+)
+
+// NewPrometheusScope creates the tally scope Temporal's and unicom's metrics
+// are reported to, along with the handler serving every metric of registry.
+// Timers are reported as histograms.
+func NewPrometheusScope(registry *prom.Registry, prefix string, onError func(error)) (tally.Scope, http.Handler, error) {
+	c := prometheus.Configuration{
+		TimerType: "histogram",
+	}
+	reporter, err := c.NewReporter(
+		prometheus.ConfigurationOptions{
+			Registry: registry,
+			OnError:  onError,
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	scopeOpts := tally.ScopeOptions{
+		CachedReporter:  reporter,
+		Separator:       prometheus.DefaultSeparator,
+		SanitizeOptions: &sanitizeOptions,
+		Prefix:          prefix,
+	}
+	scope, _ := tally.NewRootScope(scopeOpts, time.Second)
+	return scope, reporter.HTTPHandler(), nil
+}
+
+// tally sanitizer options that satisfy Prometheus restrictions.
+// This will rename metrics at the tally emission level, so metrics name we
+// use maybe different from what gets emitted. In the current implementation
+// it will replace - and . with _
+var (
+	safeCharacters = []rune{'_'}
+
+	sanitizeOptions = tally.SanitizeOptions{
+		NameCharacters: tally.ValidCharacters{
+			Ranges:     tally.AlphanumericRange,
+			Characters: safeCharacters,
+		},
+		KeyCharacters: tally.ValidCharacters{
+			Ranges:     tally.AlphanumericRange,
+			Characters: safeCharacters,
+		},
+		ValueCharacters: tally.ValidCharacters{
+			Ranges:     tally.AlphanumericRange,
+			Characters: safeCharacters,
+		},
+		ReplacementCharacter: tally.DefaultReplacementCharacter,
+	}
+)
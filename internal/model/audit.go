@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// AuditEvent records a single API call: who made it, what it was about and
+// how it ended.
+type AuditEvent struct {
+	ID         int64
+	OccurredAt time.Time
+	// Principal is who made the call, see audit.Principal.
+	Principal string
+	// ClaimedPrincipal is the principal header of a caller it isn't trusted
+	// for, which is recorded but not relied on.
+	ClaimedPrincipal string
+	// Peer is the address the call came from.
+	Peer   string
+	Domain string
+	// Method is the full gRPC method name.
+	Method string
+	// RequestHash is the SHA-256 of the request, so a request can be matched
+	// to its event without storing its content.
+	RequestHash string
+	// Code is the gRPC status code the call ended with.
+	Code       string
+	WorkflowID string
+}
+
+// AuditFilter selects audit events. Empty fields match every event.
+type AuditFilter struct {
+	Principal  string
+	Domain     string
+	Method     string
+	Code       string
+	WorkflowID string
+	Since      *time.Time
+	Until      *time.Time
+	// Before only matches events with a lower ID, for paging back through the
+	// log from the newest event.
+	Before int64
+}
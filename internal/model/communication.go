@@ -1,9 +1,14 @@
 package model
 
 import (
+	"errors"
 	"time"
 )
 
+// ErrCommunicationExists is returned when a communication is created with
+// the ID of one which already exists.
+var ErrCommunicationExists = errors.New("communication already exists")
+
 type Model struct {
 	CreatedAt time.Time
 	SentAt    time.Time
@@ -15,6 +20,9 @@ const (
 	Pending Status = "PENDING"
 	Success Status = "SUCCESS"
 	Failed  Status = "FAILED"
+	Expired Status = "EXPIRED"
+	// Cancelled communications were cancelled before they were sent.
+	Cancelled Status = "CANCELLED"
 )
 
 type NotificationType string
@@ -32,7 +40,45 @@ type Communication struct {
 	Domain           string
 	Status           Status
 	Type             NotificationType
+	Policy           DeliveryPolicy
 	ResponseChannels []*ResponseChannel
+	// Recipients identify who the communication was sent to, see
+	// EmailRecipient and CustomerRecipient, so their data can be erased. They
+	// are only stored as blind indexes.
+	Recipients []string
+	// ErrorKind and ErrorProvider classify why the communication failed, if it
+	// did.
+	ErrorKind     string
+	ErrorProvider string
+}
+
+// CommunicationFilter selects communications. Empty fields match every
+// communication.
+type CommunicationFilter struct {
+	Domain string
+	Status Status
+	Since  *time.Time
+	Until  *time.Time
+	// Before only matches communications created before it, for paging back
+	// from the newest communication.
+	Before *CommunicationCursor
+}
+
+// CommunicationCursor is a position in the communications ordered by when
+// they were created, newest first.
+type CommunicationCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// DeliveryPolicy controls how delivery of a communication is attempted.
+type DeliveryPolicy struct {
+	// AttemptTimeout bounds a single delivery attempt.
+	AttemptTimeout     time.Duration
+	MaxAttempts        int32
+	BackoffCoefficient float64
+	// ExpireAt is when delivery is given up on, if set.
+	ExpireAt *time.Time
 }
 
 type ResponseChannelType string
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Contact is how to reach a customer, identified by their external customer
+// ID, on each channel.
+type Contact struct {
+	ExternalCustomerID string
+	EmailAddresses     []string
+	// PhoneNumbers are in E.164 format.
+	PhoneNumbers []string
+	// Devices are the customer's push subscriptions, kept in the device
+	// tokens table.
+	Devices []DeviceToken
+	Locale  string
+	// Timezone is an IANA time zone name.
+	Timezone  string
+	UpdatedAt time.Time
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"time"
+	// Embedded so delivery windows resolve the same on every host, which the
+	// workflows computing them on replay rely on.
+	_ "time/tzdata"
+)
+
+// maxWindowSearch bounds how many times NextDeliveryTime moves forward looking
+// for a time inside every window.
+const maxWindowSearch = 16
+
+// DeliveryWindow is the time of day, in a time zone, a communication may be
+// delivered in.
+type DeliveryWindow struct {
+	// Start and End are offsets from midnight. A window which ends before it
+	// starts spans midnight.
+	Start time.Duration
+	End   time.Duration
+	// Timezone is an IANA time zone name, UTC when empty.
+	Timezone string
+}
+
+// Contains reports whether t is inside the window.
+func (w DeliveryWindow) Contains(t time.Time) (bool, error) {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false, err
+	}
+	local := t.In(loc)
+	timeOfDay := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second + time.Duration(local.Nanosecond())
+	if w.Start <= w.End {
+		return timeOfDay >= w.Start && timeOfDay < w.End, nil
+	}
+	return timeOfDay >= w.Start || timeOfDay < w.End, nil
+}
+
+// Next returns t if it is inside the window, otherwise when the window next
+// opens.
+func (w DeliveryWindow) Next(t time.Time) (time.Time, error) {
+	inside, err := w.Contains(t)
+	if inside || err != nil {
+		return t, err
+	}
+	loc, _ := time.LoadLocation(w.Timezone)
+	local := t.In(loc)
+
+	start := w.startOn(local, 0)
+	if !start.After(t) {
+		start = w.startOn(local, 1)
+	}
+	return start, nil
+}
+
+// startOn returns when the window opens days after the day of local, by the
+// wall clock. time.Date normalises starts falling in a daylight saving gap.
+func (w DeliveryWindow) startOn(local time.Time, days int) time.Time {
+	return time.Date(local.Year(), local.Month(), local.Day()+days,
+		int(w.Start/time.Hour), int(w.Start%time.Hour/time.Minute), 0, 0, local.Location())
+}
+
+// NextDeliveryTime returns the earliest time from t which is inside every
+// window, or an error if the windows don't overlap.
+func NextDeliveryTime(t time.Time, windows []DeliveryWindow) (time.Time, error) {
+	for range maxWindowSearch {
+		moved := false
+		for _, window := range windows {
+			next, err := window.Next(t)
+			if err != nil {
+				return t, err
+			}
+			if next.After(t) {
+				t, moved = next, true
+			}
+		}
+		if !moved {
+			return t, nil
+		}
+	}
+	return t, errors.New("delivery windows never overlap")
+}
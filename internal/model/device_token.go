@@ -0,0 +1,17 @@
+package model
+
+type DeviceTokenType string
+
+const (
+	FCM  DeviceTokenType = "FCM"
+	APNs DeviceTokenType = "APNS"
+)
+
+type DeviceToken struct {
+	Model
+	ID                 string
+	ExternalCustomerID string
+	Type               DeviceTokenType
+	Token              string
+	Locale             string
+}
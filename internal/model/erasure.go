@@ -0,0 +1,50 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// EmailRecipient identifies the recipient of an email by their address.
+func EmailRecipient(address string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(address))
+}
+
+// CustomerRecipient identifies the recipient of a push notification by their
+// external customer ID.
+func CustomerRecipient(externalCustomerID string) string {
+	return "customer:" + externalCustomerID
+}
+
+type ErasureReason string
+
+const (
+	// ErasureRequested erases a recipient's data on their request.
+	ErasureRequested ErasureReason = "REQUEST"
+	// ErasureRetention erases a domain's data older than its retention window.
+	ErasureRetention ErasureReason = "RETENTION"
+)
+
+// Erasure is the audit record of a single erasure, holding what was, or for a
+// dry run would have been, redacted.
+type Erasure struct {
+	ID     string
+	Reason ErasureReason
+	// Domain is the domain whose communications were erased by retention.
+	Domain string
+	// Recipients are the recipients erased on request, only stored as blind
+	// indexes.
+	Recipients       []string
+	DryRun           bool
+	Communications   int
+	ResponseChannels int
+	DeviceTokens     int
+	Contacts         int
+	CreatedAt        time.Time
+}
+
+// Redaction counts the rows a redaction changed.
+type Redaction struct {
+	Communications   int
+	ResponseChannels int
+}
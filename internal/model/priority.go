@@ -0,0 +1,27 @@
+package model
+
+import "fmt"
+
+// Priority is the lane a communication is processed in. Each priority has its
+// own task queue and workers, so a backlog of bulk sends can't delay critical
+// ones.
+type Priority string
+
+const (
+	PriorityCritical      Priority = "critical"
+	PriorityTransactional Priority = "transactional"
+	PriorityBulk          Priority = "bulk"
+)
+
+// Priorities lists every priority, most urgent first.
+var Priorities = []Priority{PriorityCritical, PriorityTransactional, PriorityBulk}
+
+// ParsePriority parses the name of a priority.
+func ParsePriority(name string) (Priority, error) {
+	for _, p := range Priorities {
+		if string(p) == name {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown priority %q, must be one of critical, transactional or bulk", name)
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// maxWebhookSize bounds the body of a request to the webhook sink.
+const maxWebhookSize = 1 << 20
+
+// Delivery is a captured response channel notification.
+type Delivery struct {
+	// URL is the queue URL or webhook the notification was sent to.
+	URL  string          `json:"url"`
+	Body json.RawMessage `json:"body"`
+}
+
+// NewDelivery returns the Delivery of body to url, keeping a body which isn't
+// JSON as a string.
+func NewDelivery(url string, body []byte) Delivery {
+	if !json.Valid(body) {
+		body, _ = json.Marshal(string(body))
+	}
+	return Delivery{
+		URL:  url,
+		Body: body,
+	}
+}
+
+var index = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>unicom outbox</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: 0.3em 1em; border-bottom: 1px solid #ddd; }
+</style>
+</head>
+<body>
+<h1>unicom outbox</h1>
+<p>{{len .}} captured messages, newest first. Webhooks posted to <code>/webhooks/&lt;name&gt;</code> are captured too.</p>
+<table>
+<tr><th>Time</th><th>Kind</th><th>ID</th><th>Size</th></tr>
+{{range .}}<tr><td>{{.Time.Format "2006-01-02 15:04:05.000"}}</td><td>{{.Kind}}</td><td><a href="/messages/{{.Name}}">{{.ID}}</a></td><td>{{.Size}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// Handler serves the outbox:
+//
+//   - GET / lists the captured messages on a web page
+//   - GET /messages lists them as JSON
+//   - GET /messages/{name} returns a message as it was captured
+//   - POST /webhooks/{name} captures the request, so webhook response
+//     channels can point at the outbox
+func (o *Outbox) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		messages, err := o.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = index.Execute(w, messages)
+	})
+	mux.HandleFunc("GET /messages", func(w http.ResponseWriter, r *http.Request) {
+		messages, err := o.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(messages)
+	})
+	mux.HandleFunc("GET /messages/{name}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := o.Read(r.PathValue("name"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		// Emails are shown as text rather than downloaded.
+		contentType := "text/plain; charset=utf-8"
+		if ext := filepath.Ext(r.PathValue("name")); ext != ".eml" {
+			contentType = mime.TypeByExtension(ext)
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("POST /webhooks/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		if err := o.WriteJSON(Webhook, uuid.NewString(), NewDelivery(scheme+"://"+r.Host+r.URL.RequestURI(), body)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
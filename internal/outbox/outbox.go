@@ -0,0 +1,146 @@
+// Package outbox captures the messages unicom would have sent, as files in a
+// directory, so it can run locally without any provider. Emails are written
+// as .eml files and push notifications and response channel notifications as
+// JSON, and Handler lists them on a web page.
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of captured messages.
+const (
+	Email   = "email"
+	Push    = "push"
+	SQS     = "sqs"
+	Webhook = "webhook"
+)
+
+// timeLayout prefixes the name of each file, so names sort by capture time.
+const timeLayout = "20060102T150405.000000000Z"
+
+// Message is a captured message.
+type Message struct {
+	// Name is the name of the file holding the message.
+	Name string    `json:"name"`
+	Kind string    `json:"kind"`
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	Size int64     `json:"size"`
+}
+
+// Outbox is a directory of captured messages.
+type Outbox struct {
+	dir string
+}
+
+// New returns the Outbox in dir, creating it if needed.
+func New(dir string) (*Outbox, error) {
+	if dir == "" {
+		return nil, errors.New("outbox requires a directory")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Outbox{
+		dir: dir,
+	}, nil
+}
+
+// Dir returns the directory of the outbox.
+func (o *Outbox) Dir() string {
+	return o.dir
+}
+
+// Write captures a message of a kind, with the file extension ext.
+func (o *Outbox) Write(kind, id, ext string, data []byte) error {
+	name := fmt.Sprintf("%s-%s-%s.%s", time.Now().UTC().Format(timeLayout), kind, id, ext)
+	// Written aside and renamed so the message is never listed half written.
+	tmp, err := os.CreateTemp(o.dir, ".capture-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(o.dir, name))
+}
+
+// WriteJSON captures a message of a kind as indented JSON.
+func (o *Outbox) WriteJSON(kind, id string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return o.Write(kind, id, "json", data)
+}
+
+// List returns the captured messages, newest first.
+func (o *Outbox) List() ([]Message, error) {
+	entries, err := os.ReadDir(o.dir)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]Message, 0, len(entries))
+	for _, entry := range entries {
+		message, ok := parseName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		message.Size = info.Size()
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Name > messages[j].Name
+	})
+	return messages, nil
+}
+
+// Read returns the content of the captured message in the file named name.
+func (o *Outbox) Read(name string) ([]byte, error) {
+	if _, ok := parseName(name); !ok {
+		return nil, os.ErrNotExist
+	}
+	return os.ReadFile(filepath.Join(o.dir, name))
+}
+
+// parseName parses the name of a file written by Write.
+func parseName(name string) (Message, bool) {
+	if filepath.Base(name) != name {
+		return Message{}, false
+	}
+	stamp, rest, ok := strings.Cut(strings.TrimSuffix(name, filepath.Ext(name)), "-")
+	if !ok {
+		return Message{}, false
+	}
+	at, err := time.Parse(timeLayout, stamp)
+	if err != nil {
+		return Message{}, false
+	}
+	kind, id, ok := strings.Cut(rest, "-")
+	if !ok {
+		return Message{}, false
+	}
+	return Message{
+		Name: name,
+		Kind: kind,
+		ID:   id,
+		Time: at,
+	}, true
+}
@@ -0,0 +1,87 @@
+package outbox_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/outbox"
+)
+
+type OutboxTestSuite struct {
+	suite.Suite
+	outbox *outbox.Outbox
+}
+
+func TestOutboxTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxTestSuite))
+}
+
+func (s *OutboxTestSuite) SetupTest() {
+	var err error
+	s.outbox, err = outbox.New(s.T().TempDir())
+	s.Require().NoError(err)
+}
+
+func (s *OutboxTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.outbox.Handler().ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return recorder
+}
+
+func (s *OutboxTestSuite) TestWriteAndList() {
+	s.Require().NoError(s.outbox.Write(outbox.Email, "first", "eml", []byte("Subject: Hello\r\n")))
+	s.Require().NoError(s.outbox.WriteJSON(outbox.Push, "second", map[string]string{"app_id": "app"}))
+
+	messages, err := s.outbox.List()
+	s.Require().NoError(err)
+	s.Require().Len(messages, 2)
+	s.Equal("second", messages[0].ID, "newest first")
+	s.Equal(outbox.Push, messages[0].Kind)
+	s.Equal("first", messages[1].ID)
+	s.Equal(outbox.Email, messages[1].Kind)
+
+	data, err := s.outbox.Read(messages[1].Name)
+	s.Require().NoError(err)
+	s.Equal("Subject: Hello\r\n", string(data))
+
+	_, err = s.outbox.Read("../" + messages[1].Name)
+	s.Error(err)
+}
+
+func (s *OutboxTestSuite) TestHandler_CapturesWebhooks() {
+	recorder := s.serve(http.MethodPost, "/webhooks/billing", `{"workflow_id":"wf-1","status":"SUCCESS"}`)
+	s.Equal(http.StatusNoContent, recorder.Code)
+
+	recorder = s.serve(http.MethodGet, "/messages", "")
+	s.Equal(http.StatusOK, recorder.Code)
+	var messages []outbox.Message
+	s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &messages))
+	s.Require().Len(messages, 1)
+	s.Equal(outbox.Webhook, messages[0].Kind)
+
+	recorder = s.serve(http.MethodGet, "/messages/"+messages[0].Name, "")
+	s.Equal(http.StatusOK, recorder.Code)
+	var delivery outbox.Delivery
+	s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &delivery))
+	s.Equal("http://example.com/webhooks/billing", delivery.URL)
+	s.JSONEq(`{"workflow_id":"wf-1","status":"SUCCESS"}`, string(delivery.Body))
+
+	recorder = s.serve(http.MethodGet, "/", "")
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Contains(recorder.Body.String(), messages[0].ID)
+}
+
+func (s *OutboxTestSuite) TestHandler_UnknownMessage() {
+	recorder := s.serve(http.MethodGet, "/messages/missing.json", "")
+	s.Equal(http.StatusNotFound, recorder.Code)
+}
+
+func (s *OutboxTestSuite) TestNewDelivery_KeepsTextAsString() {
+	delivery := outbox.NewDelivery("https://sqs.eu-west-1.amazonaws.com/123/queue", []byte("not json"))
+	s.JSONEq(`"not json"`, string(delivery.Body))
+}
@@ -0,0 +1,70 @@
+package payload
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Config selects where offloaded payloads are kept. The server and worker must
+// be configured with the same store.
+type Config struct {
+	// Store is one of none, postgres, file or s3.
+	Store string
+	// Threshold is the size in bytes above which a field is offloaded.
+	Threshold int
+	// Dir is the directory of the file store.
+	Dir string
+	// Bucket and Prefix locate payloads in the s3 store.
+	Bucket string
+	Prefix string
+	// S3Endpoint overrides the S3 endpoint, e.g. for LocalStack or MinIO.
+	S3Endpoint string
+}
+
+var DefaultConfig = Config{
+	Store:     "postgres",
+	Threshold: 64 << 10,
+	Prefix:    "payloads/",
+}
+
+// NewStore creates the Store selected by cfg, or nil for none. Payloads are
+// encrypted with encryptor before they are stored.
+func NewStore(cfg Config, db postgres, awsConfig aws.Config, encryptor encryptor) (Store, error) {
+	store, err := newStore(cfg, db, awsConfig)
+	if store == nil || err != nil {
+		return nil, err
+	}
+	return NewEncryptedStore(store, encryptor), nil
+}
+
+func newStore(cfg Config, db postgres, awsConfig aws.Config) (Store, error) {
+	switch cfg.Store {
+	case "", "none":
+		return nil, nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	case "file":
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("the file payload store needs a directory")
+		}
+		return NewFileStore(cfg.Dir), nil
+	case "s3":
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("the s3 payload store needs a bucket")
+		}
+		client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+			o.HTTPClient = awshttp.NewBuildableClient().WithTimeout(30 * time.Second)
+			if cfg.S3Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.S3Endpoint)
+				o.UsePathStyle = true
+			}
+		})
+		return NewS3Store(client, cfg.Bucket, cfg.Prefix), nil
+	default:
+		return nil, fmt.Errorf("unknown payload store %q", cfg.Store)
+	}
+}
@@ -0,0 +1,158 @@
+// Package payload keeps large email bodies and attachments out of Temporal's
+// workflow history using the claim check pattern: the server moves them into a
+// Store before starting the workflow, leaving a reference in their place, and
+// the send activity loads them back just before sending.
+package payload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/anicoll/unicom/internal/email"
+)
+
+// ErrNotConfigured is returned when loading a payload without a store.
+var ErrNotConfigured = errors.New("payload store is not configured")
+
+// Store holds payloads by key.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every payload whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Offloader moves the fields of an email request larger than its threshold
+// into a Store.
+type Offloader struct {
+	store     Store
+	threshold int
+}
+
+// NewOffloader creates an Offloader. A nil store, or a threshold of zero,
+// leaves every request as it is.
+func NewOffloader(store Store, threshold int) *Offloader {
+	return &Offloader{
+		store:     store,
+		threshold: threshold,
+	}
+}
+
+// Offload stores the bodies and attachment data of req larger than the
+// threshold under keys prefixed by the communication's ID, replacing them with
+// references.
+func (o *Offloader) Offload(ctx context.Context, communicationID string, req *email.Request) error {
+	if o.store == nil || o.threshold <= 0 || req == nil {
+		return nil
+	}
+	if len(req.HtmlBody) > o.threshold {
+		key := communicationID + "/html"
+		if err := o.store.Put(ctx, key, []byte(req.HtmlBody)); err != nil {
+			return fmt.Errorf("offloading html body: %w", err)
+		}
+		req.HtmlBody, req.HtmlBodyRef = "", key
+	}
+	if len(req.TextBody) > o.threshold {
+		key := communicationID + "/text"
+		if err := o.store.Put(ctx, key, []byte(req.TextBody)); err != nil {
+			return fmt.Errorf("offloading text body: %w", err)
+		}
+		req.TextBody, req.TextBodyRef = "", key
+	}
+	for i := range req.Attachments {
+		attachment := &req.Attachments[i]
+		if len(attachment.Data) <= o.threshold {
+			continue
+		}
+		key := communicationID + "/attachments/" + strconv.Itoa(i)
+		if err := o.store.Put(ctx, key, attachment.Data); err != nil {
+			return fmt.Errorf("offloading attachment %s: %w", attachment.Name, err)
+		}
+		attachment.Data, attachment.DataRef = nil, key
+	}
+	return nil
+}
+
+// Load returns a copy of req with every offloaded field read back from the
+// store.
+func (o *Offloader) Load(ctx context.Context, req email.Request) (email.Request, error) {
+	if len(Refs(req)) == 0 {
+		return req, nil
+	}
+	if o.store == nil {
+		return req, ErrNotConfigured
+	}
+	if req.HtmlBodyRef != "" {
+		data, err := o.store.Get(ctx, req.HtmlBodyRef)
+		if err != nil {
+			return req, fmt.Errorf("loading html body: %w", err)
+		}
+		req.HtmlBody, req.HtmlBodyRef = string(data), ""
+	}
+	if req.TextBodyRef != "" {
+		data, err := o.store.Get(ctx, req.TextBodyRef)
+		if err != nil {
+			return req, fmt.Errorf("loading text body: %w", err)
+		}
+		req.TextBody, req.TextBodyRef = string(data), ""
+	}
+	attachments := make([]email.Attachment, len(req.Attachments))
+	copy(attachments, req.Attachments)
+	for i := range attachments {
+		if attachments[i].DataRef == "" {
+			continue
+		}
+		data, err := o.store.Get(ctx, attachments[i].DataRef)
+		if err != nil {
+			return req, fmt.Errorf("loading attachment %s: %w", attachments[i].Name, err)
+		}
+		attachments[i].Data, attachments[i].DataRef = data, ""
+	}
+	req.Attachments = attachments
+	return req, nil
+}
+
+// Delete removes every payload offloaded from req.
+func (o *Offloader) Delete(ctx context.Context, req email.Request) error {
+	refs := Refs(req)
+	if len(refs) == 0 {
+		return nil
+	}
+	if o.store == nil {
+		return ErrNotConfigured
+	}
+	var errs error
+	for _, ref := range refs {
+		errs = errors.Join(errs, o.store.Delete(ctx, ref))
+	}
+	return errs
+}
+
+// DeleteCommunication removes every payload offloaded from a communication,
+// for erasing it without its request. Without a store there is nothing to
+// remove.
+func (o *Offloader) DeleteCommunication(ctx context.Context, communicationID string) error {
+	if o.store == nil {
+		return nil
+	}
+	return o.store.DeletePrefix(ctx, communicationID+"/")
+}
+
+// Refs returns the references of every offloaded field of req.
+func Refs(req email.Request) []string {
+	var refs []string
+	for _, ref := range []string{req.HtmlBodyRef, req.TextBodyRef} {
+		if ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	for _, attachment := range req.Attachments {
+		if attachment.DataRef != "" {
+			refs = append(refs, attachment.DataRef)
+		}
+	}
+	return refs
+}
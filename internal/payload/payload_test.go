@@ -0,0 +1,181 @@
+package payload_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/encryption"
+	"github.com/anicoll/unicom/internal/payload"
+)
+
+type PayloadTestSuite struct {
+	suite.Suite
+	store     *payload.FileStore
+	offloader *payload.Offloader
+}
+
+func TestPayloadTestSuite(t *testing.T) {
+	suite.Run(t, new(PayloadTestSuite))
+}
+
+func (s *PayloadTestSuite) SetupTest() {
+	s.store = payload.NewFileStore(s.T().TempDir())
+	s.offloader = payload.NewOffloader(s.store, 16)
+}
+
+func (s *PayloadTestSuite) TestOffload_OnlyLargeFields() {
+	ctx := context.Background()
+	html := "<p>" + strings.Repeat("a", 32) + "</p>"
+	req := email.Request{
+		HtmlBody: html,
+		TextBody: "short",
+		Attachments: []email.Attachment{
+			{Name: "small.txt", Data: []byte("small")},
+			{Name: "large.pdf", Data: []byte(strings.Repeat("b", 32))},
+		},
+	}
+
+	s.Require().NoError(s.offloader.Offload(ctx, "communication-id", &req))
+	s.Empty(req.HtmlBody)
+	s.Equal("communication-id/html", req.HtmlBodyRef)
+	s.Equal("short", req.TextBody)
+	s.Empty(req.TextBodyRef)
+	s.Equal([]byte("small"), req.Attachments[0].Data)
+	s.Nil(req.Attachments[1].Data)
+	s.Equal("communication-id/attachments/1", req.Attachments[1].DataRef)
+	s.Equal([]string{"communication-id/html", "communication-id/attachments/1"}, payload.Refs(req))
+
+	loaded, err := s.offloader.Load(ctx, req)
+	s.Require().NoError(err)
+	s.Equal(html, loaded.HtmlBody)
+	s.Equal([]byte(strings.Repeat("b", 32)), loaded.Attachments[1].Data)
+	s.Empty(payload.Refs(loaded))
+	s.Equal("communication-id/attachments/1", req.Attachments[1].DataRef, "loading leaves the input untouched")
+
+	s.Require().NoError(s.offloader.Delete(ctx, req))
+	_, err = s.offloader.Load(ctx, req)
+	s.Error(err)
+}
+
+func (s *PayloadTestSuite) TestOffload_Disabled() {
+	req := email.Request{HtmlBody: strings.Repeat("a", 32)}
+
+	s.NoError(payload.NewOffloader(nil, 16).Offload(context.Background(), "communication-id", &req))
+	s.Equal(strings.Repeat("a", 32), req.HtmlBody)
+
+	_, err := payload.NewOffloader(nil, 16).Load(context.Background(), email.Request{HtmlBodyRef: "communication-id/html"})
+	s.ErrorIs(err, payload.ErrNotConfigured)
+}
+
+func (s *PayloadTestSuite) TestFileStore_RefusesKeysOutsideDirectory() {
+	ctx := context.Background()
+	for _, key := range []string{"../escape", "a/../../escape", "/absolute", "", "a//b"} {
+		s.Error(s.store.Put(ctx, key, []byte("data")), key)
+	}
+}
+
+// staticKMS hands out the same data key every time.
+type staticKMS struct{}
+
+func (staticKMS) GenerateDataKey(context.Context) (encryption.DataKey, error) {
+	key := []byte(strings.Repeat("k", 32))
+	return encryption.DataKey{KeyID: "static", Plaintext: key, Encrypted: key}, nil
+}
+
+func (staticKMS) DecryptDataKey(_ context.Context, _ string, encrypted []byte) ([]byte, error) {
+	return encrypted, nil
+}
+
+func (s *PayloadTestSuite) TestEncryptedStore_EncryptsPayloads() {
+	ctx := context.Background()
+	store := payload.NewEncryptedStore(s.store, encryption.NewEncryptor(staticKMS{}, nil))
+
+	s.Require().NoError(store.Put(ctx, "communication-id/html", []byte("<p>hello</p>")))
+	raw, err := s.store.Get(ctx, "communication-id/html")
+	s.Require().NoError(err)
+	s.NotContains(string(raw), "hello")
+
+	got, err := store.Get(ctx, "communication-id/html")
+	s.Require().NoError(err)
+	s.Equal([]byte("<p>hello</p>"), got)
+
+	s.Require().NoError(s.store.Put(ctx, "communication-id/text", []byte("written before encryption")))
+	got, err = store.Get(ctx, "communication-id/text")
+	s.Require().NoError(err)
+	s.Equal([]byte("written before encryption"), got)
+}
+
+// fakeBucket keeps objects in memory, listing them two at a time.
+type fakeBucket struct {
+	objects map[string][]byte
+	lists   int
+}
+
+func (b *fakeBucket) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(b.objects[*params.Key]))}, nil
+}
+
+func (b *fakeBucket) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(params.Body)
+	b.objects[*params.Key] = data
+	return &s3.PutObjectOutput{}, err
+}
+
+func (b *fakeBucket) DeleteObject(_ context.Context, params *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(b.objects, *params.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (b *fakeBucket) DeleteObjects(_ context.Context, params *s3.DeleteObjectsInput, _ ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	for _, object := range params.Delete.Objects {
+		delete(b.objects, *object.Key)
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func (b *fakeBucket) ListObjectsV2(_ context.Context, params *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	b.lists++
+	var keys []string
+	for key := range b.objects {
+		if strings.HasPrefix(key, *params.Prefix) && key > aws.ToString(params.ContinuationToken) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	out := &s3.ListObjectsV2Output{}
+	for _, key := range keys[:min(2, len(keys))] {
+		out.Contents = append(out.Contents, types.Object{Key: aws.String(key)})
+	}
+	if len(keys) > 2 {
+		out.IsTruncated = aws.Bool(true)
+		out.NextContinuationToken = aws.String(keys[1])
+	}
+	return out, nil
+}
+
+func (s *PayloadTestSuite) TestS3Store_DeletePrefixPagesThroughObjects() {
+	ctx := context.Background()
+	bucket := &fakeBucket{objects: map[string][]byte{}}
+	store := payload.NewS3Store(bucket, "unicom", "payloads/")
+
+	for _, key := range []string{"comm-1/html", "comm-1/text", "comm-1/attachments/0", "comm-2/html"} {
+		s.Require().NoError(store.Put(ctx, key, []byte(key)))
+	}
+	got, err := store.Get(ctx, "comm-1/html")
+	s.Require().NoError(err)
+	s.Equal([]byte("comm-1/html"), got)
+
+	s.Require().NoError(store.DeletePrefix(ctx, "comm-1/"))
+	s.Equal(2, bucket.lists)
+	s.Equal(map[string][]byte{"payloads/comm-2/html": []byte("comm-2/html")}, bucket.objects)
+}
@@ -0,0 +1,256 @@
+package payload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/anicoll/unicom/internal/failure"
+)
+
+// FileStore keeps payloads as files under a directory, for development where
+// the server and worker share a filesystem.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) Put(_ context.Context, key string, data []byte) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o600)
+}
+
+func (s *FileStore) Get(_ context.Context, key string) ([]byte, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(name)
+}
+
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) DeletePrefix(_ context.Context, prefix string) error {
+	dir, ok := strings.CutSuffix(prefix, "/")
+	if !ok {
+		return fmt.Errorf("file payload store can only delete directories, got %q", prefix)
+	}
+	name, err := s.path(dir)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(name)
+}
+
+// path maps a key to a file, refusing keys which would escape the directory.
+func (s *FileStore) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || cleaned != "/"+key || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("invalid payload key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// s3Client is the part of the S3 API the S3Store uses.
+type s3Client interface {
+	s3.ListObjectsV2APIClient
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+}
+
+// S3Store keeps payloads as objects in an S3 bucket under a prefix. A bucket
+// lifecycle rule on the prefix cleans up payloads of communications which
+// never finished.
+type S3Store struct {
+	client s3Client
+	bucket string
+	prefix string
+}
+
+func NewS3Store(client s3Client, bucket, prefix string) *S3Store {
+	return &S3Store{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return failure.FromAWS("s3", err)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		return nil, failure.FromAWS("s3", err)
+	}
+	defer func() { _ = out.Body.Close() }()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, failure.New(failure.ProviderOutage, "s3", err)
+	}
+	return data, nil
+}
+
+// Delete removes a payload. Deleting a missing payload succeeds.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		return failure.FromAWS("s3", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every payload under prefix, a page of objects at a
+// time.
+func (s *S3Store) DeletePrefix(ctx context.Context, prefix string) error {
+	pages := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix + prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return failure.FromAWS("s3", err)
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: object.Key})
+		}
+		out, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return failure.FromAWS("s3", err)
+		}
+		if len(out.Errors) > 0 {
+			return failure.Errorf(failure.ProviderOutage, "s3", "deleting %s: %s", aws.ToString(out.Errors[0].Key), aws.ToString(out.Errors[0].Message))
+		}
+	}
+	return nil
+}
+
+type postgres interface {
+	PutPayload(ctx context.Context, key string, data []byte) error
+	GetPayload(ctx context.Context, key string) ([]byte, error)
+	DeletePayload(ctx context.Context, key string) error
+	DeletePayloads(ctx context.Context, prefix string) error
+}
+
+// PostgresStore keeps payloads in the payloads table, which needs no extra
+// infrastructure but grows the database with every large email.
+type PostgresStore struct {
+	db postgres
+}
+
+func NewPostgresStore(db postgres) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Put(ctx context.Context, key string, data []byte) error {
+	return s.db.PutPayload(ctx, key, data)
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.db.GetPayload(ctx, key)
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, key string) error {
+	return s.db.DeletePayload(ctx, key)
+}
+
+func (s *PostgresStore) DeletePrefix(ctx context.Context, prefix string) error {
+	return s.db.DeletePayloads(ctx, prefix)
+}
+
+type encryptor interface {
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// EncryptedStore encrypts payloads before handing them to another Store, as
+// email bodies and attachments are personal data.
+type EncryptedStore struct {
+	store     Store
+	encryptor encryptor
+}
+
+func NewEncryptedStore(store Store, encryptor encryptor) *EncryptedStore {
+	return &EncryptedStore{
+		store:     store,
+		encryptor: encryptor,
+	}
+}
+
+func (s *EncryptedStore) Put(ctx context.Context, key string, data []byte) error {
+	encrypted, err := s.encryptor.Encrypt(ctx, data)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(ctx, key, encrypted)
+}
+
+func (s *EncryptedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.encryptor.Decrypt(ctx, data)
+}
+
+func (s *EncryptedStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+func (s *EncryptedStore) DeletePrefix(ctx context.Context, prefix string) error {
+	return s.store.DeletePrefix(ctx, prefix)
+}
@@ -0,0 +1,255 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+const (
+	APNsProductionURL = "https://api.push.apple.com"
+	APNsSandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// APNs rejects provider tokens older than an hour and throttles those
+	// refreshed more often than every 20 minutes.
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsConfig holds the token based authentication details for APNs.
+type APNsConfig struct {
+	// BaseURL defaults to APNsProductionURL.
+	BaseURL string
+	TeamID  string
+	KeyID   string
+	// Topic is the bundle ID of the app.
+	Topic      string
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// APNsProvider sends notifications over HTTP/2 to every APNs device token
+// registered for the customer.
+type APNsProvider struct {
+	cfg      APNsConfig
+	client   *http.Client
+	registry tokenRegistry
+	logger   *zap.Logger
+
+	mu          sync.Mutex
+	bearer      string
+	bearerSetAt time.Time
+}
+
+func NewAPNsProvider(logger *zap.Logger, client *http.Client, registry tokenRegistry, cfg APNsConfig) *APNsProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = APNsProductionURL
+	}
+	return &APNsProvider{
+		cfg:      cfg,
+		client:   client,
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// ParseAPNsKey parses the PKCS#8 .p8 signing key downloaded from Apple.
+func ParseAPNsKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("apns key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("apns key is not an ECDSA key")
+	}
+	return ecKey, nil
+}
+
+type apnsPayload struct {
+	Aps            apnsAps `json:"aps"`
+	IdempotencyKey string  `json:"idempotency_key,omitempty"`
+}
+
+type apnsAps struct {
+	Alert apnsAlert `json:"alert"`
+}
+
+type apnsAlert struct {
+	Title    string `json:"title,omitempty"`
+	Subtitle string `json:"subtitle,omitempty"`
+	Body     string `json:"body,omitempty"`
+}
+
+// apnsRequest is a notification rendered for one device.
+type apnsRequest struct {
+	DeviceToken string      `json:"device_token"`
+	Payload     apnsPayload `json:"payload"`
+}
+
+type apnsErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+func (p *APNsProvider) Send(ctx context.Context, args Notification) (*string, error) {
+	devices, err := p.registry.ListDeviceTokens(ctx, args.ExternalCustomerId, model.APNs)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, failure.Errorf(failure.InvalidRecipient, "apns", "no apns devices registered for customer %s", args.ExternalCustomerId)
+	}
+
+	messageIds := make([]string, 0, len(devices))
+	var sendErrs []error
+	for _, device := range devices {
+		messageId, err := p.sendToDevice(ctx, args, device)
+		if err != nil {
+			sendErrs = append(sendErrs, err)
+			continue
+		}
+		messageIds = append(messageIds, messageId)
+	}
+	if len(messageIds) == 0 {
+		return nil, failure.Join(sendErrs...)
+	}
+	ids := strings.Join(messageIds, ",")
+	return &ids, nil
+}
+
+// Render returns the payload sent to each APNs device registered for the
+// customer, with the device's token, as a JSON array.
+func (p *APNsProvider) Render(ctx context.Context, args Notification) ([]byte, error) {
+	devices, err := p.registry.ListDeviceTokens(ctx, args.ExternalCustomerId, model.APNs)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, failure.Errorf(failure.InvalidRecipient, "apns", "no apns devices registered for customer %s", args.ExternalCustomerId)
+	}
+	requests := make([]apnsRequest, len(devices))
+	for i, device := range devices {
+		requests[i] = apnsRequest{DeviceToken: device.Token, Payload: newAPNsPayload(args, device)}
+	}
+	return json.Marshal(requests)
+}
+
+// newAPNsPayload returns the payload sent to device for args, in the device's
+// language.
+func newAPNsPayload(args Notification, device model.DeviceToken) apnsPayload {
+	payload := apnsPayload{
+		Aps: apnsAps{Alert: apnsAlert{
+			Title: args.Heading.localise(device.Locale),
+			Body:  args.Content.localise(device.Locale),
+		}},
+		IdempotencyKey: args.IdempotencyKey,
+	}
+	if args.SubTitle != nil {
+		payload.Aps.Alert.Subtitle = args.SubTitle.localise(device.Locale)
+	}
+	return payload
+}
+
+func (p *APNsProvider) sendToDevice(ctx context.Context, args Notification, device model.DeviceToken) (string, error) {
+	data, err := json.Marshal(newAPNsPayload(args, device))
+	if err != nil {
+		return "", err
+	}
+
+	bearer, err := p.providerToken()
+	if err != nil {
+		return "", err
+	}
+
+	url := strings.TrimSuffix(p.cfg.BaseURL, "/") + "/3/device/" + device.Token
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	httpRequest.Header.Set("authorization", "bearer "+bearer)
+	httpRequest.Header.Set("apns-topic", p.cfg.Topic)
+	httpRequest.Header.Set("apns-push-type", "alert")
+	httpRequest.Header.Set("apns-priority", "10")
+	// apns-id lets APNs deduplicate retried deliveries to the same device.
+	if key, err := uuid.Parse(args.IdempotencyKey); err == nil {
+		httpRequest.Header.Set("apns-id", uuid.NewSHA1(key, []byte(device.Token)).String())
+	}
+
+	response, err := p.client.Do(httpRequest)
+	if err != nil {
+		return "", failure.New(failure.ProviderOutage, "apns", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode == http.StatusOK {
+		return response.Header.Get("apns-id"), nil
+	}
+
+	errResp := apnsErrorResponse{}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+	_ = json.Unmarshal(body, &errResp)
+	sendErr := failure.FromHTTPStatus("apns", response.StatusCode, errResp.Reason)
+	if response.StatusCode == http.StatusGone || errResp.Reason == "BadDeviceToken" || errResp.Reason == "Unregistered" {
+		p.logger.Info("removing unregistered apns token", zap.String("externalCustomerId", args.ExternalCustomerId))
+		if err := p.registry.DeleteDeviceToken(ctx, model.APNs, device.Token); err != nil {
+			p.logger.Error("error removing apns token", zap.Error(err))
+		}
+		sendErr.Kind = failure.InvalidRecipient
+	}
+	return "", sendErr
+}
+
+// providerToken returns a cached ES256 signed JWT for APNs token based auth.
+func (p *APNsProvider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.bearer != "" && time.Since(p.bearerSetAt) < apnsTokenLifetime {
+		return p.bearer, nil
+	}
+
+	now := time.Now()
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": p.cfg.KeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{"iss": p.cfg.TeamID, "iat": now.Unix()})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, p.cfg.PrivateKey, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// JWS ES256 signatures are the fixed width concatenation of r and s.
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	p.bearer = unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	p.bearerSetAt = now
+	return p.bearer, nil
+}
@@ -0,0 +1,105 @@
+package push_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/push"
+)
+
+type APNsTestSuite struct {
+	suite.Suite
+	registry *mocktokenRegistry
+	key      *ecdsa.PrivateKey
+}
+
+func TestAPNsTestSuite(t *testing.T) {
+	suite.Run(t, new(APNsTestSuite))
+}
+
+func (s *APNsTestSuite) SetupTest() {
+	s.registry = newMocktokenRegistry(s.T())
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.NoError(err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	s.NoError(err)
+	s.key, err = push.ParseAPNsKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	s.NoError(err)
+}
+
+func (s *APNsTestSuite) newServer(handler http.HandlerFunc) *httptest.Server {
+	srv := httptest.NewUnstartedServer(handler)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	return srv
+}
+
+func (s *APNsTestSuite) TestAPNsProvider_Send_Success() {
+	ctx := context.Background()
+	var got struct {
+		Aps struct {
+			Alert map[string]string `json:"alert"`
+		} `json:"aps"`
+	}
+	srv := s.newServer(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(2, r.ProtoMajor)
+		s.Equal("/3/device/device-token", r.URL.Path)
+		s.Equal("com.example.app", r.Header.Get("apns-topic"))
+		s.True(strings.HasPrefix(r.Header.Get("authorization"), "bearer "))
+		s.NoError(json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("apns-id", r.Header.Get("apns-id"))
+	})
+	defer srv.Close()
+
+	s.registry.EXPECT().ListDeviceTokens(ctx, "customer-1", model.APNs).Return([]model.DeviceToken{{Token: "device-token"}}, nil)
+
+	provider := push.NewAPNsProvider(zap.NewNop(), srv.Client(), s.registry, push.APNsConfig{
+		BaseURL:    srv.URL,
+		TeamID:     "team",
+		KeyID:      "key",
+		Topic:      "com.example.app",
+		PrivateKey: s.key,
+	})
+	id, err := provider.Send(ctx, push.Notification{
+		IdempotencyKey:     uuid.NewString(),
+		ExternalCustomerId: "customer-1",
+		Heading:            push.LanguageContent{English: "title"},
+		Content:            push.LanguageContent{English: "body"},
+	})
+	s.NoError(err)
+	s.NotEmpty(*id)
+	s.Equal("title", got.Aps.Alert["title"])
+	s.Equal("body", got.Aps.Alert["body"])
+}
+
+func (s *APNsTestSuite) TestAPNsProvider_Send_RemovesUnregisteredToken() {
+	ctx := context.Background()
+	srv := s.newServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+	})
+	defer srv.Close()
+
+	s.registry.EXPECT().ListDeviceTokens(ctx, "customer-1", model.APNs).Return([]model.DeviceToken{{Token: "stale"}}, nil)
+	s.registry.EXPECT().DeleteDeviceToken(ctx, model.APNs, "stale").Return(nil)
+
+	provider := push.NewAPNsProvider(zap.NewNop(), srv.Client(), s.registry, push.APNsConfig{BaseURL: srv.URL, PrivateKey: s.key})
+	id, err := provider.Send(ctx, push.Notification{ExternalCustomerId: "customer-1"})
+	s.Nil(id)
+	s.ErrorContains(err, "Unregistered")
+}
@@ -0,0 +1,65 @@
+package push
+
+import (
+	"context"
+	"time"
+
+	"github.com/anicoll/unicom/internal/breaker"
+	"github.com/anicoll/unicom/internal/metrics"
+	"github.com/anicoll/unicom/internal/tracing"
+)
+
+// FailoverProvider sends through the first healthy provider of an ordered
+// list, e.g. OneSignal falling back to FCM.
+type FailoverProvider struct {
+	targets []breaker.Target[Provider]
+}
+
+func NewFailoverProvider(targets ...breaker.Target[Provider]) *FailoverProvider {
+	traced := make([]breaker.Target[Provider], len(targets))
+	for i, target := range targets {
+		traced[i] = breaker.Target[Provider]{
+			Provider: tracedProvider{name: target.Breaker.Name(), provider: target.Provider},
+			Breaker:  target.Breaker,
+		}
+	}
+	return &FailoverProvider{
+		targets: traced,
+	}
+}
+
+func (p *FailoverProvider) Send(ctx context.Context, args Notification) (*string, error) {
+	return breaker.Failover(p.targets, func(provider Provider) (*string, error) {
+		return provider.Send(ctx, args)
+	})
+}
+
+// Render renders with the first provider, which notifications are sent
+// through while it is healthy.
+func (p *FailoverProvider) Render(ctx context.Context, args Notification) ([]byte, error) {
+	return p.targets[0].Provider.Render(ctx, args)
+}
+
+// tracedProvider traces and times each call to a provider, named like its
+// breaker.
+type tracedProvider struct {
+	name     string
+	provider Provider
+}
+
+func (p tracedProvider) Send(ctx context.Context, args Notification) (*string, error) {
+	ctx, span := tracing.StartProviderSpan(ctx, "push", p.name, tracing.DomainKey.String(args.Domain))
+	start := time.Now()
+	id, err := p.provider.Send(ctx, args)
+	metrics.Activity(ctx).WithTags(map[string]string{
+		metrics.ChannelTag:  "push",
+		metrics.ProviderTag: p.name,
+		metrics.OutcomeTag:  metrics.Outcome(err),
+	}).Timer(metrics.ProviderLatency).Record(time.Since(start))
+	tracing.EndProviderSpan(span, id, err)
+	return id, err
+}
+
+func (p tracedProvider) Render(ctx context.Context, args Notification) ([]byte, error) {
+	return p.provider.Render(ctx, args)
+}
@@ -0,0 +1,228 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+const (
+	fcmScope          = "https://www.googleapis.com/auth/firebase.messaging"
+	defaultFCMBaseURL = "https://fcm.googleapis.com"
+)
+
+// FCMConfig holds the Firebase project notifications are sent through.
+type FCMConfig struct {
+	ProjectID string
+	// BaseURL defaults to https://fcm.googleapis.com.
+	BaseURL string
+}
+
+// FCMProvider sends notifications through the FCM HTTP v1 API to every FCM
+// token registered for the customer.
+type FCMProvider struct {
+	cfg      FCMConfig
+	client   *http.Client
+	registry tokenRegistry
+	logger   *zap.Logger
+}
+
+// NewFCMProvider creates an FCMProvider. The http client is expected to
+// authorise requests, see NewFCMTokenSource.
+func NewFCMProvider(logger *zap.Logger, client *http.Client, registry tokenRegistry, cfg FCMConfig) *FCMProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultFCMBaseURL
+	}
+	return &FCMProvider{
+		cfg:      cfg,
+		client:   client,
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// NewFCMTokenSource creates an OAuth2 token source from a Google service
+// account JSON key with the firebase.messaging scope.
+func NewFCMTokenSource(ctx context.Context, credentialsJSON []byte) (oauth2.TokenSource, error) {
+	var account struct {
+		ClientEmail  string `json:"client_email"`
+		PrivateKey   string `json:"private_key"`
+		PrivateKeyID string `json:"private_key_id"`
+		TokenURI     string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(credentialsJSON, &account); err != nil {
+		return nil, fmt.Errorf("parsing fcm service account: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("fcm service account is missing client_email or private_key")
+	}
+	cfg := &jwt.Config{
+		Email:        account.ClientEmail,
+		PrivateKey:   []byte(account.PrivateKey),
+		PrivateKeyID: account.PrivateKeyID,
+		Scopes:       []string{fcmScope},
+		TokenURL:     account.TokenURI,
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = "https://oauth2.googleapis.com/token"
+	}
+	return cfg.TokenSource(ctx), nil
+}
+
+type fcmMessage struct {
+	Message fcmMessageBody `json:"message"`
+}
+
+type fcmMessageBody struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmResponse struct {
+	Name string `json:"name"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (p *FCMProvider) Send(ctx context.Context, args Notification) (*string, error) {
+	devices, err := p.registry.ListDeviceTokens(ctx, args.ExternalCustomerId, model.FCM)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, failure.Errorf(failure.InvalidRecipient, "fcm", "no fcm devices registered for customer %s", args.ExternalCustomerId)
+	}
+
+	messageIds := make([]string, 0, len(devices))
+	var sendErrs []error
+	for _, device := range devices {
+		messageId, err := p.sendToDevice(ctx, args, device)
+		if err != nil {
+			sendErrs = append(sendErrs, err)
+			continue
+		}
+		messageIds = append(messageIds, messageId)
+	}
+	if len(messageIds) == 0 {
+		return nil, failure.Join(sendErrs...)
+	}
+	ids := strings.Join(messageIds, ",")
+	return &ids, nil
+}
+
+// Render returns the messages sent to each FCM device registered for the
+// customer, as a JSON array.
+func (p *FCMProvider) Render(ctx context.Context, args Notification) ([]byte, error) {
+	devices, err := p.registry.ListDeviceTokens(ctx, args.ExternalCustomerId, model.FCM)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, failure.Errorf(failure.InvalidRecipient, "fcm", "no fcm devices registered for customer %s", args.ExternalCustomerId)
+	}
+	messages := make([]fcmMessage, len(devices))
+	for i, device := range devices {
+		messages[i] = newFCMMessage(args, device)
+	}
+	return json.Marshal(messages)
+}
+
+func (p *FCMProvider) sendToDevice(ctx context.Context, args Notification, device model.DeviceToken) (string, error) {
+	data, err := json.Marshal(newFCMMessage(args, device))
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", strings.TrimSuffix(p.cfg.BaseURL, "/"), p.cfg.ProjectID)
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	response, err := p.client.Do(httpRequest)
+	if err != nil {
+		return "", failure.New(failure.ProviderOutage, "fcm", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode == http.StatusOK {
+		resp := fcmResponse{}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return "", err
+		}
+		return resp.Name, nil
+	}
+
+	errResp := fcmErrorResponse{}
+	_ = json.Unmarshal(body, &errResp)
+	sendErr := failure.FromHTTPStatus("fcm", response.StatusCode, errResp.Error.Status+" "+errResp.Error.Message)
+	if fcmTokenInvalid(errResp) {
+		p.logger.Info("removing unregistered fcm token", zap.String("externalCustomerId", args.ExternalCustomerId))
+		if err := p.registry.DeleteDeviceToken(ctx, model.FCM, device.Token); err != nil {
+			p.logger.Error("error removing fcm token", zap.Error(err))
+		}
+		sendErr.Kind = failure.InvalidRecipient
+	}
+	return "", sendErr
+}
+
+// fcmTokenInvalid reports whether FCM rejected the token as permanently unusable.
+func fcmTokenInvalid(resp fcmErrorResponse) bool {
+	for _, detail := range resp.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return true
+		}
+	}
+	return resp.Error.Status == "NOT_FOUND"
+}
+
+// newFCMMessage returns the message sent to device for args, in the device's
+// language.
+func newFCMMessage(args Notification, device model.DeviceToken) fcmMessage {
+	msg := fcmMessage{Message: fcmMessageBody{
+		Token: device.Token,
+		Notification: fcmNotification{
+			Title: args.Heading.localise(device.Locale),
+			Body:  args.Content.localise(device.Locale),
+		},
+		Data: map[string]string{
+			"idempotency_key": args.IdempotencyKey,
+		},
+	}}
+	if args.SubTitle != nil {
+		msg.Message.Data["subtitle"] = args.SubTitle.localise(device.Locale)
+	}
+	return msg
+}
@@ -0,0 +1,129 @@
+package push_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/push"
+)
+
+type FCMTestSuite struct {
+	suite.Suite
+	registry *mocktokenRegistry
+}
+
+func TestFCMTestSuite(t *testing.T) {
+	suite.Run(t, new(FCMTestSuite))
+}
+
+func (s *FCMTestSuite) SetupTest() {
+	s.registry = newMocktokenRegistry(s.T())
+}
+
+func (s *FCMTestSuite) TestFCMProvider_Send_Success() {
+	ctx := context.Background()
+	var got map[string]map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/v1/projects/test-project/messages:send", r.URL.Path)
+		s.NoError(json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"name":"projects/test-project/messages/1"}`))
+	}))
+	defer srv.Close()
+
+	s.registry.EXPECT().ListDeviceTokens(ctx, "customer-1", model.FCM).Return([]model.DeviceToken{
+		{Token: "token-1", Locale: "ar-AE"},
+	}, nil)
+
+	provider := push.NewFCMProvider(zap.NewNop(), srv.Client(), s.registry, push.FCMConfig{ProjectID: "test-project", BaseURL: srv.URL})
+	id, err := provider.Send(ctx, push.Notification{
+		ExternalCustomerId: "customer-1",
+		Content:            push.LanguageContent{English: "hello", Arabic: "مرحبا"},
+	})
+	s.NoError(err)
+	s.Equal("projects/test-project/messages/1", *id)
+	s.Equal("token-1", got["message"]["token"])
+	s.Equal("مرحبا", got["message"]["notification"].(map[string]any)["body"])
+}
+
+func (s *FCMTestSuite) TestFCMProvider_Send_RemovesUnregisteredToken() {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","message":"Requested entity was not found.","details":[{"errorCode":"UNREGISTERED"}]}}`))
+	}))
+	defer srv.Close()
+
+	s.registry.EXPECT().ListDeviceTokens(ctx, "customer-1", model.FCM).Return([]model.DeviceToken{{Token: "stale"}}, nil)
+	s.registry.EXPECT().DeleteDeviceToken(ctx, model.FCM, "stale").Return(nil)
+
+	provider := push.NewFCMProvider(zap.NewNop(), srv.Client(), s.registry, push.FCMConfig{ProjectID: "test-project", BaseURL: srv.URL})
+	id, err := provider.Send(ctx, push.Notification{ExternalCustomerId: "customer-1"})
+	s.Nil(id)
+	s.ErrorContains(err, "404")
+}
+
+func (s *FCMTestSuite) TestFCMProvider_Send_RetriesWhenAnyDeviceMaySucceed() {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var got map[string]map[string]any
+		s.NoError(json.NewDecoder(r.Body).Decode(&got))
+		if got["message"]["token"] == "stale" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","message":"Requested entity was not found.","details":[{"errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"code":429,"status":"RESOURCE_EXHAUSTED","message":"Quota exceeded."}}`))
+	}))
+	defer srv.Close()
+
+	s.registry.EXPECT().ListDeviceTokens(ctx, "customer-1", model.FCM).Return([]model.DeviceToken{{Token: "stale"}, {Token: "token-1"}}, nil)
+	s.registry.EXPECT().DeleteDeviceToken(ctx, model.FCM, "stale").Return(nil)
+
+	provider := push.NewFCMProvider(zap.NewNop(), srv.Client(), s.registry, push.FCMConfig{ProjectID: "test-project", BaseURL: srv.URL})
+	_, err := provider.Send(ctx, push.Notification{ExternalCustomerId: "customer-1"})
+	s.Equal(failure.Throttled, failure.KindOf(err))
+	s.ErrorContains(err, "404")
+}
+
+func (s *FCMTestSuite) TestFCMProvider_Send_NoDevices() {
+	ctx := context.Background()
+	s.registry.EXPECT().ListDeviceTokens(ctx, "customer-1", model.FCM).Return(nil, nil)
+
+	provider := push.NewFCMProvider(zap.NewNop(), http.DefaultClient, s.registry, push.FCMConfig{ProjectID: "test-project"})
+	id, err := provider.Send(ctx, push.Notification{ExternalCustomerId: "customer-1"})
+	s.Nil(id)
+	s.ErrorContains(err, "no fcm devices")
+}
+
+func (s *FCMTestSuite) TestFCMProvider_Render() {
+	ctx := context.Background()
+	s.registry.EXPECT().ListDeviceTokens(ctx, "customer-1", model.FCM).Return([]model.DeviceToken{
+		{Token: "token-1", Locale: "ar-AE"},
+		{Token: "token-2", Locale: "en-GB"},
+	}, nil)
+
+	provider := push.NewFCMProvider(zap.NewNop(), http.DefaultClient, s.registry, push.FCMConfig{ProjectID: "test-project"})
+	raw, err := provider.Render(ctx, push.Notification{
+		ExternalCustomerId: "customer-1",
+		IdempotencyKey:     "key-1",
+		Content:            push.LanguageContent{English: "hello", Arabic: "مرحبا"},
+	})
+	s.Require().NoError(err)
+
+	var got []map[string]map[string]any
+	s.Require().NoError(json.Unmarshal(raw, &got))
+	s.Require().Len(got, 2)
+	s.Equal("token-1", got[0]["message"]["token"])
+	s.Equal("مرحبا", got[0]["message"]["notification"].(map[string]any)["body"])
+	s.Equal("hello", got[1]["message"]["notification"].(map[string]any)["body"])
+	s.Equal("key-1", got[1]["message"]["data"].(map[string]any)["idempotency_key"])
+}
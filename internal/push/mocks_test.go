@@ -6,19 +6,19 @@ package push_test
 
 import (
 	"context"
-	"net/http"
 
-	"github.com/OneSignal/onesignal-go-api/v2"
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/push"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// newMocknotificationCreator creates a new instance of mocknotificationCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockProvider creates a new instance of MockProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func newMocknotificationCreator(t interface {
+func NewMockProvider(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *mocknotificationCreator {
-	mock := &mocknotificationCreator{}
+}) *MockProvider {
+	mock := &MockProvider{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -26,71 +26,140 @@ func newMocknotificationCreator(t interface {
 	return mock
 }
 
-// mocknotificationCreator is an autogenerated mock type for the notificationCreator type
-type mocknotificationCreator struct {
+// MockProvider is an autogenerated mock type for the Provider type
+type MockProvider struct {
 	mock.Mock
 }
 
-type mocknotificationCreator_Expecter struct {
+type MockProvider_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *mocknotificationCreator) EXPECT() *mocknotificationCreator_Expecter {
-	return &mocknotificationCreator_Expecter{mock: &_m.Mock}
+func (_m *MockProvider) EXPECT() *MockProvider_Expecter {
+	return &MockProvider_Expecter{mock: &_m.Mock}
 }
 
-// CreateNotification provides a mock function for the type mocknotificationCreator
-func (_mock *mocknotificationCreator) CreateNotification(ctx context.Context) onesignal.ApiCreateNotificationRequest {
-	ret := _mock.Called(ctx)
+// Render provides a mock function for the type MockProvider
+func (_mock *MockProvider) Render(ctx context.Context, args push.Notification) ([]byte, error) {
+	ret := _mock.Called(ctx, args)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateNotification")
+		panic("no return value specified for Render")
 	}
 
-	var r0 onesignal.ApiCreateNotificationRequest
-	if returnFunc, ok := ret.Get(0).(func(context.Context) onesignal.ApiCreateNotificationRequest); ok {
-		r0 = returnFunc(ctx)
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) ([]byte, error)); ok {
+		return returnFunc(ctx, args)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) []byte); ok {
+		r0 = returnFunc(ctx, args)
 	} else {
-		r0 = ret.Get(0).(onesignal.ApiCreateNotificationRequest)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, push.Notification) error); ok {
+		r1 = returnFunc(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockProvider_Render_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Render'
+type MockProvider_Render_Call struct {
+	*mock.Call
+}
+
+// Render is a helper method to define mock.On call
+//   - ctx
+//   - args
+func (_e *MockProvider_Expecter) Render(ctx interface{}, args interface{}) *MockProvider_Render_Call {
+	return &MockProvider_Render_Call{Call: _e.mock.On("Render", ctx, args)}
+}
+
+func (_c *MockProvider_Render_Call) Run(run func(ctx context.Context, args push.Notification)) *MockProvider_Render_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(push.Notification))
+	})
+	return _c
+}
+
+func (_c *MockProvider_Render_Call) Return(bytes []byte, err error) *MockProvider_Render_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *MockProvider_Render_Call) RunAndReturn(run func(ctx context.Context, args push.Notification) ([]byte, error)) *MockProvider_Render_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Send provides a mock function for the type MockProvider
+func (_mock *MockProvider) Send(ctx context.Context, args push.Notification) (*string, error) {
+	ret := _mock.Called(ctx, args)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 *string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) (*string, error)); ok {
+		return returnFunc(ctx, args)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) *string); ok {
+		r0 = returnFunc(ctx, args)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, push.Notification) error); ok {
+		r1 = returnFunc(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// mocknotificationCreator_CreateNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNotification'
-type mocknotificationCreator_CreateNotification_Call struct {
+// MockProvider_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type MockProvider_Send_Call struct {
 	*mock.Call
 }
 
-// CreateNotification is a helper method to define mock.On call
+// Send is a helper method to define mock.On call
 //   - ctx
-func (_e *mocknotificationCreator_Expecter) CreateNotification(ctx interface{}) *mocknotificationCreator_CreateNotification_Call {
-	return &mocknotificationCreator_CreateNotification_Call{Call: _e.mock.On("CreateNotification", ctx)}
+//   - args
+func (_e *MockProvider_Expecter) Send(ctx interface{}, args interface{}) *MockProvider_Send_Call {
+	return &MockProvider_Send_Call{Call: _e.mock.On("Send", ctx, args)}
 }
 
-func (_c *mocknotificationCreator_CreateNotification_Call) Run(run func(ctx context.Context)) *mocknotificationCreator_CreateNotification_Call {
+func (_c *MockProvider_Send_Call) Run(run func(ctx context.Context, args push.Notification)) *MockProvider_Send_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(push.Notification))
 	})
 	return _c
 }
 
-func (_c *mocknotificationCreator_CreateNotification_Call) Return(apiCreateNotificationRequest onesignal.ApiCreateNotificationRequest) *mocknotificationCreator_CreateNotification_Call {
-	_c.Call.Return(apiCreateNotificationRequest)
+func (_c *MockProvider_Send_Call) Return(s *string, err error) *MockProvider_Send_Call {
+	_c.Call.Return(s, err)
 	return _c
 }
 
-func (_c *mocknotificationCreator_CreateNotification_Call) RunAndReturn(run func(ctx context.Context) onesignal.ApiCreateNotificationRequest) *mocknotificationCreator_CreateNotification_Call {
+func (_c *MockProvider_Send_Call) RunAndReturn(run func(ctx context.Context, args push.Notification) (*string, error)) *MockProvider_Send_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// newMocknotificationRequest creates a new instance of mocknotificationRequest. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// newMocktokenRegistry creates a new instance of mocktokenRegistry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func newMocknotificationRequest(t interface {
+func newMocktokenRegistry(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *mocknotificationRequest {
-	mock := &mocknotificationRequest{}
+}) *mocktokenRegistry {
+	mock := &mocktokenRegistry{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -98,112 +167,120 @@ func newMocknotificationRequest(t interface {
 	return mock
 }
 
-// mocknotificationRequest is an autogenerated mock type for the notificationRequest type
-type mocknotificationRequest struct {
+// mocktokenRegistry is an autogenerated mock type for the tokenRegistry type
+type mocktokenRegistry struct {
 	mock.Mock
 }
 
-type mocknotificationRequest_Expecter struct {
+type mocktokenRegistry_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *mocknotificationRequest) EXPECT() *mocknotificationRequest_Expecter {
-	return &mocknotificationRequest_Expecter{mock: &_m.Mock}
+func (_m *mocktokenRegistry) EXPECT() *mocktokenRegistry_Expecter {
+	return &mocktokenRegistry_Expecter{mock: &_m.Mock}
 }
 
-// Execute provides a mock function for the type mocknotificationRequest
-func (_mock *mocknotificationRequest) Execute() (*onesignal.CreateNotificationSuccessResponse, *http.Response, error) {
-	ret := _mock.Called()
+// DeleteDeviceToken provides a mock function for the type mocktokenRegistry
+func (_mock *mocktokenRegistry) DeleteDeviceToken(ctx context.Context, tokenType model.DeviceTokenType, token string) error {
+	ret := _mock.Called(ctx, tokenType, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Execute")
+		panic("no return value specified for DeleteDeviceToken")
 	}
 
-	var r0 *onesignal.CreateNotificationSuccessResponse
-	var r1 *http.Response
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func() (*onesignal.CreateNotificationSuccessResponse, *http.Response, error)); ok {
-		return returnFunc()
-	}
-	if returnFunc, ok := ret.Get(0).(func() *onesignal.CreateNotificationSuccessResponse); ok {
-		r0 = returnFunc()
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*onesignal.CreateNotificationSuccessResponse)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func() *http.Response); ok {
-		r1 = returnFunc()
-	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*http.Response)
-		}
-	}
-	if returnFunc, ok := ret.Get(2).(func() error); ok {
-		r2 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.DeviceTokenType, string) error); ok {
+		r0 = returnFunc(ctx, tokenType, token)
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Error(0)
 	}
-	return r0, r1, r2
+	return r0
 }
 
-// mocknotificationRequest_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
-type mocknotificationRequest_Execute_Call struct {
+// mocktokenRegistry_DeleteDeviceToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeviceToken'
+type mocktokenRegistry_DeleteDeviceToken_Call struct {
 	*mock.Call
 }
 
-// Execute is a helper method to define mock.On call
-func (_e *mocknotificationRequest_Expecter) Execute() *mocknotificationRequest_Execute_Call {
-	return &mocknotificationRequest_Execute_Call{Call: _e.mock.On("Execute")}
+// DeleteDeviceToken is a helper method to define mock.On call
+//   - ctx
+//   - tokenType
+//   - token
+func (_e *mocktokenRegistry_Expecter) DeleteDeviceToken(ctx interface{}, tokenType interface{}, token interface{}) *mocktokenRegistry_DeleteDeviceToken_Call {
+	return &mocktokenRegistry_DeleteDeviceToken_Call{Call: _e.mock.On("DeleteDeviceToken", ctx, tokenType, token)}
 }
 
-func (_c *mocknotificationRequest_Execute_Call) Run(run func()) *mocknotificationRequest_Execute_Call {
+func (_c *mocktokenRegistry_DeleteDeviceToken_Call) Run(run func(ctx context.Context, tokenType model.DeviceTokenType, token string)) *mocktokenRegistry_DeleteDeviceToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(model.DeviceTokenType), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *mocknotificationRequest_Execute_Call) Return(createNotificationSuccessResponse *onesignal.CreateNotificationSuccessResponse, response *http.Response, err error) *mocknotificationRequest_Execute_Call {
-	_c.Call.Return(createNotificationSuccessResponse, response, err)
+func (_c *mocktokenRegistry_DeleteDeviceToken_Call) Return(err error) *mocktokenRegistry_DeleteDeviceToken_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *mocknotificationRequest_Execute_Call) RunAndReturn(run func() (*onesignal.CreateNotificationSuccessResponse, *http.Response, error)) *mocknotificationRequest_Execute_Call {
+func (_c *mocktokenRegistry_DeleteDeviceToken_Call) RunAndReturn(run func(ctx context.Context, tokenType model.DeviceTokenType, token string) error) *mocktokenRegistry_DeleteDeviceToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Notification provides a mock function for the type mocknotificationRequest
-func (_mock *mocknotificationRequest) Notification(notification onesignal.Notification) {
-	_mock.Called(notification)
-	return
+// ListDeviceTokens provides a mock function for the type mocktokenRegistry
+func (_mock *mocktokenRegistry) ListDeviceTokens(ctx context.Context, externalCustomerId string, tokenType model.DeviceTokenType) ([]model.DeviceToken, error) {
+	ret := _mock.Called(ctx, externalCustomerId, tokenType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeviceTokens")
+	}
+
+	var r0 []model.DeviceToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.DeviceTokenType) ([]model.DeviceToken, error)); ok {
+		return returnFunc(ctx, externalCustomerId, tokenType)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.DeviceTokenType) []model.DeviceToken); ok {
+		r0 = returnFunc(ctx, externalCustomerId, tokenType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.DeviceToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, model.DeviceTokenType) error); ok {
+		r1 = returnFunc(ctx, externalCustomerId, tokenType)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// mocknotificationRequest_Notification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Notification'
-type mocknotificationRequest_Notification_Call struct {
+// mocktokenRegistry_ListDeviceTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeviceTokens'
+type mocktokenRegistry_ListDeviceTokens_Call struct {
 	*mock.Call
 }
 
-// Notification is a helper method to define mock.On call
-//   - notification
-func (_e *mocknotificationRequest_Expecter) Notification(notification interface{}) *mocknotificationRequest_Notification_Call {
-	return &mocknotificationRequest_Notification_Call{Call: _e.mock.On("Notification", notification)}
+// ListDeviceTokens is a helper method to define mock.On call
+//   - ctx
+//   - externalCustomerId
+//   - tokenType
+func (_e *mocktokenRegistry_Expecter) ListDeviceTokens(ctx interface{}, externalCustomerId interface{}, tokenType interface{}) *mocktokenRegistry_ListDeviceTokens_Call {
+	return &mocktokenRegistry_ListDeviceTokens_Call{Call: _e.mock.On("ListDeviceTokens", ctx, externalCustomerId, tokenType)}
 }
 
-func (_c *mocknotificationRequest_Notification_Call) Run(run func(notification onesignal.Notification)) *mocknotificationRequest_Notification_Call {
+func (_c *mocktokenRegistry_ListDeviceTokens_Call) Run(run func(ctx context.Context, externalCustomerId string, tokenType model.DeviceTokenType)) *mocktokenRegistry_ListDeviceTokens_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(onesignal.Notification))
+		run(args[0].(context.Context), args[1].(string), args[2].(model.DeviceTokenType))
 	})
 	return _c
 }
 
-func (_c *mocknotificationRequest_Notification_Call) Return() *mocknotificationRequest_Notification_Call {
-	_c.Call.Return()
+func (_c *mocktokenRegistry_ListDeviceTokens_Call) Return(deviceTokens []model.DeviceToken, err error) *mocktokenRegistry_ListDeviceTokens_Call {
+	_c.Call.Return(deviceTokens, err)
 	return _c
 }
 
-func (_c *mocknotificationRequest_Notification_Call) RunAndReturn(run func(notification onesignal.Notification)) *mocknotificationRequest_Notification_Call {
-	_c.Run(run)
+func (_c *mocktokenRegistry_ListDeviceTokens_Call) RunAndReturn(run func(ctx context.Context, externalCustomerId string, tokenType model.DeviceTokenType) ([]model.DeviceToken, error)) *mocktokenRegistry_ListDeviceTokens_Call {
+	_c.Call.Return(run)
 	return _c
 }
@@ -2,60 +2,51 @@ package push
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/OneSignal/onesignal-go-api/v2"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"go.uber.org/zap"
-)
-
-// type OneSignalAPI interface {
-// 	CreateNotification(ctx context.Context) OneSignalNotificationCreator
-// }
 
-// type OneSignalNotificationCreator interface {
-// 	Notification(notification onesignal.Notification) OneSignalNotificationExecutor
-// }
+	"github.com/anicoll/unicom/internal/failure"
+)
 
-// type OneSignalNotificationExecutor interface {
-// 	Execute() (*onesignal.CreateNotificationSuccessResponse, *http.Response, error)
-// }
+// oneSignalAPIURL is the base URL of OneSignal's REST API.
+const oneSignalAPIURL = "https://onesignal.com/api/v1"
 
-type Service struct {
+// OneSignalProvider sends notifications to OneSignal external user IDs.
+type OneSignalProvider struct {
 	appId     string
 	authKey   string
 	apiClient *onesignal.DefaultApiService
-	logger    *zap.Logger
+	// checkClient calls the REST API directly to check the credentials.
+	checkClient *http.Client
+	logger      *zap.Logger
 }
 
-func New(logger *zap.Logger, appId, authKey string) *Service {
+func NewOneSignalProvider(logger *zap.Logger, appId, authKey string) *OneSignalProvider {
 	configuration := onesignal.NewConfiguration()
-	return &Service{
+	return &OneSignalProvider{
 		appId:     appId,
 		authKey:   authKey,
 		logger:    logger,
 		apiClient: onesignal.NewAPIClient(configuration).DefaultApi,
+		checkClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
 	}
 }
 
-type LanguageContent struct {
-	Arabic  string
-	English string
-}
-
-type Notification struct {
-	IdempotencyKey     string
-	ExternalCustomerId string
-	Content            LanguageContent
-	Heading            LanguageContent
-	SubTitle           *LanguageContent
-}
-
-func (s *Service) SetAPIClient(client *onesignal.DefaultApiService) {
+func (s *OneSignalProvider) SetAPIClient(client *onesignal.DefaultApiService) {
 	s.apiClient = client
 }
 
-func (s *Service) Send(ctx context.Context, args Notification) (*string, error) {
+func (s *OneSignalProvider) Send(ctx context.Context, args Notification) (*string, error) {
 	notification := *onesignal.NewNotification(s.appId)
 	notification.SetIsIos(true)
 	notification.SetIsAndroid(true)
@@ -84,21 +75,55 @@ func (s *Service) Send(ctx context.Context, args Notification) (*string, error)
 
 	authCtx := context.WithValue(ctx, onesignal.AppAuth, s.authKey)
 
-	resp, _, err := s.apiClient.
+	resp, httpResp, err := s.apiClient.
 		CreateNotification(authCtx).
 		Notification(notification).
 		Execute()
 	if err != nil {
 		s.logger.Error("error sending push notification", zap.Error(err))
-		return nil, err
+		if httpResp == nil {
+			return nil, failure.New(failure.ProviderOutage, "onesignal", err)
+		}
+		return nil, failure.FromHTTPStatus("onesignal", httpResp.StatusCode, err.Error())
 	}
 	if resp.Errors != nil {
 		if resp.Errors.InvalidIdentifierError != nil {
 			s.logger.Error("error sending push notification", zap.Strings("invalidExternalUserIds", resp.Errors.InvalidIdentifierError.InvalidExternalUserIds))
-			return nil, err
+			return nil, failure.Errorf(failure.InvalidRecipient, "onesignal", "invalid external user ids %v", resp.Errors.InvalidIdentifierError.InvalidExternalUserIds)
 		}
 		s.logger.Error("unknown error sending push notification", zap.Any("errors", resp.Errors))
-		return nil, errors.New("unknown error occured attempting to send communication")
+		return nil, failure.New(failure.Unknown, "onesignal", errors.New("unknown error occured attempting to send communication"))
 	}
 	return aws.String(resp.GetId()), nil
 }
+
+// Render returns the body OneSignal's create notification call would be made
+// with.
+func (s *OneSignalProvider) Render(ctx context.Context, args Notification) ([]byte, error) {
+	return json.Marshal(newOneSignalNotification(s.appId, args))
+}
+
+// Check verifies the app ID and REST API key by listing one of the app's
+// notifications, which OneSignal refuses when either is wrong.
+func (s *OneSignalProvider) Check(ctx context.Context) error {
+	query := url.Values{
+		"app_id": {s.appId},
+		"limit":  {"1"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, oneSignalAPIURL+"/notifications?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Basic "+s.authKey)
+
+	resp, err := s.checkClient.Do(req)
+	if err != nil {
+		return failure.New(failure.ProviderOutage, "onesignal", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return failure.FromHTTPStatus("onesignal", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"github.com/anicoll/unicom/internal/outbox"
+)
+
+// OutboxProvider writes each notification to an outbox as the JSON body of
+// OneSignal's create notification call instead of sending it, standing in for
+// OneSignal when running locally.
+type OutboxProvider struct {
+	appId  string
+	outbox *outbox.Outbox
+}
+
+func NewOutboxProvider(box *outbox.Outbox, appId string) *OutboxProvider {
+	return &OutboxProvider{
+		appId:  appId,
+		outbox: box,
+	}
+}
+
+// oneSignalNotification is the part of OneSignal's create notification body
+// OneSignalProvider sets.
+type oneSignalNotification struct {
+	AppId                     string            `json:"app_id"`
+	ExternalId                string            `json:"external_id"`
+	IncludeExternalUserIds    []string          `json:"include_external_user_ids"`
+	ChannelForExternalUserIds string            `json:"channel_for_external_user_ids"`
+	IsIos                     bool              `json:"isIos"`
+	IsAndroid                 bool              `json:"isAndroid"`
+	IsHuawei                  bool              `json:"isHuawei"`
+	Contents                  map[string]string `json:"contents"`
+	Headings                  map[string]string `json:"headings"`
+	Subtitle                  map[string]string `json:"subtitle,omitempty"`
+}
+
+func (p *OutboxProvider) Send(ctx context.Context, args Notification) (*string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewString()
+	if err := p.outbox.WriteJSON(outbox.Push, id, newOneSignalNotification(p.appId, args)); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// Render returns the notification as it would be written to the outbox.
+func (p *OutboxProvider) Render(ctx context.Context, args Notification) ([]byte, error) {
+	return json.Marshal(newOneSignalNotification(p.appId, args))
+}
+
+// newOneSignalNotification returns the body OneSignal is called with for args.
+func newOneSignalNotification(appId string, args Notification) oneSignalNotification {
+	notification := oneSignalNotification{
+		AppId:                     appId,
+		ExternalId:                args.IdempotencyKey,
+		IncludeExternalUserIds:    []string{args.ExternalCustomerId},
+		ChannelForExternalUserIds: "push",
+		IsIos:                     true,
+		IsAndroid:                 true,
+		IsHuawei:                  true,
+		Contents:                  args.Content.languages(),
+		Headings:                  args.Heading.languages(),
+	}
+	if args.SubTitle != nil {
+		notification.Subtitle = args.SubTitle.languages()
+	}
+	return notification
+}
+
+// languages returns the content keyed by OneSignal's language codes.
+func (c LanguageContent) languages() map[string]string {
+	languages := map[string]string{}
+	if c.English != "" {
+		languages["en"] = c.English
+	}
+	if c.Arabic != "" {
+		languages["ar"] = c.Arabic
+	}
+	return languages
+}
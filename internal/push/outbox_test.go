@@ -0,0 +1,75 @@
+package push_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/anicoll/unicom/internal/outbox"
+	"github.com/anicoll/unicom/internal/push"
+)
+
+type OutboxProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestOutboxProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxProviderTestSuite))
+}
+
+func (s *OutboxProviderTestSuite) TestSend_WritesOneSignalJSON() {
+	box, err := outbox.New(s.T().TempDir())
+	s.Require().NoError(err)
+
+	id, err := push.NewOutboxProvider(box, "app-id").Send(context.Background(), push.Notification{
+		IdempotencyKey:     "key-1",
+		ExternalCustomerId: "customer-1",
+		Content:            push.LanguageContent{English: "Your bill is ready", Arabic: "فاتورتك جاهزة"},
+		Heading:            push.LanguageContent{English: "Billing"},
+	})
+	s.Require().NoError(err)
+
+	messages, err := box.List()
+	s.Require().NoError(err)
+	s.Require().Len(messages, 1)
+	s.Equal(*id, messages[0].ID)
+	s.Equal(outbox.Push, messages[0].Kind)
+
+	raw, err := box.Read(messages[0].Name)
+	s.Require().NoError(err)
+	var notification map[string]any
+	s.Require().NoError(json.Unmarshal(raw, &notification))
+	s.Equal("app-id", notification["app_id"])
+	s.Equal("key-1", notification["external_id"])
+	s.Equal([]any{"customer-1"}, notification["include_external_user_ids"])
+	s.Equal(map[string]any{"en": "Your bill is ready", "ar": "فاتورتك جاهزة"}, notification["contents"])
+	s.Equal(map[string]any{"en": "Billing"}, notification["headings"])
+	s.NotContains(notification, "subtitle")
+}
+
+func (s *OutboxProviderTestSuite) TestRender_MatchesOneSignal() {
+	notification := push.Notification{
+		IdempotencyKey:     "key-1",
+		ExternalCustomerId: "customer-1",
+		Content:            push.LanguageContent{English: "Your bill is ready"},
+		SubTitle:           &push.LanguageContent{English: "March"},
+	}
+	box, err := outbox.New(s.T().TempDir())
+	s.Require().NoError(err)
+
+	raw, err := push.NewOutboxProvider(box, "app-id").Render(context.Background(), notification)
+	s.Require().NoError(err)
+	oneSignal, err := push.NewOneSignalProvider(zap.NewNop(), "app-id", "auth-key").Render(context.Background(), notification)
+	s.Require().NoError(err)
+	s.JSONEq(string(oneSignal), string(raw))
+
+	var got map[string]any
+	s.Require().NoError(json.Unmarshal(raw, &got))
+	s.Equal(map[string]any{"en": "March"}, got["subtitle"])
+	messages, err := box.List()
+	s.Require().NoError(err)
+	s.Empty(messages, "rendering doesn't write to the outbox")
+}
@@ -0,0 +1,81 @@
+package push
+
+import (
+	"context"
+
+	"github.com/anicoll/unicom/internal/model"
+)
+
+// Provider delivers a push notification through a single backend (OneSignal,
+// FCM, APNs, ...) and returns the provider's message ID.
+type Provider interface {
+	Send(ctx context.Context, args Notification) (*string, error)
+	// Render returns the JSON body the provider would be called with, without
+	// sending the notification.
+	Render(ctx context.Context, args Notification) ([]byte, error)
+}
+
+// tokenRegistry resolves the device tokens registered for a customer, for
+// providers which address devices directly.
+type tokenRegistry interface {
+	ListDeviceTokens(ctx context.Context, externalCustomerId string, tokenType model.DeviceTokenType) ([]model.DeviceToken, error)
+	DeleteDeviceToken(ctx context.Context, tokenType model.DeviceTokenType, token string) error
+}
+
+type Service struct {
+	defaultProvider Provider
+	domainProviders map[string]Provider
+}
+
+type LanguageContent struct {
+	Arabic  string
+	English string
+}
+
+type Notification struct {
+	Domain             string
+	IdempotencyKey     string
+	ExternalCustomerId string
+	Content            LanguageContent
+	Heading            LanguageContent
+	SubTitle           *LanguageContent
+}
+
+// NewService creates a Service which sends through defaultProvider unless the
+// notification's domain has an entry in domainProviders.
+func NewService(defaultProvider Provider, domainProviders map[string]Provider) *Service {
+	if domainProviders == nil {
+		domainProviders = map[string]Provider{}
+	}
+	return &Service{
+		defaultProvider: defaultProvider,
+		domainProviders: domainProviders,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, args Notification) (*string, error) {
+	return s.providerFor(args.Domain).Send(ctx, args)
+}
+
+// Render returns the body the notification's provider would be called with.
+func (s *Service) Render(ctx context.Context, args Notification) ([]byte, error) {
+	return s.providerFor(args.Domain).Render(ctx, args)
+}
+
+func (s *Service) providerFor(domain string) Provider {
+	if provider, ok := s.domainProviders[domain]; ok {
+		return provider
+	}
+	return s.defaultProvider
+}
+
+// localise picks the content matching a device locale, falling back to English.
+func (c LanguageContent) localise(locale string) string {
+	if len(locale) >= 2 && locale[:2] == "ar" && c.Arabic != "" {
+		return c.Arabic
+	}
+	if c.English == "" {
+		return c.Arabic
+	}
+	return c.English
+}
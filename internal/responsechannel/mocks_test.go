@@ -38,6 +38,72 @@ func (_m *mocksqsClient) EXPECT() *mocksqsClient_Expecter {
 	return &mocksqsClient_Expecter{mock: &_m.Mock}
 }
 
+// ListQueues provides a mock function for the type mocksqsClient
+func (_mock *mocksqsClient) ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+	var tmpRet mock.Arguments
+	if len(optFns) > 0 {
+		tmpRet = _mock.Called(ctx, params, optFns)
+	} else {
+		tmpRet = _mock.Called(ctx, params)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQueues")
+	}
+
+	var r0 *sqs.ListQueuesOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *sqs.ListQueuesInput, ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *sqs.ListQueuesInput, ...func(*sqs.Options)) *sqs.ListQueuesOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sqs.ListQueuesOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *sqs.ListQueuesInput, ...func(*sqs.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mocksqsClient_ListQueues_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQueues'
+type mocksqsClient_ListQueues_Call struct {
+	*mock.Call
+}
+
+// ListQueues is a helper method to define mock.On call
+//   - ctx
+//   - params
+//   - optFns
+func (_e *mocksqsClient_Expecter) ListQueues(ctx interface{}, params interface{}, optFns ...interface{}) *mocksqsClient_ListQueues_Call {
+	return &mocksqsClient_ListQueues_Call{Call: _e.mock.On("ListQueues",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *mocksqsClient_ListQueues_Call) Run(run func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options))) *mocksqsClient_ListQueues_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[2].([]func(*sqs.Options))
+		run(args[0].(context.Context), args[1].(*sqs.ListQueuesInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mocksqsClient_ListQueues_Call) Return(listQueuesOutput *sqs.ListQueuesOutput, err error) *mocksqsClient_ListQueues_Call {
+	_c.Call.Return(listQueuesOutput, err)
+	return _c
+}
+
+func (_c *mocksqsClient_ListQueues_Call) RunAndReturn(run func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)) *mocksqsClient_ListQueues_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendMessage provides a mock function for the type mocksqsClient
 func (_mock *mocksqsClient) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
 	var tmpRet mock.Arguments
@@ -0,0 +1,38 @@
+package responsechannel
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/google/uuid"
+
+	"github.com/anicoll/unicom/internal/outbox"
+)
+
+// NewOutboxSQSService returns an SQSService which writes each notification to
+// an outbox instead of sending it, standing in for SQS when running locally.
+func NewOutboxSQSService(box *outbox.Outbox) *SQSService {
+	return NewSQSService(&outboxSQSClient{
+		outbox: box,
+	})
+}
+
+type outboxSQSClient struct {
+	outbox *outbox.Outbox
+}
+
+func (c *outboxSQSClient) SendMessage(ctx context.Context, params *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	id := uuid.NewString()
+	delivery := outbox.NewDelivery(aws.ToString(params.QueueUrl), []byte(aws.ToString(params.MessageBody)))
+	if err := c.outbox.WriteJSON(outbox.SQS, id, delivery); err != nil {
+		return nil, err
+	}
+	return &sqs.SendMessageOutput{
+		MessageId: aws.String(id),
+	}, nil
+}
+
+func (c *outboxSQSClient) ListQueues(context.Context, *sqs.ListQueuesInput, ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+	return &sqs.ListQueuesOutput{}, nil
+}
@@ -0,0 +1,48 @@
+package responsechannel_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/outbox"
+	"github.com/anicoll/unicom/internal/responsechannel"
+)
+
+type OutboxSQSTestSuite struct {
+	suite.Suite
+}
+
+func TestOutboxSQSTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxSQSTestSuite))
+}
+
+func (s *OutboxSQSTestSuite) TestSend_WritesDelivery() {
+	box, err := outbox.New(s.T().TempDir())
+	s.Require().NoError(err)
+	service := responsechannel.NewOutboxSQSService(box)
+	s.Require().NoError(service.Check(context.Background()))
+
+	id, err := service.Send(context.Background(), model.ResponseChannelRequest{
+		WorkflowId: "wf-1",
+		Url:        "https://sqs.eu-west-1.amazonaws.com/123/billing",
+		Status:     "SUCCESS",
+	})
+	s.Require().NoError(err)
+
+	messages, err := box.List()
+	s.Require().NoError(err)
+	s.Require().Len(messages, 1)
+	s.Equal(*id, messages[0].ID)
+	s.Equal(outbox.SQS, messages[0].Kind)
+
+	raw, err := box.Read(messages[0].Name)
+	s.Require().NoError(err)
+	var delivery outbox.Delivery
+	s.Require().NoError(json.Unmarshal(raw, &delivery))
+	s.Equal("https://sqs.eu-west-1.amazonaws.com/123/billing", delivery.URL)
+	s.Contains(string(delivery.Body), `"wf-1"`)
+}
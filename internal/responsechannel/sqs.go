@@ -6,13 +6,20 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 
 	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/failure"
 	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/tracing"
 )
 
 type sqsClient interface {
 	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)
 }
 
 type SQSService struct {
@@ -25,7 +32,14 @@ func NewSQSService(client sqsClient) *SQSService {
 	}
 }
 
-func (s *SQSService) Send(ctx context.Context, req model.ResponseChannelRequest) (*string, error) {
+func (s *SQSService) Send(ctx context.Context, req model.ResponseChannelRequest) (id *string, err error) {
+	ctx, span := tracing.StartProviderSpan(ctx, "sqs", "sqs",
+		semconv.MessagingSystemAWSSQS,
+		semconv.MessagingOperationTypeSend,
+		semconv.MessagingDestinationName(req.Url),
+	)
+	defer func() { tracing.EndProviderSpan(span, id, err) }()
+
 	data, err := json.Marshal(pb.ResponseEvent{
 		WorkflowId:   req.WorkflowId,
 		Status:       req.Status,
@@ -39,9 +53,41 @@ func (s *SQSService) Send(ctx context.Context, req model.ResponseChannelRequest)
 		QueueUrl:               aws.String(req.Url),
 		MessageBody:            aws.String(string(data)),
 		MessageDeduplicationId: aws.String(req.WorkflowId),
+		MessageAttributes:      traceAttributes(ctx),
 	})
 	if err != nil {
-		return nil, err
+		return nil, failure.FromAWS("sqs", err)
 	}
 	return response.MessageId, nil
 }
+
+// Check verifies SQS can be reached with the worker's credentials. Queues are
+// given by each communication, so this lists at most one queue rather than
+// checking any in particular.
+func (s *SQSService) Check(ctx context.Context) error {
+	_, err := s.sqsClient.ListQueues(ctx, &sqs.ListQueuesInput{
+		MaxResults: aws.Int32(1),
+	})
+	if err != nil {
+		return failure.FromAWS("sqs", err)
+	}
+	return nil
+}
+
+// traceAttributes returns the message attributes, such as traceparent, which
+// let the consumer carry on the communication's trace.
+func traceAttributes(ctx context.Context) map[string]types.MessageAttributeValue {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	attributes := make(map[string]types.MessageAttributeValue, len(carrier))
+	for key, value := range carrier {
+		attributes[key] = types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	return attributes
+}
@@ -2,14 +2,20 @@ package responsechannel_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/smithy-go"
 	"github.com/bxcodec/faker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
+	"github.com/anicoll/unicom/internal/failure"
 	"github.com/anicoll/unicom/internal/model"
 	"github.com/anicoll/unicom/internal/responsechannel"
 )
@@ -40,7 +46,7 @@ func (s *ServiceTestSuite) TestService_SendMessage_Success() {
 	err = faker.FakeData(&expectedResponse)
 	s.NoError(err)
 
-	s.sqsClient.EXPECT().SendMessage(ctx, mock.Anything, mock.Anything).Return(&expectedResponse, nil)
+	s.sqsClient.EXPECT().SendMessage(mock.Anything, mock.Anything, mock.Anything).Return(&expectedResponse, nil)
 
 	assert := assert.New(s.T())
 
@@ -49,3 +55,29 @@ func (s *ServiceTestSuite) TestService_SendMessage_Success() {
 	assert.Equal(expectedResponse.MessageId, resp)
 	assert.NoError(err)
 }
+
+func (s *ServiceTestSuite) TestService_SendMessage_PropagatesTrace() {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "activity")
+	defer span.End()
+
+	s.sqsClient.EXPECT().SendMessage(mock.Anything, mock.MatchedBy(func(input *sqs.SendMessageInput) bool {
+		traceparent, ok := input.MessageAttributes["traceparent"]
+		return ok && strings.Contains(*traceparent.StringValue, span.SpanContext().TraceID().String())
+	}), mock.Anything).Return(&sqs.SendMessageOutput{}, nil)
+
+	_, err := s.svc.Send(ctx, model.ResponseChannelRequest{Url: "https://sqs.eu-west-2.amazonaws.com/1/queue", WorkflowId: "workflow-id"})
+	s.NoError(err)
+}
+
+func (s *ServiceTestSuite) TestService_Check() {
+	s.sqsClient.EXPECT().ListQueues(mock.Anything, mock.MatchedBy(func(input *sqs.ListQueuesInput) bool {
+		return *input.MaxResults == 1
+	}), mock.Anything).Return(&sqs.ListQueuesOutput{}, nil).Once()
+	s.NoError(s.svc.Check(context.Background()))
+
+	s.sqsClient.EXPECT().ListQueues(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, &smithy.GenericAPIError{Code: "InvalidClientTokenId"}).Once()
+	err := s.svc.Check(context.Background())
+	s.Equal(failure.Auth, failure.KindOf(err))
+}
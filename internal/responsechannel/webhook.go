@@ -3,27 +3,58 @@ package responsechannel
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 
 	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/failure"
 	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/tracing"
 )
 
+// SignatureHeader holds the signature of a webhook's body, see Sign.
+const SignatureHeader = "X-Unicom-Signature"
+
 type WebhookService struct {
 	client *http.Client
+	secret string
 }
 
-func NewWebhookService(client *http.Client) *WebhookService {
+// NewWebhookService returns a WebhookService posting with client. Webhooks
+// are signed with secret, unless it is empty.
+func NewWebhookService(client *http.Client, secret string) *WebhookService {
 	return &WebhookService{
 		client: client,
+		secret: secret,
 	}
 }
 
-func (s *WebhookService) Send(ctx context.Context, req model.ResponseChannelRequest) (*string, error) {
+// Sign returns the signature header of a webhook body sent at a time:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">". The
+// time is signed too, so receivers can reject replayed webhooks.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *WebhookService) Send(ctx context.Context, req model.ResponseChannelRequest) (id *string, err error) {
+	ctx, span := tracing.StartProviderSpan(ctx, "webhook", "webhook")
+	defer func() { tracing.EndProviderSpan(span, id, err) }()
+
 	data, err := json.Marshal(pb.ResponseEvent{
 		WorkflowId:   req.WorkflowId,
 		Status:       req.Status,
@@ -34,31 +65,30 @@ func (s *WebhookService) Send(ctx context.Context, req model.ResponseChannelRequ
 	}
 
 	reader := bytes.NewBuffer(data)
-	httpRequest, err := http.NewRequest(http.MethodPost, req.Url, reader)
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, req.Url, reader)
 	if err != nil {
 		return nil, err
 	}
 
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		httpRequest.Header.Set(SignatureHeader, Sign(s.secret, time.Now(), data))
+	}
+
+	// Lets the receiver carry on the communication's trace.
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(httpRequest.Header))
+
 	response, err := s.client.Do(httpRequest)
 	if err != nil {
-		return nil, err
+		return nil, failure.New(failure.ProviderOutage, "webhook", err)
 	}
 	defer func() { _ = response.Body.Close() }()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
 
 	if response.StatusCode >= 200 && response.StatusCode < 300 {
 		return nil, nil
 	}
 
-	body, err := io.ReadAll(response.Body)
-	// b, err := ioutil.ReadAll(resp.Body)  Go.1.15 and earlier
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	// TODO: parse error and return it
-	fmt.Println("BODY")
-	// Do I need to parse anything here?
-	fmt.Println(string(body))
-
-	return nil, nil
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+	return nil, failure.FromHTTPStatus("webhook", response.StatusCode, strings.TrimSpace(string(body)))
 }
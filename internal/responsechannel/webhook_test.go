@@ -0,0 +1,97 @@
+package responsechannel_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/responsechannel"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+func (s *WebhookTestSuite) TestWebhookService_Send_Success() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPost, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	svc := responsechannel.NewWebhookService(srv.Client(), "")
+	_, err := svc.Send(context.Background(), model.ResponseChannelRequest{Url: srv.URL, WorkflowId: "workflow-id"})
+	s.NoError(err)
+}
+
+func (s *WebhookTestSuite) TestWebhookService_Send_PropagatesTrace() {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "activity")
+	defer span.End()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Contains(r.Header.Get("traceparent"), span.SpanContext().TraceID().String())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	svc := responsechannel.NewWebhookService(srv.Client(), "")
+	_, err := svc.Send(ctx, model.ResponseChannelRequest{Url: srv.URL, WorkflowId: "workflow-id"})
+	s.NoError(err)
+}
+
+func (s *WebhookTestSuite) TestWebhookService_Send_ClassifiesErrors() {
+	statusCode := http.StatusNotFound
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", statusCode)
+	}))
+	defer srv.Close()
+
+	svc := responsechannel.NewWebhookService(srv.Client(), "")
+	_, err := svc.Send(context.Background(), model.ResponseChannelRequest{Url: srv.URL})
+	s.Equal(failure.InvalidRequest, failure.KindOf(err))
+
+	statusCode = http.StatusServiceUnavailable
+	_, err = svc.Send(context.Background(), model.ResponseChannelRequest{Url: srv.URL})
+	s.Equal(failure.ProviderOutage, failure.KindOf(err))
+}
+
+func (s *WebhookTestSuite) TestWebhookService_Send_Signed() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		s.NoError(err)
+		signature := r.Header.Get(responsechannel.SignatureHeader)
+		timestamp, _, _ := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+		at, err := strconv.ParseInt(timestamp, 10, 64)
+		s.Require().NoError(err)
+		s.Equal(responsechannel.Sign("secret", time.Unix(at, 0), body), signature)
+		s.NotEqual(responsechannel.Sign("other", time.Unix(at, 0), body), signature)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	svc := responsechannel.NewWebhookService(srv.Client(), "secret")
+	_, err := svc.Send(context.Background(), model.ResponseChannelRequest{Url: srv.URL, WorkflowId: "workflow-id"})
+	s.NoError(err)
+}
+
+func (s *WebhookTestSuite) TestSign() {
+	s.Equal("t=1700000000,v1=7b56e7c23c072cde5147102b0195e062de37f3a7adeec0ff9e6c4faafc9971b6",
+		responsechannel.Sign("secret", time.Unix(1700000000, 0), []byte(`{"workflow_id":"workflow-id"}`)))
+}
@@ -0,0 +1,238 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package sandbox_test
+
+import (
+	"context"
+
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/push"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// newMockemailService creates a new instance of mockemailService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockemailService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockemailService {
+	mock := &mockemailService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockemailService is an autogenerated mock type for the emailService type
+type mockemailService struct {
+	mock.Mock
+}
+
+type mockemailService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockemailService) EXPECT() *mockemailService_Expecter {
+	return &mockemailService_Expecter{mock: &_m.Mock}
+}
+
+// Send provides a mock function for the type mockemailService
+func (_mock *mockemailService) Send(ctx context.Context, args email.Request) (*string, error) {
+	ret := _mock.Called(ctx, args)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 *string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, email.Request) (*string, error)); ok {
+		return returnFunc(ctx, args)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, email.Request) *string); ok {
+		r0 = returnFunc(ctx, args)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, email.Request) error); ok {
+		r1 = returnFunc(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockemailService_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type mockemailService_Send_Call struct {
+	*mock.Call
+}
+
+// Send is a helper method to define mock.On call
+//   - ctx
+//   - args
+func (_e *mockemailService_Expecter) Send(ctx interface{}, args interface{}) *mockemailService_Send_Call {
+	return &mockemailService_Send_Call{Call: _e.mock.On("Send", ctx, args)}
+}
+
+func (_c *mockemailService_Send_Call) Run(run func(ctx context.Context, args email.Request)) *mockemailService_Send_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(email.Request))
+	})
+	return _c
+}
+
+func (_c *mockemailService_Send_Call) Return(s *string, err error) *mockemailService_Send_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockemailService_Send_Call) RunAndReturn(run func(ctx context.Context, args email.Request) (*string, error)) *mockemailService_Send_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockpushService creates a new instance of mockpushService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockpushService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockpushService {
+	mock := &mockpushService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockpushService is an autogenerated mock type for the pushService type
+type mockpushService struct {
+	mock.Mock
+}
+
+type mockpushService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockpushService) EXPECT() *mockpushService_Expecter {
+	return &mockpushService_Expecter{mock: &_m.Mock}
+}
+
+// Render provides a mock function for the type mockpushService
+func (_mock *mockpushService) Render(ctx context.Context, args push.Notification) ([]byte, error) {
+	ret := _mock.Called(ctx, args)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Render")
+	}
+
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) ([]byte, error)); ok {
+		return returnFunc(ctx, args)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) []byte); ok {
+		r0 = returnFunc(ctx, args)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, push.Notification) error); ok {
+		r1 = returnFunc(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockpushService_Render_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Render'
+type mockpushService_Render_Call struct {
+	*mock.Call
+}
+
+// Render is a helper method to define mock.On call
+//   - ctx
+//   - args
+func (_e *mockpushService_Expecter) Render(ctx interface{}, args interface{}) *mockpushService_Render_Call {
+	return &mockpushService_Render_Call{Call: _e.mock.On("Render", ctx, args)}
+}
+
+func (_c *mockpushService_Render_Call) Run(run func(ctx context.Context, args push.Notification)) *mockpushService_Render_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(push.Notification))
+	})
+	return _c
+}
+
+func (_c *mockpushService_Render_Call) Return(bytes []byte, err error) *mockpushService_Render_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *mockpushService_Render_Call) RunAndReturn(run func(ctx context.Context, args push.Notification) ([]byte, error)) *mockpushService_Render_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Send provides a mock function for the type mockpushService
+func (_mock *mockpushService) Send(ctx context.Context, args push.Notification) (*string, error) {
+	ret := _mock.Called(ctx, args)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 *string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) (*string, error)); ok {
+		return returnFunc(ctx, args)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, push.Notification) *string); ok {
+		r0 = returnFunc(ctx, args)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, push.Notification) error); ok {
+		r1 = returnFunc(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockpushService_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type mockpushService_Send_Call struct {
+	*mock.Call
+}
+
+// Send is a helper method to define mock.On call
+//   - ctx
+//   - args
+func (_e *mockpushService_Expecter) Send(ctx interface{}, args interface{}) *mockpushService_Send_Call {
+	return &mockpushService_Send_Call{Call: _e.mock.On("Send", ctx, args)}
+}
+
+func (_c *mockpushService_Send_Call) Run(run func(ctx context.Context, args push.Notification)) *mockpushService_Send_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(push.Notification))
+	})
+	return _c
+}
+
+func (_c *mockpushService_Send_Call) Return(s *string, err error) *mockpushService_Send_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockpushService_Send_Call) RunAndReturn(run func(ctx context.Context, args push.Notification) (*string, error)) *mockpushService_Send_Call {
+	_c.Call.Return(run)
+	return _c
+}
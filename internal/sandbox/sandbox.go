@@ -0,0 +1,116 @@
+// Package sandbox simulates the providers of domains in sandbox mode, see
+// domain.Sandbox. The email and push services are wrapped so a sandboxed
+// communication goes through the same workflow, persistence and response
+// channels as any other, only its send is simulated.
+package sandbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/push"
+)
+
+// provider is the provider name simulated failures are attributed to.
+const provider = "sandbox"
+
+type emailService interface {
+	Send(ctx context.Context, args email.Request) (*string, error)
+}
+
+type pushService interface {
+	Send(ctx context.Context, args push.Notification) (*string, error)
+	Render(ctx context.Context, args push.Notification) ([]byte, error)
+}
+
+// EmailService sends the emails of sandboxed domains to the simulator and the
+// rest to the wrapped service.
+type EmailService struct {
+	next    emailService
+	domains *domain.Registry
+}
+
+func NewEmailService(next emailService, domains *domain.Registry) *EmailService {
+	return &EmailService{
+		next:    next,
+		domains: domains,
+	}
+}
+
+// Send simulates the send when the request's domain is in sandbox mode. The
+// first recipient with an outcome other than success decides the outcome.
+func (s *EmailService) Send(ctx context.Context, args email.Request) (*string, error) {
+	config := s.domains.For(args.Domain).Sandbox
+	if !config.IsEnabled() {
+		return s.next.Send(ctx, args)
+	}
+	outcome := domain.SandboxSuccess
+	recipient := ""
+	for _, addresses := range [][]string{args.ToAddresses, args.CcAddresses, args.BccAddresses} {
+		for _, address := range addresses {
+			if parsed, err := email.ParseAddress(address); err == nil {
+				address = parsed.Address
+			}
+			if o := config.OutcomeFor(address); o != domain.SandboxSuccess && outcome == domain.SandboxSuccess {
+				outcome, recipient = o, address
+			}
+		}
+	}
+	return simulate(ctx, config, outcome, recipient)
+}
+
+// PushService sends the notifications of sandboxed domains to the simulator
+// and the rest to the wrapped service.
+type PushService struct {
+	next    pushService
+	domains *domain.Registry
+}
+
+func NewPushService(next pushService, domains *domain.Registry) *PushService {
+	return &PushService{
+		next:    next,
+		domains: domains,
+	}
+}
+
+// Send simulates the send when the notification's domain is in sandbox mode.
+func (s *PushService) Send(ctx context.Context, args push.Notification) (*string, error) {
+	config := s.domains.For(args.Domain).Sandbox
+	if !config.IsEnabled() {
+		return s.next.Send(ctx, args)
+	}
+	return simulate(ctx, config, config.OutcomeFor(args.ExternalCustomerId), args.ExternalCustomerId)
+}
+
+// Render renders with the wrapped service, as rendering never sends.
+func (s *PushService) Render(ctx context.Context, args push.Notification) ([]byte, error) {
+	return s.next.Render(ctx, args)
+}
+
+// simulate waits for the configured latency, then returns the result outcome
+// stands for: a message ID on success, or the failure a provider would have
+// returned.
+func simulate(ctx context.Context, config domain.Sandbox, outcome, recipient string) (*string, error) {
+	if config.Latency > 0 {
+		timer := time.NewTimer(config.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	switch outcome {
+	case domain.SandboxBounce:
+		return nil, failure.Errorf(failure.InvalidRecipient, provider, "simulated bounce of %s", recipient)
+	case domain.SandboxThrottle:
+		return nil, failure.Errorf(failure.Throttled, provider, "simulated throttling of %s", recipient)
+	}
+	id := provider + "-" + uuid.NewString()
+	return &id, nil
+}
@@ -0,0 +1,114 @@
+package sandbox_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/push"
+	"github.com/anicoll/unicom/internal/sandbox"
+)
+
+type SandboxTestSuite struct {
+	suite.Suite
+	email   *mockemailService
+	push    *mockpushService
+	domains *domain.Registry
+}
+
+func TestSandboxTestSuite(t *testing.T) {
+	suite.Run(t, new(SandboxTestSuite))
+}
+
+func (s *SandboxTestSuite) SetupTest() {
+	enabled := true
+	s.email = newMockemailService(s.T())
+	s.push = newMockpushService(s.T())
+	s.domains = domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"onboarding": {Sandbox: domain.Sandbox{
+			Enabled: &enabled,
+			Recipients: map[string]string{
+				"bounce@example.com": domain.SandboxBounce,
+				"customer-throttled": domain.SandboxThrottle,
+			},
+		}},
+	})
+}
+
+func (s *SandboxTestSuite) TestEmailSend_NotSandboxed() {
+	id := "ses-1"
+	req := email.Request{Domain: "billing", ToAddresses: []string{"jane@example.com"}}
+	s.email.EXPECT().Send(mock.Anything, req).Once().Return(&id, nil)
+
+	got, err := sandbox.NewEmailService(s.email, s.domains).Send(context.Background(), req)
+	s.NoError(err)
+	s.Equal(&id, got)
+}
+
+func (s *SandboxTestSuite) TestEmailSend_Success() {
+	id, err := sandbox.NewEmailService(s.email, s.domains).Send(context.Background(), email.Request{
+		Domain:      "onboarding",
+		ToAddresses: []string{"Jane <jane@example.com>"},
+	})
+	s.NoError(err)
+	s.Require().NotNil(id)
+	s.Contains(*id, "sandbox-")
+}
+
+func (s *SandboxTestSuite) TestEmailSend_Bounce() {
+	_, err := sandbox.NewEmailService(s.email, s.domains).Send(context.Background(), email.Request{
+		Domain:       "onboarding",
+		ToAddresses:  []string{"jane@example.com"},
+		BccAddresses: []string{"Bounce <bounce@example.com>"},
+	})
+	s.Equal(failure.InvalidRecipient, failure.KindOf(err))
+	s.False(failure.KindOf(err).Retryable())
+	s.ErrorContains(err, "bounce@example.com")
+}
+
+func (s *SandboxTestSuite) TestPushSend_Throttle() {
+	_, err := sandbox.NewPushService(s.push, s.domains).Send(context.Background(), push.Notification{
+		Domain:             "onboarding",
+		ExternalCustomerId: "customer-throttled",
+	})
+	s.Equal(failure.Throttled, failure.KindOf(err))
+	s.True(failure.KindOf(err).Retryable())
+}
+
+func (s *SandboxTestSuite) TestPushSend_NotSandboxed() {
+	id := "onesignal-1"
+	notification := push.Notification{Domain: "billing", ExternalCustomerId: "customer-1"}
+	s.push.EXPECT().Send(mock.Anything, notification).Once().Return(&id, nil)
+
+	got, err := sandbox.NewPushService(s.push, s.domains).Send(context.Background(), notification)
+	s.NoError(err)
+	s.Equal(&id, got)
+}
+
+func (s *SandboxTestSuite) TestPushRender_Delegates() {
+	notification := push.Notification{Domain: "onboarding", ExternalCustomerId: "customer-1"}
+	s.push.EXPECT().Render(mock.Anything, notification).Once().Return([]byte(`{}`), nil)
+
+	payload, err := sandbox.NewPushService(s.push, s.domains).Render(context.Background(), notification)
+	s.NoError(err)
+	s.Equal([]byte(`{}`), payload)
+}
+
+func (s *SandboxTestSuite) TestSend_Latency() {
+	enabled := true
+	s.domains.Replace(domain.NewRegistry(domain.Config{Sandbox: domain.Sandbox{
+		Enabled: &enabled,
+		Latency: time.Hour,
+	}}, nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := sandbox.NewPushService(s.push, s.domains).Send(ctx, push.Notification{Domain: "billing"})
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -7,6 +7,8 @@ package server_test
 import (
 	"context"
 
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/erasure"
 	"github.com/anicoll/unicom/internal/model"
 	"github.com/anicoll/unicom/internal/workflows"
 	mock "github.com/stretchr/testify/mock"
@@ -39,6 +41,52 @@ func (_m *mocktemporalClient) EXPECT() *mocktemporalClient_Expecter {
 	return &mocktemporalClient_Expecter{mock: &_m.Mock}
 }
 
+// CancelWorkflow provides a mock function for the type mocktemporalClient
+func (_mock *mocktemporalClient) CancelWorkflow(ctx context.Context, workflowId string) error {
+	ret := _mock.Called(ctx, workflowId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelWorkflow")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, workflowId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mocktemporalClient_CancelWorkflow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelWorkflow'
+type mocktemporalClient_CancelWorkflow_Call struct {
+	*mock.Call
+}
+
+// CancelWorkflow is a helper method to define mock.On call
+//   - ctx
+//   - workflowId
+func (_e *mocktemporalClient_Expecter) CancelWorkflow(ctx interface{}, workflowId interface{}) *mocktemporalClient_CancelWorkflow_Call {
+	return &mocktemporalClient_CancelWorkflow_Call{Call: _e.mock.On("CancelWorkflow", ctx, workflowId)}
+}
+
+func (_c *mocktemporalClient_CancelWorkflow_Call) Run(run func(ctx context.Context, workflowId string)) *mocktemporalClient_CancelWorkflow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *mocktemporalClient_CancelWorkflow_Call) Return(err error) *mocktemporalClient_CancelWorkflow_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mocktemporalClient_CancelWorkflow_Call) RunAndReturn(run func(ctx context.Context, workflowId string) error) *mocktemporalClient_CancelWorkflow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetWorkflowResult provides a mock function for the type mocktemporalClient
 func (_mock *mocktemporalClient) GetWorkflowResult(ctx context.Context, workflowId string) error {
 	ret := _mock.Called(ctx, workflowId)
@@ -140,6 +188,64 @@ func (_c *mocktemporalClient_GetWorkflowStatus_Call) RunAndReturn(run func(ctx c
 	return _c
 }
 
+// RenderCommunication provides a mock function for the type mocktemporalClient
+func (_mock *mocktemporalClient) RenderCommunication(ctx context.Context, workflowId string, req workflows.RenderRequest) (*workflows.Rendered, error) {
+	ret := _mock.Called(ctx, workflowId, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenderCommunication")
+	}
+
+	var r0 *workflows.Rendered
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, workflows.RenderRequest) (*workflows.Rendered, error)); ok {
+		return returnFunc(ctx, workflowId, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, workflows.RenderRequest) *workflows.Rendered); ok {
+		r0 = returnFunc(ctx, workflowId, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*workflows.Rendered)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, workflows.RenderRequest) error); ok {
+		r1 = returnFunc(ctx, workflowId, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mocktemporalClient_RenderCommunication_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenderCommunication'
+type mocktemporalClient_RenderCommunication_Call struct {
+	*mock.Call
+}
+
+// RenderCommunication is a helper method to define mock.On call
+//   - ctx
+//   - workflowId
+//   - req
+func (_e *mocktemporalClient_Expecter) RenderCommunication(ctx interface{}, workflowId interface{}, req interface{}) *mocktemporalClient_RenderCommunication_Call {
+	return &mocktemporalClient_RenderCommunication_Call{Call: _e.mock.On("RenderCommunication", ctx, workflowId, req)}
+}
+
+func (_c *mocktemporalClient_RenderCommunication_Call) Run(run func(ctx context.Context, workflowId string, req workflows.RenderRequest)) *mocktemporalClient_RenderCommunication_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(workflows.RenderRequest))
+	})
+	return _c
+}
+
+func (_c *mocktemporalClient_RenderCommunication_Call) Return(rendered *workflows.Rendered, err error) *mocktemporalClient_RenderCommunication_Call {
+	_c.Call.Return(rendered, err)
+	return _c
+}
+
+func (_c *mocktemporalClient_RenderCommunication_Call) RunAndReturn(run func(ctx context.Context, workflowId string, req workflows.RenderRequest) (*workflows.Rendered, error)) *mocktemporalClient_RenderCommunication_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartCommunicationWorkflow provides a mock function for the type mocktemporalClient
 func (_mock *mocktemporalClient) StartCommunicationWorkflow(ctx context.Context, req workflows.Request, workflowId string) error {
 	ret := _mock.Called(ctx, req, workflowId)
@@ -187,6 +293,61 @@ func (_c *mocktemporalClient_StartCommunicationWorkflow_Call) RunAndReturn(run f
 	return _c
 }
 
+// WorkflowStarted provides a mock function for the type mocktemporalClient
+func (_mock *mocktemporalClient) WorkflowStarted(ctx context.Context, workflowId string) (bool, error) {
+	ret := _mock.Called(ctx, workflowId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WorkflowStarted")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, workflowId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, workflowId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, workflowId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mocktemporalClient_WorkflowStarted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WorkflowStarted'
+type mocktemporalClient_WorkflowStarted_Call struct {
+	*mock.Call
+}
+
+// WorkflowStarted is a helper method to define mock.On call
+//   - ctx
+//   - workflowId
+func (_e *mocktemporalClient_Expecter) WorkflowStarted(ctx interface{}, workflowId interface{}) *mocktemporalClient_WorkflowStarted_Call {
+	return &mocktemporalClient_WorkflowStarted_Call{Call: _e.mock.On("WorkflowStarted", ctx, workflowId)}
+}
+
+func (_c *mocktemporalClient_WorkflowStarted_Call) Run(run func(ctx context.Context, workflowId string)) *mocktemporalClient_WorkflowStarted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *mocktemporalClient_WorkflowStarted_Call) Return(b bool, err error) *mocktemporalClient_WorkflowStarted_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *mocktemporalClient_WorkflowStarted_Call) RunAndReturn(run func(ctx context.Context, workflowId string) (bool, error)) *mocktemporalClient_WorkflowStarted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockpostgres creates a new instance of mockpostgres. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockpostgres(t interface {
@@ -259,3 +420,543 @@ func (_c *mockpostgres_CreateCommunication_Call) RunAndReturn(run func(ctx conte
 	_c.Call.Return(run)
 	return _c
 }
+
+// DeleteDeviceToken provides a mock function for the type mockpostgres
+func (_mock *mockpostgres) DeleteDeviceToken(ctx context.Context, tokenType model.DeviceTokenType, token string) error {
+	ret := _mock.Called(ctx, tokenType, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeviceToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.DeviceTokenType, string) error); ok {
+		r0 = returnFunc(ctx, tokenType, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockpostgres_DeleteDeviceToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeviceToken'
+type mockpostgres_DeleteDeviceToken_Call struct {
+	*mock.Call
+}
+
+// DeleteDeviceToken is a helper method to define mock.On call
+//   - ctx
+//   - tokenType
+//   - token
+func (_e *mockpostgres_Expecter) DeleteDeviceToken(ctx interface{}, tokenType interface{}, token interface{}) *mockpostgres_DeleteDeviceToken_Call {
+	return &mockpostgres_DeleteDeviceToken_Call{Call: _e.mock.On("DeleteDeviceToken", ctx, tokenType, token)}
+}
+
+func (_c *mockpostgres_DeleteDeviceToken_Call) Run(run func(ctx context.Context, tokenType model.DeviceTokenType, token string)) *mockpostgres_DeleteDeviceToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.DeviceTokenType), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockpostgres_DeleteDeviceToken_Call) Return(err error) *mockpostgres_DeleteDeviceToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockpostgres_DeleteDeviceToken_Call) RunAndReturn(run func(ctx context.Context, tokenType model.DeviceTokenType, token string) error) *mockpostgres_DeleteDeviceToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAuditEvents provides a mock function for the type mockpostgres
+func (_mock *mockpostgres) ListAuditEvents(ctx context.Context, filter model.AuditFilter, limit int) ([]model.AuditEvent, error) {
+	ret := _mock.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditEvents")
+	}
+
+	var r0 []model.AuditEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.AuditFilter, int) ([]model.AuditEvent, error)); ok {
+		return returnFunc(ctx, filter, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.AuditFilter, int) []model.AuditEvent); ok {
+		r0 = returnFunc(ctx, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.AuditEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.AuditFilter, int) error); ok {
+		r1 = returnFunc(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockpostgres_ListAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAuditEvents'
+type mockpostgres_ListAuditEvents_Call struct {
+	*mock.Call
+}
+
+// ListAuditEvents is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - limit
+func (_e *mockpostgres_Expecter) ListAuditEvents(ctx interface{}, filter interface{}, limit interface{}) *mockpostgres_ListAuditEvents_Call {
+	return &mockpostgres_ListAuditEvents_Call{Call: _e.mock.On("ListAuditEvents", ctx, filter, limit)}
+}
+
+func (_c *mockpostgres_ListAuditEvents_Call) Run(run func(ctx context.Context, filter model.AuditFilter, limit int)) *mockpostgres_ListAuditEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.AuditFilter), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *mockpostgres_ListAuditEvents_Call) Return(auditEvents []model.AuditEvent, err error) *mockpostgres_ListAuditEvents_Call {
+	_c.Call.Return(auditEvents, err)
+	return _c
+}
+
+func (_c *mockpostgres_ListAuditEvents_Call) RunAndReturn(run func(ctx context.Context, filter model.AuditFilter, limit int) ([]model.AuditEvent, error)) *mockpostgres_ListAuditEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCommunications provides a mock function for the type mockpostgres
+func (_mock *mockpostgres) ListCommunications(ctx context.Context, filter model.CommunicationFilter, limit int) ([]model.Communication, error) {
+	ret := _mock.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCommunications")
+	}
+
+	var r0 []model.Communication
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.CommunicationFilter, int) ([]model.Communication, error)); ok {
+		return returnFunc(ctx, filter, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.CommunicationFilter, int) []model.Communication); ok {
+		r0 = returnFunc(ctx, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Communication)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.CommunicationFilter, int) error); ok {
+		r1 = returnFunc(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockpostgres_ListCommunications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCommunications'
+type mockpostgres_ListCommunications_Call struct {
+	*mock.Call
+}
+
+// ListCommunications is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - limit
+func (_e *mockpostgres_Expecter) ListCommunications(ctx interface{}, filter interface{}, limit interface{}) *mockpostgres_ListCommunications_Call {
+	return &mockpostgres_ListCommunications_Call{Call: _e.mock.On("ListCommunications", ctx, filter, limit)}
+}
+
+func (_c *mockpostgres_ListCommunications_Call) Run(run func(ctx context.Context, filter model.CommunicationFilter, limit int)) *mockpostgres_ListCommunications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.CommunicationFilter), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *mockpostgres_ListCommunications_Call) Return(communications []model.Communication, err error) *mockpostgres_ListCommunications_Call {
+	_c.Call.Return(communications, err)
+	return _c
+}
+
+func (_c *mockpostgres_ListCommunications_Call) RunAndReturn(run func(ctx context.Context, filter model.CommunicationFilter, limit int) ([]model.Communication, error)) *mockpostgres_ListCommunications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertDeviceToken provides a mock function for the type mockpostgres
+func (_mock *mockpostgres) UpsertDeviceToken(ctx context.Context, token model.DeviceToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertDeviceToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.DeviceToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockpostgres_UpsertDeviceToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertDeviceToken'
+type mockpostgres_UpsertDeviceToken_Call struct {
+	*mock.Call
+}
+
+// UpsertDeviceToken is a helper method to define mock.On call
+//   - ctx
+//   - token
+func (_e *mockpostgres_Expecter) UpsertDeviceToken(ctx interface{}, token interface{}) *mockpostgres_UpsertDeviceToken_Call {
+	return &mockpostgres_UpsertDeviceToken_Call{Call: _e.mock.On("UpsertDeviceToken", ctx, token)}
+}
+
+func (_c *mockpostgres_UpsertDeviceToken_Call) Run(run func(ctx context.Context, token model.DeviceToken)) *mockpostgres_UpsertDeviceToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.DeviceToken))
+	})
+	return _c
+}
+
+func (_c *mockpostgres_UpsertDeviceToken_Call) Return(err error) *mockpostgres_UpsertDeviceToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockpostgres_UpsertDeviceToken_Call) RunAndReturn(run func(ctx context.Context, token model.DeviceToken) error) *mockpostgres_UpsertDeviceToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockpayloadOffloader creates a new instance of mockpayloadOffloader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockpayloadOffloader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockpayloadOffloader {
+	mock := &mockpayloadOffloader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockpayloadOffloader is an autogenerated mock type for the payloadOffloader type
+type mockpayloadOffloader struct {
+	mock.Mock
+}
+
+type mockpayloadOffloader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockpayloadOffloader) EXPECT() *mockpayloadOffloader_Expecter {
+	return &mockpayloadOffloader_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function for the type mockpayloadOffloader
+func (_mock *mockpayloadOffloader) Delete(ctx context.Context, req email.Request) error {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, email.Request) error); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockpayloadOffloader_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockpayloadOffloader_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx
+//   - req
+func (_e *mockpayloadOffloader_Expecter) Delete(ctx interface{}, req interface{}) *mockpayloadOffloader_Delete_Call {
+	return &mockpayloadOffloader_Delete_Call{Call: _e.mock.On("Delete", ctx, req)}
+}
+
+func (_c *mockpayloadOffloader_Delete_Call) Run(run func(ctx context.Context, req email.Request)) *mockpayloadOffloader_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(email.Request))
+	})
+	return _c
+}
+
+func (_c *mockpayloadOffloader_Delete_Call) Return(err error) *mockpayloadOffloader_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockpayloadOffloader_Delete_Call) RunAndReturn(run func(ctx context.Context, req email.Request) error) *mockpayloadOffloader_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Offload provides a mock function for the type mockpayloadOffloader
+func (_mock *mockpayloadOffloader) Offload(ctx context.Context, communicationID string, req *email.Request) error {
+	ret := _mock.Called(ctx, communicationID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Offload")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *email.Request) error); ok {
+		r0 = returnFunc(ctx, communicationID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockpayloadOffloader_Offload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Offload'
+type mockpayloadOffloader_Offload_Call struct {
+	*mock.Call
+}
+
+// Offload is a helper method to define mock.On call
+//   - ctx
+//   - communicationID
+//   - req
+func (_e *mockpayloadOffloader_Expecter) Offload(ctx interface{}, communicationID interface{}, req interface{}) *mockpayloadOffloader_Offload_Call {
+	return &mockpayloadOffloader_Offload_Call{Call: _e.mock.On("Offload", ctx, communicationID, req)}
+}
+
+func (_c *mockpayloadOffloader_Offload_Call) Run(run func(ctx context.Context, communicationID string, req *email.Request)) *mockpayloadOffloader_Offload_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*email.Request))
+	})
+	return _c
+}
+
+func (_c *mockpayloadOffloader_Offload_Call) Return(err error) *mockpayloadOffloader_Offload_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockpayloadOffloader_Offload_Call) RunAndReturn(run func(ctx context.Context, communicationID string, req *email.Request) error) *mockpayloadOffloader_Offload_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockeraser creates a new instance of mockeraser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockeraser(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockeraser {
+	mock := &mockeraser{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockeraser is an autogenerated mock type for the eraser type
+type mockeraser struct {
+	mock.Mock
+}
+
+type mockeraser_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockeraser) EXPECT() *mockeraser_Expecter {
+	return &mockeraser_Expecter{mock: &_m.Mock}
+}
+
+// EraseRecipient provides a mock function for the type mockeraser
+func (_mock *mockeraser) EraseRecipient(ctx context.Context, recipient erasure.Recipient, dryRun bool) (model.Erasure, error) {
+	ret := _mock.Called(ctx, recipient, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseRecipient")
+	}
+
+	var r0 model.Erasure
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, erasure.Recipient, bool) (model.Erasure, error)); ok {
+		return returnFunc(ctx, recipient, dryRun)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, erasure.Recipient, bool) model.Erasure); ok {
+		r0 = returnFunc(ctx, recipient, dryRun)
+	} else {
+		r0 = ret.Get(0).(model.Erasure)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, erasure.Recipient, bool) error); ok {
+		r1 = returnFunc(ctx, recipient, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockeraser_EraseRecipient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EraseRecipient'
+type mockeraser_EraseRecipient_Call struct {
+	*mock.Call
+}
+
+// EraseRecipient is a helper method to define mock.On call
+//   - ctx
+//   - recipient
+//   - dryRun
+func (_e *mockeraser_Expecter) EraseRecipient(ctx interface{}, recipient interface{}, dryRun interface{}) *mockeraser_EraseRecipient_Call {
+	return &mockeraser_EraseRecipient_Call{Call: _e.mock.On("EraseRecipient", ctx, recipient, dryRun)}
+}
+
+func (_c *mockeraser_EraseRecipient_Call) Run(run func(ctx context.Context, recipient erasure.Recipient, dryRun bool)) *mockeraser_EraseRecipient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(erasure.Recipient), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *mockeraser_EraseRecipient_Call) Return(erasure1 model.Erasure, err error) *mockeraser_EraseRecipient_Call {
+	_c.Call.Return(erasure1, err)
+	return _c
+}
+
+func (_c *mockeraser_EraseRecipient_Call) RunAndReturn(run func(ctx context.Context, recipient erasure.Recipient, dryRun bool) (model.Erasure, error)) *mockeraser_EraseRecipient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockcontactDirectory creates a new instance of mockcontactDirectory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockcontactDirectory(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockcontactDirectory {
+	mock := &mockcontactDirectory{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockcontactDirectory is an autogenerated mock type for the contactDirectory type
+type mockcontactDirectory struct {
+	mock.Mock
+}
+
+type mockcontactDirectory_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockcontactDirectory) EXPECT() *mockcontactDirectory_Expecter {
+	return &mockcontactDirectory_Expecter{mock: &_m.Mock}
+}
+
+// Lookup provides a mock function for the type mockcontactDirectory
+func (_mock *mockcontactDirectory) Lookup(ctx context.Context, externalCustomerID string) (model.Contact, error) {
+	ret := _mock.Called(ctx, externalCustomerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lookup")
+	}
+
+	var r0 model.Contact
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (model.Contact, error)); ok {
+		return returnFunc(ctx, externalCustomerID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) model.Contact); ok {
+		r0 = returnFunc(ctx, externalCustomerID)
+	} else {
+		r0 = ret.Get(0).(model.Contact)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, externalCustomerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockcontactDirectory_Lookup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lookup'
+type mockcontactDirectory_Lookup_Call struct {
+	*mock.Call
+}
+
+// Lookup is a helper method to define mock.On call
+//   - ctx
+//   - externalCustomerID
+func (_e *mockcontactDirectory_Expecter) Lookup(ctx interface{}, externalCustomerID interface{}) *mockcontactDirectory_Lookup_Call {
+	return &mockcontactDirectory_Lookup_Call{Call: _e.mock.On("Lookup", ctx, externalCustomerID)}
+}
+
+func (_c *mockcontactDirectory_Lookup_Call) Run(run func(ctx context.Context, externalCustomerID string)) *mockcontactDirectory_Lookup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *mockcontactDirectory_Lookup_Call) Return(contact model.Contact, err error) *mockcontactDirectory_Lookup_Call {
+	_c.Call.Return(contact, err)
+	return _c
+}
+
+func (_c *mockcontactDirectory_Lookup_Call) RunAndReturn(run func(ctx context.Context, externalCustomerID string) (model.Contact, error)) *mockcontactDirectory_Lookup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Upsert provides a mock function for the type mockcontactDirectory
+func (_mock *mockcontactDirectory) Upsert(ctx context.Context, contact model.Contact) error {
+	ret := _mock.Called(ctx, contact)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Contact) error); ok {
+		r0 = returnFunc(ctx, contact)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockcontactDirectory_Upsert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Upsert'
+type mockcontactDirectory_Upsert_Call struct {
+	*mock.Call
+}
+
+// Upsert is a helper method to define mock.On call
+//   - ctx
+//   - contact
+func (_e *mockcontactDirectory_Expecter) Upsert(ctx interface{}, contact interface{}) *mockcontactDirectory_Upsert_Call {
+	return &mockcontactDirectory_Upsert_Call{Call: _e.mock.On("Upsert", ctx, contact)}
+}
+
+func (_c *mockcontactDirectory_Upsert_Call) Run(run func(ctx context.Context, contact model.Contact)) *mockcontactDirectory_Upsert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.Contact))
+	})
+	return _c
+}
+
+func (_c *mockcontactDirectory_Upsert_Call) Return(err error) *mockcontactDirectory_Upsert_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockcontactDirectory_Upsert_Call) RunAndReturn(run func(ctx context.Context, contact model.Contact) error) *mockcontactDirectory_Upsert_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/anicoll/unicom/internal/domain"
+)
+
+// rateLimiters hold the token bucket of each rate limited domain.
+type rateLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newRateLimiters() *rateLimiters {
+	return &rateLimiters{
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// allow reports whether the named domain may request another communication
+// under limit. A domain's bucket follows its limit as the domain config is
+// reloaded.
+func (r *rateLimiters) allow(name string, limit domain.RateLimit) bool {
+	if !limit.Enabled() {
+		return true
+	}
+	r.mu.Lock()
+	limiter, ok := r.limiters[name]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit.PerSecond), limit.BurstOrDefault())
+		r.limiters[name] = limiter
+	}
+	if limiter.Limit() != rate.Limit(limit.PerSecond) {
+		limiter.SetLimit(rate.Limit(limit.PerSecond))
+	}
+	if limiter.Burst() != limit.BurstOrDefault() {
+		limiter.SetBurst(limit.BurstOrDefault())
+	}
+	r.mu.Unlock()
+	return limiter.Allow()
+}
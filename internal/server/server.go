@@ -2,43 +2,101 @@ package server
 
 import (
 	"context"
+	"errors"
 	"io"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/contact"
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/erasure"
+	"github.com/anicoll/unicom/internal/metrics"
 	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/push"
 	"github.com/anicoll/unicom/internal/workflows"
 )
 
 type temporalClient interface {
 	StartCommunicationWorkflow(ctx context.Context, req workflows.Request, workflowId string) error
+	WorkflowStarted(ctx context.Context, workflowId string) (bool, error)
 	GetWorkflowStatus(ctx context.Context, req workflows.StatusRequest) (string, error)
 	GetWorkflowResult(ctx context.Context, workflowId string) error
+	CancelWorkflow(ctx context.Context, workflowId string) error
+	RenderCommunication(ctx context.Context, workflowId string, req workflows.RenderRequest) (*workflows.Rendered, error)
 }
 
 type postgres interface {
 	CreateCommunication(ctx context.Context, comm *model.Communication) error
+	UpsertDeviceToken(ctx context.Context, token model.DeviceToken) error
+	DeleteDeviceToken(ctx context.Context, tokenType model.DeviceTokenType, token string) error
+	ListAuditEvents(ctx context.Context, filter model.AuditFilter, limit int) ([]model.AuditEvent, error)
+	ListCommunications(ctx context.Context, filter model.CommunicationFilter, limit int) ([]model.Communication, error)
 }
 
+type payloadOffloader interface {
+	Offload(ctx context.Context, communicationID string, req *email.Request) error
+	Delete(ctx context.Context, req email.Request) error
+}
+
+type eraser interface {
+	EraseRecipient(ctx context.Context, recipient erasure.Recipient, dryRun bool) (model.Erasure, error)
+}
+
+type contactDirectory interface {
+	Lookup(ctx context.Context, externalCustomerID string) (model.Contact, error)
+	Upsert(ctx context.Context, contact model.Contact) error
+}
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+
+	defaultCommunicationPageSize = 100
+	maxCommunicationPageSize     = 1000
+)
+
 type Server struct {
-	tc     temporalClient
-	db     postgres
-	logger *zap.Logger
+	tc          temporalClient
+	db          postgres
+	domains     *domain.Registry
+	attachments attachment.Config
+	payloads    payloadOffloader
+	eraser      eraser
+	contacts    contactDirectory
+	metrics     client.MetricsHandler
+	limits      *rateLimiters
+	logger      *zap.Logger
 }
 
 var _ pb.UnicomServiceServer = (*Server)(nil)
 
-// New creates a new Server instance with the provided logger, temporal client, and database.
-func New(logger *zap.Logger, tc temporalClient, db postgres) *Server {
+// New creates a new Server instance with the provided logger, temporal client, database, domain configuration,
+// attachment limits, the offloader moving large email content out of workflow history, the eraser of recipients' data,
+// the directory customers' contact details are resolved from and the handler business metrics are recorded with.
+func New(logger *zap.Logger, tc temporalClient, db postgres, domains *domain.Registry, attachments attachment.Config, payloads payloadOffloader, eraser eraser, contacts contactDirectory, metricsHandler client.MetricsHandler) *Server {
 	return &Server{
-		tc:     tc,
-		logger: logger,
-		db:     db,
+		tc:          tc,
+		logger:      logger,
+		db:          db,
+		domains:     domains,
+		attachments: attachments,
+		payloads:    payloads,
+		eraser:      eraser,
+		contacts:    contacts,
+		metrics:     metricsHandler,
+		limits:      newRateLimiters(),
 	}
 }
 
@@ -55,21 +113,34 @@ func (s *Server) sendCommunication(ctx context.Context, req *pb.SendCommunicatio
 		s.logger.Error(err.Error(), zap.Error(err))
 		return nil, err
 	}
-	emailRequest, err := mapEmailRequestIn(req.GetEmail())
-	if err != nil {
+	if limit := s.domains.For(req.GetDomain()).RateLimit; !s.limits.allow(req.GetDomain(), limit) {
+		err = status.Errorf(codes.ResourceExhausted, "domain %s exceeded its rate limit of %g communications per second", req.GetDomain(), limit.PerSecond)
 		s.logger.Error(err.Error(), zap.Error(err))
-		return nil, status.Error(codes.InvalidArgument, "unable to map email request")
+		return nil, err
+	}
+	req, customer, emailRequest, pushRequest, err := s.mapContentIn(ctx, req)
+	if err != nil {
+		return nil, err
 	}
-	pushRequest := mapPushNotificationIn(req.GetPush())
 
 	workflowRequest := workflows.Request{
 		EmailRequest:     emailRequest,
 		PushRequest:      pushRequest,
-		SleepDuration:    time.Duration(0),
 		ResponseRequests: make([]*workflows.ResponseRequest, 0, len(req.GetResponseChannels())),
 		Domain:           req.GetDomain(),
 	}
+	workflowRequest.Priority, err = mapPriorityIn(req.GetPriority(), s.domains.For(req.GetDomain()))
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "invalid domain priority: "+err.Error())
+	}
 	if req.IsAsync {
+		if len(req.GetResponseChannels()) == 0 {
+			workflowRequest.ResponseRequests = mapDomainResponseChannels(s.domains.For(req.GetDomain()).ResponseChannels)
+		}
 		for _, responseChannal := range req.GetResponseChannels() {
 			switch responseChannal.Schema {
 			case pb.ResponseSchema_RESPONSE_SCHEMA_HTTP:
@@ -92,23 +163,67 @@ func (s *Server) sendCommunication(ctx context.Context, req *pb.SendCommunicatio
 				})
 			}
 		}
-		now := time.Now()
-		requestTime := req.SendAt.AsTime()
-		if requestTime.After(now) {
-			workflowRequest.SleepDuration = requestTime.Sub(now)
-		}
 	}
-
-	workflowId := uuid.NewString()
-	err = s.db.CreateCommunication(ctx, mapWorkflowRequestToModel(workflowId, workflowRequest))
+	now := time.Now()
+	sendAt := now
+	if req.IsAsync && req.GetSendAt().AsTime().After(now) {
+		// the workflow waits for this time by its own clock, so it is right on replay.
+		sendAt = req.GetSendAt().AsTime()
+		workflowRequest.SendAt = &sendAt
+	}
+	workflowRequest.Windows, err = s.deliveryWindows(ctx, req, workflowRequest.Priority, customer, sendAt)
 	if err != nil {
 		s.logger.Error(err.Error(), zap.Error(err))
-		return nil, status.Error(codes.Internal, "unable to save communication")
+		return nil, err
 	}
-	err = s.tc.StartCommunicationWorkflow(ctx, workflowRequest, workflowId)
+
+	policy, err := s.domains.For(req.GetDomain()).Delivery.Resolve(mapDeliveryPolicyIn(req.GetDeliveryPolicy()), sendAt)
 	if err != nil {
 		s.logger.Error(err.Error(), zap.Error(err))
-		return nil, status.Error(codes.Internal, "unable to send request")
+		return nil, status.Error(codes.InvalidArgument, "invalid delivery_policy: "+err.Error())
+	}
+	workflowRequest.Policy = policy
+
+	workflowId := uuid.NewString()
+	if req.GetIdempotencyKey() != "" {
+		// retries of the request start the workflow of the first, which temporal only runs once.
+		workflowId = idempotentWorkflowID(req.GetDomain(), req.GetIdempotencyKey())
+	}
+	communication := mapWorkflowRequestToModel(workflowId, workflowRequest)
+	err = s.db.CreateCommunication(ctx, communication)
+	retried := errors.Is(err, model.ErrCommunicationExists) && req.GetIdempotencyKey() != ""
+	if err != nil && !retried {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to save communication")
+	}
+	started := false
+	if retried {
+		// the workflow of the first attempt owns its content, and deletes it once sent.
+		started, err = s.tc.WorkflowStarted(ctx, workflowId)
+		if err != nil {
+			s.logger.Error(err.Error(), zap.Error(err))
+			return nil, status.Error(codes.Internal, "unable to send request")
+		}
+	}
+	if !started {
+		// a retry still starts the workflow, in case the first attempt failed before it did.
+		err = s.payloads.Offload(ctx, workflowId, workflowRequest.EmailRequest)
+		if err != nil {
+			s.logger.Error(err.Error(), zap.Error(err))
+			return nil, status.Error(codes.Internal, "unable to store email content")
+		}
+		err = s.tc.StartCommunicationWorkflow(ctx, workflowRequest, workflowId)
+		if err != nil {
+			s.logger.Error(err.Error(), zap.Error(err))
+			return nil, status.Error(codes.Internal, "unable to send request")
+		}
+	}
+	if !retried {
+		s.metrics.WithTags(map[string]string{
+			metrics.DomainTag:   workflowRequest.Domain,
+			metrics.ChannelTag:  metrics.Channel(communication.Type),
+			metrics.PriorityTag: string(workflowRequest.Priority),
+		}).Counter(metrics.CommunicationsRequested).Inc(1)
 	}
 	if !req.IsAsync {
 		err = s.tc.GetWorkflowResult(ctx, workflowId)
@@ -122,6 +237,90 @@ func (s *Server) sendCommunication(ctx context.Context, req *pb.SendCommunicatio
 	}, nil
 }
 
+// mapContentIn maps the email or push notification of a request, redirecting a test send to the domain's seed list
+// and resolving the recipients of a request for a customer. Returns the request as resolved and the customer's
+// contact, if it was looked up. Errors are logged and returned as status errors.
+func (s *Server) mapContentIn(ctx context.Context, req *pb.SendCommunicationRequest) (*pb.SendCommunicationRequest, *model.Contact, *email.Request, *push.Notification, error) {
+	var err error
+	if req.GetTest() {
+		req, err = mapTestSendIn(req, s.domains.For(req.GetDomain()).SeedList)
+		if err != nil {
+			s.logger.Error(err.Error(), zap.Error(err))
+			return nil, nil, nil, nil, err
+		}
+	}
+	req, customer, err := s.resolveCustomer(ctx, req)
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, nil, nil, nil, err
+	}
+	emailRequest, err := mapEmailRequestIn(req.GetDomain(), req.GetEmail(), s.attachments)
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		if _, ok := status.FromError(err); ok {
+			return nil, nil, nil, nil, err
+		}
+		return nil, nil, nil, nil, status.Error(codes.InvalidArgument, "unable to map email request")
+	}
+	if emailRequest != nil {
+		err = s.domains.VerifySender(req.GetDomain(), req.GetEmail().GetFromAddress())
+		if err != nil {
+			s.logger.Error(err.Error(), zap.Error(err))
+			return nil, nil, nil, nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+	return req, customer, emailRequest, mapPushNotificationIn(req.GetDomain(), req.GetPush()), nil
+}
+
+// RenderCommunication renders a request's email as its MIME message, or its push notification as the body of the
+// domain's push provider, on a worker. Nothing is sent or recorded.
+func (s *Server) RenderCommunication(ctx context.Context, req *pb.SendCommunicationRequest) (*pb.RenderCommunicationResponse, error) {
+	err := s.validateRequest(req)
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, err
+	}
+	_, _, emailRequest, pushRequest, err := s.mapContentIn(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	workflowId := "render-" + uuid.NewString()
+	err = s.payloads.Offload(ctx, workflowId, emailRequest)
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to store email content")
+	}
+	if emailRequest != nil {
+		defer func() {
+			// the render has finished or been given up on, so its content is no longer needed.
+			if err := s.payloads.Delete(context.WithoutCancel(ctx), *emailRequest); err != nil {
+				s.logger.Error(err.Error(), zap.Error(err))
+			}
+		}()
+	}
+	rendered, err := s.tc.RenderCommunication(ctx, workflowId, workflows.RenderRequest{
+		EmailRequest: emailRequest,
+		PushRequest:  pushRequest,
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		var noWorker *serviceerror.FailedPrecondition
+		if errors.As(err, &noWorker) {
+			return nil, status.Error(codes.FailedPrecondition, "unable to render communication: "+noWorker.Error())
+		}
+		var appErr *temporal.ApplicationError
+		if errors.As(err, &appErr) && appErr.NonRetryable() {
+			// e.g. a customer without devices, an attachment which couldn't be fetched, or a result too large to return.
+			return nil, status.Error(codes.FailedPrecondition, "unable to render communication: "+appErr.Error())
+		}
+		return nil, status.Error(codes.Internal, "unable to render communication")
+	}
+	return &pb.RenderCommunicationResponse{
+		EmailMime:   rendered.Email,
+		PushPayload: rendered.Push,
+	}, nil
+}
+
 // GetStatus handles the gRPC GetStatus request and returns the workflow status for the given ID.
 func (s *Server) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
 	workflowStatus, err := s.tc.GetWorkflowStatus(ctx, workflows.StatusRequest{
@@ -133,9 +332,52 @@ func (s *Server) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.G
 	}
 	return &pb.GetStatusResponse{
 		Status: string(workflowStatus),
+		Id:     req.GetId(),
 	}, nil
 }
 
+// ListCommunications returns a page of the communications matching the request's filters, newest first.
+func (s *Server) ListCommunications(ctx context.Context, req *pb.ListCommunicationsRequest) (*pb.ListCommunicationsResponse, error) {
+	filter, err := mapCommunicationFilterIn(req)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultCommunicationPageSize
+	}
+	pageSize = min(pageSize, maxCommunicationPageSize)
+
+	comms, err := s.db.ListCommunications(ctx, filter, pageSize)
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to list communications")
+	}
+	resp := &pb.ListCommunicationsResponse{Communications: mapCommunicationsOut(comms)}
+	if len(comms) == pageSize {
+		resp.NextPageToken = communicationPageToken(comms[len(comms)-1])
+	}
+	return resp, nil
+}
+
+// CancelCommunication cancels the workflow of a communication. A communication waiting to be sent is
+// recorded as cancelled, one already sent is left as it is.
+func (s *Server) CancelCommunication(ctx context.Context, req *pb.CancelCommunicationRequest) (*pb.CancelCommunicationResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	err := s.tc.CancelWorkflow(ctx, req.GetId())
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil, status.Errorf(codes.NotFound, "communication %s not found or already finished", req.GetId())
+		}
+		return nil, status.Error(codes.Internal, "unable to cancel communication")
+	}
+	return &pb.CancelCommunicationResponse{Id: req.GetId()}, nil
+}
+
 // StreamCommunication handles the gRPC streaming endpoint for communication requests.
 // Receives requests from the stream, processes them, and sends back responses.
 func (s *Server) StreamCommunication(stream pb.UnicomService_StreamCommunicationServer) error {
@@ -150,10 +392,12 @@ func (s *Server) StreamCommunication(stream pb.UnicomService_StreamCommunication
 		}
 
 		response, err := s.sendCommunication(ctx, &pb.SendCommunicationRequest{
-			IsAsync: false,
-			Domain:  in.GetDomain(),
-			Email:   in.GetEmail(),
-			Push:    in.GetPush(),
+			IsAsync:            false,
+			Domain:             in.GetDomain(),
+			Email:              in.GetEmail(),
+			Push:               in.GetPush(),
+			ExternalCustomerId: in.GetExternalCustomerId(),
+			Priority:           in.GetPriority(),
 		})
 		if err != nil {
 			return err
@@ -167,6 +411,273 @@ func (s *Server) StreamCommunication(stream pb.UnicomService_StreamCommunication
 	}
 }
 
+// RegisterDevice stores a device token for the direct FCM and APNs push providers.
+func (s *Server) RegisterDevice(ctx context.Context, req *pb.RegisterDeviceRequest) (*pb.RegisterDeviceResponse, error) {
+	tokenType, err := mapDeviceTokenTypeIn(req.GetTokenType())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetExternalCustomerId() == "" || req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "external_customer_id and token are required")
+	}
+	err = s.db.UpsertDeviceToken(ctx, model.DeviceToken{
+		ExternalCustomerID: req.GetExternalCustomerId(),
+		Type:               tokenType,
+		Token:              req.GetToken(),
+		Locale:             req.GetLocale(),
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to register device")
+	}
+	return &pb.RegisterDeviceResponse{}, nil
+}
+
+// UnregisterDevice removes a device token.
+func (s *Server) UnregisterDevice(ctx context.Context, req *pb.UnregisterDeviceRequest) (*pb.UnregisterDeviceResponse, error) {
+	tokenType, err := mapDeviceTokenTypeIn(req.GetTokenType())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	err = s.db.DeleteDeviceToken(ctx, tokenType, req.GetToken())
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to unregister device")
+	}
+	return &pb.UnregisterDeviceResponse{}, nil
+}
+
+// DeleteRecipientData erases the data of a recipient identified by their email addresses and/or external customer ID.
+// A dry run reports what would be erased without changing anything.
+func (s *Server) DeleteRecipientData(ctx context.Context, req *pb.DeleteRecipientDataRequest) (*pb.DeleteRecipientDataResponse, error) {
+	if len(req.GetEmailAddresses()) == 0 && req.GetExternalCustomerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email_addresses or external_customer_id is required")
+	}
+	addresses := make([]string, len(req.GetEmailAddresses()))
+	for i, address := range req.GetEmailAddresses() {
+		parsed, err := email.ParseAddress(address)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "email_addresses contains an invalid email address %q", address)
+		}
+		addresses[i] = parsed.Address
+	}
+	erased, err := s.eraser.EraseRecipient(ctx, erasure.Recipient{
+		EmailAddresses:     addresses,
+		ExternalCustomerID: req.GetExternalCustomerId(),
+	}, req.GetDryRun())
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to erase recipient data")
+	}
+	return &pb.DeleteRecipientDataResponse{
+		ErasureId:        erased.ID,
+		Communications:   int32(erased.Communications),
+		ResponseChannels: int32(erased.ResponseChannels),
+		DeviceTokens:     int32(erased.DeviceTokens),
+		Contacts:         int32(erased.Contacts),
+		DryRun:           erased.DryRun,
+	}, nil
+}
+
+// ListAuditEvents returns a page of the audit log matching the request's filters, newest first.
+func (s *Server) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	filter, err := mapAuditFilterIn(req)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultAuditPageSize
+	}
+	pageSize = min(pageSize, maxAuditPageSize)
+
+	events, err := s.db.ListAuditEvents(ctx, filter, pageSize)
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to list audit events")
+	}
+	resp := &pb.ListAuditEventsResponse{Events: mapAuditEventsOut(events)}
+	if len(events) == pageSize {
+		resp.NextPageToken = strconv.FormatInt(events[len(events)-1].ID, 10)
+	}
+	return resp, nil
+}
+
+// ExportAuditEvents streams every audit event matching the request's filters, newest first.
+func (s *Server) ExportAuditEvents(req *pb.ListAuditEventsRequest, stream pb.UnicomService_ExportAuditEventsServer) error {
+	filter, err := mapAuditFilterIn(req)
+	if err != nil {
+		return err
+	}
+	for {
+		events, err := s.db.ListAuditEvents(stream.Context(), filter, maxAuditPageSize)
+		if err != nil {
+			s.logger.Error(err.Error(), zap.Error(err))
+			return status.Error(codes.Internal, "unable to list audit events")
+		}
+		for _, event := range mapAuditEventsOut(events) {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+		if len(events) < maxAuditPageSize {
+			return nil
+		}
+		filter.Before = events[len(events)-1].ID
+	}
+}
+
+// resolveCustomer fills in the recipients a request leaves out from the contact of its external customer ID:
+// the email addresses of an email without recipients and the customer of a push. The request is copied
+// rather than changed. The contact is returned when it had to be looked up.
+func (s *Server) resolveCustomer(ctx context.Context, req *pb.SendCommunicationRequest) (*pb.SendCommunicationRequest, *model.Contact, error) {
+	if req.GetExternalCustomerId() == "" {
+		return req, nil, nil
+	}
+	req = proto.Clone(req).(*pb.SendCommunicationRequest)
+	if push := req.GetPush(); push != nil && push.GetExternalCustomerId() == "" {
+		push.ExternalCustomerId = req.GetExternalCustomerId()
+	}
+	mail := req.GetEmail()
+	if mail == nil || mail.GetToAddress() != "" || len(mail.GetTo()) > 0 {
+		return req, nil, nil
+	}
+	found, err := s.lookupContact(ctx, req.GetExternalCustomerId())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(found.EmailAddresses) == 0 {
+		return nil, nil, status.Error(codes.FailedPrecondition, "contact has no email addresses")
+	}
+	for _, address := range found.EmailAddresses {
+		mail.To = append(mail.To, &pb.EmailAddress{Address: address})
+	}
+	return req, &found, nil
+}
+
+// deliveryWindows returns the windows an async request must be delivered in: its own delivery window and the
+// domain's quiet hours, both in the recipient's time zone when it is known. Urgent and critical requests have none.
+func (s *Server) deliveryWindows(ctx context.Context, req *pb.SendCommunicationRequest, priority model.Priority, customer *model.Contact, now time.Time) ([]model.DeliveryWindow, error) {
+	quietHours := s.domains.For(req.GetDomain()).QuietHours
+	if !req.GetIsAsync() || req.GetUrgent() || priority == model.PriorityCritical || (req.GetDeliveryWindow() == nil && !quietHours.Enabled()) {
+		return nil, nil
+	}
+	timezone := s.customerTimezone(ctx, req.GetExternalCustomerId(), customer)
+
+	var windows []model.DeliveryWindow
+	if window := req.GetDeliveryWindow(); window != nil {
+		windowTimezone := window.GetTimezone()
+		if windowTimezone == "" {
+			windowTimezone = timezone
+		}
+		parsed, err := domain.ParseWindow(window.GetStart(), window.GetEnd(), windowTimezone)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid delivery_window: "+err.Error())
+		}
+		windows = append(windows, parsed)
+	}
+	if quietHours.Enabled() {
+		parsed, err := quietHours.Window(timezone)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "invalid quiet hours: "+err.Error())
+		}
+		windows = append(windows, parsed)
+	}
+	if _, err := model.NextDeliveryTime(now, windows); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "delivery_window never falls outside the domain's quiet hours")
+	}
+	return windows, nil
+}
+
+// customerTimezone returns the time zone of a customer's contact, or empty when it isn't known.
+func (s *Server) customerTimezone(ctx context.Context, externalCustomerID string, customer *model.Contact) string {
+	if customer == nil && externalCustomerID != "" {
+		found, err := s.contacts.Lookup(ctx, externalCustomerID)
+		if err != nil && !errors.Is(err, contact.ErrNotFound) {
+			s.logger.Warn("unable to look up the customer's time zone", zap.Error(err))
+		}
+		customer = &found
+	}
+	if customer == nil {
+		return ""
+	}
+	// A provider may know of time zones this build doesn't.
+	if _, err := time.LoadLocation(customer.Timezone); err != nil {
+		return ""
+	}
+	return customer.Timezone
+}
+
+// lookupContact looks up a contact, mapping a missing contact to NotFound.
+func (s *Server) lookupContact(ctx context.Context, externalCustomerID string) (model.Contact, error) {
+	found, err := s.contacts.Lookup(ctx, externalCustomerID)
+	if errors.Is(err, contact.ErrNotFound) {
+		return found, status.Errorf(codes.NotFound, "no contact for external_customer_id %q", externalCustomerID)
+	}
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return found, status.Error(codes.Unavailable, "unable to look up contact")
+	}
+	return found, nil
+}
+
+// UpsertContact creates or replaces a contact in the contact directory.
+func (s *Server) UpsertContact(ctx context.Context, req *pb.UpsertContactRequest) (*pb.UpsertContactResponse, error) {
+	if err := s.upsertContact(ctx, req.GetContact()); err != nil {
+		return nil, err
+	}
+	return &pb.UpsertContactResponse{}, nil
+}
+
+// ImportContacts creates or replaces every contact received on the stream, stopping at the first invalid one.
+func (s *Server) ImportContacts(stream pb.UnicomService_ImportContactsServer) error {
+	imported := int32(0)
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.ImportContactsResponse{Imported: imported})
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.upsertContact(stream.Context(), in); err != nil {
+			return err
+		}
+		imported++
+	}
+}
+
+func (s *Server) upsertContact(ctx context.Context, req *pb.Contact) error {
+	mapped, err := mapContactIn(req)
+	if err != nil {
+		return err
+	}
+	err = s.contacts.Upsert(ctx, mapped)
+	if errors.Is(err, contact.ErrReadOnly) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		s.logger.Error(err.Error(), zap.Error(err))
+		return status.Error(codes.Internal, "unable to save contact")
+	}
+	return nil
+}
+
+// GetContact looks up a contact through the configured contact provider.
+func (s *Server) GetContact(ctx context.Context, req *pb.GetContactRequest) (*pb.Contact, error) {
+	if req.GetExternalCustomerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "external_customer_id is required")
+	}
+	found, err := s.lookupContact(ctx, req.GetExternalCustomerId())
+	if err != nil {
+		return nil, err
+	}
+	return mapContactOut(found), nil
+}
+
 // validateRequest checks that the SendCommunicationRequest contains exactly one notification medium (email or push).
 // Returns an error if the request is invalid.
 func (s *Server) validateRequest(req *pb.SendCommunicationRequest) error {
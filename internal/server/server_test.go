@@ -2,21 +2,49 @@ package server_test
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/contact"
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/erasure"
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/payload"
 	"github.com/anicoll/unicom/internal/server"
 	"github.com/stretchr/testify/assert"
 	mock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally/v4"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	sdktally "go.temporal.io/sdk/contrib/tally"
+	"go.temporal.io/sdk/temporal"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/anicoll/unicom/internal/workflows"
 )
 
 type ServerUnitTestSuite struct {
 	suite.Suite
-	svc *server.Server
-	tc  *mocktemporalClient
-	db  *mockpostgres
+	svc      *server.Server
+	tc       *mocktemporalClient
+	db       *mockpostgres
+	eraser   *mockeraser
+	contacts *mockcontactDirectory
+	scope    tally.TestScope
+	metrics  client.MetricsHandler
 }
 
 func TestServerUnitTestSuite(t *testing.T) {
@@ -25,7 +53,7 @@ func TestServerUnitTestSuite(t *testing.T) {
 
 func (s *ServerUnitTestSuite) TestSendCommunication_Email_Success() {
 	req := &pb.SendCommunicationRequest{
-		Email:   &pb.EmailRequest{ToAddress: "test@example.com", Subject: "Test", Html: "Hello"},
+		Email:   &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com", Subject: "Test", Html: "Hello"},
 		IsAsync: false,
 		Domain:  "test-domain",
 	}
@@ -36,6 +64,10 @@ func (s *ServerUnitTestSuite) TestSendCommunication_Email_Success() {
 	resp, err := s.svc.SendCommunication(context.Background(), req)
 	s.NoError(err)
 	s.NotEmpty(resp.Id)
+
+	requested := s.scope.Snapshot().Counters()["communications_requested+channel=email,domain=test-domain,priority=transactional"]
+	s.Require().NotNil(requested)
+	s.Equal(int64(1), requested.Value())
 }
 
 func (s *ServerUnitTestSuite) TestSendCommunication_Push_Success() {
@@ -57,7 +89,7 @@ func (s *ServerUnitTestSuite) TestSendCommunication_Push_Success() {
 
 func (s *ServerUnitTestSuite) TestSendCommunication_InvalidRequest_MultipleMediums() {
 	req := &pb.SendCommunicationRequest{
-		Email: &pb.EmailRequest{ToAddress: "test@example.com"},
+		Email: &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com"},
 		Push:  &pb.PushRequest{IdempotencyKey: "Push"},
 	}
 	resp, err := s.svc.SendCommunication(context.Background(), req)
@@ -74,9 +106,71 @@ func (s *ServerUnitTestSuite) TestSendCommunication_InvalidRequest_NoMedium() {
 	s.Contains(err.Error(), "invalid request must include any request medium")
 }
 
+func (s *ServerUnitTestSuite) TestSendCommunication_IdempotencyKey() {
+	req := &pb.SendCommunicationRequest{
+		Push:           &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+		IsAsync:        true,
+		Domain:         "test-domain",
+		IdempotencyKey: "order-1",
+	}
+	var ids []string
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(model.ErrCommunicationExists)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.Anything, mock.Anything).Run(func(_ context.Context, _ workflows.Request, workflowId string) {
+		ids = append(ids, workflowId)
+	}).Return(nil).Once()
+	s.tc.EXPECT().WorkflowStarted(mock.Anything, mock.Anything).Run(func(_ context.Context, workflowId string) {
+		ids = append(ids, workflowId)
+	}).Return(true, nil).Once()
+
+	first, err := s.svc.SendCommunication(context.Background(), req)
+	s.Require().NoError(err)
+	retry, err := s.svc.SendCommunication(context.Background(), req)
+	s.Require().NoError(err)
+	s.Equal(first.Id, retry.Id)
+	s.Equal([]string{first.Id, first.Id}, ids)
+
+	requested := s.scope.Snapshot().Counters()["communications_requested+channel=push,domain=test-domain,priority=transactional"]
+	s.Require().NotNil(requested)
+	s.Equal(int64(1), requested.Value(), "a retry isn't counted again")
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_IdempotencyKeyPerDomain() {
+	var ids []string
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Twice().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.Anything, mock.Anything).Run(func(_ context.Context, _ workflows.Request, workflowId string) {
+		ids = append(ids, workflowId)
+	}).Return(nil).Twice()
+
+	for _, domain := range []string{"billing", "marketing"} {
+		_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+			Push:           &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+			IsAsync:        true,
+			Domain:         domain,
+			IdempotencyKey: "order-1",
+		})
+		s.Require().NoError(err)
+	}
+	s.Require().Len(ids, 2)
+	s.NotEqual(ids[0], ids[1])
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_DuplicateWithoutIdempotencyKey() {
+	req := &pb.SendCommunicationRequest{
+		Push:    &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+		IsAsync: true,
+		Domain:  "test-domain",
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(model.ErrCommunicationExists)
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.Nil(resp)
+	s.Equal(codes.Internal, status.Code(err))
+}
+
 func (s *ServerUnitTestSuite) TestSendCommunication_DBError() {
 	req := &pb.SendCommunicationRequest{
-		Email:   &pb.EmailRequest{ToAddress: "test@example.com"},
+		Email:   &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com"},
 		IsAsync: false,
 		Domain:  "test-domain",
 	}
@@ -90,7 +184,7 @@ func (s *ServerUnitTestSuite) TestSendCommunication_DBError() {
 
 func (s *ServerUnitTestSuite) TestSendCommunication_WorkflowError() {
 	req := &pb.SendCommunicationRequest{
-		Email:   &pb.EmailRequest{ToAddress: "test@example.com"},
+		Email:   &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com"},
 		IsAsync: false,
 		Domain:  "test-domain",
 	}
@@ -105,7 +199,7 @@ func (s *ServerUnitTestSuite) TestSendCommunication_WorkflowError() {
 
 func (s *ServerUnitTestSuite) TestSendCommunication_GetWorkflowResultError() {
 	req := &pb.SendCommunicationRequest{
-		Email:   &pb.EmailRequest{ToAddress: "test@example.com"},
+		Email:   &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com"},
 		IsAsync: false,
 		Domain:  "test-domain",
 	}
@@ -118,10 +212,863 @@ func (s *ServerUnitTestSuite) TestSendCommunication_GetWorkflowResultError() {
 	s.Error(err)
 	s.Contains(err.Error(), "unable to get request result")
 }
+func (s *ServerUnitTestSuite) TestSendCommunication_AppliesDomainDeliveryPolicy() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"billing": {Delivery: domain.Delivery{MaxAttempts: 20, ExpireAfter: time.Hour}},
+	}), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	req := &pb.SendCommunicationRequest{
+		Email:          &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com"},
+		IsAsync:        true,
+		Domain:         "billing",
+		DeliveryPolicy: &pb.DeliveryPolicy{AttemptTimeout: durationpb.New(time.Minute)},
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.Policy.MaxAttempts == 20 &&
+			req.Policy.AttemptTimeout == time.Minute &&
+			req.Policy.BackoffCoefficient == domain.DefaultDelivery.BackoffCoefficient &&
+			req.Policy.ExpireAt != nil
+	}), mock.Anything).Once().Return(nil)
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+	s.NotEmpty(resp.Id)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_InvalidDeliveryPolicy() {
+	req := &pb.SendCommunicationRequest{
+		Email:          &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com"},
+		IsAsync:        false,
+		Domain:         "test-domain",
+		DeliveryPolicy: &pb.DeliveryPolicy{MaxAttempts: 1000},
+	}
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.Nil(resp)
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.Contains(err.Error(), "max_attempts")
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_MultipleRecipients() {
+	req := &pb.SendCommunicationRequest{
+		Email: &pb.EmailRequest{
+			FromAddress: "noreply@example.com",
+			FromName:    "Example",
+			To:          []*pb.EmailAddress{{Address: "jane@example.com", Name: "Jane Doe"}, {Address: "john@example.com"}},
+			Cc:          []*pb.EmailAddress{{Address: "cc@example.com"}},
+			Bcc:         []*pb.EmailAddress{{Address: "audit@example.com"}},
+			ReplyTo:     []*pb.EmailAddress{{Address: "support@example.com", Name: "Support"}},
+		},
+		IsAsync: true,
+		Domain:  "test-domain",
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		e := req.EmailRequest
+		return e.FromAddress == `"Example" <noreply@example.com>` &&
+			assert.ObjectsAreEqual([]string{`"Jane Doe" <jane@example.com>`, "john@example.com"}, e.ToAddresses) &&
+			assert.ObjectsAreEqual([]string{"cc@example.com"}, e.CcAddresses) &&
+			assert.ObjectsAreEqual([]string{"audit@example.com"}, e.BccAddresses) &&
+			assert.ObjectsAreEqual([]string{`"Support" <support@example.com>`}, e.ReplyToAddresses)
+	}), mock.Anything).Once().Return(nil)
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+	s.NotEmpty(resp.Id)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_InvalidAddress() {
+	req := &pb.SendCommunicationRequest{
+		Email: &pb.EmailRequest{
+			FromAddress: "noreply@example.com",
+			To:          []*pb.EmailAddress{{Address: "not an address"}},
+		},
+		Domain: "test-domain",
+	}
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.Nil(resp)
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.Contains(err.Error(), "to contains an invalid email address")
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_UnverifiedSender() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"billing":   {Senders: []string{"billing@example.com"}},
+		"marketing": {Senders: []string{"@news.example.com"}},
+	}), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	req := &pb.SendCommunicationRequest{
+		Email: &pb.EmailRequest{
+			FromAddress: "billing@example.com",
+			ToAddress:   "test@example.com",
+		},
+		Domain: "marketing",
+	}
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.Nil(resp)
+	s.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_UrlAttachmentIsFetchedByWorker() {
+	req := &pb.SendCommunicationRequest{
+		Email: &pb.EmailRequest{
+			FromAddress: "noreply@example.com",
+			ToAddress:   "test@example.com",
+			Attachments: []*pb.Attachment{
+				{Name: "invoice.pdf", Url: proto.String("https://files.example.com/invoice.pdf")},
+				{Name: "notes.txt", Data: []byte("notes")},
+			},
+		},
+		IsAsync: true,
+		Domain:  "test-domain",
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		attachments := req.EmailRequest.Attachments
+		return len(attachments) == 2 &&
+			attachments[0].URL == "https://files.example.com/invoice.pdf" && attachments[0].Data == nil &&
+			attachments[1].ContentType == "text/plain; charset=utf-8"
+	}), mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_InvalidAttachments() {
+	req := &pb.SendCommunicationRequest{
+		Email: &pb.EmailRequest{
+			FromAddress: "noreply@example.com",
+			ToAddress:   "test@example.com",
+			Attachments: []*pb.Attachment{
+				{Name: "a.pdf", Url: proto.String("http://169.254.169.254/latest/meta-data")},
+				{Name: "run.exe", Data: []byte("MZ\x90\x00")},
+			},
+		},
+		Domain: "test-domain",
+	}
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.Nil(resp)
+	st := status.Convert(err)
+	s.Equal(codes.InvalidArgument, st.Code())
+	s.Require().Len(st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	s.Require().True(ok)
+	s.Require().Len(badRequest.FieldViolations, 2)
+	s.Equal("email.attachments[0].url", badRequest.FieldViolations[0].Field)
+	s.Equal("email.attachments[1].data", badRequest.FieldViolations[1].Field)
+	s.Contains(badRequest.FieldViolations[1].Description, "not allowed")
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_OffloadsContent() {
+	payloads := newMockpayloadOffloader(s.T())
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, nil), attachment.DefaultConfig, payloads, s.eraser, s.contacts, s.metrics)
+	req := &pb.SendCommunicationRequest{
+		Email:   &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com", Html: "<p>large</p>"},
+		IsAsync: true,
+		Domain:  "test-domain",
+	}
+	var offloadedId string
+	payloads.EXPECT().Offload(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, id string, req *email.Request) error {
+		offloadedId = id
+		req.HtmlBody, req.HtmlBodyRef = "", id+"/html"
+		return nil
+	}).Once()
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.EmailRequest.HtmlBody == "" && req.EmailRequest.HtmlBodyRef == offloadedId+"/html"
+	}), mock.Anything).Once().Return(nil)
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+	s.Equal(offloadedId, resp.Id)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_OffloadFailure() {
+	payloads := newMockpayloadOffloader(s.T())
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, nil), attachment.DefaultConfig, payloads, s.eraser, s.contacts, s.metrics)
+	req := &pb.SendCommunicationRequest{
+		Email:  &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com", Html: "<p>large</p>"},
+		Domain: "test-domain",
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	payloads.EXPECT().Offload(mock.Anything, mock.Anything, mock.Anything).Once().Return(errors.New("store unavailable"))
+
+	resp, err := s.svc.SendCommunication(context.Background(), req)
+	s.Nil(resp)
+	s.Equal(codes.Internal, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_RetryDoesNotOffloadAgain() {
+	payloads := newMockpayloadOffloader(s.T())
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, nil), attachment.DefaultConfig, payloads, s.eraser, s.contacts, s.metrics)
+	req := &pb.SendCommunicationRequest{
+		Email:          &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com", Html: "<p>large</p>"},
+		IsAsync:        true,
+		Domain:         "test-domain",
+		IdempotencyKey: "order-1",
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(model.ErrCommunicationExists)
+	s.tc.EXPECT().WorkflowStarted(mock.Anything, mock.Anything).Once().Return(true, nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+	payloads.AssertNotCalled(s.T(), "Offload", mock.Anything, mock.Anything, mock.Anything)
+	s.tc.AssertNotCalled(s.T(), "StartCommunicationWorkflow", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_RetryStartsWorkflowNeverStarted() {
+	payloads := newMockpayloadOffloader(s.T())
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, nil), attachment.DefaultConfig, payloads, s.eraser, s.contacts, s.metrics)
+	req := &pb.SendCommunicationRequest{
+		Email:          &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com", Html: "<p>large</p>"},
+		IsAsync:        true,
+		Domain:         "test-domain",
+		IdempotencyKey: "order-1",
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(model.ErrCommunicationExists)
+	s.tc.EXPECT().WorkflowStarted(mock.Anything, mock.Anything).Once().Return(false, nil)
+	payloads.EXPECT().Offload(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_RecordsRecipients() {
+	req := &pb.SendCommunicationRequest{
+		Email: &pb.EmailRequest{
+			FromAddress: "noreply@example.com",
+			To:          []*pb.EmailAddress{{Address: "First@Example.com", Name: "First"}},
+			Bcc:         []*pb.EmailAddress{{Address: "second@example.com"}},
+		},
+		IsAsync: true,
+		Domain:  "test-domain",
+	}
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.MatchedBy(func(comm *model.Communication) bool {
+		return slices.Equal(comm.Recipients, []string{"email:first@example.com", "email:second@example.com"})
+	})).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestDeleteRecipientData_Success() {
+	s.eraser.EXPECT().EraseRecipient(mock.Anything, erasure.Recipient{
+		EmailAddresses:     []string{"someone@example.com"},
+		ExternalCustomerID: "customer-1",
+	}, true).Once().Return(model.Erasure{ID: "erasure-id", DryRun: true, Communications: 3, ResponseChannels: 1, DeviceTokens: 2}, nil)
+
+	resp, err := s.svc.DeleteRecipientData(context.Background(), &pb.DeleteRecipientDataRequest{
+		EmailAddresses:     []string{"Someone <someone@example.com>"},
+		ExternalCustomerId: "customer-1",
+		DryRun:             true,
+	})
+	s.Require().NoError(err)
+	s.Equal("erasure-id", resp.ErasureId)
+	s.Equal(int32(3), resp.Communications)
+	s.Equal(int32(1), resp.ResponseChannels)
+	s.Equal(int32(2), resp.DeviceTokens)
+	s.True(resp.DryRun)
+}
+
+func (s *ServerUnitTestSuite) TestDeleteRecipientData_InvalidRequest() {
+	_, err := s.svc.DeleteRecipientData(context.Background(), &pb.DeleteRecipientDataRequest{})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = s.svc.DeleteRecipientData(context.Background(), &pb.DeleteRecipientDataRequest{EmailAddresses: []string{"not an address"}})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestDeleteRecipientData_Failure() {
+	s.eraser.EXPECT().EraseRecipient(mock.Anything, mock.Anything, false).Once().Return(model.Erasure{}, errors.New("database unavailable"))
+
+	resp, err := s.svc.DeleteRecipientData(context.Background(), &pb.DeleteRecipientDataRequest{ExternalCustomerId: "customer-1"})
+	s.Nil(resp)
+	s.Equal(codes.Internal, status.Code(err))
+}
+
 func (s *ServerUnitTestSuite) SetupSuite() {}
 
 func (s *ServerUnitTestSuite) SetupTest() {
 	s.tc = newMocktemporalClient(s.T())
 	s.db = newMockpostgres(s.T())
-	s.svc = server.New(zap.NewNop(), s.tc, s.db)
+	s.eraser = newMockeraser(s.T())
+	s.contacts = newMockcontactDirectory(s.T())
+	s.scope = tally.NewTestScope("", nil)
+	s.metrics = sdktally.NewMetricsHandler(s.scope)
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, nil), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+}
+
+func (s *ServerUnitTestSuite) TestListAuditEvents_Success() {
+	events := []model.AuditEvent{
+		{ID: 12, Principal: "cn:billing", Method: "/unicom.api.v1.UnicomService/SendCommunication", Code: "OK", WorkflowID: "comm-1"},
+		{ID: 11, Principal: "cn:billing", Method: "/unicom.api.v1.UnicomService/SendCommunication", Code: "OK", WorkflowID: "comm-2"},
+	}
+	s.db.EXPECT().ListAuditEvents(mock.Anything, mock.MatchedBy(func(filter model.AuditFilter) bool {
+		return filter.Principal == "cn:billing" && filter.Before == 20 && filter.Since != nil
+	}), 2).Once().Return(events, nil)
+
+	resp, err := s.svc.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{
+		Principal: "cn:billing",
+		StartTime: timestamppb.New(time.Now().Add(-time.Hour)),
+		PageSize:  2,
+		PageToken: "20",
+	})
+	s.NoError(err)
+	s.Len(resp.Events, 2)
+	s.Equal("comm-1", resp.Events[0].WorkflowId)
+	s.Equal("11", resp.NextPageToken)
+}
+
+func (s *ServerUnitTestSuite) TestListAuditEvents_LastPage() {
+	s.db.EXPECT().ListAuditEvents(mock.Anything, model.AuditFilter{}, 1000).Once().Return([]model.AuditEvent{{ID: 1}}, nil)
+
+	resp, err := s.svc.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{PageSize: 5000})
+	s.NoError(err)
+	s.Len(resp.Events, 1)
+	s.Empty(resp.NextPageToken)
+}
+
+func (s *ServerUnitTestSuite) TestListAuditEvents_InvalidPageToken() {
+	resp, err := s.svc.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{PageToken: "abc"})
+	s.Nil(resp)
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestListAuditEvents_DBError() {
+	s.db.EXPECT().ListAuditEvents(mock.Anything, mock.Anything, 100).Once().Return(nil, errors.New("database unavailable"))
+
+	resp, err := s.svc.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{})
+	s.Nil(resp)
+	s.Equal(codes.Internal, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestListCommunications_Success() {
+	createdAt := time.UnixMicro(1_760_000_000_000_000).UTC()
+	externalID := "provider-1"
+	comms := []model.Communication{
+		{ID: "comm-2", Domain: "billing", Type: model.Email, Status: model.Pending, Model: model.Model{CreatedAt: createdAt.Add(time.Second)}},
+		{ID: "comm-1", Domain: "billing", Type: model.Push, Status: model.Success, ExternalId: &externalID, Model: model.Model{CreatedAt: createdAt, SentAt: createdAt}},
+	}
+	s.db.EXPECT().ListCommunications(mock.Anything, mock.MatchedBy(func(filter model.CommunicationFilter) bool {
+		return filter.Domain == "billing" && filter.Status == model.Pending && filter.Before != nil &&
+			filter.Before.ID == "comm-3" && filter.Before.CreatedAt.Equal(createdAt.Add(time.Minute))
+	}), 2).Once().Return(comms, nil)
+
+	resp, err := s.svc.ListCommunications(context.Background(), &pb.ListCommunicationsRequest{
+		Domain:    "billing",
+		Status:    "PENDING",
+		PageSize:  2,
+		PageToken: "1760000060000000_comm-3",
+	})
+	s.NoError(err)
+	s.Require().Len(resp.Communications, 2)
+	s.Nil(resp.Communications[0].SentAt, "pending communications haven't been sent")
+	s.Equal("provider-1", resp.Communications[1].ExternalId)
+	s.Equal("PUSH", resp.Communications[1].Type)
+	s.Equal("1760000000000000_comm-1", resp.NextPageToken)
+}
+
+func (s *ServerUnitTestSuite) TestListCommunications_LastPage() {
+	s.db.EXPECT().ListCommunications(mock.Anything, model.CommunicationFilter{}, 1000).Once().Return([]model.Communication{{ID: "comm-1"}}, nil)
+
+	resp, err := s.svc.ListCommunications(context.Background(), &pb.ListCommunicationsRequest{PageSize: 5000})
+	s.NoError(err)
+	s.Len(resp.Communications, 1)
+	s.Empty(resp.NextPageToken)
+}
+
+func (s *ServerUnitTestSuite) TestListCommunications_InvalidRequest() {
+	for _, req := range []*pb.ListCommunicationsRequest{{Status: "SENT"}, {PageToken: "abc"}, {PageToken: "123_"}} {
+		resp, err := s.svc.ListCommunications(context.Background(), req)
+		s.Nil(resp)
+		s.Equal(codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func (s *ServerUnitTestSuite) TestListCommunications_DBError() {
+	s.db.EXPECT().ListCommunications(mock.Anything, mock.Anything, 100).Once().Return(nil, errors.New("database unavailable"))
+
+	resp, err := s.svc.ListCommunications(context.Background(), &pb.ListCommunicationsRequest{})
+	s.Nil(resp)
+	s.Equal(codes.Internal, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestCancelCommunication_Success() {
+	s.tc.EXPECT().CancelWorkflow(mock.Anything, "comm-1").Once().Return(nil)
+
+	resp, err := s.svc.CancelCommunication(context.Background(), &pb.CancelCommunicationRequest{Id: "comm-1"})
+	s.NoError(err)
+	s.Equal("comm-1", resp.Id)
+}
+
+func (s *ServerUnitTestSuite) TestCancelCommunication_NotFound() {
+	s.tc.EXPECT().CancelWorkflow(mock.Anything, "comm-1").Once().Return(serviceerror.NewNotFound("workflow not found"))
+
+	resp, err := s.svc.CancelCommunication(context.Background(), &pb.CancelCommunicationRequest{Id: "comm-1"})
+	s.Nil(resp)
+	s.Equal(codes.NotFound, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestCancelCommunication_InvalidRequest() {
+	resp, err := s.svc.CancelCommunication(context.Background(), &pb.CancelCommunicationRequest{})
+	s.Nil(resp)
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestExportAuditEvents_PagesThroughEvents() {
+	firstPage := make([]model.AuditEvent, 1000)
+	for i := range firstPage {
+		firstPage[i] = model.AuditEvent{ID: int64(1500 - i)}
+	}
+	s.db.EXPECT().ListAuditEvents(mock.Anything, model.AuditFilter{Domain: "billing"}, 1000).Once().Return(firstPage, nil)
+	s.db.EXPECT().ListAuditEvents(mock.Anything, model.AuditFilter{Domain: "billing", Before: 501}, 1000).Once().Return([]model.AuditEvent{{ID: 500}}, nil)
+
+	stream := &auditEventStream{ctx: context.Background()}
+	err := s.svc.ExportAuditEvents(&pb.ListAuditEventsRequest{Domain: "billing"}, stream)
+	s.NoError(err)
+	s.Len(stream.sent, 1001)
+	s.Equal(int64(500), stream.sent[1000].Id)
+}
+
+type auditEventStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.AuditEvent
+}
+
+func (s *auditEventStream) Context() context.Context { return s.ctx }
+
+func (s *auditEventStream) Send(event *pb.AuditEvent) error {
+	s.sent = append(s.sent, event)
+	return nil
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Email_ResolvesCustomer() {
+	s.contacts.EXPECT().Lookup(mock.Anything, "customer-1").Once().Return(model.Contact{
+		ExternalCustomerID: "customer-1",
+		EmailAddresses:     []string{"jane@example.com", "jane@work.example.com"},
+	}, nil)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return slices.Equal(req.EmailRequest.ToAddresses, []string{"jane@example.com", "jane@work.example.com"})
+	}), mock.Anything).Once().Return(nil)
+
+	req := &pb.SendCommunicationRequest{
+		Email:              &pb.EmailRequest{FromAddress: "noreply@example.com", Subject: "Test", Html: "Hello"},
+		IsAsync:            true,
+		ExternalCustomerId: "customer-1",
+	}
+	_, err := s.svc.SendCommunication(context.Background(), req)
+	s.NoError(err)
+	s.Empty(req.Email.To, "the caller's request is left unchanged")
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_Push_ResolvesCustomer() {
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.PushRequest.ExternalCustomerId == "customer-1"
+	}), mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:               &pb.PushRequest{IdempotencyKey: "Push", Content: &pb.LanguageContent{English: "Hello"}},
+		IsAsync:            true,
+		ExternalCustomerId: "customer-1",
+	})
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_UnknownCustomer() {
+	s.contacts.EXPECT().Lookup(mock.Anything, "customer-1").Once().Return(model.Contact{}, contact.ErrNotFound)
+
+	resp, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Email:              &pb.EmailRequest{FromAddress: "noreply@example.com"},
+		ExternalCustomerId: "customer-1",
+	})
+	s.Nil(resp)
+	s.Equal(codes.NotFound, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_CustomerWithoutEmail() {
+	s.contacts.EXPECT().Lookup(mock.Anything, "customer-1").Once().Return(model.Contact{ExternalCustomerID: "customer-1"}, nil)
+
+	resp, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Email:              &pb.EmailRequest{FromAddress: "noreply@example.com"},
+		ExternalCustomerId: "customer-1",
+	})
+	s.Nil(resp)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestUpsertContact_Success() {
+	s.contacts.EXPECT().Upsert(mock.Anything, model.Contact{
+		ExternalCustomerID: "customer-1",
+		EmailAddresses:     []string{"jane@example.com"},
+		PhoneNumbers:       []string{"+447700900123"},
+		Devices:            []model.DeviceToken{{ExternalCustomerID: "customer-1", Type: model.APNs, Token: "token", Locale: "en-GB"}},
+		Locale:             "en-GB",
+		Timezone:           "Europe/London",
+	}).Once().Return(nil)
+
+	_, err := s.svc.UpsertContact(context.Background(), &pb.UpsertContactRequest{Contact: &pb.Contact{
+		ExternalCustomerId: "customer-1",
+		EmailAddresses:     []string{"jane@example.com"},
+		PhoneNumbers:       []string{"+447700900123"},
+		Devices:            []*pb.DeviceSubscription{{TokenType: pb.DeviceTokenType_DEVICE_TOKEN_TYPE_APNS, Token: "token", Locale: "en-GB"}},
+		Locale:             "en-GB",
+		Timezone:           "Europe/London",
+	}})
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestUpsertContact_InvalidContact() {
+	for name, c := range map[string]*pb.Contact{
+		"no customer id": {EmailAddresses: []string{"jane@example.com"}},
+		"email address":  {ExternalCustomerId: "customer-1", EmailAddresses: []string{"not an address"}},
+		"phone number":   {ExternalCustomerId: "customer-1", PhoneNumbers: []string{"07700 900123"}},
+		"timezone":       {ExternalCustomerId: "customer-1", Timezone: "Mars/Olympus_Mons"},
+		"device type":    {ExternalCustomerId: "customer-1", Devices: []*pb.DeviceSubscription{{Token: "token"}}},
+	} {
+		_, err := s.svc.UpsertContact(context.Background(), &pb.UpsertContactRequest{Contact: c})
+		s.Equal(codes.InvalidArgument, status.Code(err), name)
+	}
+}
+
+func (s *ServerUnitTestSuite) TestUpsertContact_ReadOnlyProvider() {
+	s.contacts.EXPECT().Upsert(mock.Anything, mock.Anything).Once().Return(contact.ErrReadOnly)
+
+	_, err := s.svc.UpsertContact(context.Background(), &pb.UpsertContactRequest{Contact: &pb.Contact{ExternalCustomerId: "customer-1"}})
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestGetContact_Success() {
+	s.contacts.EXPECT().Lookup(mock.Anything, "customer-1").Once().Return(model.Contact{
+		ExternalCustomerID: "customer-1",
+		EmailAddresses:     []string{"jane@example.com"},
+		Devices:            []model.DeviceToken{{Type: model.FCM, Token: "token"}},
+		Timezone:           "Asia/Dubai",
+	}, nil)
+
+	resp, err := s.svc.GetContact(context.Background(), &pb.GetContactRequest{ExternalCustomerId: "customer-1"})
+	s.NoError(err)
+	s.Equal([]string{"jane@example.com"}, resp.EmailAddresses)
+	s.Equal("Asia/Dubai", resp.Timezone)
+	s.Equal(pb.DeviceTokenType_DEVICE_TOKEN_TYPE_FCM, resp.Devices[0].TokenType)
+}
+
+func (s *ServerUnitTestSuite) TestGetContact_ProviderUnavailable() {
+	s.contacts.EXPECT().Lookup(mock.Anything, "customer-1").Once().Return(model.Contact{}, errors.New("connection refused"))
+
+	resp, err := s.svc.GetContact(context.Background(), &pb.GetContactRequest{ExternalCustomerId: "customer-1"})
+	s.Nil(resp)
+	s.Equal(codes.Unavailable, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_DeliveryWindowInCustomerTimezone() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"marketing": {QuietHours: domain.QuietHours{Start: "21:00", End: "08:00"}},
+	}), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	s.contacts.EXPECT().Lookup(mock.Anything, "customer-1").Once().Return(model.Contact{Timezone: "Asia/Dubai"}, nil)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return slices.Equal(req.Windows, []model.DeliveryWindow{
+			{Start: 9 * time.Hour, End: 18 * time.Hour, Timezone: "Asia/Dubai"},
+			{Start: 8 * time.Hour, End: 21 * time.Hour, Timezone: "Asia/Dubai"},
+		})
+	}), mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:               &pb.PushRequest{IdempotencyKey: "Push", Content: &pb.LanguageContent{English: "Sale"}},
+		IsAsync:            true,
+		Domain:             "marketing",
+		ExternalCustomerId: "customer-1",
+		DeliveryWindow:     &pb.DeliveryWindow{Start: "09:00", End: "18:00"},
+	})
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_UrgentIgnoresQuietHours() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{QuietHours: domain.QuietHours{Start: "21:00", End: "08:00"}}, nil),
+		attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.Windows == nil
+	}), mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:           &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Your code is 123456"}},
+		IsAsync:        true,
+		Urgent:         true,
+		DeliveryWindow: &pb.DeliveryWindow{Start: "09:00", End: "18:00"},
+	})
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_PriorityDefaultsToDomain() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"marketing": {Priority: "bulk"},
+	}), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Twice().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.Domain == "marketing" && req.Priority == model.PriorityBulk
+	}), mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.Domain == "billing" && req.Priority == model.PriorityTransactional
+	}), mock.Anything).Once().Return(nil)
+
+	for _, name := range []string{"marketing", "billing"} {
+		_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+			Push:    &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+			IsAsync: true,
+			Domain:  name,
+		})
+		s.NoError(err)
+	}
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_CriticalIgnoresQuietHours() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{QuietHours: domain.QuietHours{Start: "21:00", End: "08:00"}, Priority: "bulk"}, nil),
+		attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.Priority == model.PriorityCritical && req.Windows == nil
+	}), mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:     &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Your code is 123456"}},
+		IsAsync:  true,
+		Priority: pb.Priority_PRIORITY_CRITICAL,
+	})
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_InvalidPriority() {
+	resp, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:     &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+		IsAsync:  true,
+		Priority: pb.Priority(42),
+	})
+	s.Nil(resp)
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_SendAtIsPassedToWorkflow() {
+	sendAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.SendAt != nil && req.SendAt.Equal(sendAt) && req.SleepDuration == 0
+	}), mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:    &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+		IsAsync: true,
+		SendAt:  timestamppb.New(sendAt),
+	})
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_InvalidDeliveryWindow() {
+	for _, window := range []*pb.DeliveryWindow{
+		{Start: "9am", End: "18:00"},
+		{Start: "09:00", End: "18:00", Timezone: "Mars/Olympus_Mons"},
+	} {
+		resp, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+			Push:           &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+			IsAsync:        true,
+			DeliveryWindow: window,
+		})
+		s.Nil(resp)
+		s.Equal(codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_DomainRateLimit() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"marketing": {RateLimit: domain.RateLimit{PerSecond: 0.001, Burst: 1}},
+	}), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Twice().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.Anything, mock.Anything).Twice().Return(nil)
+
+	send := func(name string) error {
+		_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+			Push:    &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+			IsAsync: true,
+			Domain:  name,
+		})
+		return err
+	}
+	s.NoError(send("marketing"))
+	err := send("marketing")
+	s.Equal(codes.ResourceExhausted, status.Code(err))
+	s.NoError(send("billing"), "domains without a rate limit are not limited")
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_DomainResponseChannels() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"billing": {ResponseChannels: []domain.ResponseChannel{{Type: "sqs", URL: "https://sqs.eu-west-1.amazonaws.com/123/billing"}}},
+	}), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Twice().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return len(req.ResponseRequests) == 1 && req.ResponseRequests[0].Type == model.Sqs &&
+			req.ResponseRequests[0].Url == "https://sqs.eu-west-1.amazonaws.com/123/billing"
+	}), mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return len(req.ResponseRequests) == 1 && req.ResponseRequests[0].Type == model.Webhook
+	}), mock.Anything).Once().Return(nil)
+
+	for _, channels := range [][]*pb.ResponseChannel{nil, {{Schema: pb.ResponseSchema_RESPONSE_SCHEMA_HTTP, Url: "https://example.com/hook"}}} {
+		_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+			Push:             &pb.PushRequest{IdempotencyKey: "Push", ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+			IsAsync:          true,
+			Domain:           "billing",
+			ResponseChannels: channels,
+		})
+		s.NoError(err)
+	}
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_TestSendGoesToSeedList() {
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"billing": {SeedList: domain.SeedList{Emails: []string{"QA <qa@example.com>"}, PushCustomer: "qa-devices"}},
+	}), attachment.DefaultConfig, payload.NewOffloader(nil, 0), s.eraser, s.contacts, s.metrics)
+	s.db.EXPECT().CreateCommunication(mock.Anything, mock.Anything).Twice().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.EmailRequest != nil && slices.Equal(req.EmailRequest.ToAddresses, []string{`"QA" <qa@example.com>`}) &&
+			len(req.EmailRequest.CcAddresses) == 0 && len(req.EmailRequest.BccAddresses) == 0
+	}), mock.Anything).Once().Return(nil)
+	s.tc.EXPECT().StartCommunicationWorkflow(mock.Anything, mock.MatchedBy(func(req workflows.Request) bool {
+		return req.PushRequest != nil && req.PushRequest.ExternalCustomerId == "qa-devices"
+	}), mock.Anything).Once().Return(nil)
+
+	_, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Email: &pb.EmailRequest{
+			FromAddress: "noreply@example.com",
+			ToAddress:   "customer@example.com",
+			Cc:          []*pb.EmailAddress{{Address: "cc@example.com"}},
+			Html:        "Hello",
+		},
+		IsAsync: true,
+		Domain:  "billing",
+		Test:    true,
+	})
+	s.NoError(err)
+	_, err = s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:               &pb.PushRequest{Content: &pb.LanguageContent{English: "Hello"}},
+		ExternalCustomerId: "customer-1",
+		IsAsync:            true,
+		Domain:             "billing",
+		Test:               true,
+	})
+	s.NoError(err)
+}
+
+func (s *ServerUnitTestSuite) TestSendCommunication_TestSendWithoutSeedList() {
+	resp, err := s.svc.SendCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Email:  &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "customer@example.com", Html: "Hello"},
+		Domain: "billing",
+		Test:   true,
+	})
+	s.Nil(resp)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestRenderCommunication_Email() {
+	s.tc.EXPECT().RenderCommunication(mock.Anything, mock.Anything, mock.MatchedBy(func(req workflows.RenderRequest) bool {
+		return req.EmailRequest != nil && req.EmailRequest.Subject == "Test" && req.PushRequest == nil
+	})).Once().Return(&workflows.Rendered{Email: []byte("Subject: Test\r\n")}, nil)
+
+	resp, err := s.svc.RenderCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Email:  &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com", Subject: "Test", Html: "Hello"},
+		Domain: "test-domain",
+	})
+	s.NoError(err)
+	s.Equal([]byte("Subject: Test\r\n"), resp.GetEmailMime())
+	s.Empty(resp.GetPushPayload())
+}
+
+func (s *ServerUnitTestSuite) TestRenderCommunication_Push() {
+	s.tc.EXPECT().RenderCommunication(mock.Anything, mock.Anything, mock.MatchedBy(func(req workflows.RenderRequest) bool {
+		return req.PushRequest != nil && req.PushRequest.ExternalCustomerId == "customer-1"
+	})).Once().Return(&workflows.Rendered{Push: []byte(`{"app_id":"app"}`)}, nil)
+
+	resp, err := s.svc.RenderCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:               &pb.PushRequest{Content: &pb.LanguageContent{English: "Hello"}},
+		ExternalCustomerId: "customer-1",
+		Domain:             "test-domain",
+	})
+	s.NoError(err)
+	s.JSONEq(`{"app_id":"app"}`, string(resp.GetPushPayload()))
+}
+
+func (s *ServerUnitTestSuite) TestRenderCommunication_InvalidRequest() {
+	resp, err := s.svc.RenderCommunication(context.Background(), &pb.SendCommunicationRequest{})
+	s.Nil(resp)
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestRenderCommunication_NonRetryableFailure() {
+	s.tc.EXPECT().RenderCommunication(mock.Anything, mock.Anything, mock.Anything).Once().
+		Return(nil, temporal.NewNonRetryableApplicationError("no fcm devices registered for customer customer-1", "INVALID_RECIPIENT", nil))
+
+	resp, err := s.svc.RenderCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:   &pb.PushRequest{ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+		Domain: "test-domain",
+	})
+	s.Nil(resp)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+	s.Contains(status.Convert(err).Message(), "no fcm devices")
+}
+
+func (s *ServerUnitTestSuite) TestRenderCommunication_WorkflowError() {
+	s.tc.EXPECT().RenderCommunication(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil, errors.New("no worker"))
+
+	resp, err := s.svc.RenderCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:   &pb.PushRequest{ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+		Domain: "test-domain",
+	})
+	s.Nil(resp)
+	s.Equal(codes.Internal, status.Code(err))
+}
+
+func (s *ServerUnitTestSuite) TestRenderCommunication_NoWorker() {
+	s.tc.EXPECT().RenderCommunication(mock.Anything, mock.Anything, mock.Anything).Once().
+		Return(nil, serviceerror.NewFailedPrecondition("no worker polls the unicom_bulk_task_queue task queue"))
+
+	resp, err := s.svc.RenderCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Push:   &pb.PushRequest{ExternalCustomerId: "customer-1", Content: &pb.LanguageContent{English: "Hello"}},
+		Domain: "test-domain",
+	})
+	s.Nil(resp)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+	s.Contains(status.Convert(err).Message(), "no worker polls")
+}
+
+func (s *ServerUnitTestSuite) TestRenderCommunication_OffloadsContent() {
+	payloads := newMockpayloadOffloader(s.T())
+	s.svc = server.New(zap.NewNop(), s.tc, s.db, domain.NewRegistry(domain.Config{}, nil), attachment.DefaultConfig, payloads, s.eraser, s.contacts, s.metrics)
+	var renderId string
+	payloads.EXPECT().Offload(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, id string, req *email.Request) error {
+		renderId = id
+		req.HtmlBody, req.HtmlBodyRef = "", id+"/html"
+		return nil
+	}).Once()
+	s.tc.EXPECT().RenderCommunication(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, id string, req workflows.RenderRequest) (*workflows.Rendered, error) {
+		s.Equal(renderId, id)
+		s.Equal(id+"/html", req.EmailRequest.HtmlBodyRef)
+		s.Empty(req.EmailRequest.HtmlBody)
+		return &workflows.Rendered{Email: []byte("Subject: Test\r\n")}, nil
+	}).Once()
+	payloads.EXPECT().Delete(mock.Anything, mock.MatchedBy(func(req email.Request) bool {
+		return req.HtmlBodyRef == renderId+"/html"
+	})).Once().Return(nil)
+
+	resp, err := s.svc.RenderCommunication(context.Background(), &pb.SendCommunicationRequest{
+		Email:  &pb.EmailRequest{FromAddress: "noreply@example.com", ToAddress: "test@example.com", Subject: "Test", Html: "<p>large</p>"},
+		Domain: "test-domain",
+	})
+	s.NoError(err)
+	s.Equal([]byte("Subject: Test\r\n"), resp.GetEmailMime())
 }
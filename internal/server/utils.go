@@ -1,42 +1,132 @@
 package server
 
 import (
-	"io"
-	"net/http"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
 
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/domain"
 	"github.com/anicoll/unicom/internal/email"
 	"github.com/anicoll/unicom/internal/model"
 	"github.com/anicoll/unicom/internal/push"
 	"github.com/anicoll/unicom/internal/workflows"
 )
 
+// idempotencyNamespace is the namespace the workflow IDs of requests with an idempotency key are derived in.
+var idempotencyNamespace = uuid.MustParse("6f1c2b0e-7f57-4b53-9a3c-1d5e0c4a8b21")
+
+// idempotentWorkflowID derives the workflow ID of a request from its domain and idempotency key, so every
+// retry of the request gets the same ID.
+func idempotentWorkflowID(domain, key string) string {
+	return uuid.NewSHA1(idempotencyNamespace, []byte(domain+"\x00"+key)).String()
+}
+
+// maxEmailRecipients is the most to, cc and bcc recipients a single email may have, matching the SES limit.
+const maxEmailRecipients = 50
+
 // mapEmailRequestIn maps a protobuf EmailRequest to an internal email.Request structure,
-// including attachments. Returns an error if attachment mapping fails.
-func mapEmailRequestIn(req *pb.EmailRequest) (*email.Request, error) {
+// including attachments. Returns an InvalidArgument status error if any address or attachment is invalid.
+func mapEmailRequestIn(domain string, req *pb.EmailRequest, attachments attachment.Config) (*email.Request, error) {
 	if req == nil {
 		return nil, nil
 	}
 
-	attachments, err := mapAttachmentsIn(req.Attachments)
+	from, err := mapEmailAddressIn("from_address", &pb.EmailAddress{Address: req.GetFromAddress(), Name: req.GetFromName()})
+	if err != nil {
+		return nil, err
+	}
+	to := req.GetTo()
+	if req.GetToAddress() != "" {
+		to = append([]*pb.EmailAddress{{Address: req.GetToAddress()}}, to...)
+	}
+	toAddresses, err := mapEmailAddressesIn("to", to)
+	if err != nil {
+		return nil, err
+	}
+	ccAddresses, err := mapEmailAddressesIn("cc", req.GetCc())
+	if err != nil {
+		return nil, err
+	}
+	bccAddresses, err := mapEmailAddressesIn("bcc", req.GetBcc())
+	if err != nil {
+		return nil, err
+	}
+	replyToAddresses, err := mapEmailAddressesIn("reply_to", req.GetReplyTo())
+	if err != nil {
+		return nil, err
+	}
+	if len(toAddresses) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "email requires at least one to address")
+	}
+	if len(toAddresses)+len(ccAddresses)+len(bccAddresses) > maxEmailRecipients {
+		return nil, status.Errorf(codes.InvalidArgument, "email may have at most %d to, cc and bcc recipients", maxEmailRecipients)
+	}
+
+	mappedAttachments, err := mapAttachmentsIn(req.Attachments, attachments)
 	if err != nil {
 		return nil, err
 	}
 	return &email.Request{
-		FromAddress:      req.FromAddress,
+		Domain:           domain,
+		FromAddress:      from,
 		Subject:          req.Subject,
-		ReplyToAddresses: []string{req.FromAddress},
-		ToAddresses:      []string{req.ToAddress},
+		ReplyToAddresses: replyToAddresses,
+		ToAddresses:      toAddresses,
+		CcAddresses:      ccAddresses,
+		BccAddresses:     bccAddresses,
 		HtmlBody:         req.Html,
-		Attachments:      attachments,
+		TextBody:         req.Text,
+		Attachments:      mappedAttachments,
 	}, nil
 }
 
+// mapEmailAddressesIn validates and formats a list of protobuf EmailAddress objects, see mapEmailAddressIn.
+func mapEmailAddressesIn(field string, addresses []*pb.EmailAddress) ([]string, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	resp := make([]string, len(addresses))
+	for i, address := range addresses {
+		formatted, err := mapEmailAddressIn(field, address)
+		if err != nil {
+			return nil, err
+		}
+		resp[i] = formatted
+	}
+	return resp, nil
+}
+
+// mapEmailAddressIn validates a protobuf EmailAddress as an RFC 5322 address and formats it
+// with its display name. Returns an InvalidArgument status error naming the field if it is invalid.
+func mapEmailAddressIn(field string, address *pb.EmailAddress) (string, error) {
+	parsed, err := email.ParseAddress(address.GetAddress())
+	if err != nil || parsed.Name != "" {
+		return "", status.Errorf(codes.InvalidArgument, "%s contains an invalid email address %q", field, address.GetAddress())
+	}
+	return email.FormatAddress(parsed.Address, address.GetName()), nil
+}
+
 // mapPushNotificationIn maps a protobuf PushRequest to an internal push.Notification structure,
 // including language-specific content and optional subtitle.
-func mapPushNotificationIn(req *pb.PushRequest) *push.Notification {
+func mapPushNotificationIn(domain string, req *pb.PushRequest) *push.Notification {
+	if req == nil {
+		return nil
+	}
+
 	notification := &push.Notification{
+		Domain:             domain,
 		IdempotencyKey:     req.GetIdempotencyKey(),
 		ExternalCustomerId: req.GetExternalCustomerId(),
 		Content: push.LanguageContent{
@@ -59,31 +149,229 @@ func mapPushNotificationIn(req *pb.PushRequest) *push.Notification {
 	return notification
 }
 
-// mapAttachmentsIn converts a slice of protobuf Attachment objects to internal email.Attachment objects,
-// downloading file data if a URL is provided. Returns an error if any download fails.
-func mapAttachmentsIn(attachments []*pb.Attachment) ([]email.Attachment, error) {
+// mapTestSendIn redirects a test send to the domain's seed list, replacing the
+// recipients of its email or the customer of its push notification. Returns a
+// FailedPrecondition status error if the seed list has nowhere to send it.
+func mapTestSendIn(req *pb.SendCommunicationRequest, seeds domain.SeedList) (*pb.SendCommunicationRequest, error) {
+	req = proto.Clone(req).(*pb.SendCommunicationRequest)
+	if mail := req.GetEmail(); mail != nil {
+		if len(seeds.Emails) == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "domain %s has no seed_list emails to send test emails to", req.GetDomain())
+		}
+		mail.ToAddress = ""
+		mail.To = make([]*pb.EmailAddress, 0, len(seeds.Emails))
+		mail.Cc = nil
+		mail.Bcc = nil
+		for _, seed := range seeds.Emails {
+			address, err := email.ParseAddress(seed)
+			if err != nil {
+				return nil, status.Errorf(codes.FailedPrecondition, "seed_list email %q is invalid", seed)
+			}
+			mail.To = append(mail.To, &pb.EmailAddress{Address: address.Address, Name: address.Name})
+		}
+	}
+	if push := req.GetPush(); push != nil {
+		if seeds.PushCustomer == "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "domain %s has no seed_list push_customer to send test push notifications to", req.GetDomain())
+		}
+		push.ExternalCustomerId = seeds.PushCustomer
+	}
+	return req, nil
+}
+
+// mapDeliveryPolicyIn maps the protobuf DeliveryPolicy overrides to the internal model, leaving unset fields zero.
+func mapDeliveryPolicyIn(req *pb.DeliveryPolicy) model.DeliveryPolicy {
+	policy := model.DeliveryPolicy{
+		MaxAttempts:        req.GetMaxAttempts(),
+		BackoffCoefficient: req.GetBackoffCoefficient(),
+	}
+	if req.GetAttemptTimeout() != nil {
+		policy.AttemptTimeout = req.GetAttemptTimeout().AsDuration()
+	}
+	if req.GetExpireAt() != nil {
+		expireAt := req.GetExpireAt().AsTime()
+		policy.ExpireAt = &expireAt
+	}
+	return policy
+}
+
+// mapDeviceTokenTypeIn maps a protobuf DeviceTokenType to the internal model type.
+func mapDeviceTokenTypeIn(tokenType pb.DeviceTokenType) (model.DeviceTokenType, error) {
+	switch tokenType {
+	case pb.DeviceTokenType_DEVICE_TOKEN_TYPE_FCM:
+		return model.FCM, nil
+	case pb.DeviceTokenType_DEVICE_TOKEN_TYPE_APNS:
+		return model.APNs, nil
+	}
+	return "", status.Error(codes.InvalidArgument, "token_type must be FCM or APNS")
+}
+
+// mapDomainResponseChannels maps the default response channels of a domain to the workflow's response requests.
+func mapDomainResponseChannels(channels []domain.ResponseChannel) []*workflows.ResponseRequest {
+	requests := make([]*workflows.ResponseRequest, 0, len(channels))
+	for _, channel := range channels {
+		// the domain config is validated when loaded.
+		channelType, err := channel.ParseType()
+		if err != nil {
+			continue
+		}
+		requests = append(requests, &workflows.ResponseRequest{
+			Type: channelType,
+			Url:  channel.URL,
+			ID:   uuid.NewString(),
+		})
+	}
+	return requests
+}
+
+// mapPriorityIn maps a protobuf Priority to the internal model, falling back to the domain's priority when unspecified.
+func mapPriorityIn(priority pb.Priority, config domain.Config) (model.Priority, error) {
+	switch priority {
+	case pb.Priority_PRIORITY_CRITICAL:
+		return model.PriorityCritical, nil
+	case pb.Priority_PRIORITY_TRANSACTIONAL:
+		return model.PriorityTransactional, nil
+	case pb.Priority_PRIORITY_BULK:
+		return model.PriorityBulk, nil
+	case pb.Priority_PRIORITY_UNSPECIFIED:
+		return config.ParsePriority()
+	}
+	return "", status.Error(codes.InvalidArgument, "priority must be CRITICAL, TRANSACTIONAL or BULK")
+}
+
+// e164 matches a phone number in E.164 format.
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// mapContactIn validates a protobuf Contact and maps it to the internal model. Email addresses are stored
+// without display names. Returns an InvalidArgument status error naming the first invalid field.
+func mapContactIn(req *pb.Contact) (model.Contact, error) {
+	if req.GetExternalCustomerId() == "" {
+		return model.Contact{}, status.Error(codes.InvalidArgument, "external_customer_id is required")
+	}
+	contact := model.Contact{
+		ExternalCustomerID: req.GetExternalCustomerId(),
+		PhoneNumbers:       req.GetPhoneNumbers(),
+		Locale:             req.GetLocale(),
+		Timezone:           req.GetTimezone(),
+	}
+	for _, address := range req.GetEmailAddresses() {
+		parsed, err := email.ParseAddress(address)
+		if err != nil || parsed.Name != "" {
+			return model.Contact{}, status.Errorf(codes.InvalidArgument, "email_addresses contains an invalid email address %q", address)
+		}
+		contact.EmailAddresses = append(contact.EmailAddresses, parsed.Address)
+	}
+	for _, number := range req.GetPhoneNumbers() {
+		if !e164.MatchString(number) {
+			return model.Contact{}, status.Errorf(codes.InvalidArgument, "phone_numbers contains %q, which isn't in E.164 format", number)
+		}
+	}
+	if req.GetTimezone() != "" {
+		if _, err := time.LoadLocation(req.GetTimezone()); err != nil || req.GetTimezone() == "Local" {
+			return model.Contact{}, status.Errorf(codes.InvalidArgument, "timezone %q is not an IANA time zone", req.GetTimezone())
+		}
+	}
+	for _, device := range req.GetDevices() {
+		tokenType, err := mapDeviceTokenTypeIn(device.GetTokenType())
+		if err != nil {
+			return model.Contact{}, err
+		}
+		if device.GetToken() == "" {
+			return model.Contact{}, status.Error(codes.InvalidArgument, "devices must have a token")
+		}
+		contact.Devices = append(contact.Devices, model.DeviceToken{
+			ExternalCustomerID: req.GetExternalCustomerId(),
+			Type:               tokenType,
+			Token:              device.GetToken(),
+			Locale:             device.GetLocale(),
+		})
+	}
+	return contact, nil
+}
+
+// mapContactOut maps a contact to its protobuf representation.
+func mapContactOut(contact model.Contact) *pb.Contact {
+	resp := &pb.Contact{
+		ExternalCustomerId: contact.ExternalCustomerID,
+		EmailAddresses:     contact.EmailAddresses,
+		PhoneNumbers:       contact.PhoneNumbers,
+		Locale:             contact.Locale,
+		Timezone:           contact.Timezone,
+	}
+	if !contact.UpdatedAt.IsZero() {
+		resp.UpdatedAt = timestamppb.New(contact.UpdatedAt)
+	}
+	for _, device := range contact.Devices {
+		tokenType := pb.DeviceTokenType_DEVICE_TOKEN_TYPE_FCM
+		if device.Type == model.APNs {
+			tokenType = pb.DeviceTokenType_DEVICE_TOKEN_TYPE_APNS
+		}
+		resp.Devices = append(resp.Devices, &pb.DeviceSubscription{
+			TokenType: tokenType,
+			Token:     device.Token,
+			Locale:    device.Locale,
+		})
+	}
+	return resp
+}
+
+// mapAttachmentsIn converts a slice of protobuf Attachment objects to internal email.Attachment objects.
+// Attachments given by URL are fetched by the worker, so only their URL is checked here. Every attachment
+// breaking the limits of cfg is reported as a field violation of an InvalidArgument status error.
+func mapAttachmentsIn(attachments []*pb.Attachment, cfg attachment.Config) ([]email.Attachment, error) {
 	resp := make([]email.Attachment, len(attachments))
+	contentIDs := make(map[string]bool)
+	violations := []*errdetails.BadRequest_FieldViolation{}
+	violation := func(i int, field, description string) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fmt.Sprintf("email.attachments[%d].%s", i, field),
+			Description: description,
+		})
+	}
 
-	for i, attachment := range attachments {
-		if attachment.Url != nil {
-			data, err := downloadFile(*attachment.Url)
-			if err != nil {
-				return nil, err
+	for i, a := range attachments {
+		contentID := a.GetContentId()
+		if contentID != "" {
+			if strings.ContainsAny(contentID, "<> \t\r\n") {
+				violation(i, "content_id", "content_id must not contain angle brackets or whitespace")
+			} else if contentIDs[contentID] {
+				violation(i, "content_id", "content_id is used by more than one attachment")
 			}
-			resp[i] = email.Attachment{
-				Name: attachment.GetName(),
-				Data: data,
+			contentIDs[contentID] = true
+		}
+		resp[i] = email.Attachment{
+			Name:      a.GetName(),
+			ContentID: contentID,
+		}
+		if a.Url != nil {
+			if _, err := cfg.CheckURL(a.GetUrl()); err != nil {
+				violation(i, "url", err.Error())
 			}
+			resp[i].URL = a.GetUrl()
 			continue
 		}
-		resp[i] = email.Attachment{
-			Name: attachment.GetName(),
-			Data: attachment.GetData(),
+		contentType := attachment.DetectContentType(a.GetName(), "", a.GetData())
+		if err := cfg.CheckContent(int64(len(a.GetData())), contentType); err != nil {
+			violation(i, "data", err.Error())
 		}
+		resp[i].Data = a.GetData()
+		resp[i].ContentType = contentType
+	}
+	if len(violations) > 0 {
+		return nil, badRequest("invalid attachments", violations)
 	}
 	return resp, nil
 }
 
+// badRequest creates an InvalidArgument status error detailing every field violation.
+func badRequest(message string, violations []*errdetails.BadRequest_FieldViolation) error {
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, message)
+	}
+	return st.Err()
+}
+
 // mapWorkflowRequestToModel maps a workflow request and its ID to a model.Communication object,
 // setting the communication type and response channels.
 func mapWorkflowRequestToModel(workflowId string, req workflows.Request) *model.Communication {
@@ -95,7 +383,9 @@ func mapWorkflowRequestToModel(workflowId string, req workflows.Request) *model.
 		ID:               workflowId,
 		Type:             communicationType,
 		Domain:           req.Domain,
+		Policy:           req.Policy,
 		ResponseChannels: make([]*model.ResponseChannel, len(req.ResponseRequests)),
+		Recipients:       mapRecipients(req),
 	}
 	for index, channel := range req.ResponseRequests {
 		resp.ResponseChannels[index] = &model.ResponseChannel{
@@ -108,13 +398,132 @@ func mapWorkflowRequestToModel(workflowId string, req workflows.Request) *model.
 	return resp
 }
 
-// downloadFile downloads the file from the specified URL and returns its contents as a byte slice.
-// Returns an error if the download fails.
-func downloadFile(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+// mapRecipients returns the recipients of a workflow request, see model.EmailRecipient and model.CustomerRecipient.
+// Addresses were validated when the request was mapped in, so any which fail to parse are skipped.
+func mapRecipients(req workflows.Request) []string {
+	var recipients []string
+	if req.EmailRequest != nil {
+		for _, addresses := range [][]string{req.EmailRequest.ToAddresses, req.EmailRequest.CcAddresses, req.EmailRequest.BccAddresses} {
+			for _, address := range addresses {
+				parsed, err := email.ParseAddress(address)
+				if err != nil {
+					continue
+				}
+				recipients = append(recipients, model.EmailRecipient(parsed.Address))
+			}
+		}
+	}
+	if req.PushRequest != nil && req.PushRequest.ExternalCustomerId != "" {
+		recipients = append(recipients, model.CustomerRecipient(req.PushRequest.ExternalCustomerId))
+	}
+	return recipients
+}
+
+// mapAuditFilterIn maps the filters of a ListAuditEventsRequest, including its page token, to a model.AuditFilter.
+func mapAuditFilterIn(req *pb.ListAuditEventsRequest) (model.AuditFilter, error) {
+	filter := model.AuditFilter{
+		Principal:  req.GetPrincipal(),
+		Domain:     req.GetDomain(),
+		Method:     req.GetMethod(),
+		Code:       req.GetCode(),
+		WorkflowID: req.GetWorkflowId(),
+	}
+	if req.GetStartTime() != nil {
+		since := req.GetStartTime().AsTime()
+		filter.Since = &since
+	}
+	if req.GetEndTime() != nil {
+		until := req.GetEndTime().AsTime()
+		filter.Until = &until
+	}
+	if req.GetPageToken() != "" {
+		before, err := strconv.ParseInt(req.GetPageToken(), 10, 64)
+		if err != nil || before <= 0 {
+			return filter, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		filter.Before = before
+	}
+	return filter, nil
+}
+
+// mapCommunicationFilterIn maps the filters of a ListCommunicationsRequest, including its page token, to a
+// model.CommunicationFilter.
+func mapCommunicationFilterIn(req *pb.ListCommunicationsRequest) (model.CommunicationFilter, error) {
+	filter := model.CommunicationFilter{
+		Domain: req.GetDomain(),
+		Status: model.Status(req.GetStatus()),
+	}
+	switch filter.Status {
+	case "", model.Pending, model.Success, model.Failed, model.Expired, model.Cancelled:
+	default:
+		return filter, status.Errorf(codes.InvalidArgument, "invalid status %q", req.GetStatus())
+	}
+	if req.GetStartTime() != nil {
+		since := req.GetStartTime().AsTime()
+		filter.Since = &since
+	}
+	if req.GetEndTime() != nil {
+		until := req.GetEndTime().AsTime()
+		filter.Until = &until
+	}
+	if req.GetPageToken() != "" {
+		micros, id, ok := strings.Cut(req.GetPageToken(), "_")
+		createdAt, err := strconv.ParseInt(micros, 10, 64)
+		if !ok || err != nil || id == "" {
+			return filter, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		filter.Before = &model.CommunicationCursor{
+			CreatedAt: time.UnixMicro(createdAt),
+			ID:        id,
+		}
+	}
+	return filter, nil
+}
+
+// communicationPageToken returns the page token of the communications after comm.
+func communicationPageToken(comm model.Communication) string {
+	return strconv.FormatInt(comm.CreatedAt.UnixMicro(), 10) + "_" + comm.ID
+}
+
+// mapCommunicationsOut maps communications to their protobuf representation.
+func mapCommunicationsOut(comms []model.Communication) []*pb.Communication {
+	resp := make([]*pb.Communication, len(comms))
+	for i, comm := range comms {
+		resp[i] = &pb.Communication{
+			Id:            comm.ID,
+			Domain:        comm.Domain,
+			Type:          string(comm.Type),
+			Status:        string(comm.Status),
+			CreatedAt:     timestamppb.New(comm.CreatedAt),
+			ErrorKind:     comm.ErrorKind,
+			ErrorProvider: comm.ErrorProvider,
+		}
+		if comm.Status != model.Pending {
+			resp[i].SentAt = timestamppb.New(comm.SentAt)
+		}
+		if comm.ExternalId != nil {
+			resp[i].ExternalId = *comm.ExternalId
+		}
+	}
+	return resp
+}
+
+// mapAuditEventsOut maps audit events to their protobuf representation.
+func mapAuditEventsOut(events []model.AuditEvent) []*pb.AuditEvent {
+	resp := make([]*pb.AuditEvent, len(events))
+	for i, event := range events {
+		resp[i] = &pb.AuditEvent{
+			Id:               event.ID,
+			OccurredAt:       timestamppb.New(event.OccurredAt),
+			Principal:        event.Principal,
+			ClaimedPrincipal: event.ClaimedPrincipal,
+			Peer:             event.Peer,
+			Domain:           event.Domain,
+			Method:           event.Method,
+			RequestHash:      event.RequestHash,
+			Code:             event.Code,
+			WorkflowId:       event.WorkflowID,
+		}
 	}
-	defer func() { _ = resp.Body.Close() }()
-	return io.ReadAll(resp.Body)
+	return resp
 }
@@ -2,20 +2,30 @@ package temporalclient
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
 
 	"github.com/anicoll/unicom/cmd/worker"
 	"github.com/anicoll/unicom/internal/workflows"
 )
 
+// renderTimeout bounds how long RenderCommunication waits for a worker.
+const renderTimeout = time.Minute * 2
+
 type Client struct {
-	temporalClient client.Client
+	temporalClient  client.Client
+	renderTaskQueue string
 }
 
-func New(tc client.Client) *Client {
+// New creates a Client which renders communications on renderTaskQueue.
+func New(tc client.Client, renderTaskQueue string) *Client {
 	return &Client{
-		temporalClient: tc,
+		temporalClient:  tc,
+		renderTaskQueue: renderTaskQueue,
 	}
 }
 
@@ -24,14 +34,60 @@ func (c *Client) GetWorkflowResult(ctx context.Context, workflowId string) error
 	return workflowRun.Get(ctx, nil)
 }
 
+// StartCommunicationWorkflow starts the workflow of a communication. The
+// workflow of a retried request may have been started already, which isn't
+// an error, and a workflow ID is never run twice.
 func (c *Client) StartCommunicationWorkflow(ctx context.Context, req workflows.Request, workflowId string) error {
 	_, err := c.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
-		TaskQueue: worker.CommunicationTaskQueue,
-		ID:        workflowId,
+		TaskQueue:             worker.TaskQueue(req.Priority),
+		ID:                    workflowId,
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
 	}, workflows.CommunicationWorkflow, req)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil
+	}
 	return err
 }
 
+// WorkflowStarted reports whether the workflow with workflowId was started,
+// whether it is still running or has closed.
+func (c *Client) WorkflowStarted(ctx context.Context, workflowId string) (bool, error) {
+	_, err := c.temporalClient.DescribeWorkflowExecution(ctx, workflowId, "")
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RenderCommunication renders a communication on a worker polling the render
+// task queue, under workflowId, and waits for the result. It fails straight
+// away when no worker polls the queue, e.g. when no worker runs its lane, and
+// the workflow is given up on after renderTimeout.
+func (c *Client) RenderCommunication(ctx context.Context, workflowId string, req workflows.RenderRequest) (*workflows.Rendered, error) {
+	queue, err := c.temporalClient.DescribeTaskQueue(ctx, c.renderTaskQueue, enums.TASK_QUEUE_TYPE_WORKFLOW)
+	if err != nil {
+		return nil, err
+	}
+	if len(queue.GetPollers()) == 0 {
+		return nil, serviceerror.NewFailedPreconditionf("no worker polls the %s task queue", c.renderTaskQueue)
+	}
+	run, err := c.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		TaskQueue:                c.renderTaskQueue,
+		ID:                       workflowId,
+		WorkflowExecutionTimeout: renderTimeout,
+	}, workflows.RenderWorkflow, req)
+	if err != nil {
+		return nil, err
+	}
+	rendered := &workflows.Rendered{}
+	if err := run.Get(ctx, rendered); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
 func (c *Client) GetWorkflowStatus(ctx context.Context, req workflows.StatusRequest) (string, error) {
 	queryResponse, err := c.temporalClient.QueryWorkflowWithOptions(ctx, &client.QueryWorkflowWithOptionsRequest{
 		WorkflowID: req.WorkflowId,
@@ -47,3 +103,7 @@ func (c *Client) GetWorkflowStatus(ctx context.Context, req workflows.StatusRequ
 	}
 	return string(respo.Status), nil
 }
+
+func (c *Client) CancelWorkflow(ctx context.Context, workflowId string) error {
+	return c.temporalClient.CancelWorkflow(ctx, workflowId, "")
+}
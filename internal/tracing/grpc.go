@@ -0,0 +1,138 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// UnaryServerInterceptor starts a server span for each unary call, continuing
+// the trace of the caller.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := startServerSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		endRPCSpan(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor starts a server span for each streaming call,
+// continuing the trace of the caller.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &tracedStream{ServerStream: ss, ctx: ctx})
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor starts a client span for each unary call and
+// propagates it to the server.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := startClientSpan(ctx, method)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor starts a client span for each streaming call and
+// propagates it to the server. The span ends once the stream has been set up,
+// the server's span covers the rest of the call.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := startClientSpan(ctx, method)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		endRPCSpan(span, err)
+		return stream, err
+	}
+}
+
+// tracedStream carries the span started for a stream to its handler.
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+func startServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md.Copy()))
+	return tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(rpcAttributes(fullMethod)...),
+	)
+}
+
+func startClientSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	ctx, span := tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(rpcAttributes(fullMethod)...),
+	)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// rpcAttributes returns the semantic attributes of a call to a method named
+// like /package.Service/Method.
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if ok {
+		attrs = append(attrs, semconv.RPCService(service), semconv.RPCMethod(method))
+	}
+	return attrs
+}
+
+func endRPCSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if code != codes.OK {
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+	span.End()
+}
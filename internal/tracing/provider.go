@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ChannelKey is the channel a provider delivers on, e.g. email or push.
+	ChannelKey = attribute.Key("unicom.channel")
+	// DomainKey is the domain a communication was sent for.
+	DomainKey = attribute.Key("unicom.domain")
+	// MessageIDKey is the ID the provider gave the message it accepted.
+	MessageIDKey = attribute.Key("unicom.provider.message_id")
+)
+
+// StartProviderSpan starts a client span around a call to the provider named
+// like its circuit breaker, e.g. email/ses or push/onesignal.
+func StartProviderSpan(ctx context.Context, channel, provider string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, ChannelKey.String(channel), semconv.PeerService(provider))
+	return tracer().Start(ctx, "send "+provider,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// EndProviderSpan ends a span started by StartProviderSpan with the outcome of
+// the call.
+func EndProviderSpan(span trace.Span, id *string, err error) {
+	if id != nil {
+		span.SetAttributes(MessageIDKey.String(*id))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
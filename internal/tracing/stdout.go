@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// StdoutExporter writes each span as a line of JSON, for local development.
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{
+		w: w,
+	}
+}
+
+// stdoutSpan is the JSON written for each span.
+type stdoutSpan struct {
+	Name         string         `json:"name"`
+	Kind         string         `json:"kind"`
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Start        time.Time      `json:"start"`
+	End          time.Time      `json:"end"`
+	Status       string         `json:"status"`
+	Description  string         `json:"description,omitempty"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+}
+
+func (e *StdoutExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	encoder := json.NewEncoder(e.w)
+	for _, span := range spans {
+		out := stdoutSpan{
+			Name:        span.Name(),
+			Kind:        span.SpanKind().String(),
+			TraceID:     span.SpanContext().TraceID().String(),
+			SpanID:      span.SpanContext().SpanID().String(),
+			Start:       span.StartTime(),
+			End:         span.EndTime(),
+			Status:      span.Status().Code.String(),
+			Description: span.Status().Description,
+		}
+		if span.Parent().IsValid() {
+			out.ParentSpanID = span.Parent().SpanID().String()
+		}
+		if attrs := span.Attributes(); len(attrs) > 0 {
+			out.Attributes = make(map[string]any, len(attrs))
+			for _, attr := range attrs {
+				out.Attributes[string(attr.Key)] = attr.Value.AsInterface()
+			}
+		}
+		if err := encoder.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *StdoutExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,95 @@
+// Package tracing sets up OpenTelemetry tracing, so a communication can be
+// followed from the HTTP gateway through gRPC, Temporal and out to the
+// providers delivering it.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer unicom's own spans are started with.
+const instrumentationName = "github.com/anicoll/unicom"
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config selects where spans are exported to.
+type Config struct {
+	// Exporter is one of none, otlp or stdout.
+	Exporter string
+	// Endpoint is the host:port of the OTLP gRPC collector.
+	Endpoint string
+	// Insecure connects to the collector without TLS.
+	Insecure bool
+	// SampleRatio is the share of new traces which are sampled. Spans with a
+	// parent follow its decision.
+	SampleRatio float64
+}
+
+var DefaultConfig = Config{
+	Exporter:    ExporterNone,
+	Endpoint:    "localhost:4317",
+	SampleRatio: 1,
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The propagator is installed even when no exporter is, so callers'
+// traces carry on through unicom. The returned function flushes and stops the
+// exporter.
+func Setup(ctx context.Context, cfg Config, serviceName, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		var err error
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating otlp exporter: %w", err)
+		}
+	case ExporterStdout:
+		exporter = NewStdoutExporter(os.Stdout)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, must be one of none, otlp or stdout", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// tracer returns the tracer of the global provider, so spans go wherever Setup
+// last sent them.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,108 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/internal/tracing"
+)
+
+type TracingTestSuite struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
+
+func (s *TracingTestSuite) SetupTest() {
+	s.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+func (s *TracingTestSuite) TestGRPC_PropagatesFromClientToServer() {
+	var outgoing metadata.MD
+	client := tracing.UnaryClientInterceptor()
+	err := client(context.Background(), pb.UnicomService_SendCommunication_FullMethodName, nil, nil, nil,
+		func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+	s.Require().NoError(err)
+	s.Require().Len(outgoing.Get("traceparent"), 1)
+
+	server := tracing.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UnicomService_SendCommunication_FullMethodName}
+	_, err = server(metadata.NewIncomingContext(context.Background(), outgoing), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.InvalidArgument, "domain is required")
+	})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+
+	spans := s.recorder.Ended()
+	s.Require().Len(spans, 2)
+	clientSpan, serverSpan := spans[0], spans[1]
+	s.Equal(trace.SpanKindClient, clientSpan.SpanKind())
+	s.Equal(trace.SpanKindServer, serverSpan.SpanKind())
+	s.Equal(clientSpan.SpanContext().TraceID(), serverSpan.SpanContext().TraceID())
+	s.Equal(clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+	s.Equal("unicom.api.v1.UnicomService/SendCommunication", serverSpan.Name())
+	s.Contains(serverSpan.Attributes(), attribute.String("rpc.method", "SendCommunication"))
+	s.Contains(serverSpan.Attributes(), attribute.Int("rpc.grpc.status_code", int(codes.InvalidArgument)))
+	s.Equal(otelcodes.Error, serverSpan.Status().Code)
+}
+
+func (s *TracingTestSuite) TestProviderSpan_RecordsOutcome() {
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "activity")
+	_, span := tracing.StartProviderSpan(ctx, "email", "email/ses/eu-west-2", tracing.DomainKey.String("billing"))
+	id := "message-1"
+	tracing.EndProviderSpan(span, &id, nil)
+	_, span = tracing.StartProviderSpan(ctx, "email", "email/smtp")
+	tracing.EndProviderSpan(span, nil, errors.New("connection refused"))
+	parent.End()
+
+	spans := s.recorder.Ended()
+	s.Require().Len(spans, 3)
+	s.Equal("send email/ses/eu-west-2", spans[0].Name())
+	s.Equal(parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	s.Contains(spans[0].Attributes(), tracing.MessageIDKey.String("message-1"))
+	s.Contains(spans[0].Attributes(), tracing.DomainKey.String("billing"))
+	s.Equal(otelcodes.Error, spans[1].Status().Code)
+	s.Len(spans[1].Events(), 1, "the error is recorded")
+}
+
+func (s *TracingTestSuite) TestStdoutExporter_WritesJSONLines() {
+	var out bytes.Buffer
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(tracing.NewStdoutExporter(&out)))
+	_, span := provider.Tracer("test").Start(context.Background(), "send email/ses", trace.WithAttributes(tracing.DomainKey.String("billing")))
+	span.End()
+
+	decoded := map[string]any{}
+	s.Require().NoError(json.Unmarshal(out.Bytes(), &decoded))
+	s.Equal("send email/ses", decoded["name"])
+	s.Equal(span.SpanContext().TraceID().String(), decoded["trace_id"])
+	s.Equal(map[string]any{"unicom.domain": "billing"}, decoded["attributes"])
+}
+
+func (s *TracingTestSuite) TestSetup_UnknownExporter() {
+	_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: "zipkin"}, "unicom", "test")
+	s.ErrorContains(err, "zipkin")
+}
@@ -2,26 +2,43 @@ package workflows
 
 import (
 	"context"
+	"slices"
 
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/anicoll/unicom/internal/attachment"
 	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/metrics"
 	"github.com/anicoll/unicom/internal/model"
 	"github.com/anicoll/unicom/internal/push"
 )
 
 type pushService interface {
 	Send(ctx context.Context, args push.Notification) (*string, error)
+	Render(ctx context.Context, args push.Notification) ([]byte, error)
 }
 
 type emailService interface {
 	Send(ctx context.Context, args email.Request) (*string, error)
 }
 
+type attachmentFetcher interface {
+	Fetch(ctx context.Context, name, rawURL string) (*attachment.Object, error)
+}
+
+type payloadStore interface {
+	Load(ctx context.Context, req email.Request) (email.Request, error)
+	Delete(ctx context.Context, req email.Request) error
+}
+
 type notificationService interface {
 	Send(ctx context.Context, args model.ResponseChannelRequest) (*string, error)
 }
 
 type postgres interface {
 	SetCommunicationStatus(ctx context.Context, workflowId string, status model.Status, externalId *string) error
+	SetCommunicationError(ctx context.Context, workflowId string, details failure.Details) error
 	CreateResponseChannel(ctx context.Context, channel model.ResponseChannel) error
 	SetResponseChannelStatus(ctx context.Context, id, externalId string, status model.Status) error
 }
@@ -32,38 +49,161 @@ type UnicomActivities struct {
 	sqsService     notificationService
 	webhookService notificationService
 	database       postgres
+	attachments    attachmentFetcher
+	payloads       payloadStore
 }
 
-func NewActivities(es emailService, p pushService, sqs, webhook notificationService, db postgres) *UnicomActivities {
+func NewActivities(es emailService, p pushService, sqs, webhook notificationService, db postgres, attachments attachmentFetcher, payloads payloadStore) *UnicomActivities {
 	return &UnicomActivities{
 		emailService:   es,
 		sqsService:     sqs,
 		webhookService: webhook,
 		database:       db,
 		pushService:    p,
+		attachments:    attachments,
+		payloads:       payloads,
 	}
 }
 
 func (a *UnicomActivities) SendEmail(ctx context.Context, req email.Request) (*string, error) {
-	return a.emailService.Send(ctx, req)
+	req, err := a.payloads.Load(ctx, req)
+	if err != nil {
+		return nil, applicationError(err)
+	}
+	req, err = a.fetchAttachments(ctx, req)
+	if err != nil {
+		return nil, applicationError(err)
+	}
+	id, err := a.emailService.Send(ctx, req)
+	recordSendAttempt(ctx, req.Domain, model.Email, err)
+	return id, applicationError(err)
+}
+
+// fetchAttachments downloads the attachments given by URL. They are fetched on
+// every attempt rather than in an activity of their own so the files never
+// become part of the workflow's history.
+func (a *UnicomActivities) fetchAttachments(ctx context.Context, req email.Request) (email.Request, error) {
+	attachments := slices.Clone(req.Attachments)
+	for i, att := range attachments {
+		if att.URL == "" {
+			continue
+		}
+		object, err := a.attachments.Fetch(ctx, att.Name, att.URL)
+		if err != nil {
+			return req, err
+		}
+		attachments[i].Data = object.Data
+		attachments[i].ContentType = object.ContentType
+	}
+	req.Attachments = attachments
+	return req, nil
+}
+
+// DeleteEmailPayloads removes the content offloaded from an email once the
+// communication no longer needs it.
+func (a *UnicomActivities) DeleteEmailPayloads(ctx context.Context, req email.Request) error {
+	return a.payloads.Delete(ctx, req)
 }
 
 func (a *UnicomActivities) SendPush(ctx context.Context, req push.Notification) (*string, error) {
-	return a.pushService.Send(ctx, req)
+	id, err := a.pushService.Send(ctx, req)
+	recordSendAttempt(ctx, req.Domain, model.Push, err)
+	return id, applicationError(err)
+}
+
+// RenderEmail returns the raw message an email is sent as, with the
+// attachments given by URL fetched.
+func (a *UnicomActivities) RenderEmail(ctx context.Context, req email.Request) ([]byte, error) {
+	req, err := a.payloads.Load(ctx, req)
+	if err != nil {
+		return nil, applicationError(err)
+	}
+	req, err = a.fetchAttachments(ctx, req)
+	if err != nil {
+		return nil, applicationError(err)
+	}
+	raw, err := email.Render(req)
+	if err == nil {
+		err = checkRenderedSize(raw)
+	}
+	return raw, applicationError(err)
+}
+
+// RenderPush returns the body the push provider of the notification's domain
+// would be called with.
+func (a *UnicomActivities) RenderPush(ctx context.Context, req push.Notification) ([]byte, error) {
+	payload, err := a.pushService.Render(ctx, req)
+	if err == nil {
+		err = checkRenderedSize(payload)
+	}
+	return payload, applicationError(err)
+}
+
+// checkRenderedSize refuses a rendered communication too large to return
+// through the render workflow's history.
+func checkRenderedSize(rendered []byte) error {
+	if len(rendered) > maxRenderedSize {
+		return failure.Errorf(failure.PayloadTooLarge, "", "rendered communication is %d bytes, more than the %d bytes which can be returned", len(rendered), maxRenderedSize)
+	}
+	return nil
 }
 
 func (a *UnicomActivities) NotifySqs(ctx context.Context, req model.ResponseChannelRequest) (*string, error) {
-	return a.sqsService.Send(ctx, req)
+	id, err := a.sqsService.Send(ctx, req)
+	recordResponseChannelDelivery(ctx, model.Sqs, err)
+	return id, applicationError(err)
 }
 
 func (a *UnicomActivities) NotifyWebhook(ctx context.Context, req model.ResponseChannelRequest) (*string, error) {
-	return a.webhookService.Send(ctx, req)
+	id, err := a.webhookService.Send(ctx, req)
+	recordResponseChannelDelivery(ctx, model.Webhook, err)
+	return id, applicationError(err)
 }
 
 func (a *UnicomActivities) UpdateCommunicationStatus(ctx context.Context, workflowId string, status model.Status, externalId *string) error {
 	return a.database.SetCommunicationStatus(ctx, workflowId, status, externalId)
 }
 
+func (a *UnicomActivities) RecordCommunicationError(ctx context.Context, workflowId string, details failure.Details) error {
+	return a.database.SetCommunicationError(ctx, workflowId, details)
+}
+
 func (a *UnicomActivities) SaveResponseChannelOutcome(ctx context.Context, id, externalId string, status model.Status) error {
 	return a.database.SetResponseChannelStatus(ctx, id, externalId, status)
 }
+
+// recordSendAttempt counts an attempt at sending a communication, and whether
+// it was a retry.
+func recordSendAttempt(ctx context.Context, domain string, channel model.NotificationType, err error) {
+	handler := metrics.Activity(ctx).WithTags(map[string]string{
+		metrics.DomainTag:  domain,
+		metrics.ChannelTag: metrics.Channel(channel),
+	})
+	handler.WithTags(map[string]string{metrics.OutcomeTag: metrics.Outcome(err)}).Counter(metrics.SendAttempts).Inc(1)
+	if metrics.Attempt(ctx) > 1 {
+		handler.Counter(metrics.SendRetries).Inc(1)
+	}
+}
+
+// recordResponseChannelDelivery counts an attempt at notifying a response
+// channel.
+func recordResponseChannelDelivery(ctx context.Context, channelType model.ResponseChannelType, err error) {
+	metrics.Activity(ctx).WithTags(map[string]string{
+		metrics.TypeTag:    string(channelType),
+		metrics.OutcomeTag: metrics.Outcome(err),
+	}).Counter(metrics.ResponseChannelDeliveries).Inc(1)
+}
+
+// applicationError converts a provider error into a temporal application error
+// typed by its failure.Kind, so errors which can't succeed on retry (invalid
+// recipients, rejected credentials, ...) aren't retried.
+func applicationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	details := failure.DetailsOf(err)
+	if details.Kind.Retryable() {
+		return temporal.NewApplicationErrorWithCause(err.Error(), string(details.Kind), err, details)
+	}
+	return temporal.NewNonRetryableApplicationError(err.Error(), string(details.Kind), err, details)
+}
@@ -0,0 +1,139 @@
+package workflows_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/payload"
+	"github.com/anicoll/unicom/internal/workflows"
+)
+
+type stubEmailService struct {
+	err error
+}
+
+func (s stubEmailService) Send(context.Context, email.Request) (*string, error) {
+	return nil, s.err
+}
+
+type recordingEmailService struct {
+	sent []email.Request
+}
+
+func (s *recordingEmailService) Send(_ context.Context, req email.Request) (*string, error) {
+	s.sent = append(s.sent, req)
+	id := "message-id"
+	return &id, nil
+}
+
+type stubAttachmentFetcher struct {
+	object *attachment.Object
+	err    error
+}
+
+func (f stubAttachmentFetcher) Fetch(context.Context, string, string) (*attachment.Object, error) {
+	return f.object, f.err
+}
+
+type ActivitiesTestSuite struct {
+	suite.Suite
+}
+
+func TestActivitiesTestSuite(t *testing.T) {
+	suite.Run(t, new(ActivitiesTestSuite))
+}
+
+func (s *ActivitiesTestSuite) TestSendEmail_InvalidRecipientIsNotRetried() {
+	activities := workflows.NewActivities(stubEmailService{
+		err: failure.Errorf(failure.InvalidRecipient, "ses", "address does not exist"),
+	}, nil, nil, nil, nil, nil, payload.NewOffloader(nil, 0))
+
+	_, err := activities.SendEmail(context.Background(), email.Request{})
+
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(err, &appErr))
+	s.True(appErr.NonRetryable())
+	s.Equal(string(failure.InvalidRecipient), appErr.Type())
+
+	details := failure.Details{}
+	s.NoError(appErr.Details(&details))
+	s.Equal("ses", details.Provider)
+}
+
+func (s *ActivitiesTestSuite) TestSendEmail_OutageIsRetried() {
+	activities := workflows.NewActivities(stubEmailService{
+		err: failure.New(failure.ProviderOutage, "ses", errors.New("service unavailable")),
+	}, nil, nil, nil, nil, nil, payload.NewOffloader(nil, 0))
+
+	_, err := activities.SendEmail(context.Background(), email.Request{})
+
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(err, &appErr))
+	s.False(appErr.NonRetryable())
+}
+
+func (s *ActivitiesTestSuite) TestSendEmail_FetchesUrlAttachments() {
+	emailService := &recordingEmailService{}
+	activities := workflows.NewActivities(emailService, nil, nil, nil, nil, stubAttachmentFetcher{
+		object: &attachment.Object{Data: []byte("%PDF"), ContentType: "application/pdf"},
+	}, payload.NewOffloader(nil, 0))
+	req := email.Request{Attachments: []email.Attachment{
+		{Name: "invoice.pdf", URL: "https://files.example.com/invoice.pdf"},
+		{Name: "notes.txt", Data: []byte("notes")},
+	}}
+
+	_, err := activities.SendEmail(context.Background(), req)
+	s.Require().NoError(err)
+
+	s.Require().Len(emailService.sent, 1)
+	attachments := emailService.sent[0].Attachments
+	s.Equal([]byte("%PDF"), attachments[0].Data)
+	s.Equal("application/pdf", attachments[0].ContentType)
+	s.Equal([]byte("notes"), attachments[1].Data)
+	s.Nil(req.Attachments[0].Data, "the activity input is left untouched")
+}
+
+func (s *ActivitiesTestSuite) TestSendEmail_AttachmentTooLargeIsNotRetried() {
+	emailService := &recordingEmailService{}
+	activities := workflows.NewActivities(emailService, nil, nil, nil, nil, stubAttachmentFetcher{
+		err: failure.Errorf(failure.PayloadTooLarge, "attachment", "invoice.pdf is larger than 10 bytes"),
+	}, payload.NewOffloader(nil, 0))
+
+	_, err := activities.SendEmail(context.Background(), email.Request{Attachments: []email.Attachment{
+		{Name: "invoice.pdf", URL: "https://files.example.com/invoice.pdf"},
+	}})
+
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(err, &appErr))
+	s.True(appErr.NonRetryable())
+	s.Empty(emailService.sent)
+}
+
+func (s *ActivitiesTestSuite) TestSendEmail_LoadsOffloadedContent() {
+	emailService := &recordingEmailService{}
+	offloader := payload.NewOffloader(payload.NewFileStore(s.T().TempDir()), 4)
+	activities := workflows.NewActivities(emailService, nil, nil, nil, nil, nil, offloader)
+	req := email.Request{
+		HtmlBody:    "<p>hello</p>",
+		Attachments: []email.Attachment{{Name: "notes.txt", Data: []byte("notes")}},
+	}
+	s.Require().NoError(offloader.Offload(context.Background(), "communication-id", &req))
+	s.Empty(req.HtmlBody)
+
+	_, err := activities.SendEmail(context.Background(), req)
+	s.Require().NoError(err)
+	s.Require().Len(emailService.sent, 1)
+	s.Equal("<p>hello</p>", emailService.sent[0].HtmlBody)
+	s.Equal([]byte("notes"), emailService.sent[0].Attachments[0].Data)
+
+	s.NoError(activities.DeleteEmailPayloads(context.Background(), req))
+	_, err = offloader.Load(context.Background(), req)
+	s.Error(err)
+}
@@ -1,10 +1,14 @@
 package workflows
 
 import (
+	"errors"
 	"time"
 
 	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/metrics"
 	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/payload"
 	"github.com/anicoll/unicom/internal/push"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
@@ -14,8 +18,19 @@ type Request struct {
 	EmailRequest     *email.Request
 	PushRequest      *push.Notification
 	ResponseRequests []*ResponseRequest
-	SleepDuration    time.Duration
-	Domain           string
+	// SleepDuration is how long workflows started before SendAt existed wait
+	// before sending.
+	SleepDuration time.Duration
+	// SendAt is when to send, if later than the workflow starts.
+	SendAt *time.Time
+	// Windows are the delivery windows the communication must be sent in,
+	// every one of them has to allow it.
+	Windows []model.DeliveryWindow
+	Domain  string
+	Policy  model.DeliveryPolicy
+	// Priority is the lane the communication is processed in, which decides
+	// its task queue.
+	Priority model.Priority
 }
 
 type ResponseRequest struct {
@@ -34,6 +49,7 @@ const (
 	WorkflowActivityComplete Status = "ACTIVITY_COMPLETE"
 	WorkflowResponding       Status = "RESPONDING"
 	WorkflowComplete         Status = "COMPLETE"
+	WorkflowExpired          Status = "EXPIRED"
 )
 
 type WorkflowState struct {
@@ -46,14 +62,7 @@ type StatusRequest struct {
 }
 
 func CommunicationWorkflow(ctx workflow.Context, request Request) error {
-	ao := workflow.ActivityOptions{
-		StartToCloseTimeout: 30 * time.Second,
-		RetryPolicy: &temporal.RetryPolicy{
-			MaximumAttempts:    10,
-			BackoffCoefficient: 1.2,
-		},
-	}
-	ctx = workflow.WithActivityOptions(ctx, ao)
+	ctx = workflow.WithActivityOptions(ctx, defaultActivityOptions())
 	logger := workflow.GetLogger(ctx)
 	var activities *UnicomActivities
 	info := workflow.GetInfo(ctx)
@@ -73,33 +82,55 @@ func CommunicationWorkflow(ctx workflow.Context, request Request) error {
 
 	currentState.Status = WorkflowWaiting
 	// send in the future
-	err = workflow.Sleep(ctx, request.SleepDuration)
+	err = workflow.Sleep(ctx, untilSendAt(ctx, request))
 	if err != nil {
-		currentState.Status = WorkflowError
-		currentState.Error = err
-		return err
+		return recordWaitFailure(ctx, request, currentState, err)
+	}
+	if len(request.Windows) > 0 {
+		wait := untilDeliveryWindow(ctx, request.Windows)
+		if wait > 0 {
+			workflow.GetMetricsHandler(ctx).WithTags(map[string]string{
+				metrics.DomainTag: request.Domain,
+			}).Counter(metrics.CommunicationsDeferred).Inc(1)
+		}
+		err = workflow.Sleep(ctx, wait)
+		if err != nil {
+			return recordWaitFailure(ctx, request, currentState, err)
+		}
 	}
 
 	var messageId *string
 
-	if request.EmailRequest != nil {
+	if expired(ctx, request.Policy) {
+		logger.Info("Communication expired before it could be sent.")
+		currentState.Status = WorkflowExpired
 		err = workflow.ExecuteActivity(ctx,
+			activities.UpdateCommunicationStatus,
+			info.WorkflowExecution.ID,
+			model.Expired,
+			messageId,
+		).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if request.EmailRequest != nil {
+			recordOutcome(ctx, request.Domain, model.Email, model.Expired)
+		}
+		if request.PushRequest != nil {
+			recordOutcome(ctx, request.Domain, model.Push, model.Expired)
+		}
+	}
+
+	sendCtx := workflow.WithActivityOptions(ctx, sendActivityOptions(workflow.Now(ctx), request.Policy))
+
+	if request.EmailRequest != nil && currentState.Status != WorkflowExpired {
+		err = workflow.ExecuteActivity(sendCtx,
 			activities.SendEmail,
 			*request.EmailRequest,
 		).Get(ctx, &messageId)
 		if err != nil {
 			logger.Error("Activity failed.", "activities.SendEmail", "Error", err)
-			currentState.Status = WorkflowError
-			currentState.Error = err
-			err = workflow.ExecuteActivity(ctx,
-				activities.UpdateCommunicationStatus,
-				info.WorkflowExecution.ID,
-				model.Failed,
-				messageId,
-			).Get(ctx, nil)
-			if err != nil {
-				logger.Error("Activity failed.", "activities.MarkCommunicationAsFailed", "Error", err)
-			}
+			err = recordSendFailure(ctx, request, model.Email, currentState, err, messageId)
 		} else {
 			err = workflow.ExecuteActivity(ctx,
 				activities.UpdateCommunicationStatus,
@@ -110,28 +141,30 @@ func CommunicationWorkflow(ctx workflow.Context, request Request) error {
 			if err != nil {
 				return err
 			}
+			recordOutcome(ctx, request.Domain, model.Email, model.Success)
 		}
 	}
 
-	if request.PushRequest != nil {
-		err = workflow.ExecuteActivity(ctx,
+	if request.EmailRequest != nil && len(payload.Refs(*request.EmailRequest)) > 0 &&
+		workflow.GetVersion(ctx, "delete-email-payloads", workflow.DefaultVersion, 1) == 1 {
+		// the content is no longer needed once the email is sent, failed or expired.
+		deleteErr := workflow.ExecuteActivity(ctx,
+			activities.DeleteEmailPayloads,
+			*request.EmailRequest,
+		).Get(ctx, nil)
+		if deleteErr != nil {
+			logger.Error("Activity failed.", "activities.DeleteEmailPayloads", "Error", deleteErr)
+		}
+	}
+
+	if request.PushRequest != nil && currentState.Status != WorkflowExpired {
+		err = workflow.ExecuteActivity(sendCtx,
 			activities.SendPush,
 			*request.PushRequest,
 		).Get(ctx, &messageId)
 		if err != nil {
 			logger.Error("Activity failed.", "activities.SendPush", "Error", err)
-			currentState.Status = WorkflowError
-			currentState.Error = err
-			err = workflow.ExecuteActivity(ctx,
-				activities.UpdateCommunicationStatus,
-				info.WorkflowExecution.ID,
-				model.Failed,
-				messageId,
-			).Get(ctx, nil)
-			if err != nil {
-				logger.Error("Activity failed.", "activities.MarkCommunicationAsFailed", "Error", err)
-			}
-			return err
+			return recordSendFailure(ctx, request, model.Push, currentState, err, messageId)
 		} else {
 			err = workflow.ExecuteActivity(ctx,
 				activities.UpdateCommunicationStatus,
@@ -142,9 +175,12 @@ func CommunicationWorkflow(ctx workflow.Context, request Request) error {
 			if err != nil {
 				return err
 			}
+			recordOutcome(ctx, request.Domain, model.Push, model.Success)
 		}
 	}
-	currentState.Status = WorkflowActivityComplete
+	if currentState.Status != WorkflowExpired {
+		currentState.Status = WorkflowActivityComplete
+	}
 
 	for _, responseRequest := range request.ResponseRequests {
 		switch responseRequest.Type {
@@ -205,6 +241,149 @@ func CommunicationWorkflow(ctx workflow.Context, request Request) error {
 	return err
 }
 
+func defaultActivityOptions() workflow.ActivityOptions {
+	return workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts:    10,
+			BackoffCoefficient: 1.2,
+		},
+	}
+}
+
+// sendActivityOptions applies the communication's delivery policy to the send
+// activities. Retries stop once the policy's expiry time is reached.
+// Communications started before policies were stored have a zero policy and
+// keep the defaults.
+func sendActivityOptions(now time.Time, policy model.DeliveryPolicy) workflow.ActivityOptions {
+	ao := defaultActivityOptions()
+	if policy.AttemptTimeout > 0 {
+		ao.StartToCloseTimeout = policy.AttemptTimeout
+	}
+	if policy.MaxAttempts > 0 {
+		ao.RetryPolicy.MaximumAttempts = policy.MaxAttempts
+	}
+	if policy.BackoffCoefficient >= 1 {
+		ao.RetryPolicy.BackoffCoefficient = policy.BackoffCoefficient
+	}
+	if policy.ExpireAt != nil {
+		ao.ScheduleToCloseTimeout = policy.ExpireAt.Sub(now)
+	}
+	return ao
+}
+
+// untilSendAt returns how long to wait before sending, measured from the
+// workflow's clock so it is the same on replay.
+func untilSendAt(ctx workflow.Context, request Request) time.Duration {
+	if request.SendAt == nil {
+		return request.SleepDuration
+	}
+	return max(request.SendAt.Sub(workflow.Now(ctx)), 0)
+}
+
+// untilDeliveryWindow returns how long to wait for every delivery window to
+// allow sending. Windows which can't be resolved don't hold the communication
+// back.
+func untilDeliveryWindow(ctx workflow.Context, windows []model.DeliveryWindow) time.Duration {
+	now := workflow.Now(ctx)
+	next, err := model.NextDeliveryTime(now, windows)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Unable to resolve delivery windows, sending now.", "Error", err)
+		return 0
+	}
+	return next.Sub(now)
+}
+
+func expired(ctx workflow.Context, policy model.DeliveryPolicy) bool {
+	return policy.ExpireAt != nil && !workflow.Now(ctx).Before(*policy.ExpireAt)
+}
+
+// recordSendFailure records why sending failed and marks the communication as
+// failed, or as expired when it ran out of time to be sent.
+func recordSendFailure(ctx workflow.Context, request Request, channel model.NotificationType, currentState *WorkflowState, sendErr error, messageId *string) error {
+	var activities *UnicomActivities
+	logger := workflow.GetLogger(ctx)
+	workflowId := workflow.GetInfo(ctx).WorkflowExecution.ID
+
+	currentState.Status = WorkflowError
+	currentState.Error = sendErr
+	status := model.Failed
+	if expired(ctx, request.Policy) {
+		currentState.Status = WorkflowExpired
+		status = model.Expired
+	}
+	recordOutcome(ctx, request.Domain, channel, status)
+
+	// workflows which failed before errors were recorded replay without it.
+	if workflow.GetVersion(ctx, "record-communication-error", workflow.DefaultVersion, 1) == 1 {
+		err := workflow.ExecuteActivity(ctx,
+			activities.RecordCommunicationError,
+			workflowId,
+			failureDetails(sendErr),
+		).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Activity failed.", "activities.RecordCommunicationError", "Error", err)
+		}
+	}
+	err := workflow.ExecuteActivity(ctx,
+		activities.UpdateCommunicationStatus,
+		workflowId,
+		status,
+		messageId,
+	).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Activity failed.", "activities.MarkCommunicationAsFailed", "Error", err)
+	}
+	return err
+}
+
+// recordWaitFailure records why waiting to send stopped. A communication
+// cancelled while it waited is marked as cancelled.
+func recordWaitFailure(ctx workflow.Context, request Request, currentState *WorkflowState, waitErr error) error {
+	if !temporal.IsCanceledError(waitErr) {
+		currentState.Status = WorkflowError
+		currentState.Error = waitErr
+		return waitErr
+	}
+	var activities *UnicomActivities
+	currentState.Status = WorkflowCancelled
+	// ctx is cancelled, so the status is recorded on a context which isn't.
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	err := workflow.ExecuteActivity(ctx,
+		activities.UpdateCommunicationStatus,
+		workflow.GetInfo(ctx).WorkflowExecution.ID,
+		model.Cancelled,
+		(*string)(nil),
+	).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Activity failed.", "activities.UpdateCommunicationStatus", "Error", err)
+	}
+	if request.EmailRequest != nil {
+		recordOutcome(ctx, request.Domain, model.Email, model.Cancelled)
+	}
+	if request.PushRequest != nil {
+		recordOutcome(ctx, request.Domain, model.Push, model.Cancelled)
+	}
+	return waitErr
+}
+
+// failureDetails recovers the classification of a failed send activity.
+func failureDetails(err error) failure.Details {
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		details := failure.Details{}
+		if appErr.HasDetails() && appErr.Details(&details) == nil {
+			return details
+		}
+		return failure.Details{Kind: failure.Kind(appErr.Type()), Message: appErr.Message()}
+	}
+	var timeoutErr *temporal.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return failure.Details{Kind: failure.ProviderOutage, Message: err.Error()}
+	}
+	return failure.Details{Kind: failure.Unknown, Message: err.Error()}
+}
+
 func statusFromError(err error) model.Status {
 	if err != nil {
 		return model.Failed
@@ -229,3 +408,13 @@ func messageFromError(err error) *string {
 	}
 	return nil
 }
+
+// recordOutcome counts the outcome of a communication on a channel. Temporal
+// skips the count while the workflow replays.
+func recordOutcome(ctx workflow.Context, domain string, channel model.NotificationType, status model.Status) {
+	workflow.GetMetricsHandler(ctx).WithTags(map[string]string{
+		metrics.DomainTag:  domain,
+		metrics.ChannelTag: metrics.Channel(channel),
+		metrics.StatusTag:  string(status),
+	}).Counter(metrics.Communications).Inc(1)
+}
@@ -3,9 +3,12 @@ package workflows_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
 	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/push"
 	"github.com/anicoll/unicom/internal/workflows"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/bxcodec/faker"
@@ -13,7 +16,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
 )
 
 type UnitTestSuite struct {
@@ -151,6 +156,9 @@ func (s *UnitTestSuite) Test_ComminucationWorkflow_NoResponseChannels_ErrorSendi
 	s.NoError(err)
 
 	s.env.OnActivity(activities.SendEmail, mock.Anything, *emailRequest).Times(1).Return(nil, errors.New("some failed reason"))
+	s.env.OnActivity(activities.RecordCommunicationError, mock.Anything, mock.Anything, mock.MatchedBy(func(details failure.Details) bool {
+		return details.Kind == failure.Unknown
+	})).Times(1).Return(nil)
 	s.env.OnActivity(activities.UpdateCommunicationStatus, mock.Anything, mock.Anything, model.Failed, sesMessageId).Times(1).Return(nil)
 
 	s.env.ExecuteWorkflow(workflows.CommunicationWorkflow, workflows.Request{
@@ -160,3 +168,147 @@ func (s *UnitTestSuite) Test_ComminucationWorkflow_NoResponseChannels_ErrorSendi
 	s.True(s.env.IsWorkflowCompleted())
 	s.NoError(s.env.GetWorkflowError())
 }
+
+func (s *UnitTestSuite) Test_ComminucationWorkflow_InvalidRecipient_RecordsFailure() {
+	var activities *workflows.UnicomActivities
+
+	emailRequest := &email.Request{}
+	err := faker.FakeData(&emailRequest)
+	s.NoError(err)
+
+	details := failure.Details{Kind: failure.InvalidRecipient, Provider: "ses", Message: "ses: address does not exist"}
+	s.env.OnActivity(activities.SendEmail, mock.Anything, *emailRequest).Times(1).Return(nil,
+		temporal.NewNonRetryableApplicationError(details.Message, string(details.Kind), nil, details))
+	s.env.OnActivity(activities.RecordCommunicationError, mock.Anything, mock.Anything, details).Times(1).Return(nil)
+	s.env.OnActivity(activities.UpdateCommunicationStatus, mock.Anything, mock.Anything, model.Failed, (*string)(nil)).Times(1).Return(nil)
+
+	s.env.ExecuteWorkflow(workflows.CommunicationWorkflow, workflows.Request{
+		EmailRequest:  emailRequest,
+		SleepDuration: 0,
+	})
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *UnitTestSuite) Test_ComminucationWorkflow_ExpiresBeforeSend() {
+	var activities *workflows.UnicomActivities
+
+	emailRequest := &email.Request{}
+	err := faker.FakeData(&emailRequest)
+	s.NoError(err)
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	s.env.SetStartTime(start)
+	expireAt := start.Add(time.Hour)
+
+	s.env.OnActivity(activities.UpdateCommunicationStatus, mock.Anything, mock.Anything, model.Expired, (*string)(nil)).Times(1).Return(nil)
+
+	s.env.ExecuteWorkflow(workflows.CommunicationWorkflow, workflows.Request{
+		EmailRequest:  emailRequest,
+		SleepDuration: 2 * time.Hour,
+		Policy:        model.DeliveryPolicy{ExpireAt: &expireAt},
+	})
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *UnitTestSuite) Test_ComminucationWorkflow_DeletesOffloadedPayloads() {
+	var activities *workflows.UnicomActivities
+
+	sesMessageId := aws.String(uuid.NewString())
+	emailRequest := &email.Request{
+		ToAddresses: []string{"to@example.com"},
+		HtmlBodyRef: "communication-id/html",
+	}
+
+	s.env.OnActivity(activities.SendEmail, mock.Anything, *emailRequest).Times(1).Return(sesMessageId, nil)
+	s.env.OnActivity(activities.UpdateCommunicationStatus, mock.Anything, mock.Anything, model.Success, sesMessageId).Times(1).Return(nil)
+	s.env.OnActivity(activities.DeleteEmailPayloads, mock.Anything, *emailRequest).Times(1).Return(nil)
+
+	s.env.ExecuteWorkflow(workflows.CommunicationWorkflow, workflows.Request{
+		EmailRequest:  emailRequest,
+		SleepDuration: 0,
+	})
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *UnitTestSuite) Test_ComminucationWorkflow_WaitsForSendAt() {
+	var activities *workflows.UnicomActivities
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	s.env.SetStartTime(start)
+	sendAt := start.Add(3 * time.Hour)
+	pushRequest := &push.Notification{ExternalCustomerId: "customer-1"}
+	messageId := aws.String(uuid.NewString())
+
+	var sentAt time.Time
+	s.env.OnActivity(activities.SendPush, mock.Anything, *pushRequest).Times(1).Run(func(mock.Arguments) {
+		sentAt = s.env.Now()
+	}).Return(messageId, nil)
+	s.env.OnActivity(activities.UpdateCommunicationStatus, mock.Anything, mock.Anything, model.Success, messageId).Times(1).Return(nil)
+
+	s.env.ExecuteWorkflow(workflows.CommunicationWorkflow, workflows.Request{
+		PushRequest: pushRequest,
+		SendAt:      &sendAt,
+	})
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.False(sentAt.Before(sendAt))
+}
+
+func (s *UnitTestSuite) Test_ComminucationWorkflow_WaitsForDeliveryWindow() {
+	var activities *workflows.UnicomActivities
+
+	s.env.SetStartTime(time.Date(2026, 1, 1, 22, 30, 0, 0, time.UTC))
+	pushRequest := &push.Notification{ExternalCustomerId: "customer-1"}
+	messageId := aws.String(uuid.NewString())
+
+	var sentAt time.Time
+	s.env.OnActivity(activities.SendPush, mock.Anything, *pushRequest).Times(1).Run(func(mock.Arguments) {
+		sentAt = s.env.Now()
+	}).Return(messageId, nil)
+	s.env.OnActivity(activities.UpdateCommunicationStatus, mock.Anything, mock.Anything, model.Success, messageId).Times(1).Return(nil)
+
+	s.env.ExecuteWorkflow(workflows.CommunicationWorkflow, workflows.Request{
+		PushRequest: pushRequest,
+		Windows:     []model.DeliveryWindow{{Start: 8 * time.Hour, End: 21 * time.Hour, Timezone: "UTC"}},
+	})
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.Equal(time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC), sentAt.UTC())
+}
+
+func (s *UnitTestSuite) Test_ComminucationWorkflow_CancelledWhileWaiting() {
+	var activities *workflows.UnicomActivities
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	s.env.SetStartTime(start)
+	sendAt := start.Add(3 * time.Hour)
+
+	s.env.OnActivity(activities.UpdateCommunicationStatus, mock.Anything, mock.Anything, model.Cancelled, (*string)(nil)).Times(1).Return(nil)
+	s.env.RegisterDelayedCallback(s.env.CancelWorkflow, time.Hour)
+
+	s.env.ExecuteWorkflow(workflows.CommunicationWorkflow, workflows.Request{
+		PushRequest: &push.Notification{ExternalCustomerId: "customer-1"},
+		SendAt:      &sendAt,
+	})
+	s.True(s.env.IsWorkflowCompleted())
+	s.True(temporal.IsCanceledError(s.env.GetWorkflowError()))
+
+	result, err := s.env.QueryWorkflow("current_state")
+	s.Require().NoError(err)
+	state := workflows.WorkflowState{}
+	s.Require().NoError(result.Get(&state))
+	s.Equal(workflows.WorkflowCancelled, state.Status)
+}
+
+// The history was recorded by a workflow which failed to send before the
+// error of a failed send was recorded, so its commands skip
+// RecordCommunicationError.
+func (s *UnitTestSuite) Test_ComminucationWorkflow_ReplaysFailureBeforeErrorsWereRecorded() {
+	replayer := worker.NewWorkflowReplayer()
+	replayer.RegisterWorkflow(workflows.CommunicationWorkflow)
+
+	s.NoError(replayer.ReplayWorkflowHistoryFromJSONFile(nil, "testdata/communication_push_failure_before_errors.json"))
+}
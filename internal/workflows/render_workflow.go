@@ -0,0 +1,57 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/push"
+)
+
+// maxRenderedSize is the largest rendered email or push body returned. The
+// result passes through the render workflow's history, whose payloads temporal
+// limits to 2MB.
+const maxRenderedSize = 1 << 20
+
+// RenderRequest is a communication to render without sending it.
+type RenderRequest struct {
+	EmailRequest *email.Request
+	PushRequest  *push.Notification
+}
+
+// Rendered is a communication as it would be handed to its providers.
+type Rendered struct {
+	// Email is the raw RFC 5322 message of the email.
+	Email []byte
+	// Push is the JSON body the push provider would be called with.
+	Push []byte
+}
+
+// RenderWorkflow renders a communication on the worker, which knows the
+// providers each domain sends through. The server waits for its result.
+func RenderWorkflow(ctx workflow.Context, request RenderRequest) (*Rendered, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 3,
+		},
+	})
+	var activities *UnicomActivities
+
+	rendered := &Rendered{}
+	if request.EmailRequest != nil {
+		err := workflow.ExecuteActivity(ctx, activities.RenderEmail, *request.EmailRequest).Get(ctx, &rendered.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if request.PushRequest != nil {
+		err := workflow.ExecuteActivity(ctx, activities.RenderPush, *request.PushRequest).Get(ctx, &rendered.Push)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rendered, nil
+}
@@ -0,0 +1,102 @@
+package workflows_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/anicoll/unicom/internal/attachment"
+	"github.com/anicoll/unicom/internal/email"
+	"github.com/anicoll/unicom/internal/failure"
+	"github.com/anicoll/unicom/internal/payload"
+	"github.com/anicoll/unicom/internal/push"
+	"github.com/anicoll/unicom/internal/workflows"
+)
+
+type stubPushService struct {
+	payload []byte
+	err     error
+}
+
+func (s stubPushService) Send(context.Context, push.Notification) (*string, error) {
+	return nil, s.err
+}
+
+func (s stubPushService) Render(context.Context, push.Notification) ([]byte, error) {
+	return s.payload, s.err
+}
+
+func (s *UnitTestSuite) Test_RenderWorkflow_Email() {
+	var activities *workflows.UnicomActivities
+	emailRequest := email.Request{Subject: "subject"}
+
+	s.env.OnActivity(activities.RenderEmail, mock.Anything, emailRequest).Times(1).Return([]byte("Subject: subject\r\n"), nil)
+
+	s.env.ExecuteWorkflow(workflows.RenderWorkflow, workflows.RenderRequest{EmailRequest: &emailRequest})
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	rendered := workflows.Rendered{}
+	s.NoError(s.env.GetWorkflowResult(&rendered))
+	s.Equal([]byte("Subject: subject\r\n"), rendered.Email)
+	s.Empty(rendered.Push)
+}
+
+func (s *UnitTestSuite) Test_RenderWorkflow_PushFailure() {
+	var activities *workflows.UnicomActivities
+	notification := push.Notification{ExternalCustomerId: "customer-1"}
+
+	s.env.OnActivity(activities.RenderPush, mock.Anything, notification).Times(1).
+		Return(nil, temporal.NewNonRetryableApplicationError("no fcm devices registered", string(failure.InvalidRecipient), nil))
+
+	s.env.ExecuteWorkflow(workflows.RenderWorkflow, workflows.RenderRequest{PushRequest: &notification})
+	s.True(s.env.IsWorkflowCompleted())
+	s.ErrorContains(s.env.GetWorkflowError(), "no fcm devices registered")
+}
+
+func (s *ActivitiesTestSuite) TestRenderEmail_FetchesUrlAttachments() {
+	activities := workflows.NewActivities(nil, nil, nil, nil, nil, stubAttachmentFetcher{
+		object: &attachment.Object{Data: []byte("%PDF"), ContentType: "application/pdf"},
+	}, payload.NewOffloader(nil, 0))
+
+	raw, err := activities.RenderEmail(context.Background(), email.Request{
+		FromAddress: "noreply@example.com",
+		ToAddresses: []string{"to@example.com"},
+		Subject:     "subject",
+		TextBody:    "hello",
+		Attachments: []email.Attachment{{Name: "invoice.pdf", URL: "https://files.example.com/invoice.pdf"}},
+	})
+	s.Require().NoError(err)
+	s.Contains(string(raw), "Subject: subject")
+	s.Contains(string(raw), `Content-Type: application/pdf; name="invoice.pdf"`)
+}
+
+func (s *ActivitiesTestSuite) TestRenderPush_InvalidRecipientIsNotRetried() {
+	activities := workflows.NewActivities(nil, stubPushService{
+		err: failure.Errorf(failure.InvalidRecipient, "fcm", "no fcm devices registered for customer customer-1"),
+	}, nil, nil, nil, nil, payload.NewOffloader(nil, 0))
+
+	_, err := activities.RenderPush(context.Background(), push.Notification{ExternalCustomerId: "customer-1"})
+
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(err, &appErr))
+	s.True(appErr.NonRetryable())
+}
+
+func (s *ActivitiesTestSuite) TestRenderEmail_TooLarge() {
+	activities := workflows.NewActivities(nil, nil, nil, nil, nil, nil, payload.NewOffloader(nil, 0))
+
+	_, err := activities.RenderEmail(context.Background(), email.Request{
+		FromAddress: "noreply@example.com",
+		ToAddresses: []string{"to@example.com"},
+		Subject:     "subject",
+		Attachments: []email.Attachment{{Name: "large.bin", Data: make([]byte, 2<<20)}},
+	})
+
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(err, &appErr))
+	s.True(appErr.NonRetryable())
+	s.Equal(string(failure.PayloadTooLarge), appErr.Type())
+}
@@ -0,0 +1,115 @@
+package workflows
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/erasure"
+	"github.com/anicoll/unicom/internal/model"
+)
+
+type RetentionRequest struct {
+	// DryRun reports what would be erased without changing anything.
+	DryRun bool
+}
+
+// RetentionWorkflow redacts every domain's communications older than its
+// retention window. It is started on a schedule by the worker.
+func RetentionWorkflow(ctx workflow.Context, request RetentionRequest) ([]model.Erasure, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Hour,
+		HeartbeatTimeout:    5 * time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts:    5,
+			BackoffCoefficient: 2,
+		},
+	})
+	var activities *RetentionActivities
+
+	var erasures []model.Erasure
+	err := workflow.ExecuteActivity(ctx, activities.ApplyRetention, request, workflow.Now(ctx)).Get(ctx, &erasures)
+	if err != nil {
+		return nil, err
+	}
+	for _, erasure := range erasures {
+		workflow.GetLogger(ctx).Info("retention applied",
+			"domain", erasure.Domain,
+			"dryRun", erasure.DryRun,
+			"communications", erasure.Communications,
+			"responseChannels", erasure.ResponseChannels)
+	}
+	return erasures, nil
+}
+
+type eraser interface {
+	Domains(ctx context.Context) ([]string, error)
+	EraseExpired(ctx context.Context, domain string, before time.Time, dryRun bool, resume erasure.Progress, progress func(erasure.Progress)) (model.Erasure, error)
+}
+
+type RetentionActivities struct {
+	eraser  eraser
+	domains *domain.Registry
+}
+
+func NewRetentionActivities(eraser eraser, domains *domain.Registry) *RetentionActivities {
+	return &RetentionActivities{
+		eraser:  eraser,
+		domains: domains,
+	}
+}
+
+// retentionProgress is heartbeated by ApplyRetention, so a retry resumes where
+// the attempt before it stopped instead of starting over.
+type retentionProgress struct {
+	// Done are the domains which have been erased.
+	Done     []string
+	Erasures []model.Erasure
+	// Current is the progress of the domain being erased.
+	Current erasure.Progress
+}
+
+// ApplyRetention erases the communications of every domain with a retention
+// window which were created longer than that window before now. It heartbeats
+// after every batch.
+func (a *RetentionActivities) ApplyRetention(ctx context.Context, req RetentionRequest, now time.Time) ([]model.Erasure, error) {
+	progress := retentionProgress{Erasures: []model.Erasure{}}
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &progress); err != nil {
+			activity.GetLogger(ctx).Warn("Unable to resume retention, starting over.", "Error", err)
+			progress = retentionProgress{Erasures: []model.Erasure{}}
+		}
+	}
+	names, err := a.eraser.Domains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if slices.Contains(progress.Done, name) {
+			continue
+		}
+		retention := a.domains.For(name).Retention
+		if retention <= 0 {
+			continue
+		}
+		erased, err := a.eraser.EraseExpired(ctx, name, now.Add(-retention), req.DryRun, progress.Current, func(current erasure.Progress) {
+			progress.Current = current
+			activity.RecordHeartbeat(ctx, progress)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if erased.Communications > 0 {
+			progress.Erasures = append(progress.Erasures, erased)
+		}
+		progress.Done = append(progress.Done, name)
+		progress.Current = erasure.Progress{}
+		activity.RecordHeartbeat(ctx, progress)
+	}
+	return progress.Erasures, nil
+}
@@ -0,0 +1,101 @@
+package workflows_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/anicoll/unicom/internal/domain"
+	"github.com/anicoll/unicom/internal/erasure"
+	"github.com/anicoll/unicom/internal/model"
+	"github.com/anicoll/unicom/internal/workflows"
+)
+
+// recordingEraser erases one more communication from every domain it is asked
+// to than it resumes from.
+type recordingEraser struct {
+	domains []string
+	before  map[string]time.Time
+	resumed map[string]string
+}
+
+func (e *recordingEraser) Domains(context.Context) ([]string, error) {
+	return e.domains, nil
+}
+
+func (e *recordingEraser) EraseExpired(_ context.Context, domain string, before time.Time, dryRun bool, resume erasure.Progress, progress func(erasure.Progress)) (model.Erasure, error) {
+	e.before[domain] = before
+	erased := model.Erasure{Domain: domain, DryRun: dryRun, Communications: 1}
+	if resume.Erasure.Domain == domain {
+		e.resumed[domain] = resume.After
+		erased.Communications += resume.Erasure.Communications
+	}
+	progress(erasure.Progress{Erasure: erased, After: "last"})
+	return erased, nil
+}
+
+func (s *UnitTestSuite) Test_RetentionWorkflow_Success() {
+	var activities *workflows.RetentionActivities
+	erasures := []model.Erasure{{Domain: "billing", DryRun: true, Communications: 3}}
+
+	s.env.OnActivity(activities.ApplyRetention, mock.Anything, workflows.RetentionRequest{DryRun: true}, mock.Anything).Times(1).Return(erasures, nil)
+
+	s.env.ExecuteWorkflow(workflows.RetentionWorkflow, workflows.RetentionRequest{DryRun: true})
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var got []model.Erasure
+	s.NoError(s.env.GetWorkflowResult(&got))
+	s.Equal(erasures, got)
+}
+
+func (s *ActivitiesTestSuite) TestApplyRetention_UsesDomainWindows() {
+	eraser := &recordingEraser{domains: []string{"billing", "marketing", "other"}, before: map[string]time.Time{}, resumed: map[string]string{}}
+	domains := domain.NewRegistry(domain.Config{}, map[string]domain.Config{
+		"billing":   {Retention: 24 * time.Hour},
+		"marketing": {Retention: time.Hour},
+	})
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	activities := workflows.NewRetentionActivities(eraser, domains)
+	env.RegisterActivity(activities)
+
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	result, err := env.ExecuteActivity(activities.ApplyRetention, workflows.RetentionRequest{}, now)
+	s.Require().NoError(err)
+
+	var erasures []model.Erasure
+	s.Require().NoError(result.Get(&erasures))
+	s.Len(erasures, 2)
+	s.Equal(map[string]time.Time{
+		"billing":   now.Add(-24 * time.Hour),
+		"marketing": now.Add(-time.Hour),
+	}, eraser.before, "domains without a retention window are kept")
+}
+
+func (s *ActivitiesTestSuite) TestApplyRetention_ResumesFromHeartbeat() {
+	eraser := &recordingEraser{domains: []string{"billing", "marketing", "payments"}, before: map[string]time.Time{}, resumed: map[string]string{}}
+	domains := domain.NewRegistry(domain.Config{Retention: time.Hour}, nil)
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	activities := workflows.NewRetentionActivities(eraser, domains)
+	env.RegisterActivity(activities)
+	env.SetHeartbeatDetails(map[string]any{
+		"Done":     []string{"billing"},
+		"Erasures": []model.Erasure{{Domain: "billing", Communications: 4}},
+		"Current":  erasure.Progress{Erasure: model.Erasure{Domain: "marketing", Communications: 2}, After: "comm-9"},
+	})
+
+	result, err := env.ExecuteActivity(activities.ApplyRetention, workflows.RetentionRequest{}, time.Now())
+	s.Require().NoError(err)
+
+	var erasures []model.Erasure
+	s.Require().NoError(result.Get(&erasures))
+	s.NotContains(eraser.before, "billing", "domains done before the retry are skipped")
+	s.Equal(map[string]string{"marketing": "comm-9"}, eraser.resumed)
+	s.Equal([]model.Erasure{
+		{Domain: "billing", Communications: 4},
+		{Domain: "marketing", Communications: 3},
+		{Domain: "payments", Communications: 1},
+	}, erasures)
+}
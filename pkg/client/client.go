@@ -0,0 +1,248 @@
+// Package client is the Go SDK of the unicom API. It wraps the generated gRPC
+// client with builders for communications, retries calls the server couldn't
+// take with backoff, waits for communications to finish and parses the
+// events response channels deliver.
+//
+//	c, err := client.Dial("unicom:8090", client.WithTLS(nil), client.WithPrincipal("billing"))
+//	...
+//	id, err := c.Send(ctx, client.NewEmail("billing").
+//		From("billing@example.com", "Billing").
+//		To("jane@example.com").
+//		Subject("Your receipt").
+//		HTML("<p>Thanks</p>"))
+//
+// Every communication built is given an idempotency key, so a send retried
+// after the server was unavailable is only sent once. The API has no SMS
+// channel yet, so there is no SMS builder.
+//
+// Package clienttest has a fake server for consumers' tests.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+)
+
+// DefaultPrincipalHeader is the header the server reads the principal from
+// unless it is configured otherwise.
+const DefaultPrincipalHeader = "x-unicom-principal"
+
+// RetryPolicy controls how calls failing with UNAVAILABLE are retried. Each
+// retry waits twice as long as the one before, up to MaxBackoff, with jitter.
+type RetryPolicy struct {
+	// MaxAttempts is the most times a call is made, 1 disables retries.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of a Client unless WithRetry is
+// given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Millisecond * 200,
+	MaxBackoff:     time.Second * 5,
+}
+
+type options struct {
+	creds           credentials.TransportCredentials
+	token           string
+	principal       string
+	principalHeader string
+	retry           RetryPolicy
+	dialOptions     []grpc.DialOption
+}
+
+// Option configures a Client.
+type Option func(*options)
+
+// WithTLS connects with TLS. A nil config verifies the server with the
+// system's certificate authorities, and a config with Certificates set
+// authenticates the client with mutual TLS.
+func WithTLS(config *tls.Config) Option {
+	return func(o *options) {
+		if config == nil {
+			config = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		o.creds = credentials.NewTLS(config)
+	}
+}
+
+// WithToken sends token as a bearer token in the authorization header, for
+// gateways in front of the API which authenticate callers.
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithPrincipal identifies the caller in the audit log when it doesn't use a
+// client certificate. Servers which don't trust the principal header record it
+// as the claimed principal.
+func WithPrincipal(principal string) Option {
+	return func(o *options) {
+		o.principal = principal
+	}
+}
+
+// WithPrincipalHeader changes the header the principal is sent in, to match
+// the server's audit-principal-header.
+func WithPrincipalHeader(header string) Option {
+	return func(o *options) {
+		o.principalHeader = strings.ToLower(header)
+	}
+}
+
+// WithRetry replaces DefaultRetryPolicy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = policy
+	}
+}
+
+// WithDialOptions adds gRPC dial options, e.g. interceptors.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// Client calls the unicom API.
+type Client struct {
+	api  pb.UnicomServiceClient
+	conn *grpc.ClientConn
+}
+
+// Dial returns a Client of the API at address, a host:port. It connects
+// without TLS unless WithTLS is given.
+func Dial(address string, opts ...Option) (*Client, error) {
+	o := options{
+		creds:           insecure.NewCredentials(),
+		principalHeader: DefaultPrincipalHeader,
+		retry:           DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dialOptions := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(o.creds),
+		grpc.WithChainUnaryInterceptor(o.metadataInterceptor, o.retry.interceptor),
+		grpc.WithChainStreamInterceptor(o.streamMetadataInterceptor),
+	}, o.dialOptions...)
+	conn, err := grpc.NewClient(address, dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		api:  pb.NewUnicomServiceClient(conn),
+		conn: conn,
+	}, nil
+}
+
+// API returns the generated client, for the calls Client doesn't wrap. Its
+// calls are authenticated and retried as Client's are.
+func (c *Client) API() pb.UnicomServiceClient {
+	return c.api
+}
+
+// Close closes the connection to the API.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Send sends a communication, returning its ID. A communication sent with
+// Sync is sent by the time Send returns.
+func (c *Client) Send(ctx context.Context, message Message) (string, error) {
+	req, err := message.Request()
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.api.SendCommunication(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetId(), nil
+}
+
+// Render renders a communication as Send would send it, without sending it:
+// an email as its MIME message, a push notification as the body of the push
+// provider.
+func (c *Client) Render(ctx context.Context, message Message) (*pb.RenderCommunicationResponse, error) {
+	req, err := message.Request()
+	if err != nil {
+		return nil, err
+	}
+	return c.api.RenderCommunication(ctx, req)
+}
+
+// Status returns the status of a communication's workflow, such as WAITING
+// or COMPLETE.
+func (c *Client) Status(ctx context.Context, id string) (string, error) {
+	resp, err := c.api.GetStatus(ctx, &pb.GetStatusRequest{Id: id})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetStatus(), nil
+}
+
+// List returns a page of the communications matching req, newest first.
+func (c *Client) List(ctx context.Context, req *pb.ListCommunicationsRequest) (*pb.ListCommunicationsResponse, error) {
+	return c.api.ListCommunications(ctx, req)
+}
+
+// Cancel cancels a communication which is waiting to be sent.
+func (c *Client) Cancel(ctx context.Context, id string) error {
+	_, err := c.api.CancelCommunication(ctx, &pb.CancelCommunicationRequest{Id: id})
+	return err
+}
+
+func (o *options) outgoing(ctx context.Context) context.Context {
+	if o.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+o.token)
+	}
+	if o.principal != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, o.principalHeader, o.principal)
+	}
+	return ctx
+}
+
+func (o *options) metadataInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(o.outgoing(ctx), method, req, reply, cc, opts...)
+}
+
+func (o *options) streamMetadataInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(o.outgoing(ctx), desc, cc, method, opts...)
+}
+
+// interceptor retries unary calls failing with UNAVAILABLE. Sends are safe to
+// retry as each carries an idempotency key.
+func (p RetryPolicy) interceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	backoff := p.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) != codes.Unavailable || attempt >= p.MaxAttempts {
+			return err
+		}
+		// Full jitter, so clients retrying together spread out.
+		wait := time.Duration(rand.Int64N(int64(backoff) + 1))
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, p.MaxBackoff)
+	}
+}
@@ -0,0 +1,174 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/pkg/client"
+	"github.com/anicoll/unicom/pkg/client/clienttest"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	srv *clienttest.Server
+	md  metadata.MD
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
+
+func (s *ClientTestSuite) SetupTest() {
+	s.md = nil
+	s.srv = clienttest.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		s.md, _ = metadata.FromIncomingContext(ctx)
+		return handler(ctx, req)
+	}))
+}
+
+func (s *ClientTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *ClientTestSuite) email() *client.EmailBuilder {
+	return client.NewEmail("domain").From("from@example.com", "From").To("to@example.com").Subject("subject").Text("text")
+}
+
+func (s *ClientTestSuite) TestSend_Success() {
+	c := s.srv.Client(client.WithToken("token"), client.WithPrincipal("billing"))
+	defer c.Close()
+
+	id, err := c.Send(context.Background(), s.email())
+	s.NoError(err)
+	s.NotEmpty(id)
+
+	sent := s.srv.Sent()
+	s.Len(sent, 1)
+	s.Equal("domain", sent[0].GetDomain())
+	s.NotEmpty(sent[0].GetIdempotencyKey())
+	s.Equal([]string{"Bearer token"}, s.md.Get("authorization"))
+	s.Equal([]string{"billing"}, s.md.Get(client.DefaultPrincipalHeader))
+}
+
+func (s *ClientTestSuite) TestSend_InvalidMessage() {
+	c := s.srv.Client()
+	defer c.Close()
+
+	_, err := c.Send(context.Background(), client.NewEmail("domain").To("to@example.com"))
+	s.ErrorContains(err, "html or text body")
+	s.Empty(s.srv.Sent())
+}
+
+func (s *ClientTestSuite) TestSend_RetriesUnavailable() {
+	c := s.srv.Client(client.WithRetry(client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	defer c.Close()
+	s.srv.FailNext(status.Error(codes.Unavailable, "unavailable"), status.Error(codes.Unavailable, "unavailable"))
+
+	id, err := c.Send(context.Background(), s.email())
+	s.NoError(err)
+	s.NotEmpty(id)
+	s.Len(s.srv.Sent(), 1)
+}
+
+func (s *ClientTestSuite) TestSend_GivesUpRetrying() {
+	c := s.srv.Client(client.WithRetry(client.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	defer c.Close()
+	s.srv.FailNext(status.Error(codes.Unavailable, "unavailable"), status.Error(codes.Unavailable, "unavailable"))
+
+	_, err := c.Send(context.Background(), s.email())
+	s.Equal(codes.Unavailable, status.Code(err))
+	s.Empty(s.srv.Sent())
+}
+
+func (s *ClientTestSuite) TestSend_DoesNotRetryOtherErrors() {
+	c := s.srv.Client(client.WithRetry(client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	defer c.Close()
+	s.srv.FailNext(status.Error(codes.Internal, "internal"))
+
+	_, err := c.Send(context.Background(), s.email())
+	s.Equal(codes.Internal, status.Code(err))
+}
+
+func (s *ClientTestSuite) TestSend_SameMessageSentOnce() {
+	c := s.srv.Client()
+	defer c.Close()
+	message := s.email()
+
+	first, err := c.Send(context.Background(), message)
+	s.NoError(err)
+	second, err := c.Send(context.Background(), message)
+	s.NoError(err)
+
+	s.Equal(first, second)
+	s.Len(s.srv.Sent(), 1)
+}
+
+func (s *ClientTestSuite) TestWait_Complete() {
+	c := s.srv.Client()
+	defer c.Close()
+	id, err := c.Send(context.Background(), s.email())
+	s.NoError(err)
+
+	time.AfterFunc(time.Millisecond*20, func() {
+		s.srv.SetStatus(id, client.StatusComplete)
+	})
+	var seen []string
+	got, err := c.Watch(context.Background(), id, time.Millisecond*5, func(status string) {
+		seen = append(seen, status)
+	})
+	s.NoError(err)
+	s.Equal(client.StatusComplete, got)
+	s.Equal([]string{"WAITING", client.StatusComplete}, seen)
+}
+
+func (s *ClientTestSuite) TestWait_ContextDone() {
+	c := s.srv.Client()
+	defer c.Close()
+	id, err := c.Send(context.Background(), s.email())
+	s.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	got, err := c.Wait(ctx, id, time.Millisecond*5)
+	s.ErrorContains(err, "deadline exceeded")
+	s.Equal("WAITING", got)
+}
+
+func (s *ClientTestSuite) TestCancel() {
+	c := s.srv.Client()
+	defer c.Close()
+	id, err := c.Send(context.Background(), s.email())
+	s.NoError(err)
+
+	s.NoError(c.Cancel(context.Background(), id))
+	got, err := c.Status(context.Background(), id)
+	s.NoError(err)
+	s.Equal(client.StatusCancelled, got)
+
+	err = c.Cancel(context.Background(), id)
+	s.Equal(codes.NotFound, status.Code(err))
+}
+
+func (s *ClientTestSuite) TestList() {
+	c := s.srv.Client()
+	defer c.Close()
+	first, err := c.Send(context.Background(), s.email())
+	s.NoError(err)
+	_, err = c.Send(context.Background(), client.NewPush("other").To("customer").Content("content", ""))
+	s.NoError(err)
+	s.srv.SetStatus(first, client.StatusError)
+
+	resp, err := c.List(context.Background(), &pb.ListCommunicationsRequest{Domain: "domain"})
+	s.NoError(err)
+	s.Len(resp.GetCommunications(), 1)
+	s.Equal(first, resp.GetCommunications()[0].GetId())
+	s.Equal("FAILED", resp.GetCommunications()[0].GetStatus())
+}
@@ -0,0 +1,232 @@
+// Package clienttest has a fake unicom server for the tests of code using
+// package client. It keeps communications in memory, so tests can check what
+// was sent and move communications through their statuses.
+//
+//	srv := clienttest.NewServer()
+//	defer srv.Close()
+//	c := srv.Client()
+//	id, err := c.Send(ctx, client.NewEmail("billing").To("jane@example.com").Text("hi"))
+//	srv.SetStatus(id, client.StatusComplete)
+package clienttest
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/pkg/client"
+)
+
+// statusWaiting is the workflow status of an async communication until
+// SetStatus changes it.
+const statusWaiting = "WAITING"
+
+type communication struct {
+	req       *pb.SendCommunicationRequest
+	status    string
+	createdAt *timestamppb.Timestamp
+}
+
+// Server is a fake unicom server. Its zero value isn't usable, use NewServer.
+type Server struct {
+	pb.UnimplementedUnicomServiceServer
+
+	listener *bufconn.Listener
+	server   *grpc.Server
+
+	mu             sync.Mutex
+	communications map[string]*communication
+	order          []string
+	keys           map[string]string
+	failures       []error
+}
+
+// NewServer starts a fake server, listening in memory.
+func NewServer(opts ...grpc.ServerOption) *Server {
+	s := &Server{
+		listener:       bufconn.Listen(1 << 20),
+		server:         grpc.NewServer(opts...),
+		communications: map[string]*communication{},
+		keys:           map[string]string{},
+	}
+	pb.RegisterUnicomServiceServer(s.server, s)
+	go func() {
+		_ = s.server.Serve(s.listener)
+	}()
+	return s
+}
+
+// Client returns a client of the server. Closing the server closes it.
+func (s *Server) Client(opts ...client.Option) *client.Client {
+	opts = append(opts, client.WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return s.listener.DialContext(ctx)
+	})))
+	c, err := client.Dial("passthrough:///clienttest", opts...)
+	if err != nil {
+		// Dial only fails for invalid options.
+		panic(err)
+	}
+	return c
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.server.Stop()
+}
+
+// FailNext makes the next calls fail with errs, one error per call, e.g. with
+// status.Error(codes.Unavailable, "") to test retries.
+func (s *Server) FailNext(errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, errs...)
+}
+
+// Sent returns the requests of the communications sent, oldest first. A
+// request retried with the same idempotency key is only returned once.
+func (s *Server) Sent() []*pb.SendCommunicationRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := make([]*pb.SendCommunicationRequest, len(s.order))
+	for i, id := range s.order {
+		resp[i] = proto.Clone(s.communications[id].req).(*pb.SendCommunicationRequest)
+	}
+	return resp
+}
+
+// SetStatus sets the workflow status of a communication, e.g.
+// client.StatusComplete. It reports whether the communication exists.
+func (s *Server) SetStatus(id, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	comm, ok := s.communications[id]
+	if ok {
+		comm.status = status
+	}
+	return ok
+}
+
+func (s *Server) fail() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.failures) == 0 {
+		return nil
+	}
+	err := s.failures[0]
+	s.failures = s.failures[1:]
+	return err
+}
+
+func (s *Server) SendCommunication(ctx context.Context, req *pb.SendCommunicationRequest) (*pb.SendCommunicationResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	if err := req.ValidateAll(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := req.GetDomain() + "\x00" + req.GetIdempotencyKey()
+	if id, ok := s.keys[key]; ok && req.GetIdempotencyKey() != "" {
+		return &pb.SendCommunicationResponse{Id: id}, nil
+	}
+	id := uuid.NewString()
+	st := statusWaiting
+	if !req.GetIsAsync() {
+		st = client.StatusComplete
+	}
+	s.communications[id] = &communication{
+		req:       proto.Clone(req).(*pb.SendCommunicationRequest),
+		status:    st,
+		createdAt: timestamppb.Now(),
+	}
+	s.order = append(s.order, id)
+	s.keys[key] = id
+	return &pb.SendCommunicationResponse{Id: id}, nil
+}
+
+func (s *Server) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	comm, ok := s.communications[req.GetId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "workflow not found")
+	}
+	return &pb.GetStatusResponse{Id: req.GetId(), Status: comm.status}, nil
+}
+
+// ListCommunications returns every communication matching the request's
+// domain, status and time range on one page.
+func (s *Server) ListCommunications(ctx context.Context, req *pb.ListCommunicationsRequest) (*pb.ListCommunicationsResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &pb.ListCommunicationsResponse{}
+	for _, id := range slices.Backward(s.order) {
+		comm := s.communications[id]
+		c := &pb.Communication{
+			Id:        id,
+			Domain:    comm.req.GetDomain(),
+			Type:      "EMAIL",
+			Status:    communicationStatus(comm.status),
+			CreatedAt: comm.createdAt,
+		}
+		if comm.req.GetPush() != nil {
+			c.Type = "PUSH"
+		}
+		switch {
+		case req.GetDomain() != "" && req.GetDomain() != c.GetDomain(),
+			req.GetStatus() != "" && req.GetStatus() != c.GetStatus(),
+			req.GetStartTime() != nil && c.GetCreatedAt().AsTime().Before(req.GetStartTime().AsTime()),
+			req.GetEndTime() != nil && !c.GetCreatedAt().AsTime().Before(req.GetEndTime().AsTime()):
+			continue
+		}
+		resp.Communications = append(resp.Communications, c)
+	}
+	return resp, nil
+}
+
+// CancelCommunication cancels a communication which hasn't finished.
+func (s *Server) CancelCommunication(ctx context.Context, req *pb.CancelCommunicationRequest) (*pb.CancelCommunicationResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	comm, ok := s.communications[req.GetId()]
+	if !ok || client.IsFinal(comm.status) {
+		return nil, status.Error(codes.NotFound, "communication not found or already finished")
+	}
+	comm.status = client.StatusCancelled
+	return &pb.CancelCommunicationResponse{}, nil
+}
+
+// communicationStatus maps a workflow status to the status of its
+// communication.
+func communicationStatus(workflowStatus string) string {
+	switch workflowStatus {
+	case client.StatusComplete:
+		return "SUCCESS"
+	case client.StatusError:
+		return "FAILED"
+	case client.StatusCancelled:
+		return "CANCELLED"
+	default:
+		return "PENDING"
+	}
+}
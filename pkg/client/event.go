@@ -0,0 +1,96 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+)
+
+// SignatureHeader holds the signature of a webhook, when the worker signs
+// them with a webhook-signing-secret.
+const SignatureHeader = "X-Unicom-Signature"
+
+// DefaultSignatureTolerance is how old a webhook's signature ParseWebhook
+// accepts, bounding how long a captured webhook can be replayed.
+const DefaultSignatureTolerance = time.Minute * 5
+
+// maxEventSize bounds the body of a webhook ParseWebhook reads.
+const maxEventSize = 1 << 20
+
+// ErrInvalidSignature is returned for a webhook whose signature is missing,
+// doesn't match its body or has expired.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ParseEvent parses the event a response channel delivered, e.g. the body of
+// an SQS message.
+func ParseEvent(body []byte) (*pb.ResponseEvent, error) {
+	event := &pb.ResponseEvent{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, event); err != nil {
+		return nil, fmt.Errorf("parsing response event: %w", err)
+	}
+	return event, nil
+}
+
+// ParseWebhook verifies and parses the event of a webhook request. Any of
+// secrets may have signed it, so secrets can be rotated. No secrets skips
+// verification, for workers which don't sign webhooks.
+func ParseWebhook(r *http.Request, secrets ...string) (*pb.ResponseEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventSize))
+	if err != nil {
+		return nil, err
+	}
+	if len(secrets) > 0 {
+		if err := VerifySignature(r.Header.Get(SignatureHeader), body, time.Now(), DefaultSignatureTolerance, secrets...); err != nil {
+			return nil, err
+		}
+	}
+	return ParseEvent(body)
+}
+
+// VerifySignature checks signature, the SignatureHeader of a webhook, was
+// made for body with one of secrets no more than tolerance before now.
+func VerifySignature(signature string, body []byte, now time.Time, tolerance time.Duration, secrets ...string) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if mac, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, mac)
+			}
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed %s header", ErrInvalidSignature, SignatureHeader)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: signed %s ago", ErrInvalidSignature, age.Round(time.Second))
+	}
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		expected := mac.Sum(nil)
+		for _, got := range signatures {
+			if hmac.Equal(expected, got) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/anicoll/unicom/internal/responsechannel"
+	"github.com/anicoll/unicom/pkg/client"
+)
+
+const body = `{"workflow_id":"workflow-id","status":"SUCCESS","error_message":null,"unknown":1}`
+
+type EventTestSuite struct {
+	suite.Suite
+}
+
+func TestEventTestSuite(t *testing.T) {
+	suite.Run(t, new(EventTestSuite))
+}
+
+func (s *EventTestSuite) TestParseEvent() {
+	event, err := client.ParseEvent([]byte(body))
+	s.NoError(err)
+	s.Equal("workflow-id", event.GetWorkflowId())
+	s.Equal("SUCCESS", event.GetStatus())
+	s.Nil(event.ErrorMessage)
+
+	_, err = client.ParseEvent([]byte("not json"))
+	s.Error(err)
+}
+
+func (s *EventTestSuite) TestParseWebhook_Signed() {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	r.Header.Set(client.SignatureHeader, responsechannel.Sign("secret", time.Now(), []byte(body)))
+
+	event, err := client.ParseWebhook(r, "old", "secret")
+	s.NoError(err)
+	s.Equal("workflow-id", event.GetWorkflowId())
+}
+
+func (s *EventTestSuite) TestParseWebhook_WrongSecret() {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	r.Header.Set(client.SignatureHeader, responsechannel.Sign("other", time.Now(), []byte(body)))
+
+	_, err := client.ParseWebhook(r, "secret")
+	s.ErrorIs(err, client.ErrInvalidSignature)
+}
+
+func (s *EventTestSuite) TestParseWebhook_Unsigned() {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+
+	_, err := client.ParseWebhook(r, "secret")
+	s.ErrorIs(err, client.ErrInvalidSignature)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	event, err := client.ParseWebhook(r)
+	s.NoError(err)
+	s.Equal("workflow-id", event.GetWorkflowId())
+}
+
+func (s *EventTestSuite) TestVerifySignature() {
+	now := time.Unix(1700000000, 0)
+	signature := responsechannel.Sign("secret", now, []byte(body))
+
+	s.NoError(client.VerifySignature(signature, []byte(body), now.Add(time.Minute), time.Minute*5, "secret"))
+	s.ErrorIs(client.VerifySignature(signature, []byte(body), now.Add(time.Minute*6), time.Minute*5, "secret"), client.ErrInvalidSignature)
+	s.ErrorIs(client.VerifySignature(signature, []byte(body+" "), now, time.Minute*5, "secret"), client.ErrInvalidSignature)
+	s.ErrorIs(client.VerifySignature("v1=abc", []byte(body), now, time.Minute*5, "secret"), client.ErrInvalidSignature)
+}
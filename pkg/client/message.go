@@ -0,0 +1,282 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+)
+
+// Message is a communication to send.
+type Message interface {
+	// Request returns the request sending the communication.
+	Request() (*pb.SendCommunicationRequest, error)
+}
+
+// builder holds the settings every kind of communication has. Its methods
+// return the builder embedding it, T, so calls chain.
+type builder[T any] struct {
+	self *T
+	req  *pb.SendCommunicationRequest
+}
+
+func newBuilder[T any](self *T, domain string) builder[T] {
+	return builder[T]{
+		self: self,
+		req: &pb.SendCommunicationRequest{
+			Domain:  domain,
+			IsAsync: true,
+		},
+	}
+}
+
+// Sync makes Send wait for the communication to be sent. Sync
+// communications are sent at once and have no response channels.
+func (b *builder[T]) Sync() *T {
+	b.req.IsAsync = false
+	return b.self
+}
+
+// SendAt sends the communication at a later time.
+func (b *builder[T]) SendAt(at time.Time) *T {
+	b.req.SendAt = timestamppb.New(at)
+	return b.self
+}
+
+// Priority sets the lane the communication is processed in, the domain's
+// priority by default.
+func (b *builder[T]) Priority(priority pb.Priority) *T {
+	b.req.Priority = priority
+	return b.self
+}
+
+// Urgent sends the communication outside the delivery window and quiet
+// hours.
+func (b *builder[T]) Urgent() *T {
+	b.req.Urgent = true
+	return b.self
+}
+
+// Window only delivers the communication between start and end, 24 hour
+// times such as "08:00" in timezone, an IANA time zone. An empty timezone is
+// the customer's, then UTC.
+func (b *builder[T]) Window(start, end, timezone string) *T {
+	b.req.DeliveryWindow = &pb.DeliveryWindow{Start: start, End: end, Timezone: timezone}
+	return b.self
+}
+
+// DeliveryPolicy overrides the domain's retry, timeout and expiry policy.
+func (b *builder[T]) DeliveryPolicy(policy *pb.DeliveryPolicy) *T {
+	b.req.DeliveryPolicy = policy
+	return b.self
+}
+
+// Customer sets the external customer the recipients are looked up for when
+// left out.
+func (b *builder[T]) Customer(externalCustomerID string) *T {
+	b.req.ExternalCustomerId = externalCustomerID
+	return b.self
+}
+
+// Webhook posts the communication's outcome to url.
+func (b *builder[T]) Webhook(url string) *T {
+	b.req.ResponseChannels = append(b.req.ResponseChannels, &pb.ResponseChannel{Schema: pb.ResponseSchema_RESPONSE_SCHEMA_HTTP, Url: url})
+	return b.self
+}
+
+// SQS sends the communication's outcome to the queue at url.
+func (b *builder[T]) SQS(url string) *T {
+	b.req.ResponseChannels = append(b.req.ResponseChannels, &pb.ResponseChannel{Schema: pb.ResponseSchema_RESPONSE_SCHEMA_SQS, Url: url})
+	return b.self
+}
+
+// IdempotencyKey replaces the key generated for the communication, e.g. with
+// the ID of the order it is about, so it is only sent once however often it
+// is built.
+func (b *builder[T]) IdempotencyKey(key string) *T {
+	b.req.IdempotencyKey = key
+	return b.self
+}
+
+// Test sends the communication to the domain's seed list instead of its
+// recipients.
+func (b *builder[T]) Test() *T {
+	b.req.Test = true
+	return b.self
+}
+
+// request returns a copy of the request, generating the idempotency key the
+// first time. Every request the builder returns has the same key.
+func (b *builder[T]) request() *pb.SendCommunicationRequest {
+	if b.req.IdempotencyKey == "" {
+		b.req.IdempotencyKey = uuid.NewString()
+	}
+	return proto.Clone(b.req).(*pb.SendCommunicationRequest)
+}
+
+// EmailBuilder builds an email.
+type EmailBuilder struct {
+	builder[EmailBuilder]
+	email *pb.EmailRequest
+}
+
+// NewEmail returns the builder of an email sent for domain.
+func NewEmail(domain string) *EmailBuilder {
+	b := &EmailBuilder{email: &pb.EmailRequest{}}
+	b.builder = newBuilder(b, domain)
+	return b
+}
+
+// From sets the sender, name may be empty.
+func (b *EmailBuilder) From(address, name string) *EmailBuilder {
+	b.email.FromAddress = address
+	b.email.FromName = name
+	return b
+}
+
+// To adds recipients.
+func (b *EmailBuilder) To(addresses ...string) *EmailBuilder {
+	b.email.To = append(b.email.To, emailAddresses(addresses)...)
+	return b
+}
+
+// ToNamed adds a recipient with a display name.
+func (b *EmailBuilder) ToNamed(address, name string) *EmailBuilder {
+	b.email.To = append(b.email.To, &pb.EmailAddress{Address: address, Name: name})
+	return b
+}
+
+// Cc adds copied recipients.
+func (b *EmailBuilder) Cc(addresses ...string) *EmailBuilder {
+	b.email.Cc = append(b.email.Cc, emailAddresses(addresses)...)
+	return b
+}
+
+// Bcc adds blind copied recipients.
+func (b *EmailBuilder) Bcc(addresses ...string) *EmailBuilder {
+	b.email.Bcc = append(b.email.Bcc, emailAddresses(addresses)...)
+	return b
+}
+
+// ReplyTo adds addresses replies are sent to.
+func (b *EmailBuilder) ReplyTo(addresses ...string) *EmailBuilder {
+	b.email.ReplyTo = append(b.email.ReplyTo, emailAddresses(addresses)...)
+	return b
+}
+
+func (b *EmailBuilder) Subject(subject string) *EmailBuilder {
+	b.email.Subject = subject
+	return b
+}
+
+func (b *EmailBuilder) HTML(html string) *EmailBuilder {
+	b.email.Html = html
+	return b
+}
+
+func (b *EmailBuilder) Text(text string) *EmailBuilder {
+	b.email.Text = text
+	return b
+}
+
+// Attach attaches a file.
+func (b *EmailBuilder) Attach(name string, data []byte) *EmailBuilder {
+	b.email.Attachments = append(b.email.Attachments, &pb.Attachment{Name: name, Data: data})
+	return b
+}
+
+// AttachURL attaches a file the worker fetches from url.
+func (b *EmailBuilder) AttachURL(name, url string) *EmailBuilder {
+	b.email.Attachments = append(b.email.Attachments, &pb.Attachment{Name: name, Url: &url})
+	return b
+}
+
+// Inline attaches an image the HTML body shows with <img src="cid:contentID">.
+func (b *EmailBuilder) Inline(name, contentID string, data []byte) *EmailBuilder {
+	b.email.Attachments = append(b.email.Attachments, &pb.Attachment{Name: name, Data: data, ContentId: contentID})
+	return b
+}
+
+// Request returns the request sending the email.
+func (b *EmailBuilder) Request() (*pb.SendCommunicationRequest, error) {
+	if b.req.GetDomain() == "" {
+		return nil, errors.New("email requires a domain")
+	}
+	if b.email.GetHtml() == "" && b.email.GetText() == "" {
+		return nil, errors.New("email requires an html or text body")
+	}
+	if len(b.email.GetTo()) == 0 && b.req.GetExternalCustomerId() == "" {
+		return nil, errors.New("email requires a recipient or a customer")
+	}
+	b.req.Email = b.email
+	return b.request(), nil
+}
+
+// PushBuilder builds a push notification.
+type PushBuilder struct {
+	builder[PushBuilder]
+	push *pb.PushRequest
+}
+
+// NewPush returns the builder of a push notification sent for domain.
+func NewPush(domain string) *PushBuilder {
+	b := &PushBuilder{push: &pb.PushRequest{}}
+	b.builder = newBuilder(b, domain)
+	return b
+}
+
+// To sets the external customer the notification is sent to.
+func (b *PushBuilder) To(externalCustomerID string) *PushBuilder {
+	b.push.ExternalCustomerId = externalCustomerID
+	return b
+}
+
+// Heading sets the heading in English and, if not empty, Arabic.
+func (b *PushBuilder) Heading(english, arabic string) *PushBuilder {
+	b.push.Heading = &pb.LanguageContent{English: english, Arabic: arabic}
+	return b
+}
+
+// Content sets the content in English and, if not empty, Arabic.
+func (b *PushBuilder) Content(english, arabic string) *PushBuilder {
+	b.push.Content = &pb.LanguageContent{English: english, Arabic: arabic}
+	return b
+}
+
+// SubTitle sets the subtitle in English and, if not empty, Arabic.
+func (b *PushBuilder) SubTitle(english, arabic string) *PushBuilder {
+	b.push.SubTitle = &pb.LanguageContent{English: english, Arabic: arabic}
+	return b
+}
+
+// Request returns the request sending the push notification. The push
+// provider deduplicates it with the communication's idempotency key.
+func (b *PushBuilder) Request() (*pb.SendCommunicationRequest, error) {
+	if b.req.GetDomain() == "" {
+		return nil, errors.New("push requires a domain")
+	}
+	if b.push.GetContent().GetEnglish() == "" && b.push.GetContent().GetArabic() == "" {
+		return nil, errors.New("push requires content")
+	}
+	if b.push.GetExternalCustomerId() == "" && b.req.GetExternalCustomerId() == "" {
+		return nil, errors.New("push requires a customer")
+	}
+	b.req.Push = b.push
+	req := b.request()
+	if req.Push.IdempotencyKey == "" {
+		req.Push.IdempotencyKey = req.IdempotencyKey
+	}
+	return req, nil
+}
+
+func emailAddresses(addresses []string) []*pb.EmailAddress {
+	resp := make([]*pb.EmailAddress, len(addresses))
+	for i, address := range addresses {
+		resp[i] = &pb.EmailAddress{Address: address}
+	}
+	return resp
+}
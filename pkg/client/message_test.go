@@ -0,0 +1,91 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	pb "github.com/anicoll/unicom/gen/pb/go/unicom/api/v1"
+	"github.com/anicoll/unicom/pkg/client"
+)
+
+type MessageTestSuite struct {
+	suite.Suite
+}
+
+func TestMessageTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageTestSuite))
+}
+
+func (s *MessageTestSuite) TestEmail_Request() {
+	req, err := client.NewEmail("domain").
+		From("from@example.com", "From").
+		To("a@example.com", "b@example.com").
+		Cc("c@example.com").
+		Subject("subject").
+		HTML("<p>html</p>").
+		Attach("a.txt", []byte("a")).
+		Priority(pb.Priority_PRIORITY_TRANSACTIONAL).
+		Webhook("https://example.com/hook").
+		IdempotencyKey("order-1").
+		Request()
+	s.NoError(err)
+	s.NoError(req.ValidateAll())
+
+	s.Equal("domain", req.GetDomain())
+	s.True(req.GetIsAsync())
+	s.Equal("order-1", req.GetIdempotencyKey())
+	s.Equal(pb.Priority_PRIORITY_TRANSACTIONAL, req.GetPriority())
+	s.Len(req.GetEmail().GetTo(), 2)
+	s.Equal("c@example.com", req.GetEmail().GetCc()[0].GetAddress())
+	s.Equal("<p>html</p>", req.GetEmail().GetHtml())
+	s.Equal(pb.ResponseSchema_RESPONSE_SCHEMA_HTTP, req.GetResponseChannels()[0].GetSchema())
+}
+
+func (s *MessageTestSuite) TestEmail_RequestKeepsIdempotencyKey() {
+	email := client.NewEmail("domain").To("to@example.com").Text("text")
+	first, err := email.Request()
+	s.NoError(err)
+	second, err := email.Request()
+	s.NoError(err)
+
+	s.NotEmpty(first.GetIdempotencyKey())
+	s.Equal(first.GetIdempotencyKey(), second.GetIdempotencyKey())
+	s.NotSame(first, second)
+}
+
+func (s *MessageTestSuite) TestEmail_RequestInvalid() {
+	_, err := client.NewEmail("").To("to@example.com").Text("text").Request()
+	s.ErrorContains(err, "domain")
+
+	_, err = client.NewEmail("domain").Text("text").Request()
+	s.ErrorContains(err, "recipient")
+
+	req, err := client.NewEmail("domain").Customer("customer").Text("text").Request()
+	s.NoError(err)
+	s.Equal("customer", req.GetExternalCustomerId())
+}
+
+func (s *MessageTestSuite) TestPush_Request() {
+	req, err := client.NewPush("domain").
+		To("customer").
+		Heading("heading", "عنوان").
+		Content("content", "").
+		Sync().
+		Request()
+	s.NoError(err)
+	s.NoError(req.ValidateAll())
+
+	s.False(req.GetIsAsync())
+	s.Equal("customer", req.GetPush().GetExternalCustomerId())
+	s.Equal("عنوان", req.GetPush().GetHeading().GetArabic())
+	s.Equal(req.GetIdempotencyKey(), req.GetPush().GetIdempotencyKey())
+}
+
+func (s *MessageTestSuite) TestPush_RequestInvalid() {
+	_, err := client.NewPush("domain").To("customer").Request()
+	s.ErrorContains(err, "content")
+
+	_, err = client.NewPush("domain").Content("content", "").Request()
+	s.ErrorContains(err, "customer")
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Workflow statuses a communication finishes with. COMPLETE covers a
+// communication which was sent, failed or expired once its response channels
+// were notified, see the communications ListCommunications returns for which.
+const (
+	StatusComplete  = "COMPLETE"
+	StatusError     = "ERROR"
+	StatusCancelled = "CANCELLED"
+)
+
+// DefaultPollInterval is how often Wait and Watch poll a communication's
+// status.
+const DefaultPollInterval = time.Second * 2
+
+// IsFinal reports whether a communication with the workflow status has
+// finished.
+func IsFinal(status string) bool {
+	return slices.Contains([]string{StatusComplete, StatusError, StatusCancelled}, status)
+}
+
+// Wait polls the status of a communication every interval until it has
+// finished, returning the status it finished with.
+func (c *Client) Wait(ctx context.Context, id string, interval time.Duration) (string, error) {
+	return c.Watch(ctx, id, interval, nil)
+}
+
+// Watch is Wait, calling onChange with every status the communication is
+// seen with.
+func (c *Client) Watch(ctx context.Context, id string, interval time.Duration, onChange func(status string)) (string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := ""
+	for {
+		status, err := c.Status(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The call failed as ctx is done.
+				return last, ctx.Err()
+			}
+			return last, err
+		}
+		if status != last && onChange != nil {
+			onChange(status)
+		}
+		last = status
+		if IsFinal(status) {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}